@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func runDump(flags *flag.FlagSet, args []string, out io.Writer) error {
+	limit := flags.Int64("limit", -1, "maximum number of rows to print, all rows when negative")
+	columns := flags.String("columns", "", "comma separated top level columns to print, all columns when empty")
+	path, err := parseFile(flags, args)
+	if err != nil {
+		return err
+	}
+	reader := openReader(path)
+	readColumns := util.NewArrayList[*store.MothColumn]()
+	if *columns == "" {
+		readColumns = reader.GetRootColumn().GetNestedColumns()
+	} else {
+		for _, name := range strings.Split(*columns, ",") {
+			column := findColumn(reader.GetRootColumn(), strings.TrimSpace(name))
+			if column == nil {
+				return fmt.Errorf("unknown column %q", name)
+			}
+			readColumns.Add(column)
+		}
+	}
+	names := make([]string, 0, readColumns.Size())
+	readTypes := util.NewArrayList[block.Type]()
+	for _, column := range readColumns.ToArray() {
+		names = append(names, column.GetColumnName())
+		readTypes.Add(metadata.ToBlockType(reader.GetFooter().GetTypes(), column.GetColumnId()))
+	}
+
+	recordReader := reader.CreateRecordReader(readColumns, readTypes, store.TRUE, time.UTC, memory.NewSimpleAggregatedMemoryContext(), store.INITIAL_BATCH_SIZE)
+	defer recordReader.Close()
+	writer := bufio.NewWriter(out)
+	rows := util.INT64_ZERO
+	for page := recordReader.NextPage(); page != nil && (*limit < 0 || rows < *limit); page = recordReader.NextPage() {
+		page = page.GetLoadedPage()
+		for position := util.INT32_ZERO; position < page.GetPositionCount() && (*limit < 0 || rows < *limit); position++ {
+			row := &jsonObject{names: names, values: make([]interface{}, len(names))}
+			for channel := range names {
+				row.values[channel] = jsonValue(readTypes.Get(channel), page.GetBlock(int32(channel)), position)
+			}
+			line, err := marshalJson(row)
+			if err != nil {
+				return err
+			}
+			writer.Write(line)
+			writer.WriteByte('\n')
+			rows++
+		}
+	}
+	return writer.Flush()
+}
+
+func findColumn(root *store.MothColumn, name string) *store.MothColumn {
+	for _, column := range root.GetNestedColumns().ToArray() {
+		if column.GetColumnName() == name {
+			return column
+		}
+	}
+	return nil
+}
+
+/**
+ * Converts a value read by the column readers to a value encoding/json can render. Decimals are
+ * rendered as strings so they keep their precision, dates and timestamps as UTC strings and
+ * maps as lists of key value objects, as JSON object keys must be strings.
+ */
+func jsonValue(kind block.Type, b block.Block, position int32) interface{} {
+	if b.IsNull(position) {
+		return nil
+	}
+	switch t := kind.(type) {
+	case *block.BooleanType:
+		return t.GetBoolean(b, position)
+	case *block.TinyintType, *block.SmallintType, *block.IntegerType, *block.BigintType:
+		return kind.GetLong(b, position)
+	case *block.RealType:
+		return jsonFloat(float64(math.Float32frombits(uint32(t.GetLong(b, position)))))
+	case *block.DoubleType:
+		return jsonFloat(t.GetDouble(b, position))
+	case *block.VarcharType, *block.CharType:
+		return kind.GetSlice(b, position).String()
+	case *block.VarbinaryType:
+		return t.GetSlice(b, position).AvailableBytes()
+	case *block.DateType:
+		return time.Unix(t.GetLong(b, position)*86400, 0).UTC().Format("2006-01-02")
+	case *block.ShortTimestampType:
+		return time.UnixMicro(t.GetLong(b, position)).UTC().Format("2006-01-02 15:04:05.000")
+	case *block.ShortTimestampWithTimeZoneType:
+		return time.UnixMilli(block.UnpackMillisUtc(t.GetLong(b, position))).UTC().Format("2006-01-02 15:04:05.000 UTC")
+	case *block.ShortDecimalType:
+		return block.ToString(t.GetLong(b, position), t.GetScale())
+	case *block.LongDecimalType:
+		return block.ToString2(t.GetObject(b, position).(*block.Int128), t.GetScale())
+	case *block.ArrayType:
+		elements := t.GetObject(b, position).(block.Block)
+		values := make([]interface{}, elements.GetPositionCount())
+		for i := range values {
+			values[i] = jsonValue(t.GetElementType(), elements, int32(i))
+		}
+		return values
+	case *block.MapType:
+		entries := t.GetObject(b, position).(block.Block)
+		values := make([]interface{}, entries.GetPositionCount()/2)
+		for i := range values {
+			values[i] = &jsonObject{names: []string{"key", "value"}, values: []interface{}{jsonValue(t.GetKeyType(), entries, int32(2*i)), jsonValue(t.GetValueType(), entries, int32(2*i+1))}}
+		}
+		return values
+	case *block.RowType:
+		fields := t.GetObject(b, position).(block.Block)
+		row := &jsonObject{names: make([]string, t.GetFields().Size()), values: make([]interface{}, t.GetFields().Size())}
+		for i, field := range t.GetFields().ToArray() {
+			row.names[i] = field.GetName().OrElse("field" + strconv.Itoa(i))
+			row.values[i] = jsonValue(field.GetType(), fields, int32(i))
+		}
+		return row
+	}
+	return fmt.Sprint(block.ReadNativeValue(kind, b, position))
+}
+
+/**
+ * JSON has no NaN and infinities, those are rendered as strings.
+ */
+func jsonFloat(value float64) interface{} {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	return value
+}
+
+/**
+ * JSON object that keeps the order of its fields, the order of the columns in the file.
+ */
+type jsonObject struct {
+	names  []string
+	values []interface{}
+}
+
+func (jt *jsonObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, name := range jt.names {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		key, _ := marshalJson(name)
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		value, err := marshalJson(jt.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+/**
+ * Like json.Marshal, but strings are not HTML escaped, the output is read by people.
+ */
+func marshalJson(value interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte{'\n'}), nil
+}
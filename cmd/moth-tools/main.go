@@ -0,0 +1,117 @@
+// Command moth-tools inspects moth files.
+//
+// Usage:
+//
+//	moth-tools meta <file>                             postscript, footer, types and stripe layout
+//	moth-tools stats [-row-groups] <file>              file, stripe and row group column statistics
+//	moth-tools dump [-limit n] [-columns a,b] <file>    rows as JSON lines
+//	moth-tools schema <file>                           the type of the file
+//
+// meta and stats print a JSON document. Corrupt files are reported on stderr with exit code 1.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mothdb-bd/orc-go/pkg/store"
+)
+
+// errUsage is returned after the usage of a subcommand was printed
+var errUsage = errors.New("usage")
+
+type command struct {
+	name  string
+	usage string
+	run   func(flags *flag.FlagSet, args []string, out io.Writer) error
+}
+
+var commands = []*command{
+	{"meta", "meta <file>", runMeta},
+	{"stats", "stats [-row-groups] <file>", runStats},
+	{"dump", "dump [-limit n] [-columns a,b] <file>", runDump},
+	{"schema", "schema <file>", runSchema},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+/**
+ * Runs the subcommand named by the first argument and returns the exit code. The readers
+ * report corrupt files by panicking, those panics are printed as errors.
+ */
+func run(args []string, stdout io.Writer, stderr io.Writer) (exitCode int) {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return 2
+	}
+	var cmd *command
+	for _, c := range commands {
+		if c.name == args[0] {
+			cmd = c
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "moth-tools: unknown command %q\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: moth-tools %s\n", cmd.usage)
+		flags.PrintDefaults()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(stderr, "moth-tools %s: %v\n", cmd.name, r)
+			exitCode = 1
+		}
+	}()
+	if err := cmd.run(flags, args[1:], stdout); err != nil {
+		if err == errUsage {
+			return 2
+		}
+		fmt.Fprintf(stderr, "moth-tools %s: %s\n", cmd.name, err.Error())
+		return 1
+	}
+	return 0
+}
+
+func printUsage(out io.Writer) {
+	fmt.Fprintln(out, "usage:")
+	for _, c := range commands {
+		fmt.Fprintf(out, "  moth-tools %s\n", c.usage)
+	}
+}
+
+/**
+ * Parses the flags of a subcommand that reads a single file and returns the file path. The flag
+ * set prints the usage when the arguments are invalid.
+ */
+func parseFile(flags *flag.FlagSet, args []string) (string, error) {
+	if err := flags.Parse(args); err != nil {
+		return "", errUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return "", errUsage
+	}
+	return flags.Arg(0), nil
+}
+
+func openReader(path string) *store.MothReader {
+	options := store.NewMothReaderOptions()
+	return store.CreateMothReader(store.NewFileMothDataSource(path, options), options).OrElseThrow(fmt.Sprintf("File %s is empty", path))
+}
+
+func writeJson(out io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
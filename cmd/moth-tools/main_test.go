@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func writeTestFile(t *testing.T, rowCount int) string {
+	path := filepath.Join(t.TempDir(), "test.moth")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	columnNames := util.NewArrayList("id", "name", "price", "active", "day", "scores", "seen")
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR, block.DOUBLE, block.BOOLEAN, block.DATE, block.NewArrayType(block.BIGINT), block.TIMESTAMP_MILLIS)
+	options := store.NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100)
+	writer := store.NewMothWriter(store.NewOutputStreamMothDataSink(mothio.NewOutputStream(file)), columnNames, types, metadata.CreateRootMothType(columnNames, types), metadata.ZLIB, options, util.EmptyMap[string, string](), store.NewMothWriterStats())
+	pb := spi.NewPageBuilder(types)
+	for row := 0; row < rowCount; row++ {
+		pb.DeclarePosition()
+		block.WriteNativeValue(block.BIGINT, pb.GetBlockBuilder(0), int64(row))
+		if row%10 == 9 {
+			pb.GetBlockBuilder(1).AppendNull()
+		} else {
+			block.WriteNativeValue(block.VARCHAR, pb.GetBlockBuilder(1), fmt.Sprintf("name-%04d", row))
+		}
+		block.WriteNativeValue(block.DOUBLE, pb.GetBlockBuilder(2), float64(row)/4)
+		block.WriteNativeValue(block.BOOLEAN, pb.GetBlockBuilder(3), row%2 == 0)
+		block.WriteNativeValue(block.DATE, pb.GetBlockBuilder(4), int64(row))
+		scores := block.BIGINT.CreateBlockBuilder2(nil, 3)
+		for score := 0; score < row%3; score++ {
+			scores.WriteLong(int64(row * score))
+		}
+		types.Get(5).WriteObject(pb.GetBlockBuilder(5), scores.Build())
+		block.WriteNativeValue(block.TIMESTAMP_MILLIS, pb.GetBlockBuilder(6), int64(1609459200+row)*1_000_000+int64(row)*1_000)
+		if pb.GetPositionCount() == 500 {
+			writer.Write(pb.Build())
+			pb = spi.NewPageBuilder(types)
+		}
+	}
+	if !pb.IsEmpty() {
+		writer.Write(pb.Build())
+	}
+	writer.Close()
+	return path
+}
+
+func runTool(t *testing.T, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	exitCode := run(args, &stdout, &stderr)
+	return stdout.String(), stderr.String(), exitCode
+}
+
+func TestMeta(t *testing.T) {
+	path := writeTestFile(t, 2500)
+	stdout, stderr, exitCode := runTool(t, "meta", path)
+	if exitCode != 0 {
+		t.Fatalf("exit code %d: %s", exitCode, stderr)
+	}
+	var meta fileMeta
+	if err := json.Unmarshal([]byte(stdout), &meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta.PostScript.Compression != "ZLIB" || meta.NumberOfRows != 2500 || len(meta.Stripes) != 3 {
+		t.Fatalf("got compression %s, %d rows and %d stripes", meta.PostScript.Compression, meta.NumberOfRows, len(meta.Stripes))
+	}
+	if len(meta.Types) != 9 || meta.Types[0].Kind != "STRUCT" || meta.Types[6].Kind != "LIST" || meta.Types[7].Kind != "LONG" || meta.Types[8].Kind != "TIMESTAMP" {
+		t.Fatalf("got types %+v", meta.Types)
+	}
+	for _, stripe := range meta.Stripes {
+		if len(stripe.Streams) == 0 || len(stripe.Encodings) != 9 {
+			t.Fatalf("got %d streams and %d encodings", len(stripe.Streams), len(stripe.Encodings))
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	path := writeTestFile(t, 2500)
+	stdout, stderr, exitCode := runTool(t, "stats", "-row-groups", path)
+	if exitCode != 0 {
+		t.Fatalf("exit code %d: %s", exitCode, stderr)
+	}
+	var stats struct {
+		File    []map[string]interface{}
+		Stripes []struct {
+			Columns   []map[string]interface{}
+			RowGroups []struct {
+				Column    uint32
+				RowGroups []map[string]interface{}
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+		t.Fatal(err)
+	}
+	id := stats.File[1]
+	if id["min"] != 0.0 || id["max"] != 2499.0 || id["numberOfValues"] != 2500.0 {
+		t.Fatalf("got id statistics %v", id)
+	}
+	if name := stats.File[2]; name["min"] != "name-0000" || name["max"] != "name-2498" || name["numberOfValues"] != 2250.0 {
+		t.Fatalf("got name statistics %v", name)
+	}
+	if len(stats.Stripes) != 3 || len(stats.Stripes[2].RowGroups) == 0 || len(stats.Stripes[2].RowGroups[0].RowGroups) != 5 {
+		t.Fatalf("got %d stripes", len(stats.Stripes))
+	}
+	for _, column := range stats.Stripes[1].RowGroups {
+		if column.Column != 1 {
+			continue
+		}
+		if rowGroup := column.RowGroups[3]; rowGroup["min"] != 1300.0 || rowGroup["max"] != 1399.0 {
+			t.Fatalf("got row group statistics %v", rowGroup)
+		}
+		return
+	}
+	t.Fatal("no row group statistics for column 1")
+}
+
+func TestDump(t *testing.T) {
+	path := writeTestFile(t, 2500)
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			"first rows",
+			[]string{"-limit", "3"},
+			[]string{
+				`{"id":0,"name":"name-0000","price":0,"active":true,"day":"1970-01-01","scores":[],"seen":"2021-01-01 00:00:00.000"}`,
+				`{"id":1,"name":"name-0001","price":0.25,"active":false,"day":"1970-01-02","scores":[0],"seen":"2021-01-01 00:00:01.001"}`,
+				`{"id":2,"name":"name-0002","price":0.5,"active":true,"day":"1970-01-03","scores":[0,2],"seen":"2021-01-01 00:00:02.002"}`,
+			},
+		},
+		{
+			"columns",
+			[]string{"-limit", "10", "-columns", "name,id"},
+			[]string{
+				`{"name":"name-0000","id":0}`, `{"name":"name-0001","id":1}`, `{"name":"name-0002","id":2}`, `{"name":"name-0003","id":3}`, `{"name":"name-0004","id":4}`,
+				`{"name":"name-0005","id":5}`, `{"name":"name-0006","id":6}`, `{"name":"name-0007","id":7}`, `{"name":"name-0008","id":8}`, `{"name":null,"id":9}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, exitCode := runTool(t, append(append([]string{"dump"}, tt.args...), path)...)
+			if exitCode != 0 {
+				t.Fatalf("exit code %d: %s", exitCode, stderr)
+			}
+			if got := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n"); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	stdout, stderr, exitCode := runTool(t, "dump", path)
+	if exitCode != 0 {
+		t.Fatalf("exit code %d: %s", exitCode, stderr)
+	}
+	if lines := strings.Count(stdout, "\n"); lines != 2500 {
+		t.Fatalf("dumped %d rows, want 2500", lines)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	stdout, stderr, exitCode := runTool(t, "schema", writeTestFile(t, 10))
+	if exitCode != 0 {
+		t.Fatalf("exit code %d: %s", exitCode, stderr)
+	}
+	if want := "struct<id:bigint,name:string,price:double,active:boolean,day:date,scores:array<bigint>,seen:timestamp>\n"; stdout != want {
+		t.Fatalf("got %q, want %q", stdout, want)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	corrupt := filepath.Join(t.TempDir(), "corrupt.moth")
+	if err := os.WriteFile(corrupt, bytes.Repeat([]byte{0xff}, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		exitCode int
+	}{
+		{"no command", []string{}, 2},
+		{"unknown command", []string{"cat", corrupt}, 2},
+		{"missing file argument", []string{"meta"}, 2},
+		{"unknown flag", []string{"dump", "-rows", "3", corrupt}, 2},
+		{"missing file", []string{"meta", filepath.Join(t.TempDir(), "missing.moth")}, 1},
+		{"corrupt file", []string{"meta", corrupt}, 1},
+		{"unknown column", []string{"dump", "-columns", "nope", writeTestFile(t, 10)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, exitCode := runTool(t, tt.args...)
+			if exitCode != tt.exitCode {
+				t.Fatalf("exit code %d, want %d", exitCode, tt.exitCode)
+			}
+			if stderr == "" {
+				t.Fatal("nothing was printed to stderr")
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/store"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+type fileMeta struct {
+	PostScript     postScriptMeta    `json:"postScript"`
+	NumberOfRows   uint64            `json:"numberOfRows"`
+	RowsInRowGroup *int32            `json:"rowsInRowGroup,omitempty"`
+	WriterId       *uint32           `json:"writerId,omitempty"`
+	UserMetadata   map[string]string `json:"userMetadata,omitempty"`
+	Types          []typeMeta        `json:"types"`
+	Stripes        []stripeMeta      `json:"stripes"`
+	Encryption     *encryptionMeta   `json:"encryption,omitempty"`
+}
+
+type postScriptMeta struct {
+	Version              []uint32 `json:"version"`
+	FooterLength         int64    `json:"footerLength"`
+	MetadataLength       int64    `json:"metadataLength"`
+	Compression          string   `json:"compression"`
+	CompressionBlockSize uint64   `json:"compressionBlockSize"`
+	HiveWriterVersion    string   `json:"hiveWriterVersion"`
+}
+
+type typeMeta struct {
+	Column     uint32            `json:"column"`
+	Kind       string            `json:"kind"`
+	FieldNames []string          `json:"fieldNames,omitempty"`
+	FieldTypes []uint32          `json:"fieldTypes,omitempty"`
+	Length     *int32            `json:"length,omitempty"`
+	Precision  *int32            `json:"precision,omitempty"`
+	Scale      *int32            `json:"scale,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type stripeMeta struct {
+	Offset       uint64              `json:"offset"`
+	NumberOfRows int32               `json:"numberOfRows"`
+	IndexLength  uint64              `json:"indexLength"`
+	DataLength   uint64              `json:"dataLength"`
+	FooterLength uint64              `json:"footerLength"`
+	TimeZone     string              `json:"timeZone,omitempty"`
+	Streams      []streamMeta        `json:"streams"`
+	Encodings    []encodingMeta      `json:"encodings"`
+	Encryption   []stripeVariantMeta `json:"encryption,omitempty"`
+}
+
+type streamMeta struct {
+	Column uint32 `json:"column"`
+	Kind   string `json:"kind"`
+	Length int32  `json:"length"`
+}
+
+type encodingMeta struct {
+	Column         uint32 `json:"column"`
+	Kind           string `json:"kind"`
+	DictionarySize uint32 `json:"dictionarySize,omitempty"`
+}
+
+type stripeVariantMeta struct {
+	Streams   []streamMeta   `json:"streams"`
+	Encodings []encodingMeta `json:"encodings"`
+}
+
+type encryptionMeta struct {
+	KeyProvider string        `json:"keyProvider"`
+	Keys        []keyMeta     `json:"keys"`
+	Variants    []variantMeta `json:"variants"`
+	Masks       []maskMeta    `json:"masks,omitempty"`
+}
+
+type keyMeta struct {
+	Name      string `json:"name"`
+	Version   uint32 `json:"version"`
+	Algorithm string `json:"algorithm"`
+}
+
+type variantMeta struct {
+	Root     uint32 `json:"root"`
+	KeyIndex int32  `json:"keyIndex"`
+}
+
+type maskMeta struct {
+	Name       string   `json:"name"`
+	Parameters []string `json:"parameters,omitempty"`
+	Columns    []uint32 `json:"columns"`
+}
+
+func runMeta(flags *flag.FlagSet, args []string, out io.Writer) error {
+	path, err := parseFile(flags, args)
+	if err != nil {
+		return err
+	}
+	return writeJson(out, readFileMeta(openReader(path)))
+}
+
+func readFileMeta(reader *store.MothReader) *fileMeta {
+	postScript := reader.GetPostScript()
+	footer := reader.GetFooter()
+	meta := &fileMeta{
+		PostScript: postScriptMeta{
+			Version:              postScript.GetVersion(),
+			FooterLength:         postScript.GetFooterLength(),
+			MetadataLength:       postScript.GetMetadataLength(),
+			Compression:          postScript.GetCompression().String(),
+			CompressionBlockSize: postScript.GetCompressionBlockSize(),
+			HiveWriterVersion:    postScript.GetHiveWriterVersion().String(),
+		},
+		NumberOfRows: footer.GetNumberOfRows(),
+		Types:        make([]typeMeta, 0, footer.GetTypes().Size()),
+		Stripes:      make([]stripeMeta, 0, footer.GetStripes().Size()),
+	}
+	if footer.GetRowsInRowGroup().IsPresent() {
+		rowsInRowGroup := footer.GetRowsInRowGroup().Get()
+		meta.RowsInRowGroup = &rowsInRowGroup
+	}
+	if footer.GetWriterId().IsPresent() {
+		writerId := footer.GetWriterId().Get()
+		meta.WriterId = &writerId
+	}
+	if len(footer.GetUserMetadata()) > 0 {
+		meta.UserMetadata = make(map[string]string)
+		for key, value := range footer.GetUserMetadata() {
+			meta.UserMetadata[key] = value.String()
+		}
+	}
+	for i := int32(0); i < footer.GetTypes().Size(); i++ {
+		meta.Types = append(meta.Types, newTypeMeta(metadata.MothColumnId(i), footer.GetTypes().Get(metadata.MothColumnId(i))))
+	}
+	variantRoots := make([]metadata.MothColumnId, 0)
+	if footer.GetEncryption().IsPresent() {
+		meta.Encryption = newEncryptionMeta(footer.GetEncryption().Get())
+		for _, variant := range footer.GetEncryption().Get().GetVariants().ToArray() {
+			variantRoots = append(variantRoots, variant.GetRoot())
+		}
+	}
+	for _, stripe := range footer.GetStripes().ToArray() {
+		meta.Stripes = append(meta.Stripes, newStripeMeta(stripe, reader.ReadStripeFooter(stripe, time.UTC), variantRoots))
+	}
+	return meta
+}
+
+func newTypeMeta(columnId metadata.MothColumnId, mothType *metadata.MothType) typeMeta {
+	meta := typeMeta{
+		Column:     columnId.GetId(),
+		Kind:       mothType.GetMothTypeKind().String(),
+		Attributes: mothType.GetAttributes(),
+	}
+	if mothType.GetFieldNames() != nil {
+		meta.FieldNames = mothType.GetFieldNames().ToArray()
+	}
+	for _, fieldType := range mothType.GetFieldTypeIndexes().ToArray() {
+		meta.FieldTypes = append(meta.FieldTypes, fieldType.GetId())
+	}
+	if mothType.GetLength().IsPresent() {
+		length := mothType.GetLength().Get()
+		meta.Length = &length
+	}
+	if mothType.GetPrecision().IsPresent() {
+		precision := mothType.GetPrecision().Get()
+		meta.Precision = &precision
+	}
+	if mothType.GetScale().IsPresent() {
+		scale := mothType.GetScale().Get()
+		meta.Scale = &scale
+	}
+	return meta
+}
+
+/**
+ * The encodings of an encryption variant describe the columns of the variant subtree, starting
+ * at the variant root.
+ */
+func newStripeMeta(stripe *metadata.StripeInformation, stripeFooter *metadata.StripeFooter, variantRoots []metadata.MothColumnId) stripeMeta {
+	meta := stripeMeta{
+		Offset:       stripe.GetOffset(),
+		NumberOfRows: stripe.GetNumberOfRows(),
+		IndexLength:  stripe.GetIndexLength(),
+		DataLength:   stripe.GetDataLength(),
+		FooterLength: stripe.GetFooterLength(),
+		Streams:      newStreamMetas(stripeFooter.GetStreams().ToArray()),
+		Encodings:    newEncodingMetas(stripeFooter.GetColumnEncodings().List().ToArray(), metadata.ROOT_COLUMN),
+	}
+	if stripeFooter.GetTimeZone() != nil {
+		meta.TimeZone = stripeFooter.GetTimeZone().String()
+	}
+	for i, variant := range stripeFooter.GetEncryption().ToArray() {
+		root := metadata.ROOT_COLUMN
+		if i < len(variantRoots) {
+			root = variantRoots[i]
+		}
+		meta.Encryption = append(meta.Encryption, stripeVariantMeta{
+			Streams:   newStreamMetas(variant.GetStreams().ToArray()),
+			Encodings: newEncodingMetas(variant.GetEncodings().ToArray(), root),
+		})
+	}
+	return meta
+}
+
+func newStreamMetas(streams []*metadata.Stream) []streamMeta {
+	metas := make([]streamMeta, 0, len(streams))
+	for _, stream := range streams {
+		metas = append(metas, streamMeta{Column: stream.GetColumnId().GetId(), Kind: stream.GetStreamKind().String(), Length: stream.GetLength()})
+	}
+	return metas
+}
+
+func newEncodingMetas(encodings []*metadata.ColumnEncoding, firstColumn metadata.MothColumnId) []encodingMeta {
+	metas := make([]encodingMeta, 0, len(encodings))
+	for i, encoding := range encodings {
+		metas = append(metas, encodingMeta{Column: firstColumn.GetId() + uint32(i), Kind: encoding.GetColumnEncodingKind().String(), DictionarySize: encoding.GetDictionarySize()})
+	}
+	return metas
+}
+
+func newEncryptionMeta(encryption *metadata.Encryption) *encryptionMeta {
+	meta := &encryptionMeta{KeyProvider: encryption.GetKeyProvider().String()}
+	for _, key := range encryption.GetKeys().ToArray() {
+		meta.Keys = append(meta.Keys, keyMeta{Name: key.GetKeyName(), Version: key.GetKeyVersion(), Algorithm: key.GetAlgorithm().String()})
+	}
+	for _, variant := range encryption.GetVariants().ToArray() {
+		meta.Variants = append(meta.Variants, variantMeta{Root: variant.GetRoot().GetId(), KeyIndex: variant.GetKeyIndex()})
+	}
+	for _, mask := range encryption.GetMasks().ToArray() {
+		columns := make([]uint32, 0, mask.GetColumns().Size())
+		for _, column := range mask.GetColumns().ToArray() {
+			columns = append(columns, column.GetId())
+		}
+		meta.Masks = append(meta.Masks, maskMeta{Name: mask.GetName(), Parameters: mask.GetParameters().ToArray(), Columns: columns})
+	}
+	return meta
+}
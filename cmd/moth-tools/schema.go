@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+func runSchema(flags *flag.FlagSet, args []string, out io.Writer) error {
+	path, err := parseFile(flags, args)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, typeString(openReader(path).GetFooter().GetTypes(), metadata.ROOT_COLUMN))
+	return err
+}
+
+/**
+ * Renders a column type in the Hive type syntax, e.g. struct<id:bigint,name:string>.
+ */
+func typeString(types *metadata.ColumnMetadata[*metadata.MothType], columnId metadata.MothColumnId) string {
+	mothType := types.Get(columnId)
+	switch mothType.GetMothTypeKind() {
+	case metadata.BOOLEAN:
+		return "boolean"
+	case metadata.BYTE:
+		return "tinyint"
+	case metadata.SHORT:
+		return "smallint"
+	case metadata.INT:
+		return "int"
+	case metadata.LONG:
+		return "bigint"
+	case metadata.FLOAT:
+		return "float"
+	case metadata.DOUBLE:
+		return "double"
+	case metadata.STRING:
+		return "string"
+	case metadata.VARCHAR:
+		return fmt.Sprintf("varchar(%d)", mothType.GetLength().Get())
+	case metadata.CHAR:
+		return fmt.Sprintf("char(%d)", mothType.GetLength().Get())
+	case metadata.BINARY:
+		return "binary"
+	case metadata.DATE:
+		return "date"
+	case metadata.TIMESTAMP:
+		return "timestamp"
+	case metadata.TIMESTAMP_INSTANT:
+		return "timestamp with local time zone"
+	case metadata.DECIMAL:
+		return fmt.Sprintf("decimal(%d,%d)", mothType.GetPrecision().Get(), mothType.GetScale().Get())
+	case metadata.LIST:
+		return "array<" + typeString(types, mothType.GetFieldTypeIndex(0)) + ">"
+	case metadata.MAP:
+		return "map<" + typeString(types, mothType.GetFieldTypeIndex(0)) + "," + typeString(types, mothType.GetFieldTypeIndex(1)) + ">"
+	case metadata.STRUCT:
+		fields := make([]string, mothType.GetFieldCount())
+		for i := range fields {
+			fields[i] = mothType.GetFieldName(int32(i)) + ":" + typeString(types, mothType.GetFieldTypeIndex(int32(i)))
+		}
+		return "struct<" + strings.Join(fields, ",") + ">"
+	case metadata.UNION:
+		variants := make([]string, mothType.GetFieldCount())
+		for i := range variants {
+			variants[i] = typeString(types, mothType.GetFieldTypeIndex(int32(i)))
+		}
+		return "uniontype<" + strings.Join(variants, ",") + ">"
+	}
+	return mothType.GetMothTypeKind().String()
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/store"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+type fileStats struct {
+	File    []*columnStats `json:"file"`
+	Stripes []stripeStats  `json:"stripes"`
+}
+
+type stripeStats struct {
+	Stripe    int             `json:"stripe"`
+	Columns   []*columnStats  `json:"columns"`
+	RowGroups []rowGroupStats `json:"rowGroups,omitempty"`
+}
+
+type rowGroupStats struct {
+	Column    uint32         `json:"column"`
+	RowGroups []*columnStats `json:"rowGroups"`
+}
+
+/**
+ * Statistics of one column, only the statistics that the writer recorded for the column type
+ * are set. Minimum and maximum are rendered as strings for strings and decimals.
+ */
+type columnStats struct {
+	Column         uint32      `json:"column"`
+	NumberOfValues *int64      `json:"numberOfValues,omitempty"`
+	MinAverageSize *int64      `json:"minAverageValueSizeInBytes,omitempty"`
+	TrueValueCount *int64      `json:"trueValueCount,omitempty"`
+	Min            interface{} `json:"min,omitempty"`
+	Max            interface{} `json:"max,omitempty"`
+	Sum            *int64      `json:"sum,omitempty"`
+	HasBloomFilter bool        `json:"hasBloomFilter,omitempty"`
+}
+
+func runStats(flags *flag.FlagSet, args []string, out io.Writer) error {
+	rowGroups := flags.Bool("row-groups", false, "also print the statistics of every row group")
+	path, err := parseFile(flags, args)
+	if err != nil {
+		return err
+	}
+	return writeJson(out, readFileStats(openReader(path), *rowGroups))
+}
+
+func readFileStats(reader *store.MothReader, rowGroups bool) *fileStats {
+	footer := reader.GetFooter()
+	stats := &fileStats{File: make([]*columnStats, 0), Stripes: make([]stripeStats, 0)}
+	if footer.GetFileStats().IsPresent() {
+		stats.File = newColumnStatsList(footer.GetFileStats().Get().List().ToArray())
+	}
+	stripeStatsList := reader.GetMetadata().GetStripeStatsList()
+	for i, stripe := range footer.GetStripes().ToArray() {
+		stripeStat := stripeStats{Stripe: i, Columns: make([]*columnStats, 0)}
+		if i < stripeStatsList.Size() && stripeStatsList.Get(i).IsPresent() {
+			stripeStat.Columns = newColumnStatsList(stripeStatsList.Get(i).Get().GetColumnStatistics().List().ToArray())
+		}
+		if rowGroups {
+			rowGroupIndexes := reader.ReadRowGroupIndexes(stripe, reader.ReadStripeFooter(stripe, time.UTC))
+			for column := int32(0); column < footer.GetTypes().Size(); column++ {
+				indexes, ok := rowGroupIndexes[metadata.MothColumnId(column)]
+				if !ok {
+					continue
+				}
+				columnRowGroups := rowGroupStats{Column: uint32(column), RowGroups: make([]*columnStats, 0, indexes.Size())}
+				for _, index := range indexes.ToArray() {
+					columnRowGroups.RowGroups = append(columnRowGroups.RowGroups, newColumnStats(uint32(column), index.GetColumnStatistics()))
+				}
+				stripeStat.RowGroups = append(stripeStat.RowGroups, columnRowGroups)
+			}
+		}
+		stats.Stripes = append(stats.Stripes, stripeStat)
+	}
+	return stats
+}
+
+/**
+ * Columns without statistics, like the decrypted columns in the stripe statistics, are left out.
+ */
+func newColumnStatsList(statistics []*metadata.ColumnStatistics) []*columnStats {
+	stats := make([]*columnStats, 0, len(statistics))
+	for column, columnStatistics := range statistics {
+		if columnStatistics != nil {
+			stats = append(stats, newColumnStats(uint32(column), columnStatistics))
+		}
+	}
+	return stats
+}
+
+func newColumnStats(column uint32, statistics *metadata.ColumnStatistics) *columnStats {
+	stats := &columnStats{Column: column, HasBloomFilter: statistics.GetBloomFilter() != nil}
+	if statistics.HasNumberOfValues() {
+		numberOfValues := statistics.GetNumberOfValues()
+		stats.NumberOfValues = &numberOfValues
+	}
+	if statistics.HasMinAverageValueSizeInBytes() {
+		minAverageSize := statistics.GetMinAverageValueSizeInBytes()
+		stats.MinAverageSize = &minAverageSize
+	}
+	if booleanStatistics := statistics.GetBooleanStatistics(); booleanStatistics != nil {
+		trueValueCount := booleanStatistics.GetTrueValueCount()
+		stats.TrueValueCount = &trueValueCount
+	}
+	if integerStatistics := statistics.GetIntegerStatistics(); integerStatistics != nil {
+		stats.Min = integerStatistics.GetMinPtr()
+		stats.Max = integerStatistics.GetMaxPtr()
+		stats.Sum = integerStatistics.GetSumPtr()
+	}
+	if doubleStatistics := statistics.GetDoubleStatistics(); doubleStatistics != nil {
+		stats.Min = doubleStatistics.GetMinPtr()
+		stats.Max = doubleStatistics.GetMaxPtr()
+	}
+	if stringStatistics := statistics.GetStringStatistics(); stringStatistics != nil {
+		if stringStatistics.GetMin() != nil {
+			stats.Min = stringStatistics.GetMin().String()
+		}
+		if stringStatistics.GetMax() != nil {
+			stats.Max = stringStatistics.GetMax().String()
+		}
+		stats.Sum = stringStatistics.GetSumPtr()
+	}
+	if dateStatistics := statistics.GetDateStatistics(); dateStatistics != nil {
+		stats.Min = dateStatistics.GetMinPtr()
+		stats.Max = dateStatistics.GetMaxPtr()
+	}
+	if timestampStatistics := statistics.GetTimestampStatistics(); timestampStatistics != nil {
+		stats.Min = timestampStatistics.GetMinPtr()
+		stats.Max = timestampStatistics.GetMaxPtr()
+	}
+	if decimalStatistics := statistics.GetDecimalStatistics(); decimalStatistics != nil {
+		if decimalStatistics.GetMin() != nil {
+			stats.Min = decimalStatistics.GetMin().String()
+		}
+		if decimalStatistics.GetMax() != nil {
+			stats.Max = decimalStatistics.GetMax().String()
+		}
+	}
+	if binaryStatistics := statistics.GetBinaryStatistics(); binaryStatistics != nil {
+		stats.Sum = binaryStatistics.GetSumPtr()
+	}
+	return stats
+}
@@ -51,9 +51,23 @@ func (sec *StandardErrorCode) ToString() string {
 
 var (
 
+	// 用户错误
+	// 不支持的功能
+	NOT_SUPPORTED = NewCode("NOT_SUPPORTED", 13, USER_ERROR)
+
 	// 内部错误
 	// 通用内部错误
 	GENERIC_INTERNAL_ERROR = NewCode("GENERIC_INTERNAL_ERROR", 65536, INTERNAL_ERROR)
+
+	// 资源不足
+	// 通用资源不足错误
+	GENERIC_INSUFFICIENT_RESOURCES = NewCode("GENERIC_INSUFFICIENT_RESOURCES", 131072, INSUFFICIENT_RESOURCES)
+
+	// 外部错误
+	// 文件损坏
+	CORRUPT_FILE = NewCode("CORRUPT_FILE", 16777216, EXTERNAL)
+	// 读写失败
+	IO_ERROR = NewCode("IO_ERROR", 16777217, EXTERNAL)
 )
 
 type StandardError struct {
@@ -75,6 +89,10 @@ func (se *StandardError) GetCode() *StandardErrorCode {
 	return se.code
 }
 
+func (se *StandardError) GetMessage() string {
+	return se.msg
+}
+
 // 实现接口
 func (se *StandardError) Error() string {
 	return fmt.Sprintf("Error code: %v , kind: %v, msg: %v", se.code.code, se.code.kind, se.msg)
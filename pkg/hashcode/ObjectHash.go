@@ -55,6 +55,8 @@ func ObjectHashCode(value basic.Object) int32 {
 	case []byte:
 		b := value.([]byte)
 		return BytesHashCode(b)
+	case *slice.Slice:
+		return t.HashCode()
 	case uintptr:
 		s, flag := value.(*slice.Slice)
 		if flag {
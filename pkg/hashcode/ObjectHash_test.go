@@ -0,0 +1,20 @@
+package hashcode
+
+import (
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+)
+
+func TestObjectHashCode_Slice(t *testing.T) {
+	// varchar map keys are read as slices, equal keys hash equally whatever slice they are read from
+	key := slice.NewWithString("map-key")
+	other := slice.NewWithString("other-map-key")
+	region, _ := other.MakeSlice(6, 7)
+	if ObjectHashCode(key) != key.HashCode() || ObjectHashCode(region) != ObjectHashCode(key) {
+		t.Fatalf("hashed equal slices as %d and %d", ObjectHashCode(key), ObjectHashCode(region))
+	}
+	if ObjectHashCode(key) != ObjectHashCode([]byte("map-key")) {
+		t.Fatal("hashed a slice and its bytes differently")
+	}
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Root memory context of a pool with a limit, shared by the readers and writers of a process to
+ * keep them within a memory budget. Reservations through TrySetBytes are rejected when they
+ * would take the pool above its limit, the readers and writers react by reading smaller pages
+ * and flushing stripes early. Updates through SetBytes account memory that is already allocated
+ * and are never rejected, so the pool can be above its limit until that memory is released.
+ */
+type BoundedAggregatedMemoryContext struct {
+	// 继承
+	AbstractAggregatedMemoryContext
+
+	limit int64
+}
+
+func NewBoundedAggregatedMemoryContext(limit int64) *BoundedAggregatedMemoryContext {
+	util.CheckArgument2(limit >= 0, fmt.Sprintf("limit is negative: %d", limit))
+	bt := new(BoundedAggregatedMemoryContext)
+	bt.limit = limit
+	bt.lock = new(sync.Mutex)
+	return bt
+}
+
+func (bt *BoundedAggregatedMemoryContext) GetLimit() int64 {
+	return bt.limit
+}
+
+/**
+ * Returns the bytes that can still be reserved, 0 when the pool is at or above its limit.
+ */
+func (bt *BoundedAggregatedMemoryContext) GetAvailableBytes() int64 {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+
+	if bt.usedBytes >= bt.limit {
+		return 0
+	}
+	return bt.limit - bt.usedBytes
+}
+
+// @Override
+func (bt *BoundedAggregatedMemoryContext) updateBytes(allocationTag string, delta int64) {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+
+	if bt.closed {
+		panic("BoundedAggregatedMemoryContext is already closed")
+	}
+	bt.addBytes(delta)
+}
+
+// @Override
+func (bt *BoundedAggregatedMemoryContext) tryUpdateBytes(allocationTag string, delta int64) bool {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+
+	if bt.closed {
+		panic("BoundedAggregatedMemoryContext is already closed")
+	}
+	if delta > 0 && util.AddExactInt64(bt.usedBytes, delta) > bt.limit {
+		return false
+	}
+	bt.addBytes(delta)
+	return true
+}
+
+// @Override synchronized
+func (bt *BoundedAggregatedMemoryContext) GetBytes() int64 {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+
+	return bt.usedBytes
+}
+
+// @Override synchronized
+func (bt *BoundedAggregatedMemoryContext) Close() {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+
+	if bt.closed {
+		return
+	}
+	bt.closed = true
+	bt.usedBytes = 0
+}
+
+func (bt *BoundedAggregatedMemoryContext) addBytes(bytes int64) {
+	bt.usedBytes = util.AddExactInt64(bt.usedBytes, bytes)
+}
+
+// @Override
+func (bt *BoundedAggregatedMemoryContext) NewAggregatedMemoryContext() AggregatedMemoryContext {
+	return NewChildAggregatedMemoryContext(bt)
+}
+
+// @Override
+func (bt *BoundedAggregatedMemoryContext) NewLocalMemoryContext(allocationTag string) LocalMemoryContext {
+	return NewSimpleLocalMemoryContext(bt, allocationTag)
+}
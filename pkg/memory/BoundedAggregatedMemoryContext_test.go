@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundedAggregatedMemoryContext_TrySetBytes(t *testing.T) {
+	pool := NewBoundedAggregatedMemoryContext(1000)
+	reader := pool.NewAggregatedMemoryContext()
+	page := reader.NewLocalMemoryContext("page")
+	buffers := reader.NewAggregatedMemoryContext().NewLocalMemoryContext("buffers")
+
+	assert.True(t, page.TrySetBytes(600))
+	assert.False(t, page.TrySetBytes(1001))
+	assert.Equal(t, int64(600), page.GetBytes())
+	assert.Equal(t, int64(400), pool.GetAvailableBytes())
+
+	// allocated memory is accounted above the limit, further reservations are rejected until it is released
+	buffers.SetBytes(500)
+	assert.Equal(t, int64(1100), pool.GetBytes())
+	assert.Equal(t, int64(0), pool.GetAvailableBytes())
+	assert.False(t, page.TrySetBytes(601))
+	assert.True(t, page.TrySetBytes(100))
+	assert.True(t, page.TrySetBytes(400))
+	assert.Equal(t, int64(900), reader.GetBytes())
+
+	buffers.Close()
+	reader.Close()
+	assert.Equal(t, int64(0), pool.GetBytes())
+	assert.Equal(t, int64(1000), pool.GetAvailableBytes())
+}
+
+func TestBoundedAggregatedMemoryContext_Concurrent(t *testing.T) {
+	pool := NewBoundedAggregatedMemoryContext(10000)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reader := pool.NewAggregatedMemoryContext()
+			defer reader.Close()
+			page := reader.NewLocalMemoryContext("page")
+			for bytes := int64(0); bytes < 5000; bytes += 10 {
+				if page.TrySetBytes(bytes) {
+					assert.LessOrEqual(t, pool.GetBytes(), pool.GetLimit())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(0), pool.GetBytes())
+}
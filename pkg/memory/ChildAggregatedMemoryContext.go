@@ -23,14 +23,13 @@ func NewChildAggregatedMemoryContext(parentMemoryContext AggregatedMemoryContext
 // @Override
 func (ct *ChildAggregatedMemoryContext) updateBytes(allocationTag string, delta int64) {
 	ct.lock.Lock()
+	defer ct.lock.Unlock()
 
 	if ct.isClosed() {
 		panic("ChildAggregatedMemoryContext is already closed")
 	}
 	ct.parentMemoryContext.updateBytes(allocationTag, delta)
 	ct.addBytes(delta)
-
-	ct.lock.Unlock()
 }
 
 // @Override
@@ -66,6 +65,7 @@ func (at *ChildAggregatedMemoryContext) GetBytes() int64 {
 // @Override synchronized
 func (at *ChildAggregatedMemoryContext) Close() {
 	at.lock.Lock()
+	defer at.lock.Unlock()
 
 	if at.closed {
 		return
@@ -73,8 +73,6 @@ func (at *ChildAggregatedMemoryContext) Close() {
 	at.closed = true
 	at.closeContext()
 	at.usedBytes = 0
-
-	at.lock.Unlock()
 }
 
 func (at *ChildAggregatedMemoryContext) isClosed() bool {
@@ -87,9 +85,7 @@ func (at *ChildAggregatedMemoryContext) addBytes(bytes int64) {
 
 // @Override
 func (at *ChildAggregatedMemoryContext) NewAggregatedMemoryContext() AggregatedMemoryContext {
-	ct := new(ChildAggregatedMemoryContext)
-	ct.parentMemoryContext = at
-	return ct
+	return NewChildAggregatedMemoryContext(at)
 }
 
 // @Override
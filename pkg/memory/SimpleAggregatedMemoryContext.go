@@ -19,6 +19,9 @@ func NewSimpleAggregatedMemoryContext() *SimpleAggregatedMemoryContext {
 
 // @Override
 func (st *SimpleAggregatedMemoryContext) updateBytes(allocationTag string, delta int64) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
 	if st.isClosed() {
 		panic("SimpleAggregatedMemoryContext is already closed")
 	}
@@ -27,6 +30,9 @@ func (st *SimpleAggregatedMemoryContext) updateBytes(allocationTag string, delta
 
 // @Override
 func (st *SimpleAggregatedMemoryContext) tryUpdateBytes(allocationTag string, delta int64) bool {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
 	st.addBytes(delta)
 	return true
 }
@@ -42,12 +48,16 @@ func (st *SimpleAggregatedMemoryContext) closeContext() {
 
 // @Override synchronized
 func (at *SimpleAggregatedMemoryContext) GetBytes() int64 {
+	at.lock.Lock()
+	defer at.lock.Unlock()
+
 	return at.usedBytes
 }
 
 // @Override synchronized
 func (at *SimpleAggregatedMemoryContext) Close() {
 	at.lock.Lock()
+	defer at.lock.Unlock()
 
 	if at.closed {
 		return
@@ -55,8 +65,6 @@ func (at *SimpleAggregatedMemoryContext) Close() {
 	at.closed = true
 	at.closeContext()
 	at.usedBytes = 0
-
-	at.lock.Unlock()
 }
 
 func (at *SimpleAggregatedMemoryContext) isClosed() bool {
@@ -69,9 +77,7 @@ func (at *SimpleAggregatedMemoryContext) addBytes(bytes int64) {
 
 // @Override
 func (at *SimpleAggregatedMemoryContext) NewAggregatedMemoryContext() AggregatedMemoryContext {
-	ct := new(SimpleAggregatedMemoryContext)
-	ct.lock = new(sync.Mutex)
-	return ct
+	return NewChildAggregatedMemoryContext(at)
 }
 
 // @Override
@@ -0,0 +1,28 @@
+package mothio
+
+import "fmt"
+
+// IOError wraps a failure of the underlying file or writer. The readers and writers report it by
+// panicking with it, so it can be told apart from corrupt data when the panic is recovered.
+type IOError struct {
+	message string
+	cause   error
+}
+
+func NewIOError(cause error, format string, args ...interface{}) *IOError {
+	ir := new(IOError)
+	ir.message = fmt.Sprintf(format, args...)
+	ir.cause = cause
+	return ir
+}
+
+func (ir *IOError) Error() string {
+	if ir.cause == nil {
+		return ir.message
+	}
+	return ir.message + ": " + ir.cause.Error()
+}
+
+func (ir *IOError) Unwrap() error {
+	return ir.cause
+}
@@ -4,35 +4,29 @@ import (
 	"io"
 
 	"github.com/mothdb-bd/orc-go/pkg/iostream"
-	"github.com/mothdb-bd/orc-go/pkg/maths"
-	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
 type RandomAccessFile struct {
-	input  *iostream.InputStream
-	output *iostream.OutputStream
+	// reads go to the file directly, a buffered reader would not follow the seeks
+	randAcc io.ReadWriteSeeker
+	output  *iostream.OutputStream
 }
 
 func NewRandomAccessFile(randAcc io.ReadWriteSeeker) *RandomAccessFile {
 	return &RandomAccessFile{
-		input:  iostream.NewInputStream(randAcc),
-		output: iostream.NewOutputStream(randAcc),
+		randAcc: randAcc,
+		output:  iostream.NewOutputStream(randAcc),
 	}
 }
 
 func (rl *RandomAccessFile) Seek(offset int64, whence int) (int64, error) {
-	return rl.input.Seek(offset, whence)
+	return rl.randAcc.Seek(offset, whence)
 }
 
+// ReadFully2 reads exactly bLen bytes from the current position into b starting at off.
 func (rl *RandomAccessFile) ReadFully2(b []byte, off int, bLen int) {
-	l := len(b)
-	if off == 0 && l == bLen {
-		rl.input.Read(b)
-	} else {
-		nl := maths.MinInt(l-off, bLen-off)
-		nb := make([]byte, nl)
-		rl.input.Read(nb)
-		util.CopyBytes(nb, 0, b, int32(off), int32(bLen))
+	if _, err := io.ReadFull(rl.randAcc, b[off:off+bLen]); err != nil {
+		panic(NewIOError(err, "Failed to read %d bytes", bLen))
 	}
 }
 
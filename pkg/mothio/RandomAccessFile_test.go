@@ -0,0 +1,47 @@
+package mothio
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRandomAccessFile_ReadFully2(t *testing.T) {
+	data := make([]byte, 20000)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	path := filepath.Join(t.TempDir(), "test.bin")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	randomAccessFile := NewRandomAccessFile(file)
+
+	// seeks backwards and forwards after reads larger than a read buffer
+	for _, read := range []struct{ position, length int }{{15000, 5000}, {5, 10000}, {100, 10}, {19990, 10}} {
+		if _, err := randomAccessFile.Seek(int64(read.position), io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		buffer := make([]byte, read.length+3)
+		randomAccessFile.ReadFully2(buffer, 3, read.length)
+		if !bytes.Equal(buffer[3:], data[read.position:read.position+read.length]) {
+			t.Fatalf("read other bytes at %d", read.position)
+		}
+	}
+
+	// a read past the end of the file fails
+	randomAccessFile.Seek(19995, io.SeekStart)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("read past the end of the file")
+		}
+	}()
+	randomAccessFile.ReadFully2(make([]byte, 10), 0, 10)
+}
@@ -69,6 +69,7 @@ func CheckPositionIndex2(index, size int64, desc string) int64 {
 func (bt *BasicSliceInput) SetPosition(position int64) {
 	CheckPositionIndex(position, bt.Length())
 	bt.position = position
+	bt.slice.readerIndex = int(position)
 }
 
 // @Override
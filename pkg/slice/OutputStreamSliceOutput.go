@@ -50,10 +50,13 @@ func (ot *OutputStreamSliceOutput) Flush() {
 }
 
 // @Override
-func (ot *OutputStreamSliceOutput) Close() error {
-	util.Try(ot.flushBufferToOutputStream, nil, func() {
-		ot.outputStream.Close()
-	})
+func (ot *OutputStreamSliceOutput) Close() (err error) {
+	defer func() {
+		if closeErr := ot.outputStream.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	ot.flushBufferToOutputStream()
 	return nil
 }
 
@@ -335,11 +338,15 @@ func (ot *OutputStreamSliceOutput) flushBufferToOutputStream() {
 }
 
 func (ot *OutputStreamSliceOutput) WriteToOutputStream(source []byte, sourceIndex int32, length int32) {
-	ot.outputStream.WriteBS2(source, sourceIndex, length)
+	if _, err := ot.outputStream.WriteBS2(source, sourceIndex, length); err != nil {
+		panic(mothio.NewIOError(err, "Failed to write %d bytes", length))
+	}
 }
 
 func (ot *OutputStreamSliceOutput) WriteToOutputStream2(source *Slice, sourceIndex int32, length int32) {
 	// b := make([]byte, maths.MinInt32(length, source.SizeInt32()-sourceIndex))
 	// source.GetBytes(b, int(sourceIndex), int(sourceIndex+length))
-	ot.outputStream.WriteBS(source.buf[sourceIndex : sourceIndex+length])
+	if _, err := ot.outputStream.WriteBS(source.buf[sourceIndex : sourceIndex+length]); err != nil {
+		panic(mothio.NewIOError(err, "Failed to write %d bytes", length))
+	}
 }
@@ -8,10 +8,22 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
+type IMapBlock interface {
+	Block // 继承block
+	getRawKeyBlock() Block
+	getRawValueBlock() Block
+	getHashTables() *MapHashTables
+	getOffsets() []int32
+	getOffsetBase() int32
+	getMapIsNull() []bool
+}
+
 type AbstractMapBlock struct {
 	Block // 继承block
 
 	mapType *MapType
+	// the concrete map block, the abstract methods are dispatched to it
+	self IMapBlock
 }
 
 func NewAbstractMapBlock(mapType *MapType) *AbstractMapBlock {
@@ -22,17 +34,17 @@ func NewAbstractMapBlock(mapType *MapType) *AbstractMapBlock {
 
 // protected abstract Block getRawKeyBlock();
 func (b *AbstractMapBlock) getRawKeyBlock() Block {
-	return nil
+	return b.self.getRawKeyBlock()
 }
 
 // protected abstract Block getRawValueBlock();
 func (b *AbstractMapBlock) getRawValueBlock() Block {
-	return nil
+	return b.self.getRawValueBlock()
 }
 
 // protected abstract MapHashTables getHashTables();
 func (b *AbstractMapBlock) getHashTables() *MapHashTables {
-	return nil
+	return b.self.getHashTables()
 }
 
 /**
@@ -42,7 +54,7 @@ func (b *AbstractMapBlock) getHashTables() *MapHashTables {
  */
 // protected abstract int[] getOffsets();
 func (b *AbstractMapBlock) getOffsets() []int32 {
-	return nil
+	return b.self.getOffsets()
 }
 
 /**
@@ -50,13 +62,13 @@ func (b *AbstractMapBlock) getOffsets() []int32 {
  */
 // protected abstract int getOffsetBase();
 func (b *AbstractMapBlock) getOffsetBase() int32 {
-	return 0
+	return b.self.getOffsetBase()
 }
 
 // @Nullable
 // protected abstract boolean[] getMapIsNull();
 func (b *AbstractMapBlock) getMapIsNull() []bool {
-	return nil
+	return b.self.getMapIsNull()
 }
 
 // protected abstract void ensureHashTableLoaded();
@@ -122,14 +134,14 @@ func (ak *AbstractMapBlock) CopyPositions(positions []int32, offset int32, lengt
 
 // @Override
 func (ak *AbstractMapBlock) GetRegion(position int32, length int32) Block {
-	positionCount := ak.GetPositionCount()
+	positionCount := ak.self.GetPositionCount()
 	checkValidRegion(positionCount, position, length)
 	return CreateMapBlockInternal2(ak.mapType, position+ak.getOffsetBase(), length, optional.Of(ak.getMapIsNull()), ak.getOffsets(), ak.getRawKeyBlock(), ak.getRawValueBlock(), ak.getHashTables())
 }
 
 // @Override
 func (ak *AbstractMapBlock) GetRegionSizeInBytes(position int32, length int32) int64 {
-	positionCount := ak.GetPositionCount()
+	positionCount := ak.self.GetPositionCount()
 	checkValidRegion(positionCount, position, length)
 	entriesStart := ak.getOffsets()[ak.getOffsetBase()+position]
 	entriesEnd := ak.getOffsets()[ak.getOffsetBase()+position+length]
@@ -139,7 +151,7 @@ func (ak *AbstractMapBlock) GetRegionSizeInBytes(position int32, length int32) i
 
 // @Override
 func (ak *AbstractMapBlock) GetPositionsSizeInBytes(positions []bool) int64 {
-	positionCount := ak.GetPositionCount()
+	positionCount := ak.self.GetPositionCount()
 	checkValidPositions(positions, positionCount)
 	entryPositions := make([]bool, ak.getRawKeyBlock().GetPositionCount())
 	var usedEntryCount int32 = 0
@@ -161,7 +173,7 @@ func (ak *AbstractMapBlock) GetPositionsSizeInBytes(positions []bool) int64 {
 
 // @Override
 func (ak *AbstractMapBlock) CopyRegion(position int32, length int32) Block {
-	positionCount := ak.GetPositionCount()
+	positionCount := ak.self.GetPositionCount()
 	checkValidRegion(positionCount, position, length)
 	startValueOffset := ak.getOffset(position)
 	endValueOffset := ak.getOffset(position + length)
@@ -180,8 +192,8 @@ func (ak *AbstractMapBlock) CopyRegion(position int32, length int32) Block {
 	if rawHashTables != nil {
 		newRawHashTables = compactInt32Array(rawHashTables, startValueOffset*MHT_HASH_MULTIPLIER, expectedNewHashTableEntries)
 	}
-	if reflect.ValueOf(newKeys).UnsafeAddr() == reflect.ValueOf(ak.getRawKeyBlock()).UnsafeAddr() && reflect.ValueOf(newValues).UnsafeAddr() == reflect.ValueOf(ak.getRawValueBlock()).UnsafeAddr() && reflect.ValueOf(newOffsets).UnsafeAddr() == reflect.ValueOf(ak.getOffsets()).UnsafeAddr() && reflect.ValueOf(newMapIsNull).UnsafeAddr() == reflect.ValueOf(mapIsNull).UnsafeAddr() && reflect.ValueOf(newRawHashTables).UnsafeAddr() == reflect.ValueOf(rawHashTables).UnsafeAddr() {
-		return ak
+	if newKeys == ak.getRawKeyBlock() && newValues == ak.getRawValueBlock() && reflect.DeepEqual(newOffsets, ak.getOffsets()) && reflect.DeepEqual(newMapIsNull, mapIsNull) && reflect.DeepEqual(newRawHashTables, rawHashTables) {
+		return ak.self
 	}
 	return CreateMapBlockInternal2(ak.mapType, 0, length, optional.Of(newMapIsNull), newOffsets, newKeys, newValues, NewMapHashTables(ak.mapType, optional.Of(newRawHashTables)))
 }
@@ -239,7 +251,7 @@ func (ak *AbstractMapBlock) IsNull(position int32) bool {
 }
 
 func (ak *AbstractMapBlock) checkReadablePosition(position int32) {
-	if position < 0 || position >= ak.GetPositionCount() {
+	if position < 0 || position >= ak.self.GetPositionCount() {
 		panic("position is not valid")
 	}
 }
@@ -0,0 +1,27 @@
+package block
+
+import (
+	"testing"
+)
+
+func TestAbstractMapBlock_Region(t *testing.T) {
+	mapBlockBuilder := NewMapBlockBuilder(NewMapType(BIGINT, BIGINT), nil, 2)
+	for i := int64(0); i < 4; i++ {
+		entry := mapBlockBuilder.BeginBlockEntry()
+		for key := int64(0); key <= i; key++ {
+			BIGINT.WriteLong(entry, key)
+			BIGINT.WriteLong(entry, i*10+key)
+		}
+		mapBlockBuilder.CloseEntry()
+	}
+	b := mapBlockBuilder.Build()
+	for _, region := range []Block{b.GetRegion(1, 2), b.CopyRegion(1, 2)} {
+		columnarMap := ToColumnarMap(region)
+		if columnarMap.GetPositionCount() != 2 || columnarMap.GetEntryCount(0) != 2 || columnarMap.GetEntryCount(1) != 3 {
+			t.Fatalf("read a region of %d maps", columnarMap.GetPositionCount())
+		}
+		if value := BIGINT.GetLong(columnarMap.GetValuesBlock(), columnarMap.GetOffset(1)+2); value != 22 {
+			t.Fatalf("read value %d of a region of maps", value)
+		}
+	}
+}
@@ -7,30 +7,41 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
+type IRowBlock interface {
+	Block // 继承block
+	getRawFieldBlocks() []Block
+	getFieldBlockOffsets() []int32
+	getOffsetBase() int32
+	getRowIsNull() []bool
+	getFieldBlockOffset(position int32) int32
+}
+
 type AbstractRowBlock struct {
 	Block // 继承block
 
 	numFields int32
+	// the concrete row block, the abstract methods are dispatched to it
+	self IRowBlock
 }
 
 // abstract
 func (r *AbstractRowBlock) getRawFieldBlocks() []Block {
-	return nil
+	return r.self.getRawFieldBlocks()
 }
 
 func (r *AbstractRowBlock) getFieldBlockOffsets() []int32 {
-	return nil
+	return r.self.getFieldBlockOffsets()
 }
 
 func (r *AbstractRowBlock) getOffsetBase() int32 {
-	return 0
+	return r.self.getOffsetBase()
 }
 
 /**
  * @return the underlying rowIsNull array, or null when all rows are guaranteed to be non-null
  */
 func (r *AbstractRowBlock) getRowIsNull() []bool {
-	return nil
+	return r.self.getRowIsNull()
 }
 
 // @Override
@@ -40,7 +51,10 @@ func (ak *AbstractRowBlock) GetChildren() *util.ArrayList[Block] {
 
 func (ak *AbstractRowBlock) getFieldBlockOffset(position int32) int32 {
 	offsets := ak.getFieldBlockOffsets()
-	return util.If(offsets != nil, offsets[position+ak.getOffsetBase()], position+ak.getOffsetBase()).(int32)
+	if offsets != nil {
+		return offsets[position+ak.getOffsetBase()]
+	}
+	return position + ak.getOffsetBase()
 }
 func NewAbstractRowBlock(numFields int32) *AbstractRowBlock {
 	ak := new(AbstractRowBlock)
@@ -98,14 +112,14 @@ func (ak *AbstractRowBlock) CopyPositions(positions []int32, offset int32, lengt
 
 // @Override
 func (ak *AbstractRowBlock) GetRegion(position int32, length int32) Block {
-	positionCount := ak.GetPositionCount()
+	positionCount := ak.self.GetPositionCount()
 	checkValidRegion(positionCount, position, length)
 	return CreateRowBlockInternal(position+ak.getOffsetBase(), length, ak.getRowIsNull(), ak.getFieldBlockOffsets(), ak.getRawFieldBlocks())
 }
 
 // @Override
 func (ak *AbstractRowBlock) GetRegionSizeInBytes(position int32, length int32) int64 {
-	positionCount := ak.GetPositionCount()
+	positionCount := ak.self.GetPositionCount()
 	checkValidRegion(positionCount, position, length)
 	startFieldBlockOffset := ak.getFieldBlockOffset(position)
 	endFieldBlockOffset := ak.getFieldBlockOffset(position + length)
@@ -120,7 +134,7 @@ func (ak *AbstractRowBlock) GetRegionSizeInBytes(position int32, length int32) i
 
 // @Override
 func (ak *AbstractRowBlock) GetPositionsSizeInBytes(positions []bool) int64 {
-	checkValidPositions(positions, ak.GetPositionCount())
+	checkValidPositions(positions, ak.self.GetPositionCount())
 	var usedPositionCount int32 = 0
 	fieldPositions := make([]bool, ak.getRawFieldBlocks()[0].GetPositionCount())
 	for i := 0; i < len(positions); i++ {
@@ -143,7 +157,7 @@ func (ak *AbstractRowBlock) GetPositionsSizeInBytes(positions []bool) int64 {
 
 // @Override
 func (ak *AbstractRowBlock) CopyRegion(position int32, length int32) Block {
-	positionCount := ak.GetPositionCount()
+	positionCount := ak.self.GetPositionCount()
 	checkValidRegion(positionCount, position, length)
 	startFieldBlockOffset := ak.getFieldBlockOffset(position)
 	endFieldBlockOffset := ak.getFieldBlockOffset(position + length)
@@ -154,12 +168,18 @@ func (ak *AbstractRowBlock) CopyRegion(position int32, length int32) Block {
 		newBlocks[i] = ak.getRawFieldBlocks()[i].CopyRegion(startFieldBlockOffset, fieldBlockLength)
 	}
 	fieldBlockOffsets := ak.getFieldBlockOffsets()
-	newOffsets := util.If(fieldBlockOffsets == nil, nil, compactOffsets(fieldBlockOffsets, position+ak.getOffsetBase(), length)).([]int32)
+	var newOffsets []int32 = nil
+	if fieldBlockOffsets != nil {
+		newOffsets = compactOffsets(fieldBlockOffsets, position+ak.getOffsetBase(), length)
+	}
 	rowIsNull := ak.getRowIsNull()
-	newRowIsNull := util.If(rowIsNull == nil, nil, compactBoolArray(rowIsNull, position+ak.getOffsetBase(), length)).([]bool)
+	var newRowIsNull []bool = nil
+	if rowIsNull != nil {
+		newRowIsNull = compactBoolArray(rowIsNull, position+ak.getOffsetBase(), length)
+	}
 
 	if blockArraySame(newBlocks, ak.getRawFieldBlocks()) && basic.ObjectEqual(newOffsets, fieldBlockOffsets) && basic.ObjectEqual(newRowIsNull, rowIsNull) {
-		return ak
+		return ak.self
 	}
 	return CreateRowBlockInternal(0, length, newRowIsNull, newOffsets, newBlocks)
 }
@@ -212,7 +232,7 @@ func (ak *AbstractRowBlock) IsNull(position int32) bool {
 }
 
 func (ak *AbstractRowBlock) checkReadablePosition(position int32) {
-	if position < 0 || position >= ak.GetPositionCount() {
+	if position < 0 || position >= ak.self.GetPositionCount() {
 		panic("position is not valid")
 	}
 }
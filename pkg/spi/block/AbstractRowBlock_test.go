@@ -0,0 +1,52 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/optional"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func TestAbstractRowBlock_Region(t *testing.T) {
+	rowBlockBuilder := NewRowBlockBuilder(util.NewArrayList[Type](BIGINT, BIGINT), nil, 2)
+	for i := int64(0); i < 4; i++ {
+		entry := rowBlockBuilder.BeginBlockEntry()
+		BIGINT.WriteLong(entry, i)
+		BIGINT.WriteLong(entry, i*10)
+		rowBlockBuilder.CloseEntry()
+	}
+	b := rowBlockBuilder.Build()
+	for _, region := range []Block{b.GetRegion(1, 2), b.CopyRegion(1, 2)} {
+		row := ToColumnarRow(region)
+		if row.GetPositionCount() != 2 || BIGINT.GetLong(row.GetField(0), 1) != 2 || BIGINT.GetLong(row.GetField(1), 0) != 10 {
+			t.Fatalf("read a region of %d rows", row.GetPositionCount())
+		}
+	}
+	if b.GetRegionSizeInBytes(1, 2) == 0 || b.IsNull(3) {
+		t.Fatal("read the size and nulls of a row block")
+	}
+}
+
+func TestRowBlock_FieldBlocks(t *testing.T) {
+	// rows 1 and 3 are null, the field blocks hold the values of the other rows
+	rowIsNull := []bool{false, true, false, true, false}
+	values := BIGINT.CreateBlockBuilder2(nil, 3)
+	for _, value := range []int64{10, 20, 40} {
+		BIGINT.WriteLong(values, value)
+	}
+	b := FromFieldBlocks(5, optional.Of(rowIsNull), []Block{values.Build()})
+	for position, isNull := range rowIsNull {
+		if b.IsNull(int32(position)) != isNull {
+			t.Fatalf("read null %v at %d", b.IsNull(int32(position)), position)
+		}
+	}
+
+	region := b.GetRegion(2, 3)
+	if region.GetPositionCount() != 3 || region.IsNull(0) || !region.IsNull(1) || region.IsNull(2) {
+		t.Fatalf("read the nulls of a region of %d rows", region.GetPositionCount())
+	}
+	row := ToColumnarRow(region)
+	if row.GetField(0).GetPositionCount() != 2 || BIGINT.GetLong(row.GetField(0), 0) != 20 || BIGINT.GetLong(row.GetField(0), 1) != 40 {
+		t.Fatalf("read %d values of a region of rows", row.GetField(0).GetPositionCount())
+	}
+}
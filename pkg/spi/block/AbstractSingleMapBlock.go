@@ -8,23 +8,32 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
+type ISingleMapBlock interface {
+	Block // 继承block
+	getOffset() int32
+	getRawKeyBlock() Block
+	getRawValueBlock() Block
+}
+
 type AbstractSingleMapBlock struct {
 	Block // 继承block
 
+	// the concrete single map block, the abstract methods are dispatched to it
+	self ISingleMapBlock
 }
 
 // abstract
 func (ak *AbstractSingleMapBlock) getOffset() int32 {
-	return 0
+	return ak.self.getOffset()
 }
 
 // abstract
 func (ak *AbstractSingleMapBlock) getRawKeyBlock() Block {
-	return nil
+	return ak.self.getRawKeyBlock()
 }
 
 func (ak *AbstractSingleMapBlock) getRawValueBlock() Block {
-	return nil
+	return ak.self.getRawValueBlock()
 }
 
 // @Override
@@ -33,7 +42,7 @@ func (ak *AbstractSingleMapBlock) GetChildren() *util.ArrayList[Block] {
 }
 
 func (ak *AbstractSingleMapBlock) getAbsolutePosition(position int32) int32 {
-	if position < 0 || position >= ak.GetPositionCount() {
+	if position < 0 || position >= ak.self.GetPositionCount() {
 		panic("position is not valid")
 	}
 	return position + ak.getOffset()
@@ -0,0 +1,28 @@
+package block
+
+import (
+	"testing"
+)
+
+func TestAbstractSingleMapBlock_GetObject(t *testing.T) {
+	mapBlockBuilder := NewMapBlockBuilder(NewMapType(BIGINT, BIGINT), nil, 2)
+	for i := int64(0); i < 3; i++ {
+		entry := mapBlockBuilder.BeginBlockEntry()
+		for key := int64(0); key <= i; key++ {
+			BIGINT.WriteLong(entry, key)
+			BIGINT.WriteLong(entry, i*10+key)
+		}
+		mapBlockBuilder.CloseEntry()
+	}
+	b := mapBlockBuilder.Build()
+	// the keys and values of a map alternate in its single map block
+	singleMap := b.GetObject(2, BLOCK_TYPE).(Block)
+	if singleMap.GetPositionCount() != 6 {
+		t.Fatalf("read a map of %d keys and values, want 6", singleMap.GetPositionCount())
+	}
+	for key := int32(0); key < 3; key++ {
+		if BIGINT.GetLong(singleMap, 2*key) != int64(key) || BIGINT.GetLong(singleMap, 2*key+1) != int64(20+key) || singleMap.IsNull(2*key+1) {
+			t.Fatalf("read entry %d of a single map block", key)
+		}
+	}
+}
@@ -9,24 +9,33 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
+type ISingleRowBlock interface {
+	Block // 继承block
+	getRawFieldBlocks() []Block
+	getRawFieldBlock(fieldIndex int32) Block
+	getRowIndex() int32
+}
+
 type AbstractSingleRowBlock struct {
 	Block // 继承block
 
+	// the concrete single row block, the abstract methods are dispatched to it
+	self ISingleRowBlock
 }
 
 // abstract
 func (ak *AbstractSingleRowBlock) getRawFieldBlocks() []Block {
-	return nil
+	return ak.self.getRawFieldBlocks()
 }
 
 // abstract Block getRawFieldBlock(int fieldIndex);
 func (ak *AbstractSingleRowBlock) getRawFieldBlock(fieldIndex int32) Block {
-	return nil
+	return ak.self.getRawFieldBlock(fieldIndex)
 }
 
 // abstract int getRowIndex();
 func (ak *AbstractSingleRowBlock) getRowIndex() int32 {
-	return 0
+	return ak.self.getRowIndex()
 }
 
 // @Override
@@ -35,7 +44,7 @@ func (ak *AbstractSingleRowBlock) GetChildren() *util.ArrayList[Block] {
 }
 
 func (ak *AbstractSingleRowBlock) checkFieldIndex(position int32) {
-	if position < 0 || position >= ak.GetPositionCount() {
+	if position < 0 || position >= ak.self.GetPositionCount() {
 		panic(fmt.Sprintf("position is not valid: %d", position))
 	}
 }
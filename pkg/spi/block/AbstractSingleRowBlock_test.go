@@ -0,0 +1,25 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func TestAbstractSingleRowBlock_GetObject(t *testing.T) {
+	rowBlockBuilder := NewRowBlockBuilder(util.NewArrayList[Type](BIGINT, BIGINT), nil, 2)
+	for i := int64(0); i < 4; i++ {
+		entry := rowBlockBuilder.BeginBlockEntry()
+		BIGINT.WriteLong(entry, i)
+		BIGINT.WriteLong(entry, i*10)
+		rowBlockBuilder.CloseEntry()
+	}
+	b := rowBlockBuilder.Build()
+	// the fields of a row are the positions of its single row block
+	for _, region := range []Block{b, b.GetRegion(1, 3)} {
+		singleRow := region.GetObject(region.GetPositionCount()-1, BLOCK_TYPE).(Block)
+		if singleRow.GetPositionCount() != 2 || BIGINT.GetLong(singleRow, 0) != 3 || BIGINT.GetLong(singleRow, 1) != 30 || singleRow.IsNull(1) {
+			t.Fatalf("read a single row block of %d fields", singleRow.GetPositionCount())
+		}
+	}
+}
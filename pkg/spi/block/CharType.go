@@ -129,7 +129,7 @@ func (te *CharType) GetTypeParameters() *util.ArrayList[Type] {
 
 // @Override
 func (te *CharType) CreateBlockBuilder(blockBuilderStatus *BlockBuilderStatus, expectedEntries int32, expectedBytesPerEntry int32) BlockBuilder {
-	return te.AbstractType.CreateBlockBuilder(blockBuilderStatus, expectedEntries, expectedBytesPerEntry)
+	return te.AbstractVariableWidthType.CreateBlockBuilder(blockBuilderStatus, expectedEntries, expectedBytesPerEntry)
 }
 
 // @Override
@@ -23,15 +23,14 @@ func ToColumnarMap(block Block) *ColumnarMap {
 	if flag {
 		return toColumnarMap2(rb)
 	}
-	ab, flag := block.(*AbstractMapBlock)
+	mapBlock, flag := block.(IMapBlock)
 	if !flag {
-		panic("Invalid array block: " + reflect.TypeOf(block).String())
+		panic("Invalid map block: " + reflect.TypeOf(block).String())
 	}
-	mapBlock := ab
 	offsetBase := mapBlock.getOffsetBase()
 	offsets := mapBlock.getOffsets()
-	firstEntryPosition := mapBlock.getOffset(0)
-	totalEntryCount := mapBlock.getOffset(block.GetPositionCount()) - firstEntryPosition
+	firstEntryPosition := offsets[offsetBase]
+	totalEntryCount := offsets[offsetBase+block.GetPositionCount()] - firstEntryPosition
 	keysBlock := mapBlock.getRawKeyBlock().GetRegion(firstEntryPosition, totalEntryCount)
 	valuesBlock := mapBlock.getRawValueBlock().GetRegion(firstEntryPosition, totalEntryCount)
 	return NewColumnarMap(block, offsetBase, offsets, keysBlock, valuesBlock)
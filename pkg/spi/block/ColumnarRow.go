@@ -23,14 +23,14 @@ func ToColumnarRow(block Block) *ColumnarRow {
 	if flag {
 		return toColumnarRow2(rb)
 	}
-	ab, flag := block.(*AbstractRowBlock)
+	ab, flag := block.(IRowBlock)
 	if !flag {
 		panic("Invalid row block: " + reflect.TypeOf(block).String())
 	}
 	rowBlock := ab
 	firstRowPosition := rowBlock.getFieldBlockOffset(0)
 	totalRowCount := rowBlock.getFieldBlockOffset(block.GetPositionCount()) - firstRowPosition
-	fieldBlocks := make([]Block, rowBlock.numFields)
+	fieldBlocks := make([]Block, len(rowBlock.getRawFieldBlocks()))
 	for i := 0; i < len(fieldBlocks); i++ {
 		fieldBlocks[i] = rowBlock.getRawFieldBlocks()[i].GetRegion(firstRowPosition, totalRowCount)
 	}
@@ -18,6 +18,8 @@ type IDecimalType interface {
 
 	IsShort() bool
 
+	GetPrecision() int32
+
 	GetScale() int32
 }
 
@@ -57,6 +57,7 @@ func validateConstructorArguments(mapType *MapType, startOffset int32, positionC
 func NewMapBlock(mapType *MapType, startOffset int32, positionCount int32, mapIsNull []bool, offsets []int32, keyBlock Block, valueBlock Block, hashTables *MapHashTables) *MapBlock {
 	mk := new(MapBlock)
 	mk.mapType = mapType
+	mk.self = mk
 	rawHashTables := hashTables.tryGet().OrElse(nil)
 	if rawHashTables != nil && util.Int32sLenInt32(rawHashTables) < keyBlock.GetPositionCount()*MHT_HASH_MULTIPLIER {
 		panic(fmt.Sprintf("keyBlock/valueBlock size does not match hash table size: %d %d", keyBlock.GetPositionCount(), util.Int32sLenInt32(rawHashTables)))
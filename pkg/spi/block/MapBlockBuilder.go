@@ -41,6 +41,7 @@ func NewMapBlockBuilder2(mapType *MapType, blockBuilderStatus *BlockBuilderStatu
 	mr := new(MapBlockBuilder)
 	// NewMapBlockBuilder(mapType)
 	mr.mapType = mapType
+	mr.self = mr
 
 	mr.blockBuilderStatus = blockBuilderStatus
 	mr.positionCount = 0
@@ -177,5 +177,5 @@ func (ms *MapHashTables) getHashPosition(keyBlock Block, position int32, hashTab
 }
 
 func computePosition(he int64, hashTableSize int32) int32 {
-	return int32(uint64(hashcode.Int64HashCode(he)*hashTableSize) >> 32)
+	return int32((uint64(uint32(hashcode.Int64HashCode(he))) * uint64(hashTableSize)) >> 32)
 }
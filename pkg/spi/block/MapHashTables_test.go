@@ -0,0 +1,37 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/optional"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+)
+
+func TestComputePosition(t *testing.T) {
+	for _, hash := range []int64{0, 1, -1, 42, -42, 1 << 31, -1 << 31, 1<<63 - 1, -1 << 63} {
+		for _, hashTableSize := range []int32{1, 2, 6, 1000} {
+			if position := computePosition(hash, hashTableSize); position < 0 || position >= hashTableSize {
+				t.Fatalf("computed position %d of hash %d in a table of %d", position, hash, hashTableSize)
+			}
+		}
+	}
+}
+
+func TestMapHashTables_VarcharKeys(t *testing.T) {
+	keys := []string{"a", "bb", "ccc", "a-much-longer-key"}
+	data := slice.NewWithSize(0)
+	offsets := []int32{0}
+	for _, key := range keys {
+		data.WriteBytes([]byte(key))
+		offsets = append(offsets, int32(data.Size()))
+	}
+	keyBlock := NewVariableWidthBlock(int32(len(keys)), data, offsets, optional.Empty[[]bool]())
+
+	hashTableSize := int32(len(keys)) * MHT_HASH_MULTIPLIER
+	hashTables := NewMapHashTables(NewMapType(VARCHAR, BIGINT), optional.Empty[[]int32]())
+	for position := range keys {
+		if hash := hashTables.getHashPosition(keyBlock, int32(position), hashTableSize); hash < 0 || hash >= hashTableSize {
+			t.Fatalf("computed position %d of key %s in a table of %d", hash, keys[position], hashTableSize)
+		}
+	}
+}
@@ -78,6 +78,7 @@ func rowValidateConstructorArguments(startOffset int32, positionCount int32, row
 func NewRowBlock(startOffset int32, positionCount int32, rowIsNull []bool, fieldBlockOffsets []int32, fieldBlocks []Block) *RowBlock {
 	rk := new(RowBlock)
 	rk.numFields = int32(len(fieldBlocks))
+	rk.self = rk
 	rk.startOffset = startOffset
 	rk.positionCount = positionCount
 	rk.rowIsNull = rowIsNull
@@ -39,6 +39,7 @@ func NewRowBlockBuilder2(blockBuilderStatus *BlockBuilderStatus, fieldBlockBuild
 		panic("Number of fields in RowBlock must be positive")
 	}
 	rr.numFields = numFields
+	rr.self = rr
 	rr.blockBuilderStatus = blockBuilderStatus
 	rr.positionCount = 0
 	rr.fieldBlockOffsets = fieldBlockOffsets
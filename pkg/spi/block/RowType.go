@@ -18,6 +18,8 @@ type RowType struct {
 	fieldTypes *util.ArrayList[Type]
 	comparable bool
 	orderable  bool
+	// the row is the block layout of a union column, see NewUnionRowType
+	union bool
 }
 
 func NewRowType(typeSignature *TypeSignature, fields *util.ArrayList[*Field]) *RowType {
@@ -62,6 +64,24 @@ func From(fields *util.ArrayList[*Field]) *RowType {
 	return NewRowType(makeSignature(fields), fields)
 }
 
+/**
+ * Creates the row type of a union column, whose first field is the tag of the variant held by
+ * a row, followed by one field per variant. Only a type created by NewUnionRowType is a union, a
+ * row type of the same fields is not.
+ */
+func NewUnionRowType(fields *util.ArrayList[*Field]) *RowType {
+	re := From(fields)
+	re.union = true
+	return re
+}
+
+/**
+ * Returns true if the row type was created by NewUnionRowType.
+ */
+func (re *RowType) IsUnion() bool {
+	return re.union
+}
+
 func Anonymous(types *util.ArrayList[Type]) *RowType {
 	// fields := types.stream().map(func(type interface{}) {
 	// 	NewField(Optional.empty(), type)
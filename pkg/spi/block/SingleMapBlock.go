@@ -28,6 +28,7 @@ func NewSingleMapBlock(offset int32, positionCount int32, mapBlock *AbstractMapB
 	sk.offset = offset
 	sk.positionCount = positionCount
 	sk.mapBlock = mapBlock
+	sk.self = sk
 	return sk
 }
 
@@ -47,7 +48,7 @@ func (sk *SingleMapBlock) GetSizeInBytes() int64 {
 
 // @Override
 func (sk *SingleMapBlock) GetRetainedSizeInBytes() int64 {
-	return int64(SINGLE_MAP_BLOCK_INSTANCE_SIZE) + sk.mapBlock.GetRetainedSizeInBytes()
+	return int64(SINGLE_MAP_BLOCK_INSTANCE_SIZE) + sk.mapBlock.self.GetRetainedSizeInBytes()
 }
 
 // @Override
@@ -55,6 +56,11 @@ func (sk *SingleMapBlock) GetOffset() int32 {
 	return sk.offset
 }
 
+// @Override
+func (sk *SingleMapBlock) getOffset() int32 {
+	return sk.offset
+}
+
 // @Override
 func (sk *SingleMapBlock) getRawKeyBlock() Block {
 	return sk.mapBlock.getRawKeyBlock()
@@ -34,6 +34,7 @@ func NewSingleMapBlockWriter(start int32, keyBlockBuilder BlockBuilder, valueBlo
 	sr.keyBlockBuilder = keyBlockBuilder
 	sr.valueBlockBuilder = valueBlockBuilder
 	sr.setStrict = setStrict
+	sr.self = sr
 	sr.initialBlockBuilderSize = keyBlockBuilder.GetSizeInBytes() + valueBlockBuilder.GetSizeInBytes()
 	return sr
 }
@@ -21,6 +21,7 @@ func NewSingleRowBlock(rowIndex int32, fieldBlocks []Block) *SingleRowBlock {
 	sk := new(SingleRowBlock)
 	sk.rowIndex = rowIndex
 	sk.fieldBlocks = fieldBlocks
+	sk.self = sk
 	return sk
 }
 
@@ -62,6 +63,11 @@ func (sk *SingleRowBlock) GetRowIndex() int32 {
 	return sk.rowIndex
 }
 
+// @Override
+func (sk *SingleRowBlock) getRowIndex() int32 {
+	return sk.rowIndex
+}
+
 // @Override
 func (sk *SingleRowBlock) ToString() string {
 	return fmt.Sprintf("SingleRowBlock{numFields=%d}", len(sk.fieldBlocks))
@@ -25,6 +25,8 @@ type SingleRowBlockWriter struct {
 func NewSingleRowBlockWriter(fieldBlockBuilders []BlockBuilder) *SingleRowBlockWriter {
 	sr := new(SingleRowBlockWriter)
 	sr.fieldBlockBuilders = fieldBlockBuilders
+	sr.rowIndex = -1
+	sr.self = sr
 	return sr
 }
 
@@ -91,7 +91,7 @@ func (te *VarbinaryType) GetTypeParameters() *util.ArrayList[Type] {
 
 // @Override
 func (te *VarbinaryType) CreateBlockBuilder(blockBuilderStatus *BlockBuilderStatus, expectedEntries int32, expectedBytesPerEntry int32) BlockBuilder {
-	return te.AbstractType.CreateBlockBuilder(blockBuilderStatus, expectedEntries, expectedBytesPerEntry)
+	return te.AbstractVariableWidthType.CreateBlockBuilder(blockBuilderStatus, expectedEntries, expectedBytesPerEntry)
 }
 
 // @Override
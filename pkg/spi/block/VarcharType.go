@@ -170,7 +170,7 @@ func (te *VarcharType) GetTypeParameters() *util.ArrayList[Type] {
 
 // @Override
 func (te *VarcharType) CreateBlockBuilder(blockBuilderStatus *BlockBuilderStatus, expectedEntries int32, expectedBytesPerEntry int32) BlockBuilder {
-	return te.AbstractType.CreateBlockBuilder(blockBuilderStatus, expectedEntries, expectedBytesPerEntry)
+	return te.AbstractVariableWidthType.CreateBlockBuilder(blockBuilderStatus, expectedEntries, expectedBytesPerEntry)
 }
 
 // @Override
@@ -34,7 +34,7 @@ func ByteCount(slice *slice.Slice, offset int32, length int32, codePointCount in
 	if offset > endIndex {
 		panic("offset cannot be smaller than or equal to endIndex")
 	}
-	return maths.MaxInt32(endIndex-offset, length)
+	return maths.MinInt32(endIndex-offset, length)
 }
 
 var (
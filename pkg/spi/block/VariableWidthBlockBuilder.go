@@ -32,7 +32,7 @@ func NewVariableWidthBlockBuilder(blockBuilderStatus *BlockBuilderStatus, expect
 	vr := new(VariableWidthBlockBuilder)
 	vr.sliceOutput = slice.NewWithSize(0)
 	vr.valueIsNull = make([]bool, 0)
-	vr.offsets = make([]int32, 0)
+	vr.offsets = make([]int32, 1)
 
 	vr.blockBuilderStatus = blockBuilderStatus
 	vr.initialEntryCount = expectedEntries
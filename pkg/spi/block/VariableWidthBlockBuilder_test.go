@@ -0,0 +1,40 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+)
+
+type sliceType interface {
+	Type
+	WriteSlice(blockBuilder BlockBuilder, value *slice.Slice)
+	GetSlice(block Block, position int32) *slice.Slice
+}
+
+func TestVariableWidthBlockBuilder(t *testing.T) {
+	values := []string{"a", "bb", "ccc", "dddd"}
+	for _, kind := range []sliceType{VARCHAR, VARBINARY, CreateCharType(4)} {
+		// a builder without entries builds an empty block
+		if b := kind.CreateBlockBuilder(nil, 2, 4).Build(); b.GetPositionCount() != 0 {
+			t.Fatalf("built an empty %s block of %d positions", kind.GetDisplayName(), b.GetPositionCount())
+		}
+
+		blockBuilder := kind.CreateBlockBuilder(nil, 2, 4)
+		for i, value := range values {
+			if i == 2 {
+				blockBuilder.AppendNull()
+			}
+			kind.WriteSlice(blockBuilder, slice.NewWithString(value))
+		}
+		b := blockBuilder.Build()
+		if b.GetPositionCount() != int32(len(values))+1 || !b.IsNull(2) {
+			t.Fatalf("built a %s block of %d positions", kind.GetDisplayName(), b.GetPositionCount())
+		}
+		for i, position := range []int32{0, 1, 3, 4} {
+			if value := kind.GetSlice(b, position).String(); value != values[i] {
+				t.Fatalf("read %q at %d of a %s block, want %q", value, position, kind.GetDisplayName(), values[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package predicate
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/basic"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Defines the possible values of a single variable in terms of its valid scalar values and nullability.
+ *
+ * For example:
+ *
+ *	Domain.none() => no scalar values allowed, NULL not allowed
+ *	Domain.all() => all scalar values allowed, NULL allowed
+ *	Domain.onlyNull() => no scalar values allowed, NULL allowed
+ *	Domain.notNull() => all scalar values allowed, NULL not allowed
+ */
+type Domain struct {
+	values      *ValueSet
+	nullAllowed bool
+}
+
+func NewDomain(values *ValueSet, nullAllowed bool) *Domain {
+	dn := new(Domain)
+	dn.values = values
+	dn.nullAllowed = nullAllowed
+	return dn
+}
+
+func AllDomain(kind block.Type) *Domain {
+	return NewDomain(AllValueSet(kind), true)
+}
+
+func NoneDomain(kind block.Type) *Domain {
+	return NewDomain(NoneValueSet(kind), false)
+}
+
+func OnlyNullDomain(kind block.Type) *Domain {
+	return NewDomain(NoneValueSet(kind), true)
+}
+
+func NotNullDomain(kind block.Type) *Domain {
+	return NewDomain(AllValueSet(kind), false)
+}
+
+func SingleValueDomain(kind block.Type, value basic.Object) *Domain {
+	return NewDomain(ValueSetOf(kind, value), false)
+}
+
+func MultipleValuesDomain(kind block.Type, values ...basic.Object) *Domain {
+	if len(values) == 0 {
+		panic("values cannot be empty")
+	}
+	return NewDomain(ValueSetOf(kind, values...), false)
+}
+
+func (dn *Domain) GetType() block.Type {
+	return dn.values.GetType()
+}
+
+func (dn *Domain) GetValues() *ValueSet {
+	return dn.values
+}
+
+func (dn *Domain) IsNullAllowed() bool {
+	return dn.nullAllowed
+}
+
+func (dn *Domain) IsNone() bool {
+	return dn.values.IsNone() && !dn.nullAllowed
+}
+
+func (dn *Domain) IsAll() bool {
+	return dn.values.IsAll() && dn.nullAllowed
+}
+
+func (dn *Domain) IsOnlyNull() bool {
+	return dn.values.IsNone() && dn.nullAllowed
+}
+
+func (dn *Domain) IsSingleValue() bool {
+	return !dn.nullAllowed && dn.values.IsSingleValue()
+}
+
+func (dn *Domain) GetSingleValue() basic.Object {
+	if !dn.IsSingleValue() {
+		panic("Domain is not a single value")
+	}
+	return dn.values.GetSingleValue()
+}
+
+/**
+ * Returns the discrete values of the domain if the non-null part of the domain
+ * only consists of discrete values.
+ */
+func (dn *Domain) GetDiscreteValues() *util.ArrayList[basic.Object] {
+	return dn.values.GetDiscreteValues()
+}
+
+/**
+ * Tests a value against the domain, a nil value stands for NULL.
+ */
+func (dn *Domain) IncludesNullableValue(value basic.Object) bool {
+	if value == nil {
+		return dn.nullAllowed
+	}
+	return dn.values.ContainsValue(value)
+}
+
+func (dn *Domain) Overlaps(other *Domain) bool {
+	return (dn.nullAllowed && other.nullAllowed) || dn.values.Overlaps(other.values)
+}
+
+func (dn *Domain) Contains(other *Domain) bool {
+	return (dn.nullAllowed || !other.nullAllowed) && dn.values.Contains(other.values)
+}
+
+func (dn *Domain) Intersect(other *Domain) *Domain {
+	return NewDomain(dn.values.Intersect(other.values), dn.nullAllowed && other.nullAllowed)
+}
+
+func (dn *Domain) Union(other *Domain) *Domain {
+	return NewDomain(dn.values.Union(other.values), dn.nullAllowed || other.nullAllowed)
+}
+
+func (dn *Domain) Complement() *Domain {
+	return NewDomain(dn.values.Complement(), !dn.nullAllowed)
+}
+
+// @Override
+func (dn *Domain) String() string {
+	if dn.IsNone() {
+		return "NONE"
+	}
+	if dn.IsAll() {
+		return "ALL"
+	}
+	if dn.IsOnlyNull() {
+		return "[NULL]"
+	}
+	if dn.nullAllowed {
+		return "[NULL, " + dn.values.String() + "]"
+	}
+	return "[" + dn.values.String() + "]"
+}
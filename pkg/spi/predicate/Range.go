@@ -0,0 +1,255 @@
+package predicate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mothdb-bd/orc-go/pkg/basic"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+)
+
+/**
+ * A Range of values across the continuous space defined by the types of the values.
+ * An unbounded end of the range is represented by a nil value.
+ */
+type Range struct {
+	kind          block.Type
+	low           basic.Object
+	lowInclusive  bool
+	high          basic.Object
+	highInclusive bool
+}
+
+func NewRange(kind block.Type, low basic.Object, lowInclusive bool, high basic.Object, highInclusive bool) *Range {
+	if low != nil {
+		checkValue(low)
+	}
+	if high != nil {
+		checkValue(high)
+	}
+	if low != nil && high != nil {
+		cmp := CompareValues(low, high)
+		if cmp > 0 {
+			panic(fmt.Sprintf("low must be less than or equal to high: %s, %s", valueString(low), valueString(high)))
+		}
+		if cmp == 0 && !(lowInclusive && highInclusive) {
+			panic(fmt.Sprintf("invalid bounds for single value range: %s", valueString(low)))
+		}
+	}
+	re := new(Range)
+	re.kind = kind
+	re.low = low
+	re.lowInclusive = lowInclusive && low != nil
+	re.high = high
+	re.highInclusive = highInclusive && high != nil
+	return re
+}
+
+func AllRange(kind block.Type) *Range {
+	return NewRange(kind, nil, false, nil, false)
+}
+
+func GreaterThanRange(kind block.Type, low basic.Object) *Range {
+	return NewRange(kind, low, false, nil, false)
+}
+
+func GreaterThanOrEqualRange(kind block.Type, low basic.Object) *Range {
+	return NewRange(kind, low, true, nil, false)
+}
+
+func LessThanRange(kind block.Type, high basic.Object) *Range {
+	return NewRange(kind, nil, false, high, false)
+}
+
+func LessThanOrEqualRange(kind block.Type, high basic.Object) *Range {
+	return NewRange(kind, nil, false, high, true)
+}
+
+func EqualRange(kind block.Type, value basic.Object) *Range {
+	return NewRange(kind, value, true, value, true)
+}
+
+func (re *Range) GetType() block.Type {
+	return re.kind
+}
+
+func (re *Range) IsLowUnbounded() bool {
+	return re.low == nil
+}
+
+func (re *Range) IsLowInclusive() bool {
+	return re.lowInclusive
+}
+
+func (re *Range) GetLowValue() basic.Object {
+	return re.low
+}
+
+func (re *Range) IsHighUnbounded() bool {
+	return re.high == nil
+}
+
+func (re *Range) IsHighInclusive() bool {
+	return re.highInclusive
+}
+
+func (re *Range) GetHighValue() basic.Object {
+	return re.high
+}
+
+func (re *Range) IsSingleValue() bool {
+	return re.lowInclusive && re.highInclusive && CompareValues(re.low, re.high) == 0
+}
+
+func (re *Range) GetSingleValue() basic.Object {
+	if !re.IsSingleValue() {
+		panic("Range does not have just a single value")
+	}
+	return re.low
+}
+
+func (re *Range) IsAll() bool {
+	return re.low == nil && re.high == nil
+}
+
+func (re *Range) Contains(value basic.Object) bool {
+	if re.low != nil {
+		cmp := CompareValues(re.low, value)
+		if cmp > 0 || (cmp == 0 && !re.lowInclusive) {
+			return false
+		}
+	}
+	if re.high != nil {
+		cmp := CompareValues(value, re.high)
+		if cmp > 0 || (cmp == 0 && !re.highInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+func (re *Range) Overlaps(other *Range) bool {
+	return !re.endsBefore(other) && !other.endsBefore(re)
+}
+
+// endsBefore returns true if every value of this range is lower than every value of other.
+func (re *Range) endsBefore(other *Range) bool {
+	if re.high == nil || other.low == nil {
+		return false
+	}
+	cmp := CompareValues(re.high, other.low)
+	return cmp < 0 || (cmp == 0 && !(re.highInclusive && other.lowInclusive))
+}
+
+// adjacentTo returns true if this range ends exactly where other begins and there is no gap in between.
+func (re *Range) adjacentTo(other *Range) bool {
+	if re.high == nil || other.low == nil {
+		return false
+	}
+	return CompareValues(re.high, other.low) == 0 && (re.highInclusive || other.lowInclusive)
+}
+
+func (re *Range) Intersect(other *Range) *Range {
+	if !re.Overlaps(other) {
+		return nil
+	}
+	low, lowInclusive := re.low, re.lowInclusive
+	if compareLow(other, re) > 0 {
+		low, lowInclusive = other.low, other.lowInclusive
+	}
+	high, highInclusive := re.high, re.highInclusive
+	if compareHigh(other, re) < 0 {
+		high, highInclusive = other.high, other.highInclusive
+	}
+	return NewRange(re.kind, low, lowInclusive, high, highInclusive)
+}
+
+func (re *Range) Span(other *Range) *Range {
+	low, lowInclusive := re.low, re.lowInclusive
+	if compareLow(other, re) < 0 {
+		low, lowInclusive = other.low, other.lowInclusive
+	}
+	high, highInclusive := re.high, re.highInclusive
+	if compareHigh(other, re) > 0 {
+		high, highInclusive = other.high, other.highInclusive
+	}
+	return NewRange(re.kind, low, lowInclusive, high, highInclusive)
+}
+
+// compareLow orders the lower bounds of two ranges, an unbounded low being the lowest.
+func compareLow(a *Range, b *Range) int {
+	if a.low == nil || b.low == nil {
+		if a.low == nil && b.low == nil {
+			return 0
+		}
+		if a.low == nil {
+			return -1
+		}
+		return 1
+	}
+	cmp := CompareValues(a.low, b.low)
+	if cmp != 0 {
+		return cmp
+	}
+	if a.lowInclusive == b.lowInclusive {
+		return 0
+	}
+	if a.lowInclusive {
+		return -1
+	}
+	return 1
+}
+
+// compareHigh orders the upper bounds of two ranges, an unbounded high being the highest.
+func compareHigh(a *Range, b *Range) int {
+	if a.high == nil || b.high == nil {
+		if a.high == nil && b.high == nil {
+			return 0
+		}
+		if a.high == nil {
+			return 1
+		}
+		return -1
+	}
+	cmp := CompareValues(a.high, b.high)
+	if cmp != 0 {
+		return cmp
+	}
+	if a.highInclusive == b.highInclusive {
+		return 0
+	}
+	if a.highInclusive {
+		return 1
+	}
+	return -1
+}
+
+// @Override
+func (re *Range) String() string {
+	if re.IsSingleValue() {
+		return "[" + valueString(re.low) + "]"
+	}
+	var sb strings.Builder
+	if re.lowInclusive {
+		sb.WriteString("[")
+	} else {
+		sb.WriteString("(")
+	}
+	if re.low == nil {
+		sb.WriteString("<min>")
+	} else {
+		sb.WriteString(valueString(re.low))
+	}
+	sb.WriteString(", ")
+	if re.high == nil {
+		sb.WriteString("<max>")
+	} else {
+		sb.WriteString(valueString(re.high))
+	}
+	if re.highInclusive {
+		sb.WriteString("]")
+	} else {
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
@@ -0,0 +1,234 @@
+package predicate
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mothdb-bd/orc-go/pkg/basic"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * A set of values stored as a sorted list of non-overlapping and non-adjacent ranges.
+ * Discrete values are stored as single value ranges.
+ */
+type ValueSet struct {
+	kind   block.Type
+	ranges []*Range
+}
+
+func NoneValueSet(kind block.Type) *ValueSet {
+	return newValueSet(kind, make([]*Range, 0))
+}
+
+func AllValueSet(kind block.Type) *ValueSet {
+	return newValueSet(kind, []*Range{AllRange(kind)})
+}
+
+func ValueSetOf(kind block.Type, values ...basic.Object) *ValueSet {
+	ranges := make([]*Range, len(values))
+	for i, value := range values {
+		ranges[i] = EqualRange(kind, value)
+	}
+	return ValueSetOfRanges(kind, ranges...)
+}
+
+func ValueSetOfRanges(kind block.Type, ranges ...*Range) *ValueSet {
+	return newValueSet(kind, normalizeRanges(ranges))
+}
+
+func newValueSet(kind block.Type, ranges []*Range) *ValueSet {
+	vt := new(ValueSet)
+	vt.kind = kind
+	vt.ranges = ranges
+	return vt
+}
+
+// normalizeRanges sorts the ranges by their lower bound and merges overlapping or adjacent ranges.
+func normalizeRanges(ranges []*Range) []*Range {
+	sorted := make([]*Range, len(ranges))
+	copy(sorted, ranges)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareLow(sorted[i], sorted[j]) < 0
+	})
+	result := make([]*Range, 0, len(sorted))
+	for _, r := range sorted {
+		if len(result) > 0 {
+			last := result[len(result)-1]
+			if last.Overlaps(r) || last.adjacentTo(r) {
+				result[len(result)-1] = last.Span(r)
+				continue
+			}
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+func (vt *ValueSet) GetType() block.Type {
+	return vt.kind
+}
+
+func (vt *ValueSet) IsNone() bool {
+	return len(vt.ranges) == 0
+}
+
+func (vt *ValueSet) IsAll() bool {
+	return len(vt.ranges) == 1 && vt.ranges[0].IsAll()
+}
+
+func (vt *ValueSet) IsSingleValue() bool {
+	return len(vt.ranges) == 1 && vt.ranges[0].IsSingleValue()
+}
+
+func (vt *ValueSet) GetSingleValue() basic.Object {
+	if !vt.IsSingleValue() {
+		panic("ValueSet does not have just a single value")
+	}
+	return vt.ranges[0].GetSingleValue()
+}
+
+/**
+ * Returns true if the set only consists of discrete values, i.e. every range is a single value.
+ */
+func (vt *ValueSet) IsDiscreteSet() bool {
+	if vt.IsNone() {
+		return false
+	}
+	for _, r := range vt.ranges {
+		if !r.IsSingleValue() {
+			return false
+		}
+	}
+	return true
+}
+
+func (vt *ValueSet) GetDiscreteValues() *util.ArrayList[basic.Object] {
+	if !vt.IsDiscreteSet() {
+		panic("ValueSet is not a discrete set")
+	}
+	values := util.NewArrayList[basic.Object]()
+	for _, r := range vt.ranges {
+		values.Add(r.GetSingleValue())
+	}
+	return values
+}
+
+func (vt *ValueSet) GetRanges() *util.ArrayList[*Range] {
+	return util.NewArrayList(vt.ranges...)
+}
+
+func (vt *ValueSet) GetRangeCount() int32 {
+	return util.Lens(vt.ranges)
+}
+
+/**
+ * Returns the smallest range which covers every value of this set.
+ */
+func (vt *ValueSet) GetSpan() *Range {
+	if vt.IsNone() {
+		panic("Cannot get span if no ranges exist")
+	}
+	return vt.ranges[0].Span(vt.ranges[len(vt.ranges)-1])
+}
+
+func (vt *ValueSet) ContainsValue(value basic.Object) bool {
+	idx := sort.Search(len(vt.ranges), func(i int) bool {
+		r := vt.ranges[i]
+		return r.high == nil || CompareValues(r.high, value) >= 0
+	})
+	return idx < len(vt.ranges) && vt.ranges[idx].Contains(value)
+}
+
+func (vt *ValueSet) Overlaps(other *ValueSet) bool {
+	i, j := 0, 0
+	for i < len(vt.ranges) && j < len(other.ranges) {
+		a, b := vt.ranges[i], other.ranges[j]
+		if a.Overlaps(b) {
+			return true
+		}
+		if a.endsBefore(b) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return false
+}
+
+func (vt *ValueSet) Intersect(other *ValueSet) *ValueSet {
+	result := make([]*Range, 0)
+	i, j := 0, 0
+	for i < len(vt.ranges) && j < len(other.ranges) {
+		a, b := vt.ranges[i], other.ranges[j]
+		if intersection := a.Intersect(b); intersection != nil {
+			result = append(result, intersection)
+		}
+		if compareHigh(a, b) <= 0 {
+			i++
+		} else {
+			j++
+		}
+	}
+	return newValueSet(vt.kind, result)
+}
+
+func (vt *ValueSet) Union(other *ValueSet) *ValueSet {
+	ranges := make([]*Range, 0, len(vt.ranges)+len(other.ranges))
+	ranges = append(ranges, vt.ranges...)
+	ranges = append(ranges, other.ranges...)
+	return newValueSet(vt.kind, normalizeRanges(ranges))
+}
+
+func (vt *ValueSet) Complement() *ValueSet {
+	result := make([]*Range, 0, len(vt.ranges)+1)
+	if vt.IsNone() {
+		result = append(result, AllRange(vt.kind))
+		return newValueSet(vt.kind, result)
+	}
+	first := vt.ranges[0]
+	if first.low != nil {
+		result = append(result, NewRange(vt.kind, nil, false, first.low, !first.lowInclusive))
+	}
+	for i := 1; i < len(vt.ranges); i++ {
+		previous, current := vt.ranges[i-1], vt.ranges[i]
+		result = append(result, NewRange(vt.kind, previous.high, !previous.highInclusive, current.low, !current.lowInclusive))
+	}
+	last := vt.ranges[len(vt.ranges)-1]
+	if last.high != nil {
+		result = append(result, NewRange(vt.kind, last.high, !last.highInclusive, nil, false))
+	}
+	return newValueSet(vt.kind, result)
+}
+
+func (vt *ValueSet) Contains(other *ValueSet) bool {
+	return vt.Union(other).equalRanges(vt)
+}
+
+func (vt *ValueSet) equalRanges(other *ValueSet) bool {
+	if len(vt.ranges) != len(other.ranges) {
+		return false
+	}
+	for i := range vt.ranges {
+		if compareLow(vt.ranges[i], other.ranges[i]) != 0 || compareHigh(vt.ranges[i], other.ranges[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// @Override
+func (vt *ValueSet) String() string {
+	if vt.IsNone() {
+		return "NONE"
+	}
+	if vt.IsAll() {
+		return "ALL"
+	}
+	parts := make([]string, len(vt.ranges))
+	for i, r := range vt.ranges {
+		parts[i] = r.String()
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
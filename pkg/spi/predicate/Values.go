@@ -0,0 +1,71 @@
+package predicate
+
+import (
+	"fmt"
+
+	"github.com/mothdb-bd/orc-go/pkg/basic"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/shopspring/decimal"
+)
+
+/**
+ * Compares two predicate values of the same type. Values are represented as:
+ *
+ *	BOOLEAN                               bool
+ *	TINYINT/SMALLINT/INTEGER/BIGINT/DATE  int64 (epoch days for DATE)
+ *	TIMESTAMP/TIMESTAMP WITH TIME ZONE    int64 (epoch micros)
+ *	REAL/DOUBLE                           float64
+ *	CHAR/VARCHAR/VARBINARY                *slice.Slice
+ *	DECIMAL                               *decimal.Decimal
+ */
+func CompareValues(a basic.Object, b basic.Object) int {
+	switch av := a.(type) {
+	case int64:
+		bv := b.(int64)
+		if av < bv {
+			return -1
+		} else if av > bv {
+			return 1
+		}
+		return 0
+	case float64:
+		bv := b.(float64)
+		if av < bv {
+			return -1
+		} else if av > bv {
+			return 1
+		}
+		return 0
+	case bool:
+		bv := b.(bool)
+		if av == bv {
+			return 0
+		} else if !av {
+			return -1
+		}
+		return 1
+	case *slice.Slice:
+		return av.CompareTo(b.(*slice.Slice))
+	case *decimal.Decimal:
+		return av.Cmp(*b.(*decimal.Decimal))
+	}
+	panic(fmt.Sprintf("Unsupported predicate value %v of type %T", a, a))
+}
+
+func checkValue(value basic.Object) {
+	switch value.(type) {
+	case int64, float64, bool, *slice.Slice, *decimal.Decimal:
+		return
+	}
+	panic(fmt.Sprintf("Unsupported predicate value %v of type %T", value, value))
+}
+
+func valueString(value basic.Object) string {
+	switch v := value.(type) {
+	case *slice.Slice:
+		return "'" + v.String() + "'"
+	case *decimal.Decimal:
+		return v.String()
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -1,8 +1,9 @@
 package store
 
 import (
-	"fmt"
+	"sync"
 
+	"github.com/mothdb-bd/orc-go/pkg/errors"
 	"github.com/mothdb-bd/orc-go/pkg/slice"
 	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/util"
@@ -27,9 +28,20 @@ func NewAbstractMothDataSource(id *common.MothDataSourceId, estimatedSize int64,
 	return ae
 }
 
+func (ae *AbstractMothDataSource) getOptions() *MothReaderOptions {
+	return ae.options
+}
+
+/**
+ * Loads a merged disk range on first use. A loader may be loaded concurrently, by a prefetch and
+ * a reader of its ranges, the range is read once.
+ */
 type LazyBufferLoader struct {
+	lock        sync.Mutex
 	diskRange   *DiskRange
 	bufferSlice *slice.Slice
+	// the range is fetched in the background, its buffer is retained before it is read
+	prefetched bool
 
 	parent MothDataSource
 }
@@ -50,6 +62,8 @@ func (lr *LazyBufferLoader) LoadNestedDiskRangeBuffer(nestedDiskRange *DiskRange
 }
 
 func (lr *LazyBufferLoader) load() {
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
 	if lr.bufferSlice != nil {
 		return
 	}
@@ -78,7 +92,7 @@ func (mr *MergedMothDataReader) GetMothDataSourceId() *common.MothDataSourceId {
 
 // @Override
 func (mr *MergedMothDataReader) GetRetainedSize() int64 {
-	return int64(util.Ternary(mr.data == nil, 0, mr.diskRange.GetLength()))
+	return int64(util.Ternary(mr.data == nil && !mr.lazyBufferLoader.prefetched, 0, mr.diskRange.GetLength()))
 }
 
 // @Override
@@ -96,10 +110,10 @@ func (mr *MergedMothDataReader) SeekBuffer(newPosition int32) *slice.Slice {
 	if mr.data == nil {
 		mr.data = mr.lazyBufferLoader.LoadNestedDiskRangeBuffer(mr.diskRange)
 		if mr.data == nil {
-			panic("Data loader returned null")
+			panic(common.NewMothError(errors.IO_ERROR, mr.GetMothDataSourceId(), "Data loader returned null"))
 		}
 		if mr.data.SizeInt32() != mr.diskRange.GetLength() {
-			panic(fmt.Sprintf("Expected to load %d bytes, but %d bytes were loaded", mr.diskRange.GetLength(), mr.data.Length()))
+			panic(common.NewMothError(errors.IO_ERROR, mr.GetMothDataSourceId(), "Expected to load %d bytes, but %d bytes were loaded", mr.diskRange.GetLength(), mr.data.Length()))
 		}
 	}
 	s, _ := mr.data.MakeSlice(int(newPosition), int(mr.data.Length()-newPosition))
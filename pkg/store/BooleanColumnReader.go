@@ -6,6 +6,7 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -60,7 +61,7 @@ func (br *BooleanColumnReader) ReadBlock() block.Block {
 		}
 		if br.readOffset > 0 {
 			if br.dataStream == nil {
-				panic("Value is not null but data stream is missing")
+				panic(common.NewMothCorruptionError(br.column.GetMothDataSourceId(), "Value is not null but data stream is missing"))
 			}
 			br.dataStream.SkipInt32(br.readOffset)
 		}
@@ -68,7 +69,7 @@ func (br *BooleanColumnReader) ReadBlock() block.Block {
 	var b block.Block
 	if br.dataStream == nil {
 		if br.presentStream == nil {
-			panic("Value is null but present stream is missing")
+			panic(common.NewMothCorruptionError(br.column.GetMothDataSourceId(), "Value is null but present stream is missing"))
 		}
 		br.presentStream.SkipInt32(br.nextBatchSize)
 		b = block.CreateRunLengthEncodedBlock(block.BOOLEAN, nil, br.nextBatchSize)
@@ -31,15 +31,15 @@ var (
 	BOOLEAN_COLUMN_ENCODING *metadata.ColumnEncoding = metadata.NewColumnEncoding(metadata.DIRECT, 0)
 )
 
-func NewBooleanColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, bufferSize int32) *BooleanColumnWriter {
+func NewBooleanColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) *BooleanColumnWriter {
 	br := new(BooleanColumnWriter)
 
 	br.statisticsBuilder = metadata.NewBooleanStatisticsBuilder()
 	br.columnId = columnId
 	br.kind = kind
 	br.compressed = compression != metadata.NONE
-	br.dataStream = NewBooleanOutputStream(compression, bufferSize)
-	br.presentStream = NewPresentOutputStream(compression, bufferSize)
+	br.dataStream = NewBooleanOutputStream(compression, compressionLevel, bufferSize)
+	br.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 
 	br.rowGroupColumnStatistics = util.NewArrayList[*metadata.ColumnStatistics]()
 	return br
@@ -280,27 +280,27 @@ func (bi *BooleanInputStream) GetSetBits2(vector []byte, batchSize int32) {
 		case 7:
 			vector[offset] = byte((value & 64) >> 6)
 			offset++
-			// fall through
+			fallthrough
 		case 6:
 			vector[offset] = byte((value & 32) >> 5)
 			offset++
-			// fall through
+			fallthrough
 		case 5:
 			vector[offset] = byte((value & 16) >> 4)
 			offset++
-			// fall through
+			fallthrough
 		case 4:
 			vector[offset] = byte((value & 8) >> 3)
 			offset++
-			// fall through
+			fallthrough
 		case 3:
 			vector[offset] = byte((value & 4) >> 2)
 			offset++
-			// fall through
+			fallthrough
 		case 2:
 			vector[offset] = byte((value & 2) >> 1)
 			offset++
-			// fall through
+			fallthrough
 		case 1:
 			vector[offset] = byte((value & 1) >> 0)
 			offset++
@@ -324,7 +324,6 @@ func (bi *BooleanInputStream) GetSetBits2(vector []byte, batchSize int32) {
 		vector[offset+5] = byte((value & 4) >> 2)
 		vector[offset+6] = byte((value & 2) >> 1)
 		vector[offset+7] = byte((value & 1))
-		offset += 8
 	}
 
 	// the tail
@@ -336,27 +335,27 @@ func (bi *BooleanInputStream) GetSetBits2(vector []byte, batchSize int32) {
 		case 7:
 			vector[offset] = byte((value & 64) >> 6)
 			offset++
-			// fall through
+			fallthrough
 		case 6:
 			vector[offset] = byte((value & 32) >> 5)
 			offset++
-			// fall through
+			fallthrough
 		case 5:
 			vector[offset] = byte((value & 16) >> 4)
 			offset++
-			// fall through
+			fallthrough
 		case 4:
 			vector[offset] = byte((value & 8) >> 3)
 			offset++
-			// fall through
+			fallthrough
 		case 3:
 			vector[offset] = byte((value & 4) >> 2)
 			offset++
-			// fall through
+			fallthrough
 		case 2:
 			vector[offset] = byte((value & 2) >> 1)
 			offset++
-			// fall through
+			fallthrough
 		case 1:
 			vector[offset] = byte((value & 1) >> 0)
 			offset++
@@ -474,27 +473,27 @@ func (bi *BooleanInputStream) GetUnsetBits(batchSize int32, vector []bool) int32
 		case 7:
 			vector[offset] = (value & 64) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 6:
 			vector[offset] = (value & 32) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 5:
 			vector[offset] = (value & 16) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 4:
 			vector[offset] = (value & 8) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 3:
 			vector[offset] = (value & 4) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 2:
 			vector[offset] = (value & 2) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 1:
 			vector[offset] = (value & 1) == 0
 			offset++
@@ -532,27 +531,27 @@ func (bi *BooleanInputStream) GetUnsetBits(batchSize int32, vector []bool) int32
 		case 7:
 			vector[offset] = (value & 64) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 6:
 			vector[offset] = (value & 32) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 5:
 			vector[offset] = (value & 16) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 4:
 			vector[offset] = (value & 8) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 3:
 			vector[offset] = (value & 4) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 2:
 			vector[offset] = (value & 2) == 0
 			offset++
-			// fall through
+			fallthrough
 		case 1:
 			vector[offset] = (value & 1) == 0
 			offset++
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/optional"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+func TestBooleanInputStream_GetSetBits2(t *testing.T) {
+	values := make([]bool, 100)
+	outputStream := NewBooleanOutputStream(metadata.NONE, 0, 1024)
+	for i := range values {
+		values[i] = i%3 == 0 || i%7 == 0
+		outputStream.WriteBoolean(values[i])
+	}
+	outputStream.Close()
+	output := slice.NewDynamicSliceOutput(64)
+	outputStream.GetStreamDataOutput(metadata.NewMothColumnId(0)).WriteData(output)
+	inputStream := NewBooleanInputStream(NewMothInputStream(CreateChunkLoader(common.NewMothDataSourceId("test"), output.Slice(), optional.Empty[MothDecompressor](), memory.NewSimpleAggregatedMemoryContext())))
+
+	// an unaligned head, several whole bytes and a tail
+	offset := 0
+	for _, batchSize := range []int32{3, 29, 60, 8} {
+		vector := make([]byte, batchSize)
+		inputStream.GetSetBits2(vector, batchSize)
+		for i, bit := range vector {
+			if (bit == 1) != values[offset+i] {
+				t.Fatalf("read %d for value %d in a batch of %d", bit, offset+i, batchSize)
+			}
+		}
+		offset += int(batchSize)
+	}
+}
+
+func TestBooleanInputStream_GetUnsetBits(t *testing.T) {
+	values := make([]bool, 100)
+	outputStream := NewBooleanOutputStream(metadata.NONE, 0, 1024)
+	for i := range values {
+		values[i] = i%3 == 0 || i%7 == 0
+		outputStream.WriteBoolean(values[i])
+	}
+	outputStream.Close()
+	output := slice.NewDynamicSliceOutput(64)
+	outputStream.GetStreamDataOutput(metadata.NewMothColumnId(0)).WriteData(output)
+	inputStream := NewBooleanInputStream(NewMothInputStream(CreateChunkLoader(common.NewMothDataSourceId("test"), output.Slice(), optional.Empty[MothDecompressor](), memory.NewSimpleAggregatedMemoryContext())))
+
+	// the heads and tails of the unaligned batches fall through to their last bit
+	offset := 0
+	for _, batchSize := range []int32{3, 29, 7, 53, 8} {
+		vector := make([]bool, batchSize)
+		unsetCount := inputStream.GetUnsetBits(batchSize, vector)
+		wantUnsetCount := int32(0)
+		for i, unset := range vector {
+			if unset == values[offset+i] {
+				t.Fatalf("read unset %v for value %d in a batch of %d", unset, offset+i, batchSize)
+			}
+			if !values[offset+i] {
+				wantUnsetCount++
+			}
+		}
+		if unsetCount != wantUnsetCount {
+			t.Fatalf("counted %d unset bits in a batch of %d, want %d", unsetCount, batchSize, wantUnsetCount)
+		}
+		offset += int(batchSize)
+	}
+}
@@ -19,8 +19,8 @@ type BooleanOutputStream struct {
 	closed               bool
 }
 
-func NewBooleanOutputStream(compression metadata.CompressionKind, bufferSize int32) *BooleanOutputStream {
-	return NewBooleanOutputStream3(NewByteOutputStream(compression, bufferSize))
+func NewBooleanOutputStream(compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) *BooleanOutputStream {
+	return NewBooleanOutputStream3(NewByteOutputStream(compression, compressionLevel, bufferSize))
 }
 func NewBooleanOutputStream2(buffer *MothOutputBuffer) *BooleanOutputStream {
 	return NewBooleanOutputStream3(NewByteOutputStream2(buffer))
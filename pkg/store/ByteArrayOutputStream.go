@@ -18,14 +18,14 @@ type ByteArrayOutputStream struct {
 	closed      bool
 }
 
-func NewByteArrayOutputStream(compression metadata.CompressionKind, bufferSize int32) *ByteArrayOutputStream {
-	return NewByteArrayOutputStream2(compression, bufferSize, metadata.DATA)
+func NewByteArrayOutputStream(compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) *ByteArrayOutputStream {
+	return NewByteArrayOutputStream2(compression, compressionLevel, bufferSize, metadata.DATA)
 }
-func NewByteArrayOutputStream2(compression metadata.CompressionKind, bufferSize int32, streamKind metadata.StreamKind) *ByteArrayOutputStream {
+func NewByteArrayOutputStream2(compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, streamKind metadata.StreamKind) *ByteArrayOutputStream {
 	bm := new(ByteArrayOutputStream)
 	bm.checkpoints = util.NewArrayList[*ByteArrayStreamCheckpoint]()
 
-	bm.buffer = NewMothOutputBuffer(compression, bufferSize)
+	bm.buffer = NewMothOutputBuffer(compression, compressionLevel, bufferSize)
 	bm.streamKind = streamKind
 	return bm
 }
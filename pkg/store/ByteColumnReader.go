@@ -6,6 +6,7 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -16,6 +17,7 @@ type ByteColumnReader struct {
 	// 继承
 	ColumnReader
 
+	kind                block.Type
 	column              *MothColumn
 	readOffset          int32
 	nextBatchSize       int32
@@ -36,6 +38,7 @@ func NewByteColumnReader(kind block.Type, column *MothColumn, memoryContext memo
 
 	br.nonNullValueTemp = make([]byte, 0)
 
+	br.kind = kind
 	br.column = column
 	br.memoryContext = memoryContext
 	return br
@@ -58,7 +61,7 @@ func (br *ByteColumnReader) ReadBlock() block.Block {
 		}
 		if br.readOffset > 0 {
 			if br.dataStream == nil {
-				panic("Value is not null but data stream is missing")
+				panic(common.NewMothCorruptionError(br.column.GetMothDataSourceId(), "Value is not null but data stream is missing"))
 			}
 			br.dataStream.Skip(int64(br.readOffset))
 		}
@@ -66,10 +69,10 @@ func (br *ByteColumnReader) ReadBlock() block.Block {
 	var b block.Block
 	if br.dataStream == nil {
 		if br.presentStream == nil {
-			panic("Value is null but present stream is missing")
+			panic(common.NewMothCorruptionError(br.column.GetMothDataSourceId(), "Value is null but present stream is missing"))
 		}
 		br.presentStream.Skip(int64(br.nextBatchSize))
-		b = block.CreateRunLengthEncodedBlock(block.TINYINT, nil, br.nextBatchSize)
+		b = block.CreateRunLengthEncodedBlock(br.kind, nil, br.nextBatchSize)
 	} else if br.presentStream == nil {
 		b = br.readNonNullBlock()
 	} else {
@@ -80,7 +83,7 @@ func (br *ByteColumnReader) ReadBlock() block.Block {
 		} else if nullCount != br.nextBatchSize {
 			b = br.readNullBlock(isNull, br.nextBatchSize-nullCount)
 		} else {
-			b = block.CreateRunLengthEncodedBlock(block.TINYINT, nil, br.nextBatchSize)
+			b = block.CreateRunLengthEncodedBlock(br.kind, nil, br.nextBatchSize)
 		}
 	}
 	br.readOffset = 0
@@ -90,7 +93,7 @@ func (br *ByteColumnReader) ReadBlock() block.Block {
 
 func (br *ByteColumnReader) readNonNullBlock() block.Block {
 	values := br.dataStream.Next2(br.nextBatchSize)
-	return block.NewByteArrayBlock(br.nextBatchSize, optional.Empty[[]bool](), values)
+	return br.createBlock(br.nextBatchSize, optional.Empty[[]bool](), values)
 }
 
 func (br *ByteColumnReader) readNullBlock(isNull []bool, nonNullCount int32) block.Block {
@@ -101,7 +104,35 @@ func (br *ByteColumnReader) readNullBlock(isNull []bool, nonNullCount int32) blo
 	}
 	br.dataStream.Next3(br.nonNullValueTemp, nonNullCount)
 	result := UnpackByteNulls(br.nonNullValueTemp, isNull)
-	return block.NewByteArrayBlock(br.nextBatchSize, optional.Of(isNull), result)
+	return br.createBlock(br.nextBatchSize, optional.Of(isNull), result)
+}
+
+/**
+ * Creates the block of the values, the values are widened when the column is read as a SMALLINT,
+ * INTEGER or BIGINT.
+ */
+func (br *ByteColumnReader) createBlock(positionCount int32, valueIsNull *optional.Optional[[]bool], values []byte) block.Block {
+	switch br.kind.(type) {
+	case *block.SmallintType:
+		result := make([]int16, positionCount)
+		for i := util.INT32_ZERO; i < positionCount; i++ {
+			result[i] = int16(int8(values[i]))
+		}
+		return block.NewShortArrayBlock(positionCount, valueIsNull, result)
+	case *block.IntegerType:
+		result := make([]int32, positionCount)
+		for i := util.INT32_ZERO; i < positionCount; i++ {
+			result[i] = int32(int8(values[i]))
+		}
+		return block.NewIntArrayBlock(positionCount, valueIsNull, result)
+	case *block.BigintType:
+		result := make([]int64, positionCount)
+		for i := util.INT32_ZERO; i < positionCount; i++ {
+			result[i] = int64(int8(values[i]))
+		}
+		return block.NewLongArrayBlock(positionCount, valueIsNull, result)
+	}
+	return block.NewByteArrayBlock(positionCount, valueIsNull, values)
 }
 
 func (br *ByteColumnReader) openRowGroup() {
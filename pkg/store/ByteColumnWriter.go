@@ -27,13 +27,13 @@ type ByteColumnWriter struct {
 	closed                   bool
 }
 
-func NewByteColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, bufferSize int32) *ByteColumnWriter {
+func NewByteColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) *ByteColumnWriter {
 	br := new(ByteColumnWriter)
 	br.columnId = columnId
 	br.kind = kind
 	br.compressed = compression != metadata.NONE
-	br.dataStream = NewByteOutputStream(compression, bufferSize)
-	br.presentStream = NewPresentOutputStream(compression, bufferSize)
+	br.dataStream = NewByteOutputStream(compression, compressionLevel, bufferSize)
+	br.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 	br.rowGroupColumnStatistics = util.NewArrayList[*metadata.ColumnStatistics]()
 	return br
 }
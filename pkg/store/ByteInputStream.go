@@ -4,6 +4,7 @@ import (
 	"io"
 
 	"github.com/mothdb-bd/orc-go/pkg/maths"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
@@ -34,14 +35,14 @@ func (bm *ByteInputStream) readNextBlock() {
 	bm.lastReadInputCheckpoint = bm.input.GetCheckpoint()
 	control, err := bm.input.ReadBS()
 	if err != nil && err != io.EOF {
-		panic("Read past end of buffer RLE byte")
+		panic(common.NewMothCorruptionError(bm.input.GetMothDataSourceId(), "Read past end of buffer RLE byte"))
 	}
 	bm.offset = 0
 	if (control & 0x80) == 0 {
 		bm.length = int32(control) + MIN_REPEAT_SIZE
 		value, err := bm.input.ReadBS()
 		if err != nil && err != io.EOF {
-			panic("Reading RLE byte got EOF")
+			panic(common.NewMothCorruptionError(bm.input.GetMothDataSourceId(), "Reading RLE byte got EOF"))
 		}
 		util.FillArrays(bm.buffer, 0, bm.length, byte(value))
 	} else {
@@ -97,7 +98,7 @@ func (bm *ByteInputStream) Next3(values []byte, items int32) {
 			bm.readNextBlock()
 		}
 		if bm.length == 0 {
-			panic("Unexpected end of stream")
+			panic(common.NewMothCorruptionError(bm.input.GetMothDataSourceId(), "Unexpected end of stream"))
 		}
 		chunkSize := maths.MinInt32(items-outputOffset, bm.length-bm.offset)
 		util.CopyBytes(bm.buffer, bm.offset, values, outputOffset, chunkSize)
@@ -26,8 +26,8 @@ type ByteOutputStream struct {
 	closed         bool
 }
 
-func NewByteOutputStream(compression metadata.CompressionKind, bufferSize int32) *ByteOutputStream {
-	return NewByteOutputStream2(NewMothOutputBuffer(compression, bufferSize))
+func NewByteOutputStream(compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) *ByteOutputStream {
+	return NewByteOutputStream2(NewMothOutputBuffer(compression, compressionLevel, bufferSize))
 }
 func NewByteOutputStream2(buffer *MothOutputBuffer) *ByteOutputStream {
 	bm := new(ByteOutputStream)
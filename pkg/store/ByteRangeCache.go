@@ -0,0 +1,285 @@
+package store
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * A byte range of a file, the file is identified like the tails in the FileTailCache.
+ */
+type ByteRangeKey struct {
+	file   FileTailKey
+	offset int64
+	length int32
+}
+
+/**
+ * A process-wide cache of byte ranges of files, shared by the data sources wrapped in a
+ * ByteRangeCachingMothDataSource. The ranges are kept in a memory tier whose bytes are reserved
+ * in a memory context, so the cache gives way to the readers and writers of a shared bounded
+ * pool, and which holds at most maxMemorySize bytes. The least recently used ranges are evicted
+ * from the memory tier to the optional disk tier, which holds at most maxDiskSize bytes in files
+ * of its directory. A range read from the disk tier is moved back to the memory tier. A cache is
+ * safe for concurrent use.
+ */
+type ByteRangeCache struct {
+	lock          sync.Mutex
+	memoryUsage   memory.LocalMemoryContext
+	maxMemorySize int64
+	memorySize    int64
+	memoryEntries map[ByteRangeKey]*list.Element
+	memoryLru     *list.List
+
+	directory   string
+	maxDiskSize int64
+	diskSize    int64
+	diskEntries map[ByteRangeKey]*list.Element
+	diskLru     *list.List
+
+	memoryHitCount int64
+	diskHitCount   int64
+	missCount      int64
+	evictionCount  int64
+}
+
+type byteRangeEntry struct {
+	key  ByteRangeKey
+	data []byte
+	path string
+}
+
+/**
+ * Creates a cache with a memory tier only.
+ */
+func NewByteRangeCache(memoryContext memory.AggregatedMemoryContext, maxMemorySize util.DataSize) *ByteRangeCache {
+	return NewByteRangeCache2(memoryContext, maxMemorySize, "", util.Ofds(0, util.B))
+}
+
+/**
+ * Creates a cache with a disk tier in the directory, which is created when it does not exist.
+ * The files of the disk tier are removed by Close.
+ */
+func NewByteRangeCache2(memoryContext memory.AggregatedMemoryContext, maxMemorySize util.DataSize, directory string, maxDiskSize util.DataSize) *ByteRangeCache {
+	be := new(ByteRangeCache)
+	be.memoryUsage = memoryContext.NewLocalMemoryContext("ByteRangeCache")
+	be.maxMemorySize = int64(maxMemorySize.Bytes())
+	be.memoryEntries = make(map[ByteRangeKey]*list.Element)
+	be.memoryLru = list.New()
+	be.diskEntries = make(map[ByteRangeKey]*list.Element)
+	be.diskLru = list.New()
+	if directory != "" && maxDiskSize.Bytes() > 0 {
+		if err := os.MkdirAll(directory, 0o755); err != nil {
+			panic(fmt.Sprintf("Failed to create the cache directory %s: %s", directory, err.Error()))
+		}
+		be.directory = directory
+		be.maxDiskSize = int64(maxDiskSize.Bytes())
+	}
+	return be
+}
+
+/**
+ * Returns the cached bytes of the range, nil when the range is not cached. Every hit returns a
+ * new slice over the cached bytes, so the positions of the readers of a range are not shared.
+ */
+func (be *ByteRangeCache) get(key ByteRangeKey) *slice.Slice {
+	be.lock.Lock()
+	if element, ok := be.memoryEntries[key]; ok {
+		be.memoryHitCount++
+		be.memoryLru.MoveToFront(element)
+		data := element.Value.(*byteRangeEntry).data
+		be.lock.Unlock()
+		return slice.NewWithBuf(data)
+	}
+	element, ok := be.diskEntries[key]
+	if !ok {
+		be.missCount++
+		be.lock.Unlock()
+		return nil
+	}
+	path := element.Value.(*byteRangeEntry).path
+	be.lock.Unlock()
+
+	// a file removed by a concurrent eviction is a miss
+	data, err := os.ReadFile(path)
+	be.lock.Lock()
+	if err != nil || len(data) != int(key.length) {
+		be.missCount++
+		be.lock.Unlock()
+		return nil
+	}
+	be.diskHitCount++
+	be.lock.Unlock()
+	be.putBytes(key, data)
+	return slice.NewWithBuf(data)
+}
+
+/**
+ * Caches the bytes of the range in the memory tier, the evicted ranges are moved to the disk
+ * tier.
+ */
+func (be *ByteRangeCache) put(key ByteRangeKey, data *slice.Slice) {
+	be.putBytes(key, data.UnsafeBytes()[:key.length])
+}
+
+func (be *ByteRangeCache) putBytes(key ByteRangeKey, data []byte) {
+	size := int64(key.length)
+	be.lock.Lock()
+	if _, ok := be.memoryEntries[key]; ok {
+		be.lock.Unlock()
+		return
+	}
+	be.removeFromDisk(key)
+	evicted := make([]*byteRangeEntry, 0)
+	for be.memoryLru.Len() > 0 && (be.memorySize+size > be.maxMemorySize || !be.memoryUsage.TrySetBytes(be.memorySize+size)) {
+		evicted = append(evicted, be.removeFromMemory(be.memoryLru.Back()))
+	}
+	if be.memorySize+size <= be.maxMemorySize && be.memoryUsage.TrySetBytes(be.memorySize+size) {
+		be.memoryEntries[key] = be.memoryLru.PushFront(&byteRangeEntry{key: key, data: data})
+		be.memorySize += size
+	} else {
+		evicted = append(evicted, &byteRangeEntry{key: key, data: data})
+	}
+	be.lock.Unlock()
+
+	for _, entry := range evicted {
+		be.spill(entry)
+	}
+}
+
+func (be *ByteRangeCache) removeFromMemory(element *list.Element) *byteRangeEntry {
+	entry := be.memoryLru.Remove(element).(*byteRangeEntry)
+	delete(be.memoryEntries, entry.key)
+	be.memorySize -= int64(entry.key.length)
+	be.memoryUsage.SetBytes(be.memorySize)
+	be.evictionCount++
+	return entry
+}
+
+/**
+ * Writes the evicted range to the disk tier, the range is dropped when the disk tier is disabled
+ * or the file can not be written.
+ */
+func (be *ByteRangeCache) spill(entry *byteRangeEntry) {
+	size := int64(entry.key.length)
+	if be.directory == "" || size > be.maxDiskSize {
+		return
+	}
+	digest := sha256.Sum256([]byte(fmt.Sprintf("%s/%d/%s/%d/%d", entry.key.file.id, entry.key.file.length, entry.key.file.modificationStamp, entry.key.offset, entry.key.length)))
+	path := filepath.Join(be.directory, hex.EncodeToString(digest[:]))
+	if err := os.WriteFile(path, entry.data, 0o644); err != nil {
+		return
+	}
+
+	be.lock.Lock()
+	defer be.lock.Unlock()
+	if _, ok := be.diskEntries[entry.key]; ok {
+		return
+	}
+	for be.diskSize+size > be.maxDiskSize {
+		be.removeFromDisk(be.diskLru.Back().Value.(*byteRangeEntry).key)
+	}
+	be.diskEntries[entry.key] = be.diskLru.PushFront(&byteRangeEntry{key: entry.key, path: path})
+	be.diskSize += size
+}
+
+func (be *ByteRangeCache) removeFromDisk(key ByteRangeKey) {
+	element, ok := be.diskEntries[key]
+	if !ok {
+		return
+	}
+	entry := be.diskLru.Remove(element).(*byteRangeEntry)
+	delete(be.diskEntries, key)
+	be.diskSize -= int64(key.length)
+	os.Remove(entry.path)
+}
+
+func (be *ByteRangeCache) GetHitCount() int64 {
+	be.lock.Lock()
+	defer be.lock.Unlock()
+	return be.memoryHitCount + be.diskHitCount
+}
+
+func (be *ByteRangeCache) GetMemoryHitCount() int64 {
+	be.lock.Lock()
+	defer be.lock.Unlock()
+	return be.memoryHitCount
+}
+
+func (be *ByteRangeCache) GetDiskHitCount() int64 {
+	be.lock.Lock()
+	defer be.lock.Unlock()
+	return be.diskHitCount
+}
+
+func (be *ByteRangeCache) GetMissCount() int64 {
+	be.lock.Lock()
+	defer be.lock.Unlock()
+	return be.missCount
+}
+
+/**
+ * Returns the share of the lookups that were served from the memory or disk tier, 0 before the
+ * first lookup.
+ */
+func (be *ByteRangeCache) GetHitRatio() float64 {
+	be.lock.Lock()
+	defer be.lock.Unlock()
+	lookups := be.memoryHitCount + be.diskHitCount + be.missCount
+	if lookups == 0 {
+		return 0
+	}
+	return float64(be.memoryHitCount+be.diskHitCount) / float64(lookups)
+}
+
+/**
+ * Returns the number of ranges evicted from the memory tier.
+ */
+func (be *ByteRangeCache) GetEvictionCount() int64 {
+	be.lock.Lock()
+	defer be.lock.Unlock()
+	return be.evictionCount
+}
+
+func (be *ByteRangeCache) GetMemorySize() int64 {
+	be.lock.Lock()
+	defer be.lock.Unlock()
+	return be.memorySize
+}
+
+func (be *ByteRangeCache) GetDiskSize() int64 {
+	be.lock.Lock()
+	defer be.lock.Unlock()
+	return be.diskSize
+}
+
+/**
+ * Drops the cached ranges, releases the memory of the memory tier and removes the files of the
+ * disk tier.
+ */
+func (be *ByteRangeCache) Close() {
+	be.lock.Lock()
+	defer be.lock.Unlock()
+	for be.diskLru.Len() > 0 {
+		be.removeFromDisk(be.diskLru.Back().Value.(*byteRangeEntry).key)
+	}
+	be.memoryEntries = make(map[ByteRangeKey]*list.Element)
+	be.memoryLru.Init()
+	be.memorySize = 0
+	be.memoryUsage.Close()
+}
+
+func (be *ByteRangeCache) String() string {
+	be.lock.Lock()
+	defer be.lock.Unlock()
+	return util.NewSB().AddInt64("memorySize", be.memorySize).AddInt64("diskSize", be.diskSize).AddInt64("memoryHitCount", be.memoryHitCount).AddInt64("diskHitCount", be.diskHitCount).AddInt64("missCount", be.missCount).AddInt64("evictionCount", be.evictionCount).String()
+}
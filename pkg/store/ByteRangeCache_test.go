@@ -0,0 +1,183 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func readCachedRows(t *testing.T, path string, cache *ByteRangeCache, options *MothReaderOptions) int64 {
+	source := NewByteRangeCachingMothDataSource(NewFileMothDataSource(path, options), cache, options)
+	recordReader := createRecordReader(source, options, TRUE)
+	defer recordReader.Close()
+	if ids := pageIds(t, recordReader.ReadRows(0, 3000).ToArray()...); !reflect.DeepEqual(ids, idRange(0, 3000)) {
+		t.Fatalf("read %d rows through %s", len(ids), cache)
+	}
+	return source.GetReadBytes()
+}
+
+func TestByteRangeCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.moth")
+	writeTailCacheFile(t, path, 3000)
+	for _, options := range []*MothReaderOptions{NewMothReaderOptions(), NewMothReaderOptions().WithLazyReadSmallRanges(false)} {
+		// tiny files are not read into memory, so the stream ranges are cached
+		options = options.WithTinyStripeThreshold(util.Ofds(0, util.B))
+		memoryContext := memory.NewSimpleAggregatedMemoryContext()
+		cache := NewByteRangeCache(memoryContext, util.Ofds(16, util.MB))
+
+		if readBytes := readCachedRows(t, path, cache, options); readBytes == 0 {
+			t.Fatalf("read the rows without reading the file with %s", cache)
+		}
+		if readBytes := readCachedRows(t, path, cache, options); readBytes != 0 {
+			t.Fatalf("read %d bytes of a cached file with %s", readBytes, cache)
+		}
+		if cache.GetHitCount() != cache.GetMissCount() || cache.GetHitRatio() != 0.5 || cache.GetDiskHitCount() != 0 {
+			t.Fatalf("read a file twice with %s", cache)
+		}
+		if memoryContext.GetBytes() != cache.GetMemorySize() || cache.GetMemorySize() == 0 {
+			t.Fatalf("reserved %d bytes for %s", memoryContext.GetBytes(), cache)
+		}
+		cache.Close()
+		if memoryContext.GetBytes() != 0 {
+			t.Fatalf("reserved %d bytes after closing the cache", memoryContext.GetBytes())
+		}
+	}
+
+	// a rewritten file is read again
+	options := NewMothReaderOptions().WithTinyStripeThreshold(util.Ofds(0, util.B))
+	cache := NewByteRangeCache(memory.NewSimpleAggregatedMemoryContext(), util.Ofds(16, util.MB))
+	readCachedRows(t, path, cache, options)
+	writeTailCacheFile(t, path, 3000)
+	if modTime := time.Now().Add(time.Hour); os.Chtimes(path, modTime, modTime) != nil {
+		t.Fatal("failed to touch the rewritten file")
+	}
+	if readBytes := readCachedRows(t, path, cache, options); readBytes == 0 {
+		t.Fatalf("read a rewritten file from the cache with %s", cache)
+	}
+}
+
+func readCachedColumns(t *testing.T, path string, cache *ByteRangeCache, options *MothReaderOptions, columns ...int32) int64 {
+	source := NewByteRangeCachingMothDataSource(NewFileMothDataSource(path, options), cache, options)
+	reader := CreateMothReader(source, options).Get()
+	fileColumns := reader.GetRootColumn().GetNestedColumns()
+	fileTypes := []block.Type{block.BIGINT, block.VARCHAR}
+	readColumns := util.NewArrayList[*MothColumn]()
+	readTypes := util.NewArrayList[block.Type]()
+	for _, column := range columns {
+		readColumns.Add(fileColumns.Get(int(column)))
+		readTypes.Add(fileTypes[column])
+	}
+	recordReader := reader.CreateRecordReader(readColumns, readTypes, TRUE, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+	defer recordReader.Close()
+	rowCount := util.INT32_ZERO
+	for _, page := range recordReader.ReadRows(0, 3000).ToArray() {
+		page.GetLoadedPage()
+		rowCount += page.GetPositionCount()
+	}
+	if rowCount != 3000 {
+		t.Fatalf("read %d rows of columns %v through %s", rowCount, columns, cache)
+	}
+	return source.GetReadBytes()
+}
+
+func TestByteRangeCache_Projections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.moth")
+	writeTailCacheFile(t, path, 3000)
+	for _, options := range []*MothReaderOptions{NewMothReaderOptions(), NewMothReaderOptions().WithLazyReadSmallRanges(false)} {
+		options = options.WithTinyStripeThreshold(util.Ofds(0, util.B))
+		cache := NewByteRangeCache(memory.NewSimpleAggregatedMemoryContext(), util.Ofds(16, util.MB))
+
+		// the streams are cached one by one, so every projection of the cached columns is served
+		// from the cache, whichever projections cached their streams
+		if readBytes := readCachedColumns(t, path, cache, options, 0); readBytes == 0 {
+			t.Fatalf("read a column without reading the file with %s", cache)
+		}
+		if readBytes := readCachedColumns(t, path, cache, options, 0, 1); readBytes == 0 {
+			t.Fatalf("read a new column without reading the file with %s", cache)
+		}
+		for _, columns := range [][]int32{{0}, {1}, {1, 0}, {0, 1}} {
+			if readBytes := readCachedColumns(t, path, cache, options, columns...); readBytes != 0 {
+				t.Fatalf("read %d bytes of the cached columns %v with %s", readBytes, columns, cache)
+			}
+		}
+		cache.Close()
+	}
+}
+
+func TestByteRangeCache_TinyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.moth")
+	writeTailCacheFile(t, path, 3000)
+	options := NewMothReaderOptions()
+	cache := NewByteRangeCache(memory.NewSimpleAggregatedMemoryContext(), util.Ofds(16, util.MB))
+
+	// a tiny file is read as a whole through the cache
+	readCachedRows(t, path, cache, options)
+	if readBytes := readCachedRows(t, path, cache, options); readBytes != 0 || cache.GetHitCount() == 0 {
+		t.Fatalf("read %d bytes of a cached tiny file with %s", readBytes, cache)
+	}
+}
+
+func TestByteRangeCache_MemoryLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.moth")
+	writeTailCacheFile(t, path, 3000)
+	options := NewMothReaderOptions().WithTinyStripeThreshold(util.Ofds(0, util.B))
+	cache := NewByteRangeCache(memory.NewSimpleAggregatedMemoryContext(), util.Ofds(16, util.MB))
+	readCachedRows(t, path, cache, options)
+	cachedSize := cache.GetMemorySize()
+
+	// the cache gives way to the budget of a bounded pool
+	pool := memory.NewBoundedAggregatedMemoryContext(cachedSize / 2)
+	cache = NewByteRangeCache(pool, util.Ofds(16, util.MB))
+	readCachedRows(t, path, cache, options)
+	if cache.GetMemorySize() > cachedSize/2 || pool.GetBytes() != cache.GetMemorySize() || cache.GetMemorySize() == 0 {
+		t.Fatalf("cached %d bytes in a pool of %d bytes with %s", pool.GetBytes(), pool.GetLimit(), cache)
+	}
+
+	// and to its own maximum size
+	cache = NewByteRangeCache(memory.NewSimpleAggregatedMemoryContext(), util.Ofds(uint64(cachedSize/2), util.B))
+	readCachedRows(t, path, cache, options)
+	if cache.GetMemorySize() > cachedSize/2 || cache.GetMemorySize() == 0 {
+		t.Fatalf("cached %d bytes in a cache of %d bytes with %s", cache.GetMemorySize(), cachedSize/2, cache)
+	}
+}
+
+func TestByteRangeCache_Disk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.moth")
+	writeTailCacheFile(t, path, 3000)
+	directory := filepath.Join(t.TempDir(), "cache")
+	options := NewMothReaderOptions().WithTinyStripeThreshold(util.Ofds(0, util.B))
+
+	// no range fits in the memory tier, so every range is written to the disk tier
+	cache := NewByteRangeCache2(memory.NewSimpleAggregatedMemoryContext(), util.Ofds(1, util.B), directory, util.Ofds(16, util.MB))
+	readCachedRows(t, path, cache, options)
+	if cache.GetMemorySize() != 0 || cache.GetDiskSize() == 0 {
+		t.Fatalf("read a file with %s", cache)
+	}
+	if readBytes := readCachedRows(t, path, cache, options); readBytes != 0 || cache.GetDiskHitCount() == 0 || cache.GetMemoryHitCount() != 0 {
+		t.Fatalf("read %d bytes of a file cached on disk with %s", readBytes, cache)
+	}
+	cache.Close()
+	if files, err := os.ReadDir(directory); err != nil || len(files) != 0 {
+		t.Fatalf("left %d files in the cache directory: %v", len(files), err)
+	}
+
+	// a range read from the disk tier is moved back to the memory tier
+	cache = NewByteRangeCache2(memory.NewSimpleAggregatedMemoryContext(), util.Ofds(100, util.B), directory, util.Ofds(16, util.MB))
+	delegate := NewFileMothDataSource(path, options)
+	source := NewByteRangeCachingMothDataSource(delegate, cache, options)
+	for _, position := range []int64{0, 100, 0, 100} {
+		if data := source.ReadFully(position, 100); !reflect.DeepEqual(data.AvailableBytes(), delegate.ReadFully(position, 100).AvailableBytes()) {
+			t.Fatalf("read other bytes at %d from the cache", position)
+		}
+	}
+	if cache.GetMissCount() != 2 || cache.GetDiskHitCount() != 2 || cache.GetEvictionCount() != 3 || cache.GetMemorySize() != 100 || cache.GetDiskSize() != 100 {
+		t.Fatalf("read 2 ranges twice with %s", cache)
+	}
+	cache.Close()
+}
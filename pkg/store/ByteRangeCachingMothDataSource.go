@@ -0,0 +1,195 @@
+package store
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * A data source reading the byte ranges of its delegate through a shared ByteRangeCache, so the
+ * readers of a file that is read again, in this or another data source, do not fetch the cached
+ * ranges from the delegate. The stream ranges of ReadFully2 are cached one by one, so readers
+ * projecting other columns of a stripe share the ranges of their common streams, and only the
+ * missing stream ranges are merged by the maxMergeDistance and maxBufferSize of the options and
+ * read from the delegate. The read bytes and read time are the ones of the delegate, so they only
+ * count the missed ranges.
+ */
+type ByteRangeCachingMothDataSource struct {
+	delegate MothDataSource
+	cache    *ByteRangeCache
+	options  *MothReaderOptions
+	key      FileTailKey
+}
+
+func NewByteRangeCachingMothDataSource(delegate MothDataSource, cache *ByteRangeCache, options *MothReaderOptions) *ByteRangeCachingMothDataSource {
+	be := new(ByteRangeCachingMothDataSource)
+	be.delegate = delegate
+	be.cache = cache
+	be.options = options
+	be.key = NewFileTailKey(delegate)
+	return be
+}
+
+/**
+ * Returns the modification stamp of the delegate, so the file tail of the data source is cached
+ * under the key of the delegate.
+ */
+// @Override
+func (be *ByteRangeCachingMothDataSource) GetModificationStamp() string {
+	return be.key.modificationStamp
+}
+
+// @Override
+func (be *ByteRangeCachingMothDataSource) GetId() *common.MothDataSourceId {
+	return be.delegate.GetId()
+}
+
+// @Override
+func (be *ByteRangeCachingMothDataSource) GetReadBytes() int64 {
+	return be.delegate.GetReadBytes()
+}
+
+// @Override
+func (be *ByteRangeCachingMothDataSource) GetReadTimeNanos() int64 {
+	return be.delegate.GetReadTimeNanos()
+}
+
+// @Override
+func (be *ByteRangeCachingMothDataSource) GetEstimatedSize() int64 {
+	return be.delegate.GetEstimatedSize()
+}
+
+// @Override
+func (be *ByteRangeCachingMothDataSource) GetRetainedSize() int64 {
+	return be.delegate.GetRetainedSize()
+}
+
+// @Override
+func (be *ByteRangeCachingMothDataSource) ReadTail(length int32) *slice.Slice {
+	return be.ReadFully(be.delegate.GetEstimatedSize()-int64(length), length)
+}
+
+// @Override
+func (be *ByteRangeCachingMothDataSource) ReadFully(position int64, length int32) *slice.Slice {
+	key := ByteRangeKey{file: be.key, offset: position, length: length}
+	if data := be.cache.get(key); data != nil {
+		return data
+	}
+	data := be.delegate.ReadFully(position, length)
+	be.cache.put(key, data)
+	return data
+}
+
+// @Override
+func (be *ByteRangeCachingMothDataSource) ReadFully2(diskRanges map[StreamId]*DiskRange) map[StreamId]MothDataReader {
+	if len(diskRanges) == 0 {
+		return util.EmptyMap[StreamId, MothDataReader]()
+	}
+	slices := make(map[StreamId]MothDataReader, len(diskRanges))
+	missingRanges := make(map[StreamId]*DiskRange)
+	for key, diskRange := range diskRanges {
+		if data := be.cache.get(be.rangeKey(diskRange)); data != nil {
+			slices[key] = NewMemoryMothDataReader(be.GetId(), data, int64(diskRange.GetLength()))
+		} else {
+			missingRanges[key] = diskRange
+		}
+	}
+	if len(missingRanges) == 0 {
+		return slices
+	}
+	// the missing stream ranges are read from the delegate in merged ranges, but cached by stream
+	mergedRanges := MergeAdjacentDiskRanges(util.MapValues(missingRanges), be.options.GetMaxMergeDistance(), be.options.GetMaxBufferSize()).ToArray()
+	if be.options.IsLazyReadSmallRanges() {
+		for _, mergedRange := range mergedRanges {
+			mergedRangeLazyLoader := NewLazyBufferLoader(mergedRange, be.delegate)
+			for key, diskRange := range missingRanges {
+				if mergedRange.Contains(diskRange) {
+					slices[key] = newByteRangeCachingMothDataReader(NewMergedMothDataReader(be.GetId(), diskRange, mergedRangeLazyLoader), be.cache, be.rangeKey(diskRange))
+				}
+			}
+		}
+		return slices
+	}
+	buffers := make(map[*DiskRange]*slice.Slice, len(mergedRanges))
+	for _, mergedRange := range mergedRanges {
+		buffers[mergedRange] = be.delegate.ReadFully(mergedRange.GetOffset(), mergedRange.GetLength())
+	}
+	for key, diskRange := range missingRanges {
+		data := GetDiskRangeSlice(diskRange, buffers)
+		be.cache.put(be.rangeKey(diskRange), slice.NewWithBuf(data.AvailableBytes()))
+		slices[key] = NewMemoryMothDataReader(be.GetId(), data, int64(diskRange.GetLength()))
+	}
+	return slices
+}
+
+func (be *ByteRangeCachingMothDataSource) rangeKey(diskRange *DiskRange) ByteRangeKey {
+	return ByteRangeKey{file: be.key, offset: diskRange.GetOffset(), length: diskRange.GetLength()}
+}
+
+/**
+ * Closes the delegate, the cached ranges stay in the cache.
+ */
+// @Override
+func (be *ByteRangeCachingMothDataSource) Close() {
+	be.delegate.Close()
+}
+
+// @Override
+func (be *ByteRangeCachingMothDataSource) String() string {
+	return be.delegate.String()
+}
+
+/**
+ * A lazily loaded stream range of a merged range, which is cached when it is loaded. The bytes
+ * of the stream are copied, so the cache does not retain the merged range.
+ */
+type byteRangeCachingMothDataReader struct {
+	delegate *MergedMothDataReader
+	cache    *ByteRangeCache
+	key      ByteRangeKey
+	cached   bool
+}
+
+func newByteRangeCachingMothDataReader(delegate *MergedMothDataReader, cache *ByteRangeCache, key ByteRangeKey) *byteRangeCachingMothDataReader {
+	br := new(byteRangeCachingMothDataReader)
+	br.delegate = delegate
+	br.cache = cache
+	br.key = key
+	return br
+}
+
+// @Override
+func (br *byteRangeCachingMothDataReader) GetMothDataSourceId() *common.MothDataSourceId {
+	return br.delegate.GetMothDataSourceId()
+}
+
+// @Override
+func (br *byteRangeCachingMothDataReader) GetRetainedSize() int64 {
+	return br.delegate.GetRetainedSize()
+}
+
+// @Override
+func (br *byteRangeCachingMothDataReader) GetSize() int32 {
+	return br.delegate.GetSize()
+}
+
+// @Override
+func (br *byteRangeCachingMothDataReader) GetMaxBufferSize() int32 {
+	return br.delegate.GetMaxBufferSize()
+}
+
+// @Override
+func (br *byteRangeCachingMothDataReader) SeekBuffer(newPosition int32) *slice.Slice {
+	buffer := br.delegate.SeekBuffer(newPosition)
+	if !br.cached {
+		br.cache.put(br.key, slice.NewWithBuf(br.delegate.data.AvailableBytes()))
+		br.cached = true
+	}
+	return buffer
+}
+
+// @Override
+func (br *byteRangeCachingMothDataReader) String() string {
+	return br.delegate.String()
+}
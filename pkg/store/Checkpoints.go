@@ -1,8 +1,7 @@
 package store
 
 import (
-	"fmt"
-
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -26,7 +25,7 @@ func GetStreamCheckpoints(columns util.SetInterface[metadata.MothColumnId], colu
 		switch columnType {
 		case metadata.BOOLEAN:
 			util.PutAll(checkpoints, getBooleanColumnCheckpoints(columnId, compressed, availableStreams, columnPositionsList))
-		case metadata.BYTE:
+		case metadata.BYTE, metadata.UNION:
 			util.PutAll(checkpoints, getByteColumnCheckpoints(columnId, compressed, availableStreams, columnPositionsList))
 		case metadata.SHORT, metadata.INT, metadata.LONG, metadata.DATE:
 			util.PutAll(checkpoints, getLongColumnCheckpoints(columnId, columnEncoding, compressed, availableStreams, columnPositionsList))
@@ -63,7 +62,7 @@ func GetDictionaryStreamCheckpoint(streamId StreamId, columnType metadata.MothTy
 			return NewLongStreamV1Checkpoint(0, CreateInputStreamCheckpoint2(0, 0))
 		}
 	}
-	panic(fmt.Sprintf("Unsupported column type %d for dictionary stream %s", columnType, streamId))
+	panic(common.NewMothUnsupportedError(nil, "Unsupported column type %d for dictionary stream %s", columnType, streamId))
 }
 
 func getBooleanColumnCheckpoints(columnId metadata.MothColumnId, compressed bool, availableStreams util.SetInterface[metadata.StreamKind], positionsList *ColumnPositionsList) map[StreamId]StreamCheckpoint {
@@ -152,7 +151,7 @@ func getSliceColumnCheckpoints(columnId metadata.MothColumnId, encoding metadata
 			checkpoints[NewStreamId(columnId, metadata.DATA)] = createLongStreamCheckpoint(encoding, compressed, positionsList)
 		}
 	} else {
-		panic(fmt.Sprintf("Unsupported encoding for slice column: %d", encoding))
+		panic(common.NewMothUnsupportedError(nil, "Unsupported encoding for slice column: %d", encoding))
 	}
 	return checkpoints
 }
@@ -197,7 +196,7 @@ func createLongStreamCheckpoint(encoding metadata.ColumnEncodingKind, compressed
 	if encoding == metadata.DIRECT || encoding == metadata.DICTIONARY {
 		return NewLongStreamV1Checkpoint2(compressed, positionsList)
 	}
-	panic(fmt.Sprintf("Unsupported encoding for long stream: %d", encoding))
+	panic(common.NewMothUnsupportedError(nil, "Unsupported encoding for long stream: %d", encoding))
 }
 
 type ColumnPositionsList struct {
@@ -225,7 +224,7 @@ func (ct *ColumnPositionsList) HasNextPosition() bool {
 
 func (ct *ColumnPositionsList) NextPosition() int32 {
 	if !ct.HasNextPosition() {
-		panic(fmt.Sprintf("Not enough positions for column %s:%d checkpoints", ct.columnId.String(), ct.columnType))
+		panic(common.NewMothCorruptionError(nil, "Not enough positions for column %s:%d checkpoints", ct.columnId.String(), ct.columnType))
 	}
 	position := ct.positionsList.Get(int(ct.index))
 	ct.index++
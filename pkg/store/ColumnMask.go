@@ -0,0 +1,68 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+var (
+	// every value is null, supported for all types
+	NULLIFY_MASK = "nullify"
+	// every character is replaced with the mask character, the optional parameter, default "X"
+	REDACT_MASK = "redact"
+	// values are replaced with the hex encoded SHA-256 of the value
+	SHA256_MASK = "sha256"
+)
+
+/**
+ * Produces the masked copy of an encrypted column, which readers without the key read instead of
+ * the encrypted values. Null values stay null.
+ */
+type ColumnMask interface {
+	Mask(b block.Block) block.Block
+}
+
+/**
+ * Types whose values are accessed as slices, the only types redact and sha256 can mask
+ */
+type sliceType interface {
+	block.Type
+
+	GetSlice(b block.Block, position int32) *slice.Slice
+	WriteSlice(blockBuilder block.BlockBuilder, value *slice.Slice)
+}
+
+func CreateColumnMask(dataMask *metadata.DataMask, kind block.Type) ColumnMask {
+	if dataMask.GetName() == NULLIFY_MASK {
+		return NewNullifyColumnMask(kind)
+	}
+	if dataMask.GetName() != REDACT_MASK && dataMask.GetName() != SHA256_MASK {
+		panic(fmt.Sprintf("Unknown mask %s", dataMask.GetName()))
+	}
+	var maxLength int32 = -1
+	switch t := kind.(type) {
+	case *block.VarcharType:
+		if !t.IsUnbounded() {
+			maxLength = t.GetBoundedLength()
+		}
+	case *block.CharType:
+		maxLength = t.GetLength()
+	case *block.VarbinaryType:
+		if dataMask.GetName() == REDACT_MASK {
+			panic(fmt.Sprintf("Mask %s is not supported for type %s", dataMask.GetName(), kind.GetDisplayName()))
+		}
+	default:
+		panic(fmt.Sprintf("Mask %s is not supported for type %s", dataMask.GetName(), kind.GetDisplayName()))
+	}
+	if dataMask.GetName() == REDACT_MASK {
+		replacement := "X"
+		if !dataMask.GetParameters().IsEmpty() {
+			replacement = dataMask.GetParameters().Get(0)
+		}
+		return NewRedactColumnMask(kind.(sliceType), replacement)
+	}
+	return NewSha256ColumnMask(kind.(sliceType), maxLength)
+}
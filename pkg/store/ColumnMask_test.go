@@ -0,0 +1,64 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func TestColumnMask_Mask(t *testing.T) {
+	keyProvider := encryption.NewInMemoryKeyProvider().AddKey("pii", 1, metadata.AES_CTR_256, []byte("0123456789abcdef0123456789abcdef"))
+	sha256Hex := func(value string) string {
+		hash := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(hash[:])
+	}
+	tests := []struct {
+		name     string
+		mask     *metadata.DataMask
+		wantMask string
+		want     func(value string) string
+	}{
+		{"default", nil, NULLIFY_MASK, func(value string) string { return "" }},
+		{"nullify", metadata.NewDataMask(NULLIFY_MASK, util.NewArrayList[string](), util.NewArrayList[metadata.MothColumnId]()), NULLIFY_MASK, func(value string) string { return "" }},
+		{"redact", metadata.NewDataMask(REDACT_MASK, util.NewArrayList("*"), util.NewArrayList[metadata.MothColumnId]()), REDACT_MASK, func(value string) string { return "***********" }},
+		{"sha256", metadata.NewDataMask(SHA256_MASK, util.NewArrayList[string](), util.NewArrayList[metadata.MothColumnId]()), SHA256_MASK, sha256Hex},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := NewMothWriterOptions().WithStripeMaxRowCount(1000).WithColumnEncryption(map[string]string{"name": "pii"}).WithKeyProvider(keyProvider)
+			if tt.mask != nil {
+				options = options.WithColumnMasks(map[string]*metadata.DataMask{"name": tt.mask})
+			}
+			data := writeTestFile(1500, options, metadata.ZSTD)
+
+			reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), NewMothReaderOptions()).Get()
+			if mask := reader.GetColumnMask(metadata.MothColumnId(2)); mask == nil || mask.GetName() != tt.wantMask {
+				t.Fatalf("GetColumnMask() = %v, want %s", mask, tt.wantMask)
+			}
+			if mask := reader.GetColumnMask(metadata.MothColumnId(1)); mask != nil {
+				t.Errorf("unencrypted column has mask %s", mask.GetName())
+			}
+			for i, name := range readNames(t, reader) {
+				if want := tt.want(fmt.Sprintf("name-%06d", i)); name != want {
+					t.Fatalf("row %d: masked name = %q, want %q", i, name, want)
+				}
+			}
+
+			reader = CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), NewMothReaderOptions().WithKeyProvider(keyProvider)).Get()
+			if mask := reader.GetColumnMask(metadata.MothColumnId(2)); mask != nil {
+				t.Errorf("decrypted column has mask %s", mask.GetName())
+			}
+			for i, name := range readNames(t, reader) {
+				if want := fmt.Sprintf("name-%06d", i); name != want {
+					t.Fatalf("row %d: name = %q, want %q", i, name, want)
+				}
+			}
+		})
+	}
+}
@@ -1,10 +1,9 @@
 package store
 
 import (
-	"fmt"
-
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 )
 
@@ -13,7 +12,7 @@ func CreateColumnReader(kind block.Type, column *MothColumn, projectedLayout Pro
 	_, flag := kind.(*block.TimeType)
 	if flag {
 		if !kind.Equals(block.TIME_MICROS) || column.GetColumnType() != metadata.LONG || "TIME" != (column.GetAttributes()["iceberg.long-type"]) {
-			panic(fmt.Sprintf("Cannot read SQL type '%s' from MOTH stream '%s' of type %d with attributes %s", kind, column.GetPath(), column.GetColumnType(), column.GetAttributes()))
+			panic(common.NewMothUnsupportedError(column.GetMothDataSourceId(), "Cannot read SQL type '%s' from MOTH stream '%s' of type %d with attributes %s", kind, column.GetPath(), column.GetColumnType(), column.GetAttributes()))
 		}
 		return NewTimeColumnReader(kind, column, memoryContext.NewLocalMemoryContext("ColumnReaders"))
 	}
@@ -43,5 +42,5 @@ func CreateColumnReader(kind block.Type, column *MothColumn, projectedLayout Pro
 	case metadata.UNION:
 		return NewUnionColumnReader(kind, column, memoryContext, blockFactory, fieldMapperFactory)
 	}
-	panic(fmt.Sprintf("Unsupported type: %d", column.GetColumnType()))
+	panic(common.NewMothUnsupportedError(column.GetMothDataSourceId(), "Unsupported type: %d", column.GetColumnType()))
 }
@@ -9,66 +9,86 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
+/**
+ * Supplier which creates a new value on every call, column writers use it to
+ * start a fresh statistics builder for each row group.
+ */
 type DataSupplier[T basic.Object] struct {
-	data T
+	supplier func() T
 }
 
 func (s *DataSupplier[T]) Get() T {
-	return s.data
+	return s.supplier()
 }
 
-func NewDataSupplier[T basic.Object](data T) *DataSupplier[T] {
-	return &DataSupplier[T]{data: data}
+func NewDataSupplier[T basic.Object](supplier func() T) *DataSupplier[T] {
+	return &DataSupplier[T]{supplier: supplier}
 }
 
-func CreateColumnWriter(columnId metadata.MothColumnId, mothTypes *metadata.ColumnMetadata[*metadata.MothType], kind block.Type, compression metadata.CompressionKind, bufferSize int32, stringStatisticsLimit util.DataSize, bloomFilterBuilder func() metadata.BloomFilterBuilder) ColumnWriter {
+func CreateColumnWriter(columnId metadata.MothColumnId, mothTypes *metadata.ColumnMetadata[*metadata.MothType], kind block.Type, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, stringStatisticsLimit util.DataSize, bloomFilterBuilder func() metadata.BloomFilterBuilder) ColumnWriter {
 	mothType := mothTypes.Get(columnId)
 
 	_, flag := kind.(*block.TimeType)
 	if flag {
-		var tmp metadata.LongValueStatisticsBuilder = metadata.NewIntegerStatisticsBuilder(bloomFilterBuilder())
-		return NewTimeColumnWriter(columnId, kind, compression, bufferSize, NewDataSupplier(tmp))
+		tmp := func() metadata.LongValueStatisticsBuilder {
+			return metadata.NewIntegerStatisticsBuilder(bloomFilterBuilder())
+		}
+		return NewTimeColumnWriter(columnId, kind, compression, compressionLevel, bufferSize, NewDataSupplier(tmp))
 	}
 	switch mothType.GetMothTypeKind() {
 	case metadata.BOOLEAN:
-		return NewBooleanColumnWriter(columnId, kind, compression, bufferSize)
+		return NewBooleanColumnWriter(columnId, kind, compression, compressionLevel, bufferSize)
 	case metadata.FLOAT:
-		tmp := metadata.NewDoubleStatisticsBuilder(bloomFilterBuilder())
-		return NewFloatColumnWriter(columnId, kind, compression, bufferSize, NewDataSupplier(tmp))
+		tmp := func() *metadata.DoubleStatisticsBuilder {
+			return metadata.NewDoubleStatisticsBuilder(bloomFilterBuilder())
+		}
+		return NewFloatColumnWriter(columnId, kind, compression, compressionLevel, bufferSize, NewDataSupplier(tmp))
 	case metadata.DOUBLE:
-		tmp := metadata.NewDoubleStatisticsBuilder(bloomFilterBuilder())
-		return NewDoubleColumnWriter(columnId, kind, compression, bufferSize, NewDataSupplier(tmp))
+		tmp := func() *metadata.DoubleStatisticsBuilder {
+			return metadata.NewDoubleStatisticsBuilder(bloomFilterBuilder())
+		}
+		return NewDoubleColumnWriter(columnId, kind, compression, compressionLevel, bufferSize, NewDataSupplier(tmp))
 	case metadata.BYTE:
-		return NewByteColumnWriter(columnId, kind, compression, bufferSize)
+		return NewByteColumnWriter(columnId, kind, compression, compressionLevel, bufferSize)
 	case metadata.DATE:
-		var tmp metadata.LongValueStatisticsBuilder = metadata.NewDateStatisticsBuilder(bloomFilterBuilder())
-		return NewLongColumnWriter(columnId, kind, compression, bufferSize, NewDataSupplier(tmp))
+		tmp := func() metadata.LongValueStatisticsBuilder {
+			return metadata.NewDateStatisticsBuilder(bloomFilterBuilder())
+		}
+		return NewLongColumnWriter(columnId, kind, compression, compressionLevel, bufferSize, NewDataSupplier(tmp))
 	case metadata.SHORT, metadata.INT, metadata.LONG:
-		var tmp metadata.LongValueStatisticsBuilder = metadata.NewIntegerStatisticsBuilder(bloomFilterBuilder())
-		return NewLongColumnWriter(columnId, kind, compression, bufferSize, NewDataSupplier(tmp))
+		tmp := func() metadata.LongValueStatisticsBuilder {
+			return metadata.NewIntegerStatisticsBuilder(bloomFilterBuilder())
+		}
+		return NewLongColumnWriter(columnId, kind, compression, compressionLevel, bufferSize, NewDataSupplier(tmp))
 	case metadata.DECIMAL:
-		return NewDecimalColumnWriter(columnId, kind, compression, bufferSize)
+		return NewDecimalColumnWriter(columnId, kind, compression, compressionLevel, bufferSize)
 	case metadata.TIMESTAMP, metadata.TIMESTAMP_INSTANT:
-		tmp := metadata.NewTimestampStatisticsBuilder(bloomFilterBuilder())
-		return NewTimestampColumnWriter(columnId, kind, compression, bufferSize, NewDataSupplier(tmp))
+		tmp := func() *metadata.TimestampStatisticsBuilder {
+			return metadata.NewTimestampStatisticsBuilder(bloomFilterBuilder())
+		}
+		return NewTimestampColumnWriter(columnId, kind, compression, compressionLevel, bufferSize, NewDataSupplier(tmp))
 	case metadata.BINARY:
-		var tmp metadata.SliceColumnStatisticsBuilder = metadata.NewBinaryStatisticsBuilder()
-		return NewSliceDirectColumnWriter(columnId, kind, compression, bufferSize, NewDataSupplier(tmp))
+		tmp := func() metadata.SliceColumnStatisticsBuilder {
+			return metadata.NewBinaryStatisticsBuilder()
+		}
+		return NewSliceDirectColumnWriter(columnId, kind, compression, compressionLevel, bufferSize, NewDataSupplier(tmp))
 	case metadata.CHAR, metadata.VARCHAR, metadata.STRING:
-		var tmp metadata.SliceColumnStatisticsBuilder = metadata.NewStringStatisticsBuilder(util.Int32Exact(int64(stringStatisticsLimit.Bytes())), bloomFilterBuilder())
-		return NewSliceDictionaryColumnWriter(columnId, kind, compression, bufferSize, NewDataSupplier(tmp))
+		tmp := func() metadata.SliceColumnStatisticsBuilder {
+			return metadata.NewStringStatisticsBuilder(util.Int32Exact(int64(stringStatisticsLimit.Bytes())), bloomFilterBuilder())
+		}
+		return NewSliceDictionaryColumnWriter(columnId, kind, compression, compressionLevel, bufferSize, NewDataSupplier(tmp))
 	case metadata.LIST:
 		{
 			fieldColumnIndex := mothType.GetFieldTypeIndex(0)
 			fieldType := kind.GetTypeParameters().Get(0)
-			elementWriter := CreateColumnWriter(fieldColumnIndex, mothTypes, fieldType, compression, bufferSize, stringStatisticsLimit, bloomFilterBuilder)
-			return NewListColumnWriter(columnId, compression, bufferSize, elementWriter)
+			elementWriter := CreateColumnWriter(fieldColumnIndex, mothTypes, fieldType, compression, compressionLevel, bufferSize, stringStatisticsLimit, bloomFilterBuilder)
+			return NewListColumnWriter(columnId, compression, compressionLevel, bufferSize, elementWriter)
 		}
 	case metadata.MAP:
 		{
-			keyWriter := CreateColumnWriter(mothType.GetFieldTypeIndex(0), mothTypes, kind.GetTypeParameters().Get(0), compression, bufferSize, stringStatisticsLimit, bloomFilterBuilder)
-			valueWriter := CreateColumnWriter(mothType.GetFieldTypeIndex(1), mothTypes, kind.GetTypeParameters().Get(1), compression, bufferSize, stringStatisticsLimit, bloomFilterBuilder)
-			return NewMapColumnWriter(columnId, compression, bufferSize, keyWriter, valueWriter)
+			keyWriter := CreateColumnWriter(mothType.GetFieldTypeIndex(0), mothTypes, kind.GetTypeParameters().Get(0), compression, compressionLevel, bufferSize, stringStatisticsLimit, bloomFilterBuilder)
+			valueWriter := CreateColumnWriter(mothType.GetFieldTypeIndex(1), mothTypes, kind.GetTypeParameters().Get(1), compression, compressionLevel, bufferSize, stringStatisticsLimit, bloomFilterBuilder)
+			return NewMapColumnWriter(columnId, compression, compressionLevel, bufferSize, keyWriter, valueWriter)
 		}
 	case metadata.STRUCT:
 		{
@@ -76,12 +96,20 @@ func CreateColumnWriter(columnId metadata.MothColumnId, mothTypes *metadata.Colu
 			for fieldId := util.INT32_ZERO; fieldId < mothType.GetFieldCount(); fieldId++ {
 				fieldColumnIndex := mothType.GetFieldTypeIndex(fieldId)
 				fieldType := kind.GetTypeParameters().GetByInt32(fieldId)
-				fieldWriters.Add(CreateColumnWriter(fieldColumnIndex, mothTypes, fieldType, compression, bufferSize, stringStatisticsLimit, bloomFilterBuilder))
+				fieldWriters.Add(CreateColumnWriter(fieldColumnIndex, mothTypes, fieldType, compression, compressionLevel, bufferSize, stringStatisticsLimit, bloomFilterBuilder))
 			}
-			return NewStructColumnWriter(columnId, compression, bufferSize, fieldWriters)
+			return NewStructColumnWriter(columnId, compression, compressionLevel, bufferSize, fieldWriters)
 		}
 	case metadata.UNION:
-		// unsupported
+		{
+			variantWriters := util.NewArrayList[ColumnWriter]()
+			for variantId := util.INT32_ZERO; variantId < mothType.GetFieldCount(); variantId++ {
+				// the first field of the union row is the tag
+				variantType := kind.GetTypeParameters().GetByInt32(variantId + 1)
+				variantWriters.Add(CreateColumnWriter(mothType.GetFieldTypeIndex(variantId), mothTypes, variantType, compression, compressionLevel, bufferSize, stringStatisticsLimit, bloomFilterBuilder))
+			}
+			return NewUnionColumnWriter(columnId, compression, compressionLevel, bufferSize, variantWriters)
+		}
 	}
 	panic(fmt.Sprintf("Unsupported kind: %s ,MothTypeKind %d", kind.GetDisplayName(), mothType.GetMothTypeKind()))
 }
@@ -11,10 +11,10 @@ type CompressedMetadataWriter struct {
 	buffer         *MothOutputBuffer
 }
 
-func NewCompressedMetadataWriter(metadataWriter metadata.MetadataWriter, compression metadata.CompressionKind, bufferSize int32) *CompressedMetadataWriter {
+func NewCompressedMetadataWriter(metadataWriter metadata.MetadataWriter, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) *CompressedMetadataWriter {
 	cr := new(CompressedMetadataWriter)
 	cr.metadataWriter = metadataWriter
-	cr.buffer = NewMothOutputBuffer(compression, bufferSize)
+	cr.buffer = NewMothOutputBuffer(compression, compressionLevel, bufferSize)
 	return cr
 }
 
@@ -38,6 +38,11 @@ func (cr *CompressedMetadataWriter) WriteFooter(footer *metadata.Footer) *slice.
 	return cr.getSliceOutput()
 }
 
+func (cr *CompressedMetadataWriter) WriteFileStatistics(fileStatistics *metadata.ColumnMetadata[*metadata.ColumnStatistics]) *slice.Slice {
+	cr.metadataWriter.WriteFileStatistics(cr.buffer, fileStatistics)
+	return cr.getSliceOutput()
+}
+
 func (cr *CompressedMetadataWriter) WriteStripeFooter(footer *metadata.StripeFooter) *slice.Slice {
 	cr.metadataWriter.WriteStripeFooter(cr.buffer, footer)
 	return cr.getSliceOutput()
@@ -53,6 +58,16 @@ func (cr *CompressedMetadataWriter) WriteBloomFilters(bloomFilters *util.ArrayLi
 	return cr.getSliceOutput()
 }
 
+func (cr *CompressedMetadataWriter) WriteDeleteKeys(deleteKeys *util.ArrayList[*metadata.DeleteKey]) *slice.Slice {
+	cr.metadataWriter.WriteDeleteKeys(cr.buffer, deleteKeys)
+	return cr.getSliceOutput()
+}
+
+func (cr *CompressedMetadataWriter) WriteKeys(keys *util.ArrayList[*metadata.Key]) *slice.Slice {
+	cr.metadataWriter.WriteKeys(cr.buffer, keys)
+	return cr.getSliceOutput()
+}
+
 func (cr *CompressedMetadataWriter) getSliceOutput() *slice.Slice {
 	cr.buffer.Close()
 	output := slice.NewDynamicSliceOutput(util.Int32Exact(cr.buffer.GetOutputDataSize()))
@@ -1,8 +1,6 @@
 package store
 
 import (
-	"fmt"
-
 	"github.com/mothdb-bd/orc-go/pkg/maths"
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/slice"
@@ -60,7 +58,7 @@ func (cr *CompressedMothChunkLoader) GetLastCheckpoint() int64 {
 func (cr *CompressedMothChunkLoader) SeekToCheckpoint(checkpoint int64) {
 	compressedOffset := DecodeCompressedBlockOffset(checkpoint)
 	if compressedOffset >= cr.dataReader.GetSize() {
-		panic(fmt.Sprintf("%s Seek past end of stream", cr.dataReader.GetMothDataSourceId()))
+		panic(common.NewMothCorruptionError(cr.dataReader.GetMothDataSourceId(), "Seek past end of stream"))
 	}
 	if cr.compressedBufferStart <= compressedOffset && compressedOffset < cr.compressedBufferStart+int32(cr.compressedBufferStream.Length()) {
 		cr.compressedBufferStream.SetPosition(int64(compressedOffset - cr.compressedBufferStart))
@@ -103,16 +101,16 @@ func (cr *CompressedMothChunkLoader) ensureCompressedBytesAvailable(size int32)
 		return
 	}
 	if size > cr.dataReader.GetMaxBufferSize() {
-		panic(fmt.Sprintf("Requested read size (%d bytes) is greater than max buffer size (%d bytes", size, cr.dataReader.GetMaxBufferSize()))
+		panic(common.NewMothCorruptionError(cr.dataReader.GetMothDataSourceId(), "Requested read size (%d bytes) is greater than max buffer size (%d bytes)", size, cr.dataReader.GetMaxBufferSize()))
 	}
 	if cr.compressedBufferStart+int32(cr.compressedBufferStream.Position())+size > cr.dataReader.GetSize() {
-		panic("Read past end of stream")
+		panic(common.NewMothCorruptionError(cr.dataReader.GetMothDataSourceId(), "Read past end of stream"))
 	}
 	cr.compressedBufferStart = cr.compressedBufferStart + util.Int32Exact(cr.compressedBufferStream.Position())
 	compressedBuffer := cr.dataReader.SeekBuffer(cr.compressedBufferStart)
 	cr.dataReaderMemoryUsage.SetBytes(cr.dataReader.GetRetainedSize())
 	if compressedBuffer.Length() < size {
-		panic(fmt.Sprintf("Requested read of %d bytes but only %d were bytes", size, compressedBuffer.SizeInt32()))
+		panic(common.NewMothCorruptionError(cr.dataReader.GetMothDataSourceId(), "Requested read of %d bytes but only %d were bytes", size, compressedBuffer.SizeInt32()))
 	}
 	cr.compressedBufferStream = compressedBuffer.GetInput()
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -61,7 +62,7 @@ func (dr *DecimalColumnReader) ReadBlock() block.Block {
 	var b block.Block
 	if dr.decimalStream == nil && dr.scaleStream == nil {
 		if dr.presentStream == nil {
-			panic("Value is null but present stream is missing")
+			panic(common.NewMothCorruptionError(dr.column.GetMothDataSourceId(), "Value is null but present stream is missing"))
 		}
 		dr.presentStream.Skip(int64(dr.nextBatchSize))
 		b = block.CreateRunLengthEncodedBlock(dr.kind, nil, dr.nextBatchSize)
@@ -77,7 +78,7 @@ func (dr *DecimalColumnReader) ReadBlock() block.Block {
 		} else if nullCount != dr.nextBatchSize {
 			b = dr.readNullBlock(isNull, dr.nextBatchSize-nullCount)
 		} else {
-			b = block.CreateRunLengthEncodedBlock(block.DOUBLE, nil, dr.nextBatchSize)
+			b = block.CreateRunLengthEncodedBlock(dr.kind, nil, dr.nextBatchSize)
 		}
 	}
 	dr.readOffset = 0
@@ -87,10 +88,10 @@ func (dr *DecimalColumnReader) ReadBlock() block.Block {
 
 func (dr *DecimalColumnReader) checkDataStreamsArePresent() {
 	if dr.decimalStream == nil {
-		panic("Value is not null but decimal stream is missing")
+		panic(common.NewMothCorruptionError(dr.column.GetMothDataSourceId(), "Value is not null but decimal stream is missing"))
 	}
 	if dr.scaleStream == nil {
-		panic("Value is not null but scale stream is missing")
+		panic(common.NewMothCorruptionError(dr.column.GetMothDataSourceId(), "Value is not null but scale stream is missing"))
 	}
 }
 
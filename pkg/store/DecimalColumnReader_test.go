@@ -0,0 +1,69 @@
+package store
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func TestDecimalColumnReader_Nulls(t *testing.T) {
+	// the first rows have values, so the batches of the later rows are all null
+	decimalType := block.CreateDecimalType(20, 2)
+	out := new(memoryWriteCloser)
+	types := util.NewArrayList[block.Type](decimalType, block.VARCHAR)
+	columnNames := util.NewArrayList("price", "name")
+	writer := NewMothWriter(NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), columnNames, types, metadata.CreateRootMothType(columnNames, types), metadata.NONE, NewMothWriterOptions(), util.EmptyMap[string, string](), NewMothWriterStats())
+	pb := spi.NewPageBuilder(types)
+	for i := 0; i < 2000; i++ {
+		pb.DeclarePosition()
+		if i < 10 {
+			decimalType.WriteObject(pb.GetBlockBuilder(0), block.I128From64(int64(i)))
+		} else {
+			pb.GetBlockBuilder(0).AppendNull()
+		}
+		block.WriteNativeValue(block.VARCHAR, pb.GetBlockBuilder(1), "name")
+	}
+	writer.Write(pb.Build())
+	writer.Close()
+
+	reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), slice.NewWithBuf(out.Bytes())), NewMothReaderOptions()).Get()
+	recordReader := reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns(), types, TRUE, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+	defer recordReader.Close()
+	row, nullBatches := 0, 0
+	for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+		priceBlock := page.GetBlock(0).GetLoadedBlock()
+		if rle, ok := priceBlock.(*block.RunLengthEncodedBlock); ok {
+			// the values of long decimals are 128 bit
+			if _, ok := rle.GetValue().(*block.Int128ArrayBlock); !ok {
+				t.Fatalf("read the nulls of %s as %s", decimalType.GetDisplayName(), reflect.TypeOf(rle.GetValue()))
+			}
+			nullBatches++
+		}
+		for position := util.INT32_ZERO; position < page.GetPositionCount(); position++ {
+			if isNull := priceBlock.IsNull(position); isNull != (row >= 10) {
+				t.Fatalf("read null %t at row %d", isNull, row)
+			}
+			if row < 10 {
+				value := new(big.Int)
+				decimalType.GetObject(priceBlock, position).(*block.Int128).IntoBigInt(value)
+				if value.Int64() != int64(row) {
+					t.Fatalf("read %s at row %d", value, row)
+				}
+			}
+			row++
+		}
+	}
+	if row != 2000 || nullBatches == 0 {
+		t.Fatalf("read %d rows with %d null batches", row, nullBatches)
+	}
+}
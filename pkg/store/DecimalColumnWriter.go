@@ -27,15 +27,15 @@ type DecimalColumnWriter struct {
 	closed                        bool
 }
 
-func NewDecimalColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, bufferSize int32) *DecimalColumnWriter {
+func NewDecimalColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) *DecimalColumnWriter {
 	dr := new(DecimalColumnWriter)
 	dr.columnId = columnId
 	dr.kind = kind.(block.IDecimalType)
 	dr.compressed = compression != metadata.NONE
 	dr.columnEncoding = metadata.NewColumnEncoding(metadata.DIRECT_V2, 0)
-	dr.dataStream = NewDecimalOutputStream(compression, bufferSize)
-	dr.scaleStream = NewLongOutputStreamV2(compression, bufferSize, true, metadata.SECONDARY)
-	dr.presentStream = NewPresentOutputStream(compression, bufferSize)
+	dr.dataStream = NewDecimalOutputStream(compression, compressionLevel, bufferSize)
+	dr.scaleStream = NewLongOutputStreamV2(compression, compressionLevel, bufferSize, true, metadata.SECONDARY)
+	dr.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 	if dr.kind.IsShort() {
 		dr.shortDecimalStatisticsBuilder = metadata.NewShortDecimalStatisticsBuilder(dr.kind.GetScale())
 	} else {
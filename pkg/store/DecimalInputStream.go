@@ -3,6 +3,7 @@ package store
 import (
 	"github.com/mothdb-bd/orc-go/pkg/maths"
 	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
@@ -96,7 +97,7 @@ func (dm *DecimalInputStream) NextLongDecimal(result []int64, batchSize int32) {
 					high |= int64(maths.UnsignedRightShiftInt32((last & 0x7F), 0))
 					high = high & ((1 << (end * 7)) - 1)
 					if end == 4 || high > 0xFF_FF {
-						panic("Decimal exceeds 128 bits")
+						panic(common.NewMothCorruptionError(nil, "Decimal exceeds 128 bits"))
 					}
 				}
 			}
@@ -135,12 +136,12 @@ func (dm *DecimalInputStream) decodeLongDecimalTail(result []int64, count int32,
 		} else if offset < 19 {
 			high |= (value & 0x7F) << ((offset - 16) * 7)
 		} else {
-			panic("Decimal exceeds 128 bits")
+			panic(common.NewMothCorruptionError(nil, "Decimal exceeds 128 bits"))
 		}
 		offset++
 		if (value & 0x80) == 0 {
 			if high > 0xFF_FF {
-				panic("Decimal exceeds 128 bits")
+				panic(common.NewMothCorruptionError(nil, "Decimal exceeds 128 bits"))
 			}
 			emitLongDecimal(result, count, low, middle, high, negative)
 			count++
@@ -222,7 +223,7 @@ func (dm *DecimalInputStream) NextShortDecimal(result []int64, batchSize int32)
 				high |= int64(maths.UnsignedRightShiftInt32(int32(uint32(last)&0x7F), 0))
 				high = high & ((1 << (end * 7)) - 1)
 				if end >= 3 || high > 0xFF {
-					panic("Decimal does not fit long (invalid table schema?)")
+					panic(common.NewMothCorruptionError(nil, "Decimal does not fit long (invalid table schema?)"))
 				}
 			}
 			emitShortDecimal(result, count, low, high)
@@ -256,12 +257,12 @@ func (dm *DecimalInputStream) decodeShortDecimalTail(result []int64, count int32
 		} else if offset < 11 {
 			high |= (value & 0x7F) << ((offset - 8) * 7)
 		} else {
-			panic("Decimal does not fit long (invalid table schema?)")
+			panic(common.NewMothCorruptionError(nil, "Decimal does not fit long (invalid table schema?)"))
 		}
 		offset++
 		if (value & 0x80) == 0 {
 			if high > 0xFF {
-				panic("Decimal does not fit long (invalid table schema?)")
+				panic(common.NewMothCorruptionError(nil, "Decimal does not fit long (invalid table schema?)"))
 			}
 			emitShortDecimal(result, count, low, high)
 			count++
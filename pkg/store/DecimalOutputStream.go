@@ -20,9 +20,9 @@ type DecimalOutputStream struct {
 	closed      bool
 }
 
-func NewDecimalOutputStream(compression metadata.CompressionKind, bufferSize int32) *DecimalOutputStream {
+func NewDecimalOutputStream(compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) *DecimalOutputStream {
 	dm := new(DecimalOutputStream)
-	dm.buffer = NewMothOutputBuffer(compression, bufferSize)
+	dm.buffer = NewMothOutputBuffer(compression, compressionLevel, bufferSize)
 	dm.checkpoints = util.NewArrayList[*DecimalStreamCheckpoint]()
 	return dm
 }
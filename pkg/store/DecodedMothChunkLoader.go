@@ -0,0 +1,125 @@
+package store
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Chunk loader that can read and decompress all chunks of its delegate ahead of time, so the
+ * expensive part of reading a stream can run on another goroutine than the one consuming it.
+ * Until Decode is called all calls are forwarded to the delegate.
+ */
+type DecodedMothChunkLoader struct {
+	// 继承
+	MothChunkLoader
+
+	delegate           MothChunkLoader
+	decodedMemoryUsage memory.LocalMemoryContext
+	decoded            bool
+	chunks             []*slice.Slice
+	checkpoints        []int64
+	nextChunk          int
+	nextChunkOffset    int32
+	lastCheckpoint     int64
+}
+
+func NewDecodedMothChunkLoader(delegate MothChunkLoader, memoryContext memory.AggregatedMemoryContext) *DecodedMothChunkLoader {
+	dr := new(DecodedMothChunkLoader)
+	dr.delegate = delegate
+	dr.decodedMemoryUsage = memoryContext.NewLocalMemoryContext("DecodedMothChunkLoader")
+	return dr
+}
+
+/**
+ * Reads all chunks of the delegate and keeps a copy of them, the delegate buffers are reused
+ * between chunks. Must be called before the first chunk is read.
+ */
+func (dr *DecodedMothChunkLoader) Decode() {
+	if dr.decoded {
+		return
+	}
+	retainedBytes := util.INT64_ZERO
+	for dr.delegate.HasNextChunk() {
+		chunk := dr.delegate.NextChunk()
+		dr.chunks = append(dr.chunks, slice.NewWithBuf(chunk.AvailableBytes()))
+		dr.checkpoints = append(dr.checkpoints, dr.delegate.GetLastCheckpoint())
+		retainedBytes += int64(chunk.Length())
+	}
+	dr.decodedMemoryUsage.SetBytes(retainedBytes)
+	dr.decoded = true
+}
+
+// @Override
+func (dr *DecodedMothChunkLoader) GetMothDataSourceId() *common.MothDataSourceId {
+	return dr.delegate.GetMothDataSourceId()
+}
+
+// @Override
+func (dr *DecodedMothChunkLoader) HasNextChunk() bool {
+	if !dr.decoded {
+		return dr.delegate.HasNextChunk()
+	}
+	return dr.nextChunk < len(dr.chunks)
+}
+
+// @Override
+func (dr *DecodedMothChunkLoader) NextChunk() *slice.Slice {
+	if !dr.decoded {
+		return dr.delegate.NextChunk()
+	}
+	if dr.nextChunk >= len(dr.chunks) {
+		panic(common.NewMothCorruptionError(dr.GetMothDataSourceId(), "Read past end of stream"))
+	}
+	chunk := dr.chunks[dr.nextChunk]
+	checkpoint := dr.checkpoints[dr.nextChunk]
+	dr.lastCheckpoint = CreateInputStreamCheckpoint2(DecodeCompressedBlockOffset(checkpoint), DecodeDecompressedOffset(checkpoint)+dr.nextChunkOffset)
+	if dr.nextChunkOffset != 0 {
+		chunk, _ = chunk.MakeSlice(int(dr.nextChunkOffset), int(chunk.Length()-dr.nextChunkOffset))
+	}
+	dr.nextChunk++
+	dr.nextChunkOffset = 0
+	return chunk
+}
+
+// @Override
+func (dr *DecodedMothChunkLoader) GetLastCheckpoint() int64 {
+	if !dr.decoded {
+		return dr.delegate.GetLastCheckpoint()
+	}
+	return dr.lastCheckpoint
+}
+
+// @Override
+func (dr *DecodedMothChunkLoader) SeekToCheckpoint(checkpoint int64) {
+	if !dr.decoded {
+		dr.delegate.SeekToCheckpoint(checkpoint)
+		return
+	}
+	compressedOffset := DecodeCompressedBlockOffset(checkpoint)
+	decompressedOffset := DecodeDecompressedOffset(checkpoint)
+	for i, chunkCheckpoint := range dr.checkpoints {
+		chunkStart := DecodeDecompressedOffset(chunkCheckpoint)
+		chunkEnd := chunkStart + dr.chunks[i].Length()
+		if DecodeCompressedBlockOffset(chunkCheckpoint) != compressedOffset || decompressedOffset < chunkStart || decompressedOffset > chunkEnd {
+			continue
+		}
+		if decompressedOffset == chunkEnd {
+			dr.nextChunk = i + 1
+			dr.nextChunkOffset = 0
+		} else {
+			dr.nextChunk = i
+			dr.nextChunkOffset = decompressedOffset - chunkStart
+		}
+		dr.lastCheckpoint = checkpoint
+		return
+	}
+	panic(common.NewMothCorruptionError(dr.GetMothDataSourceId(), "Seek past end of stream"))
+}
+
+// @Override
+func (dr *DecodedMothChunkLoader) String() string {
+	return util.NewSB().AddString("loader", dr.delegate.String()).AddBool("decoded", dr.decoded).AddInt32("chunks", int32(len(dr.chunks))).String()
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * An encryption variant the reader holds the master key for. The columns of the variant are read
+ * from the encrypted streams instead of the masked copy.
+ */
+type DecryptedVariant struct {
+	variantIndex int32
+	root         metadata.MothColumnId
+	columnCount  int32
+	localKey     []byte
+}
+
+func NewDecryptedVariant(variantIndex int32, root metadata.MothColumnId, columnCount int32, localKey []byte) *DecryptedVariant {
+	dt := new(DecryptedVariant)
+	dt.variantIndex = variantIndex
+	dt.root = root
+	dt.columnCount = columnCount
+	dt.localKey = localKey
+	return dt
+}
+
+/**
+ * Index of the variant in the file encryption and in the stripe footer encryption
+ */
+func (dt *DecryptedVariant) GetVariantIndex() int32 {
+	return dt.variantIndex
+}
+
+func (dt *DecryptedVariant) GetRoot() metadata.MothColumnId {
+	return dt.root
+}
+
+func (dt *DecryptedVariant) GetColumnCount() int32 {
+	return dt.columnCount
+}
+
+func (dt *DecryptedVariant) GetLocalKey() []byte {
+	return dt.localKey
+}
+
+func (dt *DecryptedVariant) Contains(columnId metadata.MothColumnId) bool {
+	return columnId >= dt.root && columnId < dt.root+metadata.MothColumnId(dt.columnCount)
+}
+
+/**
+ * Returns the variants of the file whose local key the key provider can decrypt
+ */
+func GetDecryptedVariants(footer *metadata.Footer, keyProvider encryption.KeyProvider) *util.ArrayList[*DecryptedVariant] {
+	decryptedVariants := util.NewArrayList[*DecryptedVariant]()
+	if keyProvider == nil || footer.GetEncryption().IsEmpty() {
+		return decryptedVariants
+	}
+	fileEncryption := footer.GetEncryption().Get()
+	for i := util.INT32_ZERO; i < fileEncryption.GetVariants().SizeInt32(); i++ {
+		variant := fileEncryption.GetVariants().GetByInt32(i)
+		key := fileEncryption.GetKeys().GetByInt32(variant.GetKeyIndex())
+		localKey := keyProvider.DecryptLocalKey(key, variant.GetEncryptedKey())
+		if localKey != nil {
+			decryptedVariants.Add(NewDecryptedVariant(i, variant.GetRoot(), metadata.GetSubtreeColumnCount(footer.GetTypes(), variant.GetRoot()), localKey))
+		}
+	}
+	return decryptedVariants
+}
+
+func getDecryptedVariant(decryptedVariants *util.ArrayList[*DecryptedVariant], columnId metadata.MothColumnId) *DecryptedVariant {
+	for _, variant := range decryptedVariants.ToArray() {
+		if variant.Contains(columnId) {
+			return variant
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Decrypts an AES-CTR encrypted stream. Streams the delegate keeps in a single buffer are
+ * decrypted once, other streams are decrypted buffer by buffer.
+ */
+type DecryptingMothDataReader struct {
+	// 继承
+	MothDataReader
+
+	delegate  MothDataReader
+	key       []byte
+	iv        []byte
+	decrypted *slice.Slice
+}
+
+func NewDecryptingMothDataReader(delegate MothDataReader, key []byte, iv []byte) *DecryptingMothDataReader {
+	dr := new(DecryptingMothDataReader)
+	dr.delegate = delegate
+	dr.key = key
+	dr.iv = iv
+	return dr
+}
+
+// @Override
+func (dr *DecryptingMothDataReader) GetMothDataSourceId() *common.MothDataSourceId {
+	return dr.delegate.GetMothDataSourceId()
+}
+
+// @Override
+func (dr *DecryptingMothDataReader) GetRetainedSize() int64 {
+	retainedSize := dr.delegate.GetRetainedSize()
+	if dr.decrypted != nil {
+		retainedSize += dr.decrypted.LenInt64()
+	}
+	return retainedSize
+}
+
+// @Override
+func (dr *DecryptingMothDataReader) GetSize() int32 {
+	return dr.delegate.GetSize()
+}
+
+// @Override
+func (dr *DecryptingMothDataReader) GetMaxBufferSize() int32 {
+	return dr.delegate.GetMaxBufferSize()
+}
+
+// @Override
+func (dr *DecryptingMothDataReader) SeekBuffer(position int32) *slice.Slice {
+	if dr.decrypted == nil && dr.delegate.GetMaxBufferSize() >= dr.delegate.GetSize() {
+		dr.decrypted = dr.decrypt(0)
+	}
+	if dr.decrypted != nil {
+		s, _ := dr.decrypted.MakeSlice(int(position), dr.decrypted.Size()-int(position))
+		return s
+	}
+	return dr.decrypt(position)
+}
+
+func (dr *DecryptingMothDataReader) decrypt(position int32) *slice.Slice {
+	// copy the data, the delegate may return a view of the file data
+	data := dr.delegate.SeekBuffer(position).AvailableBytes()
+	encryption.Crypt(dr.key, dr.iv, int64(position), data)
+	return slice.NewWithBuf(data)
+}
+
+// @Override
+func (dr *DecryptingMothDataReader) String() string {
+	return util.NewSB().AppendString("DecryptingMothDataReader").AddString("delegate", dr.delegate.String()).String()
+}
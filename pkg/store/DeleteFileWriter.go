@@ -0,0 +1,80 @@
+package store
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Writes a delete file, the keys deleted from the data files of a table with the timestamp of
+ * their deletion. The file is laid out like the tail of a moth file: the magic, the compressed
+ * DeleteKey messages in place of the footer and a postscript, so the keys are deleted without
+ * rewriting the data files. The rows are filtered when they are read with a DeleteFilter.
+ */
+type DeleteFileWriter struct {
+	mothDataSink   MothDataSink
+	compression    metadata.CompressionKind
+	bufferSize     int32
+	formatFlavor   metadata.FormatFlavor
+	metadataWriter *CompressedMetadataWriter
+	deleteKeys     *util.ArrayList[*metadata.DeleteKey]
+	closed         bool
+}
+
+func NewDeleteFileWriter(mothDataSink MothDataSink, compression metadata.CompressionKind, options *MothWriterOptions) *DeleteFileWriter {
+	dr := new(DeleteFileWriter)
+	dr.mothDataSink = mothDataSink
+	dr.compression = compression
+	dr.bufferSize = util.Int32Exact(int64(options.GetMaxCompressionBufferSize().Bytes()))
+	dr.formatFlavor = options.GetFormatFlavor()
+	dr.metadataWriter = NewCompressedMetadataWriter(metadata.NewMothMetadataWriter2(options.GetWriterIdentification(), options.GetFormatFlavor()), compression, options.GetZstdCompressionLevel(), dr.bufferSize)
+	dr.deleteKeys = util.NewArrayList[*metadata.DeleteKey]()
+	return dr
+}
+
+/**
+ * Deletes the rows with the key that were written before ts, see LongKey for the keys of integer
+ * key columns.
+ */
+func (dr *DeleteFileWriter) Delete(key *slice.Slice, ts uint64) {
+	util.CheckState2(!dr.closed, "Writer is closed")
+	dr.deleteKeys.Add(metadata.NewDeleteKey(key, ts))
+}
+
+func (dr *DeleteFileWriter) GetDeleteKeyCount() int32 {
+	return dr.deleteKeys.SizeInt32()
+}
+
+/**
+ * Writes the delete keys and closes the sink.
+ */
+func (dr *DeleteFileWriter) Close() {
+	if dr.closed {
+		return
+	}
+	dr.closed = true
+	defer dr.mothDataSink.Close()
+
+	outputData := util.NewArrayList[MothDataOutput]()
+	outputData.Add(CreateDataOutput(dr.formatFlavor.GetMagicSlice()))
+	deleteKeysSlice := dr.metadataWriter.WriteDeleteKeys(dr.deleteKeys)
+	outputData.Add(CreateDataOutput(deleteKeysSlice))
+	postscriptSlice := dr.metadataWriter.WritePostscript(deleteKeysSlice.Length(), 0, dr.compression, dr.bufferSize)
+	outputData.Add(CreateDataOutput(postscriptSlice))
+	s := slice.NewBaseBuf(make([]byte, 1))
+	s.WriteUInt8(uint8(postscriptSlice.Length()))
+	outputData.Add(CreateDataOutput(s))
+	dr.mothDataSink.Write(outputData)
+	dr.deleteKeys.Clear()
+}
+
+/**
+ * Like Close, but a failure to write the file is returned as a *common.MothError instead of
+ * panicking.
+ */
+func (dr *DeleteFileWriter) TryClose() (err error) {
+	defer recoverMothError(&err, nil)
+	dr.Close()
+	return nil
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * The keys deleted from a table, read from its delete files. A row is deleted when its key was
+ * deleted with a timestamp newer than the version of the row, so a key written again after its
+ * deletion is read again. Only the newest delete of each key is kept.
+ */
+type DeleteFilter struct {
+	deletes map[string]uint64
+}
+
+func NewDeleteFilter() *DeleteFilter {
+	dr := new(DeleteFilter)
+	dr.deletes = make(map[string]uint64)
+	return dr
+}
+
+func (dr *DeleteFilter) Add(deleteKey *metadata.DeleteKey) {
+	key := deleteKey.GetKey().String()
+	if ts, ok := dr.deletes[key]; !ok || ts < deleteKey.GetTs() {
+		dr.deletes[key] = deleteKey.GetTs()
+	}
+}
+
+/**
+ * Adds the keys of a delete file written by a DeleteFileWriter.
+ */
+func (dr *DeleteFilter) AddDeleteFile(mothDataSource MothDataSource, options *MothReaderOptions) {
+	for _, deleteKey := range ReadDeleteFile(mothDataSource, options).ToArray() {
+		dr.Add(deleteKey)
+	}
+}
+
+/**
+ * Like AddDeleteFile, but a corrupt or unreadable file is returned as a *common.MothError
+ * instead of panicking.
+ */
+func (dr *DeleteFilter) TryAddDeleteFile(mothDataSource MothDataSource, options *MothReaderOptions) (err error) {
+	defer recoverMothError(&err, mothDataSource.GetId())
+	dr.AddDeleteFile(mothDataSource, options)
+	return nil
+}
+
+/**
+ * Returns if the row with the key written with the version is deleted.
+ */
+func (dr *DeleteFilter) IsDeleted(key *slice.Slice, version uint64) bool {
+	ts, ok := dr.deletes[key.String()]
+	return ok && ts > version
+}
+
+/**
+ * Returns the number of deleted keys.
+ */
+func (dr *DeleteFilter) Size() int32 {
+	return int32(len(dr.deletes))
+}
+
+/**
+ * Returns the rows of the page that are not deleted. Rows without a version column,
+ * versionChannel -1, are deleted by any delete of their key, and rows with a null key are never
+ * deleted.
+ */
+func (dr *DeleteFilter) filterPage(page *spi.Page, keyType block.Type, keyChannel int32, versionType block.Type, versionChannel int32) *spi.Page {
+	if len(dr.deletes) == 0 {
+		return page
+	}
+	keys := page.GetBlock(keyChannel).GetLoadedBlock()
+	var versions block.Block
+	if versionChannel >= 0 {
+		versions = page.GetBlock(versionChannel).GetLoadedBlock()
+	}
+	retainedPositions := make([]int32, 0, page.GetPositionCount())
+	for position := util.INT32_ZERO; position < page.GetPositionCount(); position++ {
+		key := readRowKey(keyType, keys, position)
+		version := uint64(0)
+		if versions != nil && !versions.IsNull(position) {
+			version = uint64(versionType.GetLong(versions, position))
+		}
+		if key == nil || !dr.IsDeleted(key, version) {
+			retainedPositions = append(retainedPositions, position)
+		}
+	}
+	if util.Lens(retainedPositions) == page.GetPositionCount() {
+		return page
+	}
+	return page.CopyPositions(retainedPositions, 0, util.Lens(retainedPositions))
+}
+
+/**
+ * Reads the keys of a delete file written by a DeleteFileWriter.
+ */
+func ReadDeleteFile(mothDataSource MothDataSource, options *MothReaderOptions) *util.ArrayList[*metadata.DeleteKey] {
+	size := mothDataSource.GetEstimatedSize()
+	magicLength := int64(len(options.GetFormatFlavor().GetMagic()))
+	if size <= magicLength {
+		panic(common.NewMothCorruptionError(mothDataSource.GetId(), "Invalid delete file size %d", size))
+	}
+	file := mothDataSource.ReadFully(0, util.Int32Exact(size))
+	postScriptSize, _ := file.GetUInt8(file.Size() - util.BYTE_BYTES)
+	if int64(postScriptSize)+magicLength >= size {
+		panic(common.NewMothCorruptionError(mothDataSource.GetId(), "Invalid postscript length %d", postScriptSize))
+	}
+	verifyMagic(mothDataSource, options.GetFormatFlavor(), int32(postScriptSize), file)
+	metadataReader := metadata.NewExceptionWrappingMetadataReader(mothDataSource.GetId(), metadata.NewMothMetadataReader2(options.GetFormatFlavor()))
+	postScriptSlice, _ := file.MakeSlice(file.Size()-util.BYTE_BYTES-int(postScriptSize), int(postScriptSize))
+	postScript := metadataReader.ReadPostScript(postScriptSlice.GetInput())
+	checkMothVersion(mothDataSource, postScript.GetVersion())
+
+	deleteKeysSize := postScript.GetFooterLength()
+	deleteKeysOffset := size - util.BYTE_BYTES - int64(postScriptSize) - deleteKeysSize
+	if deleteKeysSize < 0 || deleteKeysOffset < magicLength {
+		panic(common.NewMothCorruptionError(mothDataSource.GetId(), "Invalid delete keys length %d", deleteKeysSize))
+	}
+	deleteKeysSlice, _ := file.MakeSlice(int(deleteKeysOffset), int(deleteKeysSize))
+	decompressor := CreateMothDecompressor(mothDataSource.GetId(), postScript.GetCompression(), util.Int32ExactU(postScript.GetCompressionBlockSize()))
+	inputStream := NewMothInputStream(CreateChunkLoader(mothDataSource.GetId(), deleteKeysSlice, decompressor, memory.NewSimpleAggregatedMemoryContext()))
+	return metadataReader.ReadDeleteKeys(inputStream)
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+func writeDeleteFile(t *testing.T, compression metadata.CompressionKind, deletes func(writer *DeleteFileWriter)) MothDataSource {
+	out := new(memoryWriteCloser)
+	writer := NewDeleteFileWriter(NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), compression, NewMothWriterOptions())
+	deletes(writer)
+	if err := writer.TryClose(); err != nil {
+		t.Fatal(err)
+	}
+	return NewMemoryMothDataSource(common.NewMothDataSourceId("deletes"), slice.NewWithBuf(out.Bytes()))
+}
+
+func readDeletedIds(t *testing.T, deleteFilter *DeleteFilter, keyChannel int32, versionChannel int32) []int64 {
+	data := writeTestFile(3000, NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100), metadata.ZLIB)
+	recordReader := createRecordReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), NewMothReaderOptions(), TRUE)
+	defer recordReader.Close()
+	recordReader.SetDeleteFilter(deleteFilter, keyChannel, versionChannel)
+	pages := make([]*spi.Page, 0)
+	for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+		pages = append(pages, page.GetLoadedPage())
+	}
+	return pageIds(t, pages...)
+}
+
+func TestDeleteFilter(t *testing.T) {
+	for _, compression := range []metadata.CompressionKind{metadata.NONE, metadata.ZSTD} {
+		source := writeDeleteFile(t, compression, func(writer *DeleteFileWriter) {
+			writer.Delete(LongKey(5), 10)
+			writer.Delete(LongKey(-5), 10)
+			writer.Delete(LongKey(7), 0)
+			writer.Delete(LongKey(7), 20)
+			writer.Delete(LongKey(7), 15)
+		})
+		deleteKeys := ReadDeleteFile(source, NewMothReaderOptions())
+		if deleteKeys.Size() != 5 || !deleteKeys.Get(1).GetKey().Equal(LongKey(-5)) || deleteKeys.Get(4).GetTs() != 15 {
+			t.Fatalf("read %d delete keys from a %s file", deleteKeys.Size(), compression)
+		}
+		deleteFilter := NewDeleteFilter()
+		deleteFilter.AddDeleteFile(source, NewMothReaderOptions())
+		if deleteFilter.Size() != 3 || !deleteFilter.IsDeleted(LongKey(7), 19) || deleteFilter.IsDeleted(LongKey(7), 20) || deleteFilter.IsDeleted(LongKey(6), 0) {
+			t.Fatalf("kept %d deleted keys from a %s file", deleteFilter.Size(), compression)
+		}
+	}
+}
+
+func TestDeleteFilter_MothRecordReader(t *testing.T) {
+	// every row of the first page and rows of later stripes are deleted by id
+	source := writeDeleteFile(t, metadata.ZLIB, func(writer *DeleteFileWriter) {
+		for id := int64(0); id < int64(INITIAL_BATCH_SIZE); id++ {
+			writer.Delete(LongKey(id), 1)
+		}
+		writer.Delete(LongKey(1500), 1)
+		writer.Delete(LongKey(2999), 1)
+		writer.Delete(LongKey(5000), 1)
+	})
+	deleteFilter := NewDeleteFilter()
+	if err := deleteFilter.TryAddDeleteFile(source, NewMothReaderOptions()); err != nil {
+		t.Fatal(err)
+	}
+	want := idRange(int64(INITIAL_BATCH_SIZE), 1500)
+	want = append(want, idRange(1501, 2999)...)
+	if ids := readDeletedIds(t, deleteFilter, 0, -1); !reflect.DeepEqual(ids, want) {
+		t.Fatalf("read %d rows, want %d", len(ids), len(want))
+	}
+
+	// the names are deleted at ts 20, the rows with an id of 20 and above were written later
+	source = writeDeleteFile(t, metadata.NONE, func(writer *DeleteFileWriter) {
+		for _, name := range []string{"name-000003", "name-000019", "name-000020", "name-002000"} {
+			writer.Delete(slice.NewWithString(name), 20)
+		}
+	})
+	deleteFilter = NewDeleteFilter()
+	deleteFilter.AddDeleteFile(source, NewMothReaderOptions())
+	want = append(append(idRange(0, 3), idRange(4, 19)...), idRange(20, 3000)...)
+	if ids := readDeletedIds(t, deleteFilter, 1, 0); !reflect.DeepEqual(ids, want) {
+		t.Fatalf("read %d rows, want %d", len(ids), len(want))
+	}
+
+	recordReader := createRecordReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), writeTestFile(3000, NewMothWriterOptions().WithStripeMaxRowCount(1000), metadata.NONE)), NewMothReaderOptions(), TRUE)
+	defer recordReader.Close()
+	recordReader.SetDeleteFilter(deleteFilter, 1, 0)
+	if ids := pageIds(t, recordReader.ReadRows(1, 30).ToArray()...); !reflect.DeepEqual(ids, append(append(idRange(1, 3), idRange(4, 19)...), idRange(20, 31)...)) {
+		t.Fatalf("read rows %v", ids)
+	}
+}
+
+func TestDeleteFilter_Corrupt(t *testing.T) {
+	data := writeTestFile(10, NewMothWriterOptions(), metadata.NONE)
+	err := NewDeleteFilter().TryAddDeleteFile(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), NewMothReaderOptions())
+	if err == nil {
+		t.Fatal("read the delete keys of a data file")
+	}
+	err = NewDeleteFilter().TryAddDeleteFile(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), slice.NewWithString("MOTH")), NewMothReaderOptions())
+	if err == nil {
+		t.Fatal("read the delete keys of a truncated file")
+	}
+}
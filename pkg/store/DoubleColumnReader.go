@@ -6,6 +6,7 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -58,7 +59,7 @@ func (dr *DoubleColumnReader) ReadBlock() block.Block {
 		}
 		if dr.readOffset > 0 {
 			if dr.dataStream == nil {
-				panic("Value is not null but data stream is missing")
+				panic(common.NewMothCorruptionError(dr.column.GetMothDataSourceId(), "Value is not null but data stream is missing"))
 			}
 			dr.dataStream.Skip(int64(dr.readOffset))
 		}
@@ -66,7 +67,7 @@ func (dr *DoubleColumnReader) ReadBlock() block.Block {
 	var b block.Block
 	if dr.dataStream == nil {
 		if dr.presentStream == nil {
-			panic("Value is null but present stream is missing")
+			panic(common.NewMothCorruptionError(dr.column.GetMothDataSourceId(), "Value is null but present stream is missing"))
 		}
 		dr.presentStream.Skip(int64(dr.nextBatchSize))
 		b = block.CreateRunLengthEncodedBlock(block.DOUBLE, nil, dr.nextBatchSize)
@@ -28,13 +28,13 @@ type DoubleColumnWriter struct {
 	closed                    bool
 }
 
-func NewDoubleColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, bufferSize int32, statisticsBuilderSupplier function.Supplier[*metadata.DoubleStatisticsBuilder]) *DoubleColumnWriter {
+func NewDoubleColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, statisticsBuilderSupplier function.Supplier[*metadata.DoubleStatisticsBuilder]) *DoubleColumnWriter {
 	dr := new(DoubleColumnWriter)
 	dr.columnId = columnId
 	dr.kind = kind
 	dr.compressed = compression != metadata.NONE
-	dr.dataStream = NewDoubleOutputStream(compression, bufferSize)
-	dr.presentStream = NewPresentOutputStream(compression, bufferSize)
+	dr.dataStream = NewDoubleOutputStream(compression, compressionLevel, bufferSize)
+	dr.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 	dr.statisticsBuilderSupplier = statisticsBuilderSupplier
 	dr.statisticsBuilder = statisticsBuilderSupplier.Get()
 	dr.rowGroupColumnStatistics = util.NewArrayList[*metadata.ColumnStatistics]()
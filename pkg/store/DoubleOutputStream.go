@@ -16,10 +16,10 @@ type DoubleOutputStream struct {
 	closed      bool
 }
 
-func NewDoubleOutputStream(compression metadata.CompressionKind, bufferSize int32) *DoubleOutputStream {
+func NewDoubleOutputStream(compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) *DoubleOutputStream {
 	dm := new(DoubleOutputStream)
 	dm.checkpoints = util.NewArrayList[*DoubleStreamCheckpoint]()
-	dm.buffer = NewMothOutputBuffer(compression, bufferSize)
+	dm.buffer = NewMothOutputBuffer(compression, compressionLevel, bufferSize)
 	return dm
 }
 
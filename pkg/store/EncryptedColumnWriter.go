@@ -0,0 +1,162 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/mothdb-bd/orc-go/pkg/basic"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Writes the encryption variant of a top level column. The column writer of the variant gets the
+ * real values and its streams are encrypted with AES-CTR using the local key of the variant,
+ * while the unencrypted copy of the column in the file is masked for readers without the key.
+ *
+ * Stripe statistics of the variant are not stored, the file statistics are stored encrypted in
+ * the footer.
+ */
+type EncryptedColumnWriter struct {
+	channel          int32
+	root             metadata.MothColumnId
+	columnCount      int32
+	keyIndex         int32
+	localKey         *encryption.LocalKey
+	columnWriter     ColumnWriter
+	dataMask         *metadata.DataMask
+	columnMask       ColumnMask
+	stripeStatistics *util.ArrayList[*metadata.ColumnMetadata[*metadata.ColumnStatistics]]
+}
+
+func NewEncryptedColumnWriter(channel int32, root metadata.MothColumnId, columnCount int32, keyIndex int32, localKey *encryption.LocalKey, columnWriter ColumnWriter, dataMask *metadata.DataMask, kind block.Type) *EncryptedColumnWriter {
+	er := new(EncryptedColumnWriter)
+	er.channel = channel
+	er.root = root
+	er.columnCount = columnCount
+	er.keyIndex = keyIndex
+	er.localKey = localKey
+	er.columnWriter = columnWriter
+	er.dataMask = metadata.NewDataMask(dataMask.GetName(), dataMask.GetParameters(), util.NewArrayList(root))
+	er.columnMask = CreateColumnMask(dataMask, kind)
+	er.stripeStatistics = util.NewArrayList[*metadata.ColumnMetadata[*metadata.ColumnStatistics]]()
+	return er
+}
+
+func (er *EncryptedColumnWriter) GetChannel() int32 {
+	return er.channel
+}
+
+func (er *EncryptedColumnWriter) GetRoot() metadata.MothColumnId {
+	return er.root
+}
+
+func (er *EncryptedColumnWriter) GetColumnWriter() ColumnWriter {
+	return er.columnWriter
+}
+
+/**
+ * The mask of the unencrypted copy of the column
+ */
+func (er *EncryptedColumnWriter) GetDataMask() *metadata.DataMask {
+	return er.dataMask
+}
+
+/**
+ * The block written to the unencrypted copy of the column
+ */
+func (er *EncryptedColumnWriter) MaskBlock(b block.Block) block.Block {
+	return er.columnMask.Mask(b)
+}
+
+/**
+ * Encrypted index and bloom filter streams of the closed column writer
+ */
+func (er *EncryptedColumnWriter) GetIndexStreams(metadataWriter *CompressedMetadataWriter, stripeId uint64) *util.ArrayList[*StreamDataOutput] {
+	indexStreams := util.NewArrayList[*StreamDataOutput]()
+	indexStreams.AddAll(er.columnWriter.GetIndexStreams(metadataWriter))
+	indexStreams.AddAll(er.columnWriter.GetBloomFilters(metadataWriter))
+	return er.encryptStreams(indexStreams, stripeId)
+}
+
+/**
+ * Encrypted data streams of the closed column writer, ordered by size
+ */
+func (er *EncryptedColumnWriter) GetDataStreams(stripeId uint64) *util.ArrayList[*StreamDataOutput] {
+	dataStreams := util.NewCmpList[*StreamDataOutput](NewStreamDataOutputCmp())
+	dataStreams.AddAll(er.columnWriter.GetDataStreams())
+	sort.Sort(dataStreams)
+	return er.encryptStreams(dataStreams, stripeId)
+}
+
+func (er *EncryptedColumnWriter) encryptStreams(streams *util.ArrayList[*StreamDataOutput], stripeId uint64) *util.ArrayList[*StreamDataOutput] {
+	encryptedStreams := util.NewArrayList[*StreamDataOutput]()
+	for _, output := range streams.ToArray() {
+		encryptedStreams.Add(encryptStreamDataOutput(output, er.localKey.GetDecryptedKey(), stripeId))
+	}
+	return encryptedStreams
+}
+
+func encryptStreamDataOutput(output *StreamDataOutput, key []byte, stripeId uint64) *StreamDataOutput {
+	stream := output.GetStream()
+	iv := encryption.CreateIv(stream.GetColumnId(), stream.GetStreamKind(), stripeId)
+	return NewStreamDataOutput2(func(sliceOutput slice.SliceOutput) int64 {
+		buffer := slice.NewDynamicSliceOutput(stream.GetLength())
+		output.WriteData(buffer)
+		data := buffer.Slice().AvailableBytes()
+		encryption.Crypt(key, iv, 0, data)
+		sliceOutput.WriteBytes(data)
+		return int64(len(data))
+	}, stream)
+}
+
+/**
+ * Encrypts the key index of the stripe when the column is the key column of the file, so its keys
+ * are only readable with the key of the variant
+ */
+func (er *EncryptedColumnWriter) EncryptKeyIndex(keyIndex *slice.Slice, stripeId uint64) *slice.Slice {
+	data := keyIndex.AvailableBytes()
+	encryption.Crypt(er.localKey.GetDecryptedKey(), encryption.CreateIv(er.root, metadata.KEY_INDEX, stripeId), 0, data)
+	return slice.NewWithBuf(data)
+}
+
+/**
+ * Encodings of the variant columns, starting at the root
+ */
+func (er *EncryptedColumnWriter) GetColumnEncodings() *util.ArrayList[*metadata.ColumnEncoding] {
+	return toSubtreeMetadata(er.columnWriter.GetColumnEncodings(), er.root, er.columnCount).List()
+}
+
+/**
+ * Records the statistics of the closed column writer for the file statistics
+ */
+func (er *EncryptedColumnWriter) FinishStripe() {
+	er.stripeStatistics.Add(toSubtreeMetadata(er.columnWriter.GetColumnStripeStatistics(), er.root, er.columnCount))
+}
+
+func toSubtreeMetadata[T basic.Object](data map[metadata.MothColumnId]T, root metadata.MothColumnId, columnCount int32) *metadata.ColumnMetadata[T] {
+	list := util.NewArrayList[T]()
+	for i := util.INT32_ZERO; i < columnCount; i++ {
+		list.Add(data[root+metadata.MothColumnId(i)])
+	}
+	return metadata.NewColumnMetadata(list)
+}
+
+func (er *EncryptedColumnWriter) GetEncryptionVariant(metadataWriter *CompressedMetadataWriter) *metadata.EncryptionVariant {
+	var fileStatistics []byte
+	toFileStats(er.stripeStatistics).IfPresent(func(stats *metadata.ColumnMetadata[*metadata.ColumnStatistics]) {
+		fileStatistics = metadataWriter.WriteFileStatistics(stats).AvailableBytes()
+		encryption.Crypt(er.localKey.GetDecryptedKey(), encryption.CreateIv(er.root, metadata.FILE_STATISTICS, 0), 0, fileStatistics)
+	})
+	return metadata.NewEncryptionVariant(er.root, er.keyIndex, er.localKey.GetEncryptedKey(), fileStatistics)
+}
+
+func (er *EncryptedColumnWriter) GetRetainedBytes() int64 {
+	retainedBytes := er.columnWriter.GetRetainedBytes()
+	for _, stats := range er.stripeStatistics.ToArray() {
+		retainedBytes += stats.Stream().MapToLong((*metadata.ColumnStatistics).GetRetainedSizeInBytes).Sum()
+	}
+	return retainedBytes
+}
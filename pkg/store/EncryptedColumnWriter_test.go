@@ -0,0 +1,95 @@
+package store
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+// readNames returns the name column of a file written by writeTestFile, "" for null names
+func readNames(t *testing.T, reader *MothReader) []string {
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+	recordReader := reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns(), types, TRUE, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+	defer recordReader.Close()
+	names := make([]string, 0)
+	for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+		ids := page.GetBlock(0).GetLoadedBlock()
+		values := page.GetBlock(1).GetLoadedBlock()
+		for position := util.INT32_ZERO; position < ids.GetPositionCount(); position++ {
+			if id := block.BIGINT.GetLong(ids, position); id != int64(len(names)) {
+				t.Fatalf("id = %d, want %d", id, len(names))
+			}
+			if values.IsNull(position) {
+				names = append(names, "")
+			} else {
+				names = append(names, block.VARCHAR.GetSlice(values, position).String())
+			}
+		}
+	}
+	return names
+}
+
+func TestEncryptedColumnWriter_RoundTrip(t *testing.T) {
+	masterKey := []byte("0123456789abcdef")
+	writerKeys := encryption.NewInMemoryKeyProvider().AddKey("pii", 1, metadata.AES_CTR_128, masterKey)
+	keyFile := filepath.Join(t.TempDir(), "keys")
+	content := fmt.Sprintf("# test keys\npii 1 AES_CTR_128 %s\n", base64.StdEncoding.EncodeToString(masterKey))
+	if err := os.WriteFile(keyFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rowCount := 2500
+	options := NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100).WithColumnEncryption(map[string]string{"name": "pii"}).WithKeyProvider(writerKeys)
+	data := writeTestFile(rowCount, options, metadata.ZLIB)
+
+	tests := []struct {
+		name        string
+		keyProvider encryption.KeyProvider
+		decrypted   bool
+	}{
+		{"in memory key", writerKeys, true},
+		{"key file", encryption.NewLocalFileKeyProvider(keyFile), true},
+		{"wrong key", encryption.NewInMemoryKeyProvider().AddKey("pii", 1, metadata.AES_CTR_128, []byte("fedcba9876543210")), false},
+		{"no key", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), NewMothReaderOptions().WithKeyProvider(tt.keyProvider)).Get()
+			if reader.GetFooter().GetEncryption().IsEmpty() {
+				t.Fatalf("footer has no encryption")
+			}
+			names := readNames(t, reader)
+			if len(names) != rowCount {
+				t.Fatalf("read %d rows, want %d", len(names), rowCount)
+			}
+			for i, name := range names {
+				want := ""
+				if tt.decrypted {
+					want = fmt.Sprintf("name-%06d", i)
+				}
+				if name != want {
+					t.Fatalf("row %d: name = %q, want %q", i, name, want)
+				}
+			}
+
+			nameStatistics := reader.GetFooter().GetFileStats().Get().Get(metadata.MothColumnId(2))
+			if tt.decrypted {
+				if nameStatistics.GetStringStatistics() == nil || nameStatistics.GetStringStatistics().GetMax().String() != fmt.Sprintf("name-%06d", rowCount-1) {
+					t.Errorf("name statistics = %v, want the decrypted statistics", nameStatistics)
+				}
+			} else if nameStatistics.GetNumberOfValues() != 0 {
+				t.Errorf("masked name has %d values, want 0", nameStatistics.GetNumberOfValues())
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package store
 import (
 	"io"
 	"os"
+	"sync"
 	"time"
 
+	"github.com/mothdb-bd/orc-go/pkg/errors"
 	"github.com/mothdb-bd/orc-go/pkg/maths"
 	"github.com/mothdb-bd/orc-go/pkg/mothio"
 	"github.com/mothdb-bd/orc-go/pkg/slice"
@@ -16,23 +18,51 @@ type FileMothDataSource struct {
 	// 继承
 	AbstractMothDataSource
 
-	input *mothio.RandomAccessFile
+	input             *mothio.RandomAccessFile
+	modificationStamp string
+	// guards the file position, lazily loaded streams may be read by several goroutines
+	lock sync.Mutex
 }
 
 // *os.File
 func NewFileMothDataSource(path string, options *MothReaderOptions) *FileMothDataSource {
+	fe, err := OpenFileMothDataSource(path, options)
+	if err != nil {
+		panic(err)
+	}
+	return fe
+}
+
+/**
+ * Like NewFileMothDataSource, but a file that can not be opened is returned as a
+ * *common.MothError instead of panicking.
+ */
+func OpenFileMothDataSource(path string, options *MothReaderOptions) (*FileMothDataSource, error) {
 	fe := new(FileMothDataSource)
 	fe.id = common.NewMothDataSourceId(path)
 
 	file, err := os.Open(path)
 	if err != nil {
-		panic(err.Error())
+		return nil, common.NewMothErrorWithCause(errors.IO_ERROR, fe.id, err, "Failed to open file: %s", err.Error())
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, common.NewMothErrorWithCause(errors.IO_ERROR, fe.id, err, "Failed to open file: %s", err.Error())
 	}
 	fe.input = mothio.NewRandomAccessFile(file)
-	fi, _ := file.Stat()
 	fe.estimatedSize = fi.Size()
+	fe.modificationStamp = fi.ModTime().UTC().Format(time.RFC3339Nano)
 	fe.options = options
-	return fe
+	return fe, nil
+}
+
+/**
+ * Returns the modification time of the file when it was opened.
+ */
+// @Override
+func (fe *FileMothDataSource) GetModificationStamp() string {
+	return fe.modificationStamp
 }
 
 // @Override
@@ -72,11 +102,8 @@ func (ae *FileMothDataSource) GetEstimatedSize() int64 {
 
 // @Override
 func (ae *FileMothDataSource) ReadTail(length int32) *slice.Slice {
-	start := time.Now()
-	tailSlice := ae.readTailInternal(length)
-	ae.readTimeNanos += time.Since(start).Nanoseconds()
-	ae.readBytes += int64(tailSlice.Length())
-	return tailSlice
+	// the read bytes and time are counted by readFully
+	return ae.readTailInternal(length)
 }
 
 // @Override
@@ -92,6 +119,8 @@ func (ae *FileMothDataSource) ReadFully(position int64, length int32) *slice.Sli
 }
 
 func (ae *FileMothDataSource) readFully(position int64, buffer []byte, bufferOffset int32, bufferLength int32) {
+	ae.lock.Lock()
+	defer ae.lock.Unlock()
 	start := time.Now()
 	ae.readInternal(position, buffer, bufferOffset, bufferLength)
 	ae.readTimeNanos += time.Since(start).Nanoseconds()
@@ -172,16 +201,26 @@ type DiskMothDataReader struct {
 
 	diskRange *DiskRange
 
-	parent *FileMothDataSource
+	parent diskMothDataSource
+}
+
+/**
+ * A data source whose streams are read in buffers of the stream buffer size straight from the
+ * disk, see DiskMothDataReader.
+ */
+type diskMothDataSource interface {
+	MothDataSource
+	getOptions() *MothReaderOptions
+	readFully(position int64, buffer []byte, bufferOffset int32, bufferLength int32)
 }
 
-func NewDiskMothDataReader(parent *FileMothDataSource, diskRange *DiskRange) *DiskMothDataReader {
+func NewDiskMothDataReader(parent diskMothDataSource, diskRange *DiskRange) *DiskMothDataReader {
 	dr := new(DiskMothDataReader)
 	// NewdiskMothDataReader(id, requireNonNull(diskRange, "diskRange is null").getLength(), toIntExact(options.getStreamBufferSize().toBytes()))
 	dr.parent = parent
 	dr.mothDataSourceId = parent.GetId()
 	dr.dataSize = diskRange.GetLength()
-	dr.maxBufferSize = maths.MinInt32(int32(parent.options.GetStreamBufferSize().Bytes()), dr.dataSize)
+	dr.maxBufferSize = maths.MinInt32(int32(parent.getOptions().GetStreamBufferSize().Bytes()), dr.dataSize)
 	dr.diskRange = diskRange
 	return dr
 }
@@ -0,0 +1,159 @@
+package store
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * The cache key of the tail of a file, a file rewritten with another length or modification
+ * stamp gets a new key. The tail of a file is parsed, and its magic and version verified, for
+ * the format flavour of the reader, so readers of another flavour do not share the tail.
+ */
+type FileTailKey struct {
+	id                string
+	length            int64
+	modificationStamp string
+	formatFlavor      metadata.FormatFlavor
+}
+
+/**
+ * Returns the key of the file of the data source, the modification stamp is empty unless the
+ * data source is a VersionedMothDataSource.
+ */
+func NewFileTailKey(mothDataSource MothDataSource) FileTailKey {
+	key := FileTailKey{id: mothDataSource.GetId().String(), length: mothDataSource.GetEstimatedSize()}
+	if versioned, ok := mothDataSource.(VersionedMothDataSource); ok {
+		key.modificationStamp = versioned.GetModificationStamp()
+	}
+	return key
+}
+
+/**
+ * Returns the key of the tail of the file of the data source read with the format flavour.
+ */
+func NewFileTailKey2(mothDataSource MothDataSource, formatFlavor metadata.FormatFlavor) FileTailKey {
+	key := NewFileTailKey(mothDataSource)
+	key.formatFlavor = formatFlavor
+	return key
+}
+
+/**
+ * A size-bounded LRU cache of the parsed postscripts, footers and metadata of files, shared by the
+ * readers of a process through MothReaderOptions.WithFileTailCache. A reader created with a
+ * cached tail does not read the tail of its file again, and reads a tiny file into memory only
+ * when its stripes are read. The entries are weighed by the retained size of the parsed tail,
+ * the statistics included. A cache is safe for concurrent use.
+ */
+type FileTailCache struct {
+	lock          sync.Mutex
+	maxSize       int64
+	size          int64
+	entries       map[FileTailKey]*list.Element
+	lru           *list.List
+	hitCount      int64
+	missCount     int64
+	evictionCount int64
+}
+
+type fileTailEntry struct {
+	key  FileTailKey
+	tail *mothFileTail
+}
+
+func NewFileTailCache(maxSize util.DataSize) *FileTailCache {
+	fe := new(FileTailCache)
+	fe.maxSize = int64(maxSize.Bytes())
+	fe.entries = make(map[FileTailKey]*list.Element)
+	fe.lru = list.New()
+	return fe
+}
+
+func (fe *FileTailCache) get(key FileTailKey) *mothFileTail {
+	fe.lock.Lock()
+	defer fe.lock.Unlock()
+	element, ok := fe.entries[key]
+	if !ok {
+		fe.missCount++
+		return nil
+	}
+	fe.hitCount++
+	fe.lru.MoveToFront(element)
+	return element.Value.(*fileTailEntry).tail
+}
+
+func (fe *FileTailCache) put(key FileTailKey, tail *mothFileTail) {
+	fe.lock.Lock()
+	defer fe.lock.Unlock()
+	if tail.retainedSize > fe.maxSize {
+		return
+	}
+	if element, ok := fe.entries[key]; ok {
+		fe.remove(element)
+	}
+	fe.entries[key] = fe.lru.PushFront(&fileTailEntry{key: key, tail: tail})
+	fe.size += tail.retainedSize
+	for fe.size > fe.maxSize {
+		fe.remove(fe.lru.Back())
+		fe.evictionCount++
+	}
+}
+
+func (fe *FileTailCache) remove(element *list.Element) {
+	entry := fe.lru.Remove(element).(*fileTailEntry)
+	delete(fe.entries, entry.key)
+	fe.size -= entry.tail.retainedSize
+}
+
+/**
+ * Removes the tail of the file from the cache.
+ */
+func (fe *FileTailCache) Invalidate(key FileTailKey) {
+	fe.lock.Lock()
+	defer fe.lock.Unlock()
+	if element, ok := fe.entries[key]; ok {
+		fe.remove(element)
+	}
+}
+
+func (fe *FileTailCache) GetHitCount() int64 {
+	fe.lock.Lock()
+	defer fe.lock.Unlock()
+	return fe.hitCount
+}
+
+func (fe *FileTailCache) GetMissCount() int64 {
+	fe.lock.Lock()
+	defer fe.lock.Unlock()
+	return fe.missCount
+}
+
+func (fe *FileTailCache) GetEvictionCount() int64 {
+	fe.lock.Lock()
+	defer fe.lock.Unlock()
+	return fe.evictionCount
+}
+
+/**
+ * Returns the retained size of the parsed tails in the cache.
+ */
+func (fe *FileTailCache) GetSize() int64 {
+	fe.lock.Lock()
+	defer fe.lock.Unlock()
+	return fe.size
+}
+
+func (fe *FileTailCache) GetEntryCount() int32 {
+	fe.lock.Lock()
+	defer fe.lock.Unlock()
+	return int32(len(fe.entries))
+}
+
+func (fe *FileTailCache) String() string {
+	fe.lock.Lock()
+	defer fe.lock.Unlock()
+	return util.NewSB().AddInt64("maxSize", fe.maxSize).AddInt64("size", fe.size).AddInt32("entries", int32(len(fe.entries))).AddInt64("hitCount", fe.hitCount).AddInt64("missCount", fe.missCount).AddInt64("evictionCount", fe.evictionCount).String()
+}
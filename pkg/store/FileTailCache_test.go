@@ -0,0 +1,117 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func writeTailCacheFile(t *testing.T, path string, rowCount int) {
+	data := writeTestFile(rowCount, NewMothWriterOptions().WithStripeMaxRowCount(1000), metadata.ZLIB)
+	if err := os.WriteFile(path, data.AvailableBytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileTailCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.moth")
+	writeTailCacheFile(t, path, 3000)
+	cache := NewFileTailCache(util.Ofds(1, util.MB))
+	options := NewMothReaderOptions().WithFileTailCache(cache)
+
+	first := CreateMothReader(NewFileMothDataSource(path, options), options).Get()
+	source := NewFileMothDataSource(path, options)
+	second := CreateMothReader(source, options).Get()
+	// the file is tiny, but is not read for a cached tail
+	if cache.GetMissCount() != 1 || cache.GetHitCount() != 1 || cache.GetEntryCount() != 1 || source.GetReadBytes() != 0 {
+		t.Fatalf("created readers with %s and %d bytes read", cache, source.GetReadBytes())
+	}
+	if second.GetFooter() != first.GetFooter() || second.GetFooter().GetNumberOfRows() != 3000 {
+		t.Fatalf("created a reader of %d rows from the cache", second.GetFooter().GetNumberOfRows())
+	}
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+	recordReader := second.CreateRecordReader(second.GetRootColumn().GetNestedColumns(), types, TRUE, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+	defer recordReader.Close()
+	// the tiny file is read at once when its stripes are read
+	if source.GetReadBytes() != source.GetEstimatedSize() {
+		t.Fatalf("read %d bytes of a tiny file of %d bytes", source.GetReadBytes(), source.GetEstimatedSize())
+	}
+	if ids := pageIds(t, recordReader.ReadRows(0, 3000).ToArray()...); !reflect.DeepEqual(ids, idRange(0, 3000)) {
+		t.Fatalf("read %d rows with a cached tail", len(ids))
+	}
+
+	// a rewritten file is read again
+	writeTailCacheFile(t, path, 2000)
+	third := CreateMothReader(NewFileMothDataSource(path, options), options).Get()
+	if third.GetFooter().GetNumberOfRows() != 2000 || cache.GetMissCount() != 2 || cache.GetEntryCount() != 2 {
+		t.Fatalf("created a reader of %d rows of a rewritten file with %s", third.GetFooter().GetNumberOfRows(), cache)
+	}
+	cache.Invalidate(NewFileTailKey2(NewFileMothDataSource(path, options), options.GetFormatFlavor()))
+	if cache.GetEntryCount() != 1 {
+		t.Fatalf("invalidated a file with %s", cache)
+	}
+}
+
+func TestFileTailCache_FormatFlavor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.moth")
+	writeTailCacheFile(t, path, 3000)
+	cache := NewFileTailCache(util.Ofds(1, util.MB))
+	options := NewMothReaderOptions().WithFileTailCache(cache)
+	CreateMothReader(NewFileMothDataSource(path, options), options)
+
+	// the tail cached for a Moth reader is not used by an ORC reader, which verifies the magic
+	orcOptions := options.WithFormatFlavor(metadata.ORC_FLAVOR)
+	_, err := TryCreateMothReader(NewFileMothDataSource(path, orcOptions), orcOptions)
+	if err == nil || cache.GetHitCount() != 0 || cache.GetMissCount() != 2 {
+		t.Fatalf("created an ORC reader of a Moth file with %s", cache)
+	}
+}
+
+func TestFileTailCache_Eviction(t *testing.T) {
+	directory := t.TempDir()
+	paths := make([]string, 3)
+	for i := range paths {
+		paths[i] = filepath.Join(directory, string(rune('a'+i))+".moth")
+		writeTailCacheFile(t, paths[i], 3000)
+	}
+	var options *MothReaderOptions
+	open := func(i int) {
+		CreateMothReader(NewFileMothDataSource(paths[i], options), options)
+	}
+	// the files have the same tail size
+	cache := NewFileTailCache(util.Ofds(1, util.MB))
+	options = NewMothReaderOptions().WithFileTailCache(cache)
+	open(0)
+	tailSize := uint64(cache.GetSize())
+	cache = NewFileTailCache(util.Ofds(2*tailSize, util.B))
+	options = NewMothReaderOptions().WithFileTailCache(cache)
+
+	// the least recently used file is evicted
+	open(0)
+	open(1)
+	open(0)
+	open(2)
+	if cache.GetEvictionCount() != 1 || uint64(cache.GetSize()) != 2*tailSize || cache.GetHitCount() != 1 {
+		t.Fatalf("cached the tails of 3 files with %s", cache)
+	}
+	open(0)
+	open(1)
+	if cache.GetHitCount() != 2 || cache.GetMissCount() != 4 || cache.GetEvictionCount() != 2 {
+		t.Fatalf("evicted the wrong tail with %s", cache)
+	}
+
+	// a tail larger than the cache is not cached
+	cache = NewFileTailCache(util.Ofds(tailSize-1, util.B))
+	options = NewMothReaderOptions().WithFileTailCache(cache)
+	open(0)
+	if cache.GetEntryCount() != 0 || cache.GetMissCount() != 1 {
+		t.Fatalf("cached a tail larger than the cache with %s", cache)
+	}
+}
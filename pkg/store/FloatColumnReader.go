@@ -1,11 +1,13 @@
 package store
 
 import (
+	"math"
 	"time"
 
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -16,6 +18,7 @@ type FloatColumnReader struct {
 	// 继承
 	ColumnReader
 
+	kind                block.Type
 	column              *MothColumn
 	readOffset          int32
 	nextBatchSize       int32
@@ -35,6 +38,7 @@ func NewFloatColumnReader(kind block.Type, column *MothColumn, memoryContext mem
 	fr.dataStreamSource = MissingStreamSource()    //[*FloatInputStream]
 	fr.nonNullValueTemp = make([]int32, 0)
 
+	fr.kind = kind
 	fr.column = column
 	fr.memoryContext = memoryContext
 	return fr
@@ -57,7 +61,7 @@ func (fr *FloatColumnReader) ReadBlock() block.Block {
 		}
 		if fr.readOffset > 0 {
 			if fr.dataStream == nil {
-				panic("Value is not null but data stream is missing")
+				panic(common.NewMothCorruptionError(fr.column.GetMothDataSourceId(), "Value is not null but data stream is missing"))
 			}
 			fr.dataStream.Skip(int64(fr.readOffset))
 		}
@@ -65,10 +69,10 @@ func (fr *FloatColumnReader) ReadBlock() block.Block {
 	var b block.Block
 	if fr.dataStream == nil {
 		if fr.presentStream == nil {
-			panic("Value is null but present stream is missing")
+			panic(common.NewMothCorruptionError(fr.column.GetMothDataSourceId(), "Value is null but present stream is missing"))
 		}
 		fr.presentStream.Skip(int64(fr.nextBatchSize))
-		b = block.CreateRunLengthEncodedBlock(block.REAL, nil, fr.nextBatchSize)
+		b = block.CreateRunLengthEncodedBlock(fr.kind, nil, fr.nextBatchSize)
 	} else if fr.presentStream == nil {
 		b = fr.readNonNullBlock()
 	} else {
@@ -79,7 +83,7 @@ func (fr *FloatColumnReader) ReadBlock() block.Block {
 		} else if nullCount != fr.nextBatchSize {
 			b = fr.readNullBlock(isNull, fr.nextBatchSize-nullCount)
 		} else {
-			b = block.CreateRunLengthEncodedBlock(block.REAL, nil, fr.nextBatchSize)
+			b = block.CreateRunLengthEncodedBlock(fr.kind, nil, fr.nextBatchSize)
 		}
 	}
 	fr.readOffset = 0
@@ -90,7 +94,7 @@ func (fr *FloatColumnReader) ReadBlock() block.Block {
 func (fr *FloatColumnReader) readNonNullBlock() block.Block {
 	values := make([]int32, fr.nextBatchSize)
 	fr.dataStream.Next2(values, fr.nextBatchSize)
-	return block.NewIntArrayBlock(fr.nextBatchSize, optional.Empty[[]bool](), values)
+	return fr.createBlock(fr.nextBatchSize, optional.Empty[[]bool](), values)
 }
 
 func (fr *FloatColumnReader) readNullBlock(isNull []bool, nonNullCount int32) block.Block {
@@ -101,7 +105,22 @@ func (fr *FloatColumnReader) readNullBlock(isNull []bool, nonNullCount int32) bl
 	}
 	fr.dataStream.Next2(fr.nonNullValueTemp, nonNullCount)
 	result := UnpackIntNulls(fr.nonNullValueTemp, isNull)
-	return block.NewIntArrayBlock(util.Lens(isNull), optional.Of(isNull), result)
+	return fr.createBlock(util.Lens(isNull), optional.Of(isNull), result)
+}
+
+/**
+ * Creates the block of the float bits, the values are widened when the column is read as a
+ * DOUBLE.
+ */
+func (fr *FloatColumnReader) createBlock(positionCount int32, valueIsNull *optional.Optional[[]bool], values []int32) block.Block {
+	if _, flag := fr.kind.(*block.DoubleType); flag {
+		result := make([]int64, positionCount)
+		for i := util.INT32_ZERO; i < positionCount; i++ {
+			result[i] = int64(math.Float64bits(float64(math.Float32frombits(uint32(values[i])))))
+		}
+		return block.NewLongArrayBlock(positionCount, valueIsNull, result)
+	}
+	return block.NewIntArrayBlock(positionCount, valueIsNull, values)
 }
 
 func (fr *FloatColumnReader) openRowGroup() {
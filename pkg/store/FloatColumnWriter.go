@@ -30,13 +30,13 @@ type FloatColumnWriter struct {
 	closed                    bool
 }
 
-func NewFloatColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, bufferSize int32, statisticsBuilderSupplier function.Supplier[*metadata.DoubleStatisticsBuilder]) *FloatColumnWriter {
+func NewFloatColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, statisticsBuilderSupplier function.Supplier[*metadata.DoubleStatisticsBuilder]) *FloatColumnWriter {
 	fr := new(FloatColumnWriter)
 	fr.columnId = columnId
 	fr.kind = kind
 	fr.compressed = compression != metadata.NONE
-	fr.dataStream = NewFloatOutputStream(compression, bufferSize)
-	fr.presentStream = NewPresentOutputStream(compression, bufferSize)
+	fr.dataStream = NewFloatOutputStream(compression, compressionLevel, bufferSize)
+	fr.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 	fr.statisticsBuilderSupplier = statisticsBuilderSupplier
 	fr.statisticsBuilder = statisticsBuilderSupplier.Get()
 	fr.rowGroupColumnStatistics = util.NewArrayList[*metadata.ColumnStatistics]()
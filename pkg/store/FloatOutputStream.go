@@ -16,10 +16,10 @@ type FloatOutputStream struct {
 	closed      bool
 }
 
-func NewFloatOutputStream(compression metadata.CompressionKind, bufferSize int32) *FloatOutputStream {
+func NewFloatOutputStream(compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) *FloatOutputStream {
 	fm := new(FloatOutputStream)
 	fm.checkpoints = util.NewArrayList[*FloatStreamCheckpoint]()
-	fm.buffer = NewMothOutputBuffer(compression, bufferSize)
+	fm.buffer = NewMothOutputBuffer(compression, compressionLevel, bufferSize)
 	return fm
 }
 
@@ -0,0 +1,183 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/errors"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+)
+
+var (
+	DEFAULT_HTTP_MAX_RETRIES int32         = 3
+	DEFAULT_HTTP_RETRY_DELAY time.Duration = 100 * time.Millisecond
+)
+
+/**
+ * Reads an object over HTTP with Range GETs, such as an object of an S3 compatible bucket
+ * behind a presigned URL. Network failures, throttling and server errors are retried with an
+ * exponential backoff starting at retryDelay. A reader is safe for concurrent use.
+ */
+type HttpRangeReader struct {
+	client     *http.Client
+	url        string
+	maxRetries int32
+	retryDelay time.Duration
+	// the ETag or Last-Modified of the object returned with its size
+	version string
+}
+
+func NewHttpRangeReader(client *http.Client, url string) *HttpRangeReader {
+	return NewHttpRangeReader2(client, url, DEFAULT_HTTP_MAX_RETRIES, DEFAULT_HTTP_RETRY_DELAY)
+}
+
+func NewHttpRangeReader2(client *http.Client, url string, maxRetries int32, retryDelay time.Duration) *HttpRangeReader {
+	hr := new(HttpRangeReader)
+	hr.client = client
+	hr.url = url
+	hr.maxRetries = maxRetries
+	hr.retryDelay = retryDelay
+	return hr
+}
+
+/**
+ * Opens the object at the url as a data source, the ranges of the record readers are merged by
+ * the maxMergeDistance of the options and every merged range is read with one Range GET. The
+ * query of the url, which holds the signature of a presigned url, is not part of the data
+ * source id.
+ */
+func OpenHttpMothDataSource(client *http.Client, url string, options *MothReaderOptions) (*ReaderAtMothDataSource, error) {
+	return OpenHttpMothDataSource2(NewHttpRangeReader(client, url), options)
+}
+
+func OpenHttpMothDataSource2(reader *HttpRangeReader, options *MothReaderOptions) (*ReaderAtMothDataSource, error) {
+	id := common.NewMothDataSourceId(redactUrl(reader.url))
+	size, err := reader.Size()
+	if err != nil {
+		return nil, common.NewMothErrorWithCause(errors.IO_ERROR, id, err, "Failed to open object: %s", err.Error())
+	}
+	re := NewReaderAtMothDataSource(id, reader, size, options)
+	re.modificationStamp = reader.version
+	return re, nil
+}
+
+/**
+ * Returns the size of the object from the Content-Range of a GET of its first byte, a HEAD is
+ * not allowed by presigned GET urls. The ETag of the object is kept as the version of the data
+ * source.
+ */
+func (hr *HttpRangeReader) Size() (int64, error) {
+	var size int64
+	err := hr.retry(func() (bool, error) {
+		response, err := hr.get("bytes=0-0")
+		if err != nil {
+			return true, err
+		}
+		defer response.Body.Close()
+		if retryable, err := checkRangeResponse(response); err != nil {
+			return retryable, err
+		}
+		contentRange := response.Header.Get("Content-Range")
+		index := strings.LastIndexByte(contentRange, '/')
+		if index < 0 {
+			return false, fmt.Errorf("invalid Content-Range %q", contentRange)
+		}
+		size, err = strconv.ParseInt(contentRange[index+1:], 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid Content-Range %q", contentRange)
+		}
+		hr.version = response.Header.Get("ETag")
+		if hr.version == "" {
+			hr.version = response.Header.Get("Last-Modified")
+		}
+		return false, nil
+	})
+	return size, err
+}
+
+// @Override
+func (hr *HttpRangeReader) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	var n int
+	err := hr.retry(func() (bool, error) {
+		response, err := hr.get(fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1))
+		if err != nil {
+			return true, err
+		}
+		defer response.Body.Close()
+		if retryable, err := checkRangeResponse(response); err != nil {
+			return retryable, err
+		}
+		// the range ends early at the end of the object
+		n, err = io.ReadFull(response.Body, p)
+		if err == io.ErrUnexpectedEOF && response.ContentLength >= 0 && int64(n) == response.ContentLength {
+			return false, io.EOF
+		}
+		return err != nil, err
+	})
+	return n, err
+}
+
+func (hr *HttpRangeReader) get(byteRange string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, hr.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Range", byteRange)
+	return hr.client.Do(request)
+}
+
+/**
+ * Runs the attempt until it succeeds, fails with an error that is not retryable or the retries
+ * are exhausted. The delay doubles after every failed attempt.
+ */
+func (hr *HttpRangeReader) retry(attempt func() (bool, error)) error {
+	delay := hr.retryDelay
+	for retries := int32(0); ; retries++ {
+		retryable, err := attempt()
+		if err == nil || !retryable || retries >= hr.maxRetries {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+/**
+ * Returns an error unless the response is a partial content response, throttling and server
+ * errors are retryable.
+ */
+func checkRangeResponse(response *http.Response) (bool, error) {
+	switch {
+	case response.StatusCode == http.StatusPartialContent:
+		return false, nil
+	case response.StatusCode == http.StatusOK:
+		return false, fmt.Errorf("server does not support range requests")
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError:
+		return true, fmt.Errorf("unexpected status %s", response.Status)
+	default:
+		return false, fmt.Errorf("unexpected status %s", response.Status)
+	}
+}
+
+func redactUrl(rawUrl string) string {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return rawUrl
+	}
+	parsed.RawQuery = ""
+	parsed.User = nil
+	return parsed.String()
+}
+
+// @Override
+func (hr *HttpRangeReader) String() string {
+	return redactUrl(hr.url)
+}
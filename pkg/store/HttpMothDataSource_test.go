@@ -0,0 +1,158 @@
+package store
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+// a bucket serving one object, the first failures requests fail with the status
+type testBucket struct {
+	data     []byte
+	lock     sync.Mutex
+	ranges   []string
+	failures int
+	status   int
+}
+
+func (tt *testBucket) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	tt.lock.Lock()
+	tt.ranges = append(tt.ranges, request.Header.Get("Range"))
+	fail := tt.failures > 0
+	tt.failures--
+	tt.lock.Unlock()
+	if fail {
+		http.Error(writer, "failed", tt.status)
+		return
+	}
+	if request.URL.Path != "/bucket/test.moth" {
+		http.NotFound(writer, request)
+		return
+	}
+	http.ServeContent(writer, request, "test.moth", time.Time{}, bytes.NewReader(tt.data))
+}
+
+func (tt *testBucket) requests() int {
+	tt.lock.Lock()
+	defer tt.lock.Unlock()
+	return len(tt.ranges)
+}
+
+func TestHttpMothDataSource(t *testing.T) {
+	data := writeTestFile(3000, NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100), metadata.ZLIB)
+	bucket := &testBucket{data: data.AvailableBytes()}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	source, err := OpenHttpMothDataSource(server.Client(), server.URL+"/bucket/test.moth?X-Amz-Signature=secret", NewMothReaderOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source.GetEstimatedSize() != int64(data.Size()) || strings.Contains(source.GetId().String(), "secret") {
+		t.Fatalf("opened %s of %d bytes", source.GetId(), source.GetEstimatedSize())
+	}
+	recordReader := createRecordReader(source, NewMothReaderOptions(), TRUE)
+	defer recordReader.Close()
+	if ids := pageIds(t, recordReader.ReadRows(0, 3000).ToArray()...); !reflect.DeepEqual(ids, idRange(0, 3000)) {
+		t.Fatalf("read %d rows", len(ids))
+	}
+	if source.GetReadBytes() == 0 || source.GetReadTimeNanos() == 0 {
+		t.Fatalf("counted %d read bytes in %d ns", source.GetReadBytes(), source.GetReadTimeNanos())
+	}
+	for _, byteRange := range bucket.ranges {
+		if !strings.HasPrefix(byteRange, "bytes=") {
+			t.Fatalf("requested range %q", byteRange)
+		}
+	}
+
+	if _, err := OpenHttpMothDataSource(server.Client(), server.URL+"/bucket/missing.moth", NewMothReaderOptions()); err == nil {
+		t.Fatal("opened a missing object")
+	}
+}
+
+func TestHttpMothDataSource_MergedRanges(t *testing.T) {
+	data := make([]byte, 100000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	bucket := &testBucket{data: data}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	options := NewMothReaderOptions().WithLazyReadSmallRanges(false).WithMaxMergeDistance(util.Ofds(1, util.KB))
+	source, err := OpenHttpMothDataSource(server.Client(), server.URL+"/bucket/test.moth", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first three ranges are merged, the others are further apart than the merge distance
+	diskRanges := map[StreamId]*DiskRange{
+		NewStreamId(metadata.NewMothColumnId(1), metadata.DATA):    NewDiskRange(100, 400),
+		NewStreamId(metadata.NewMothColumnId(2), metadata.DATA):    NewDiskRange(500, 300),
+		NewStreamId(metadata.NewMothColumnId(3), metadata.DATA):    NewDiskRange(1500, 100),
+		NewStreamId(metadata.NewMothColumnId(4), metadata.DATA):    NewDiskRange(50000, 50000),
+		NewStreamId(metadata.NewMothColumnId(4), metadata.PRESENT): NewDiskRange(10000, 10),
+	}
+	readers := source.ReadFully2(diskRanges)
+	for streamId, diskRange := range diskRanges {
+		if buffer := readers[streamId].SeekBuffer(0); !bytes.Equal(buffer.AvailableBytes()[:diskRange.GetLength()], data[diskRange.GetOffset():diskRange.GetEnd()]) {
+			t.Fatalf("read wrong bytes of %s", diskRange)
+		}
+	}
+	want := []string{"bytes=0-0", "bytes=100-1599", "bytes=10000-10009", "bytes=50000-99999"}
+	// the merged ranges are read in parallel
+	got := append([]string{}, bucket.ranges...)
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("requested ranges %v, want %v", got, want)
+	}
+	if source.GetReadBytes() != 1500+10+50000 {
+		t.Fatalf("counted %d read bytes", source.GetReadBytes())
+	}
+	if tail := source.ReadTail(10); !bytes.Equal(tail.AvailableBytes(), data[len(data)-10:]) || bucket.ranges[len(bucket.ranges)-1] != "bytes=99990-99999" {
+		t.Fatalf("read tail with range %s", bucket.ranges[len(bucket.ranges)-1])
+	}
+}
+
+func TestHttpMothDataSource_Retries(t *testing.T) {
+	bucket := &testBucket{data: []byte("0123456789"), failures: 2, status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	reader := NewHttpRangeReader2(server.Client(), server.URL+"/bucket/test.moth", 2, time.Millisecond)
+	source, err := OpenHttpMothDataSource2(reader, NewMothReaderOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bucket.requests() != 3 || source.GetEstimatedSize() != 10 {
+		t.Fatalf("opened an object of %d bytes with %d requests", source.GetEstimatedSize(), bucket.requests())
+	}
+
+	// throttling is retried until the retries are exhausted
+	bucket.failures, bucket.status = 3, http.StatusTooManyRequests
+	if _, err := reader.ReadAt(make([]byte, 4), 2); err == nil || bucket.requests() != 6 {
+		t.Fatalf("read with %d requests: %v", bucket.requests(), err)
+	}
+	buffer := make([]byte, 4)
+	if n, err := reader.ReadAt(buffer, 2); err != nil || n != 4 || string(buffer) != "2345" {
+		t.Fatalf("read %q: %v", buffer[:n], err)
+	}
+	if n, err := reader.ReadAt(buffer, 8); n != 2 || err == nil || string(buffer[:n]) != "89" {
+		t.Fatalf("read %q past the end: %v", buffer[:n], err)
+	}
+
+	// client errors are not retried
+	bucket.failures, bucket.status = 1, http.StatusForbidden
+	requests := bucket.requests()
+	if _, err := reader.ReadAt(buffer, 0); err == nil || bucket.requests() != requests+1 {
+		t.Fatalf("read a forbidden object with %d requests", bucket.requests()-requests)
+	}
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Maps the field names of a requested struct type to the nested columns with the same field ids,
+ * so renamed and reordered fields are still found.
+ */
+type IdBasedFieldMapper struct {
+	// 继承
+	FieldMapper
+
+	nestedColumns map[string]*MothColumn
+}
+
+func NewIdBasedFieldMapper(nestedColumns map[string]*MothColumn) *IdBasedFieldMapper {
+	ir := new(IdBasedFieldMapper)
+	ir.nestedColumns = nestedColumns
+	return ir
+}
+
+// @Override
+func (ir *IdBasedFieldMapper) Get(fieldName string) *MothColumn {
+	return ir.nestedColumns[fieldName]
+}
+
+/**
+ * Creates the field mappers of a table with stable field ids, the ids of the columns of a file
+ * are read from the ICEBERG_ID_KEY attribute of their types. The nested columns of files written
+ * without field ids are mapped by name.
+ */
+type IdBasedFieldMapperFactory struct {
+	// 继承
+	FieldMapperFactory
+
+	columns          *util.ArrayList[*metadata.ColumnIdentity]
+	columnsByFieldId map[int32]*metadata.ColumnIdentity
+}
+
+/**
+ * Creates a factory for the table columns, the identities of the top level columns that are read.
+ */
+func NewIdBasedFieldMapperFactory(columns *util.ArrayList[*metadata.ColumnIdentity]) *IdBasedFieldMapperFactory {
+	iy := new(IdBasedFieldMapperFactory)
+	iy.columns = columns
+	iy.columnsByFieldId = make(map[int32]*metadata.ColumnIdentity)
+	iy.indexColumns(columns)
+	return iy
+}
+
+func (iy *IdBasedFieldMapperFactory) indexColumns(columns *util.ArrayList[*metadata.ColumnIdentity]) {
+	for _, column := range columns.ToArray() {
+		iy.columnsByFieldId[column.GetId()] = column
+		iy.indexColumns(column.GetChildren())
+	}
+}
+
+// @Override
+func (iy *IdBasedFieldMapperFactory) Create(column *MothColumn) FieldMapper {
+	nestedColumnsByFieldId := make(map[int32]*MothColumn)
+	for _, nestedColumn := range column.GetNestedColumns().ToArray() {
+		fieldId, ok := getFieldId(nestedColumn)
+		if ok {
+			nestedColumnsByFieldId[fieldId] = nestedColumn
+		}
+	}
+	if len(nestedColumnsByFieldId) == 0 {
+		return Create(column)
+	}
+
+	// the root column has no field id, its fields are the top level columns
+	fields := iy.columns
+	fieldId, ok := getFieldId(column)
+	if ok {
+		identity := iy.columnsByFieldId[fieldId]
+		if identity == nil {
+			return NewIdBasedFieldMapper(make(map[string]*MothColumn))
+		}
+		fields = identity.GetChildren()
+	}
+	nestedColumns := make(map[string]*MothColumn)
+	for _, field := range fields.ToArray() {
+		nestedColumn := nestedColumnsByFieldId[field.GetId()]
+		if nestedColumn != nil {
+			nestedColumns[strings.ToLower(field.GetName())] = nestedColumn
+		}
+	}
+	return NewIdBasedFieldMapper(nestedColumns)
+}
+
+func getFieldId(column *MothColumn) (int32, bool) {
+	value, ok := column.GetAttributes()[metadata.ICEBERG_ID_KEY]
+	if !ok {
+		return 0, false
+	}
+	fieldId, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		panic(common.NewMothCorruptionError(column.GetMothDataSourceId(), "Invalid field id '%s' of column %s", value, column.GetPath()))
+	}
+	return int32(fieldId), true
+}
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+type fieldIdPointV1 struct {
+	X int32 `moth:"x"`
+	Y int32 `moth:"y"`
+}
+
+type fieldIdRowV1 struct {
+	Id    int64            `moth:"id"`
+	Name  string           `moth:"name"`
+	Point fieldIdPointV1   `moth:"point"`
+	Tags  map[string]int32 `moth:"tags"`
+}
+
+// the columns renamed and reordered, with an added column and field
+type fieldIdPointV2 struct {
+	Down  int32  `moth:"down"`
+	Right int64  `moth:"right"`
+	Z     *int32 `moth:"z"`
+}
+
+type fieldIdRowV2 struct {
+	Location fieldIdPointV2   `moth:"location"`
+	Label    string           `moth:"label"`
+	Key      int64            `moth:"key"`
+	Added    *string          `moth:"added"`
+	Labels   map[string]int64 `moth:"labels"`
+}
+
+func writeFieldIdFile(t *testing.T, rows []fieldIdRowV1, columns *util.ArrayList[*metadata.ColumnIdentity]) []byte {
+	schema := GetRowSchema[fieldIdRowV1]()
+	mothTypes := schema.GetMothTypes()
+	if columns != nil {
+		mothTypes = metadata.AddFieldIds(mothTypes, columns)
+	}
+	out := new(memoryWriteCloser)
+	writer := NewMothWriter(NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), schema.GetColumnNames(), schema.GetTypes(), mothTypes, metadata.NONE, NewMothWriterOptions(), util.EmptyMap[string, string](), NewMothWriterStats())
+	pageBuilder := spi.NewPageBuilder(schema.GetTypes())
+	for i := range rows {
+		pageBuilder.DeclarePosition()
+		schema.writeRow(reflect.ValueOf(rows[i]), pageBuilder.GetBlockBuilder)
+	}
+	writer.Write(pageBuilder.Build())
+	if err := writer.TryClose(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func readFieldIdFile(data []byte, columns *util.ArrayList[*metadata.ColumnIdentity]) []fieldIdRowV2 {
+	reader := openEvolutionFile(data)
+	schema := GetRowSchema[fieldIdRowV2]()
+	evolution := NewSchemaEvolution2(reader, schema.GetColumnNames(), schema.GetTypes(), NewIdBasedFieldMapperFactory(columns))
+	recordReader := reader.CreateRecordReader3(evolution, TRUE, nil, memory.NewSimpleAggregatedMemoryContext(), MAX_BATCH_SIZE)
+	defer recordReader.Close()
+	rows := make([]fieldIdRowV2, 0)
+	for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+		page = page.GetLoadedPage()
+		blocks := make([]block.Block, page.GetChannelCount())
+		for channel := range blocks {
+			blocks[channel] = page.GetBlock(int32(channel))
+		}
+		for position := util.INT32_ZERO; position < page.GetPositionCount(); position++ {
+			var row fieldIdRowV2
+			schema.readRow(blocks, position, reflect.ValueOf(&row).Elem())
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func TestIdBasedFieldMapper(t *testing.T) {
+	rows := make([]fieldIdRowV1, 20)
+	for i := range rows {
+		rows[i] = fieldIdRowV1{Id: int64(i), Name: fmt.Sprintf("name-%d", i), Point: fieldIdPointV1{X: int32(i), Y: int32(-i)}, Tags: map[string]int32{"a": int32(i)}}
+	}
+	writeColumns := util.NewArrayList(
+		metadata.NewColumnIdentity(1, "id"),
+		metadata.NewColumnIdentity(2, "name"),
+		metadata.NewColumnIdentity(3, "point", metadata.NewColumnIdentity(4, "x"), metadata.NewColumnIdentity(5, "y")),
+		metadata.NewColumnIdentity(6, "tags", metadata.NewColumnIdentity(7, "key"), metadata.NewColumnIdentity(8, "value")))
+	readColumns := util.NewArrayList(
+		metadata.NewColumnIdentity(3, "location", metadata.NewColumnIdentity(5, "down"), metadata.NewColumnIdentity(4, "right"), metadata.NewColumnIdentity(9, "z")),
+		metadata.NewColumnIdentity(2, "label"),
+		metadata.NewColumnIdentity(1, "key"),
+		metadata.NewColumnIdentity(10, "added"),
+		metadata.NewColumnIdentity(6, "labels", metadata.NewColumnIdentity(7, "key"), metadata.NewColumnIdentity(8, "value")))
+
+	data := writeFieldIdFile(t, rows, writeColumns)
+	reader := openEvolutionFile(data)
+	if fieldId := reader.GetFooter().GetTypes().Get(reader.GetRootColumn().GetNestedColumns().Get(2).GetNestedColumns().Get(1).GetColumnId()).GetAttributes()[metadata.ICEBERG_ID_KEY]; fieldId != "5" {
+		t.Fatalf("wrote field id %s, want 5", fieldId)
+	}
+	got := readFieldIdFile(data, readColumns)
+	if len(got) != len(rows) {
+		t.Fatalf("read %d rows, want %d", len(got), len(rows))
+	}
+	for i, row := range got {
+		want := fieldIdRowV2{Location: fieldIdPointV2{Down: int32(-i), Right: int64(i)}, Label: fmt.Sprintf("name-%d", i), Key: int64(i), Labels: map[string]int64{"a": int64(i)}}
+		if !reflect.DeepEqual(row, want) {
+			t.Fatalf("read %+v, want %+v", row, want)
+		}
+	}
+
+	// the columns of files written without field ids are mapped by name
+	got = readFieldIdFile(writeFieldIdFile(t, rows, nil), readColumns)
+	if len(got) != len(rows) || got[3].Key != 0 || got[3].Label != "" || got[3].Labels != nil {
+		t.Fatalf("read %+v from a file without field ids", got[3])
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -59,7 +60,7 @@ func (lr *ListColumnReader) ReadBlock() block.Block {
 		}
 		if lr.readOffset > 0 {
 			if lr.lengthStream == nil {
-				panic("Value is not null but data stream is not present")
+				panic(common.NewMothCorruptionError(lr.column.GetMothDataSourceId(), "Value is not null but data stream is not present"))
 			}
 			elementSkipSize := lr.lengthStream.Sum(lr.readOffset)
 			i, _ := util.ToInt32Exact(elementSkipSize)
@@ -70,7 +71,7 @@ func (lr *ListColumnReader) ReadBlock() block.Block {
 	var nullVector []bool = nil
 	if lr.presentStream == nil {
 		if lr.lengthStream == nil {
-			panic("Value is not null but data stream is not present")
+			panic(common.NewMothCorruptionError(lr.column.GetMothDataSourceId(), "Value is not null but data stream is not present"))
 		}
 		lr.lengthStream.Next3(offsetVector, lr.nextBatchSize)
 	} else {
@@ -78,7 +79,7 @@ func (lr *ListColumnReader) ReadBlock() block.Block {
 		nullValues := lr.presentStream.GetUnsetBits(lr.nextBatchSize, nullVector)
 		if nullValues != lr.nextBatchSize {
 			if lr.lengthStream == nil {
-				panic("Value is not null but data stream is not present")
+				panic(common.NewMothCorruptionError(lr.column.GetMothDataSourceId(), "Value is not null but data stream is not present"))
 			}
 			lr.lengthStream.Next3(offsetVector, lr.nextBatchSize-nullValues)
 			UnpackLengthNulls(offsetVector, nullVector, lr.nextBatchSize-nullValues)
@@ -24,14 +24,14 @@ type ListColumnWriter struct {
 	closed                   bool
 }
 
-func NewListColumnWriter(columnId metadata.MothColumnId, compression metadata.CompressionKind, bufferSize int32, elementWriter ColumnWriter) *ListColumnWriter {
+func NewListColumnWriter(columnId metadata.MothColumnId, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, elementWriter ColumnWriter) *ListColumnWriter {
 	lr := new(ListColumnWriter)
 	lr.columnId = columnId
 	lr.compressed = compression != metadata.NONE
 	lr.columnEncoding = metadata.NewColumnEncoding(metadata.DIRECT_V2, 0)
 	lr.elementWriter = elementWriter
-	lr.lengthStream = CreateLengthOutputStream(compression, bufferSize)
-	lr.presentStream = NewPresentOutputStream(compression, bufferSize)
+	lr.lengthStream = CreateLengthOutputStream(compression, compressionLevel, bufferSize)
+	lr.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 	lr.rowGroupColumnStatistics = util.NewArrayList[*metadata.ColumnStatistics]()
 	return lr
 }
@@ -117,21 +117,27 @@ func unpack1Unaligned(buffer []int64, outputIndex int32, length int32, value int
 	case 7:
 		buffer[outputIndex+6] = maths.UnsignedRightShift(int64(0b0000_0010&value), 1)
 		//noinspection fallthrough
+		fallthrough
 	case 6:
 		buffer[outputIndex+5] = maths.UnsignedRightShift(int64(0b0000_0100&value), 2)
 		//noinspection fallthrough
+		fallthrough
 	case 5:
 		buffer[outputIndex+4] = maths.UnsignedRightShift(int64(0b0000_1000&value), 3)
 		//noinspection fallthrough
+		fallthrough
 	case 4:
 		buffer[outputIndex+3] = maths.UnsignedRightShift(int64(0b0001_0000&value), 4)
 		//noinspection fallthrough
+		fallthrough
 	case 3:
 		buffer[outputIndex+2] = maths.UnsignedRightShift(int64(0b0010_0000&value), 5)
 		//noinspection fallthrough
+		fallthrough
 	case 2:
 		buffer[outputIndex+1] = maths.UnsignedRightShift(int64(0b0100_0000&value), 6)
 		//noinspection fallthrough
+		fallthrough
 	case 1:
 		buffer[outputIndex] = maths.UnsignedRightShift(int64(0b1000_0000&value), 7)
 	}
@@ -171,9 +177,11 @@ func unpack2Unaligned(buffer []int64, outputIndex int32, length int32, value int
 	case 3:
 		buffer[outputIndex+2] = maths.UnsignedRightShift(int64(0b0000_1100&value), 2)
 		//noinspection fallthrough
+		fallthrough
 	case 2:
 		buffer[outputIndex+1] = maths.UnsignedRightShift(int64(0b0011_0000&value), 4)
 		//noinspection fallthrough
+		fallthrough
 	case 1:
 		buffer[outputIndex] = maths.UnsignedRightShift(int64(0b1100_0000&value), 6)
 	}
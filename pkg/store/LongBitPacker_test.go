@@ -0,0 +1,25 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLongBitPacker_UnpackUnaligned(t *testing.T) {
+	for length := int32(1); length < 8; length++ {
+		buffer := make([]int64, 8)
+		unpack1Unaligned(buffer, 1, length, 0b1011_0110)
+		want := append([]int64{0}, []int64{1, 0, 1, 1, 0, 1, 1}[:length]...)
+		if !reflect.DeepEqual(buffer[:length+1], want) {
+			t.Fatalf("unpacked %d bits as %v", length, buffer)
+		}
+	}
+	for length := int32(1); length < 4; length++ {
+		buffer := make([]int64, 4)
+		unpack2Unaligned(buffer, 1, length, 0b11_01_10_00)
+		want := append([]int64{0}, []int64{3, 1, 2}[:length]...)
+		if !reflect.DeepEqual(buffer[:length+1], want) {
+			t.Fatalf("unpacked %d pairs of bits as %v", length, buffer)
+		}
+	}
+}
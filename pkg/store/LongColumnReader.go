@@ -1,12 +1,12 @@
 package store
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -65,7 +65,7 @@ func (lr *LongColumnReader) ReadBlock() block.Block {
 		}
 		if lr.readOffset > 0 {
 			if lr.dataStream == nil {
-				panic("Value is not null but data stream is missing")
+				panic(common.NewMothCorruptionError(lr.column.GetMothDataSourceId(), "Value is not null but data stream is missing"))
 			}
 			lr.dataStream.Skip(int64(lr.readOffset))
 		}
@@ -73,7 +73,7 @@ func (lr *LongColumnReader) ReadBlock() block.Block {
 	var b block.Block
 	if lr.dataStream == nil {
 		if lr.presentStream == nil {
-			panic("Value is null but present stream is missing")
+			panic(common.NewMothCorruptionError(lr.column.GetMothDataSourceId(), "Value is null but present stream is missing"))
 		}
 		lr.presentStream.Skip(int64(lr.nextBatchSize))
 		b = block.CreateRunLengthEncodedBlock(lr.kind, nil, lr.nextBatchSize)
@@ -126,7 +126,7 @@ func (lr *LongColumnReader) readNonNullBlock() block.Block {
 		lr.dataStream.Next4(values, lr.nextBatchSize)
 		return block.NewShortArrayBlock(lr.nextBatchSize, optional.Empty[[]bool](), values)
 	}
-	panic(fmt.Sprintf("Unsupported type %s", lr.kind))
+	panic(common.NewMothUnsupportedError(lr.column.GetMothDataSourceId(), "Unsupported type %s", lr.kind))
 }
 
 func (lr *LongColumnReader) maybeTransformValues(values []int64, nextBatchSize int32) {
@@ -148,7 +148,7 @@ func (lr *LongColumnReader) readNullBlock(isNull []bool, nonNullCount int32) blo
 	if flag {
 		return lr.shortReadNullBlock(isNull, nonNullCount)
 	}
-	panic(fmt.Sprintf("Unsupported type %s", lr.kind))
+	panic(common.NewMothUnsupportedError(lr.column.GetMothDataSourceId(), "Unsupported type %s", lr.kind))
 }
 
 func (lr *LongColumnReader) longReadNullBlock(isNull []bool, nonNullCount int32) block.Block {
@@ -197,7 +197,7 @@ func (lr *LongColumnReader) openRowGroup() {
 
 	de := lr.dataStreamSource.OpenStream()
 	if de != nil {
-		lr.dataStream = de.(*LongInputStreamV2)
+		lr.dataStream = de.(LongInputStream)
 	} else {
 		lr.dataStream = nil
 	}
@@ -26,14 +26,14 @@ type LongColumnWriter struct {
 	closed                    bool
 }
 
-func NewLongColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, bufferSize int32, statisticsBuilderSupplier function.Supplier[metadata.LongValueStatisticsBuilder]) *LongColumnWriter {
+func NewLongColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, statisticsBuilderSupplier function.Supplier[metadata.LongValueStatisticsBuilder]) *LongColumnWriter {
 	lr := new(LongColumnWriter)
 	lr.columnId = columnId
 	lr.kind = kind
 	lr.compressed = compression != metadata.NONE
 	lr.columnEncoding = metadata.NewColumnEncoding(metadata.DIRECT_V2, 0)
-	lr.dataStream = NewLongOutputStreamV2(compression, bufferSize, true, metadata.DATA)
-	lr.presentStream = NewPresentOutputStream(compression, bufferSize)
+	lr.dataStream = NewLongOutputStreamV2(compression, compressionLevel, bufferSize, true, metadata.DATA)
+	lr.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 	lr.statisticsBuilderSupplier = statisticsBuilderSupplier
 	lr.statisticsBuilder = statisticsBuilderSupplier.Get()
 	lr.rowGroupColumnStatistics = util.NewArrayList[*metadata.ColumnStatistics]()
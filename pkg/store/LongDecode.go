@@ -1,9 +1,9 @@
 package store
 
 import (
-	"fmt"
-
+	"github.com/mothdb-bd/orc-go/pkg/maths"
 	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 )
 
 type FixedBitSizes_V1 int8
@@ -88,20 +88,17 @@ func ReadSignedVInt(inputStream *MothInputStream) int64 {
 func ReadUnsignedVInt(inputStream *MothInputStream) int64 {
 	var result int64 = 0
 	var offset int64 = 0
-	b, err := inputStream.ReadBS()
-
-	if err != nil {
-		panic("EOF while reading unsigned vint")
-	}
-	for (b & 0b1000_0000) != 0 {
-		result |= int64(b&0b0111_1111) << offset
-		offset += 7
+	for {
 		b, err := inputStream.ReadBS()
 		if err != nil {
-			panic(fmt.Sprintf("EOF while reading unsigned vint %d", b))
+			panic(common.NewMothCorruptionError(nil, "EOF while reading unsigned vint"))
+		}
+		result |= int64(b&0b0111_1111) << offset
+		if (b & 0b1000_0000) == 0 {
+			return result
 		}
+		offset += 7
 	}
-	return result
 }
 
 func ReadVInt(signed bool, inputStream *MothInputStream) int64 {
@@ -126,12 +123,12 @@ func WriteVLong(buffer mothio.DataOutput, value int64, signed bool) {
 func WriteVLongUnsigned(output mothio.DataOutput, value int64) {
 	for {
 		// if there are less than 7 bits left, we are done
-		if (value & 0b111_1111) == 0 {
+		if (value & ^0b111_1111) == 0 {
 			output.WriteByte(byte(value))
 			return
 		} else {
 			output.WriteByte((byte)(0x80 | (value & 0x7f)))
-			value >>= uint64(7)
+			value = maths.UnsignedRightShift(value, 7)
 		}
 	}
 }
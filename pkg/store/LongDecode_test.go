@@ -0,0 +1,40 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/optional"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+func TestWriteVLong(t *testing.T) {
+	buffer := NewMothOutputBuffer(metadata.NONE, 0, 1024)
+	WriteVLong(buffer, 300, false)
+	buffer.Close()
+	output := slice.NewDynamicSliceOutput(16)
+	buffer.WriteDataTo(output)
+	if data := output.Slice().AvailableBytes(); !reflect.DeepEqual(data, []byte{0xac, 0x02}) {
+		t.Fatalf("wrote 300 as %x", data)
+	}
+
+	values := []int64{0, 1, 127, 128, 300, 16383, 16384, 1 << 40, -1, -300, 1<<63 - 1, -1 << 63}
+	for _, signed := range []bool{true, false} {
+		buffer := NewMothOutputBuffer(metadata.NONE, 0, 1024)
+		for _, value := range values {
+			WriteVLong(buffer, value, signed)
+		}
+		buffer.Close()
+		output := slice.NewDynamicSliceOutput(256)
+		buffer.WriteDataTo(output)
+		input := NewMothInputStream(CreateChunkLoader(common.NewMothDataSourceId("test"), output.Slice(), optional.Empty[MothDecompressor](), memory.NewSimpleAggregatedMemoryContext()))
+		for _, value := range values {
+			if read := ReadVInt(signed, input); read != value {
+				t.Fatalf("read %d for %d, signed %t", read, value, signed)
+			}
+		}
+	}
+}
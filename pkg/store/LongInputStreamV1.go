@@ -2,6 +2,7 @@ package store
 
 import (
 	"github.com/mothdb-bd/orc-go/pkg/maths"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
@@ -39,7 +40,7 @@ func (l1 *LongInputStreamV1) readValues() {
 	control, err := l1.input.ReadBS()
 
 	if err != nil {
-		panic("Read past end of RLE integer")
+		panic(common.NewMothCorruptionError(l1.input.GetMothDataSourceId(), "Read past end of RLE integer"))
 	}
 	controlInt := int32(control)
 	if control < 0x80 {
@@ -48,7 +49,7 @@ func (l1 *LongInputStreamV1) readValues() {
 		l1.repeat = true
 		delta, err := l1.input.ReadBS()
 		if err != nil {
-			panic("End of stream in RLE Integer")
+			panic(common.NewMothCorruptionError(l1.input.GetMothDataSourceId(), "End of stream in RLE Integer"))
 		}
 		l1.delta = int32(byte(delta))
 		l1.literals[0] = ReadVInt(l1.signed, l1.input)
@@ -117,7 +118,7 @@ func (l1 *LongInputStreamV1) Next3(values []int32, items int32) {
 				literal := l1.literals[0] + int64((l1.used+i)*l1.delta)
 				value := int32(literal)
 				if int32(literal) != value {
-					panic("Decoded value out of range for a 32bit number")
+					panic(common.NewMothCorruptionError(l1.input.GetMothDataSourceId(), "Decoded value out of range for a 32bit number"))
 				}
 				values[offset+i] = value
 			}
@@ -126,7 +127,7 @@ func (l1 *LongInputStreamV1) Next3(values []int32, items int32) {
 				literal := l1.literals[l1.used+i]
 				value := int32(literal)
 				if int32(literal) != value {
-					panic("Decoded value out of range for a 32bit number")
+					panic(common.NewMothCorruptionError(l1.input.GetMothDataSourceId(), "Decoded value out of range for a 32bit number"))
 				}
 				values[offset+i] = value
 			}
@@ -152,7 +153,7 @@ func (l1 *LongInputStreamV1) Next4(values []int16, items int32) {
 				literal := l1.literals[0] + int64((l1.used+i)*l1.delta)
 				value := int16(literal)
 				if literal != int64(value) {
-					panic("Decoded value out of range for a 16bit number")
+					panic(common.NewMothCorruptionError(l1.input.GetMothDataSourceId(), "Decoded value out of range for a 16bit number"))
 				}
 				values[offset+i] = value
 			}
@@ -161,7 +162,7 @@ func (l1 *LongInputStreamV1) Next4(values []int16, items int32) {
 				literal := l1.literals[l1.used+i]
 				value := int16(literal)
 				if literal != int64(value) {
-					panic("Decoded value out of range for a 16bit number")
+					panic(common.NewMothCorruptionError(l1.input.GetMothDataSourceId(), "Decoded value out of range for a 16bit number"))
 				}
 				values[offset+i] = value
 			}
@@ -201,3 +202,8 @@ func (l1 *LongInputStreamV1) Skip(items int64) {
 func (l1 *LongInputStreamV1) String() string {
 	return "LongInputStreamV1"
 }
+
+// @Override
+func (l1 *LongInputStreamV1) Sum(items int32) int64 {
+	return SumDefault(l1, items)
+}
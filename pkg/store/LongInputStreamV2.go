@@ -3,6 +3,7 @@ package store
 import (
 	"github.com/mothdb-bd/orc-go/pkg/maths"
 	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
@@ -50,7 +51,7 @@ func (l2 *LongInputStreamV2) readValues() {
 	firstByte, err := l2.input.ReadBS()
 	firstInt := int32(firstByte)
 	if err != nil {
-		panic("Read past end of RLE integer")
+		panic(common.NewMothCorruptionError(l2.input.GetMothDataSourceId(), "Read past end of RLE integer"))
 	}
 	enc := (maths.UnsignedRightShiftInt32(firstInt, 6)) & 0x03
 	if SHORT_REPEAT.ordinal() == enc {
@@ -159,7 +160,7 @@ func (l2 *LongInputStreamV2) Next3(values []int32, items int32) {
 			literal := l2.literals[l2.used+i]
 			var value int32 = int32(literal)
 			if literal != int64(value) {
-				panic("Decoded value out of range for a 32bit number")
+				panic(common.NewMothCorruptionError(l2.input.GetMothDataSourceId(), "Decoded value out of range for a 32bit number"))
 			}
 			values[offset+i] = value
 		}
@@ -183,7 +184,7 @@ func (l2 *LongInputStreamV2) Next4(values []int16, items int32) {
 			literal := l2.literals[l2.used+i]
 			value := int16(literal)
 			if literal != int64(value) {
-				panic("Decoded value out of range for a 16bit number")
+				panic(common.NewMothCorruptionError(l2.input.GetMothDataSourceId(), "Decoded value out of range for a 16bit number"))
 			}
 			values[offset+i] = value
 		}
@@ -278,7 +279,7 @@ func (l2 *LongInputStreamV2) readPatchedBaseValues(firstByte int32) {
 	l2.packer.Unpack(unpacked, 0, length, fb, l2.input)
 	unpackedPatch := make([]int64, patchListLength)
 	if patchWidth+patchGapWidth > 64 && !l2.skipCorrupt {
-		panic("Invalid RLEv2 encoded stream")
+		panic(common.NewMothCorruptionError(l2.input.GetMothDataSourceId(), "Invalid RLEv2 encoded stream"))
 	}
 	bitSize := GetClosestFixedBits(patchWidth + patchGapWidth)
 	l2.packer.Unpack(unpackedPatch, 0, patchListLength, bitSize, l2.input)
@@ -325,3 +326,8 @@ func (l2 *LongInputStreamV2) readPatchedBaseValues(firstByte int32) {
 func (l2 *LongInputStreamV2) String() string {
 	return "LongInputStreamV2"
 }
+
+// @Override
+func (l2 *LongInputStreamV2) Sum(items int32) int64 {
+	return SumDefault(l2, items)
+}
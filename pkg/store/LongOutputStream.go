@@ -13,6 +13,6 @@ type LongOutputStream interface {
 	WriteLong(value int64)
 }
 
-func CreateLengthOutputStream(compression metadata.CompressionKind, bufferSize int32) LongOutputStream {
-	return NewLongOutputStreamV2(compression, bufferSize, false, metadata.LENGTH)
+func CreateLengthOutputStream(compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) LongOutputStream {
+	return NewLongOutputStreamV2(compression, compressionLevel, bufferSize, false, metadata.LENGTH)
 }
@@ -32,7 +32,7 @@ type LongOutputStreamV1 struct {
 	closed         bool
 }
 
-func NewLongOutputStreamV1(compression metadata.CompressionKind, bufferSize int32, signed bool, streamKind metadata.StreamKind) *LongOutputStreamV1 {
+func NewLongOutputStreamV1(compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, signed bool, streamKind metadata.StreamKind) *LongOutputStreamV1 {
 	l1 := new(LongOutputStreamV1)
 
 	l1.checkpoints = util.NewArrayList[LongStreamCheckpoint]()
@@ -40,7 +40,7 @@ func NewLongOutputStreamV1(compression metadata.CompressionKind, bufferSize int3
 	l1.lastDelta = LONG_OUTPUTV1_UNMATCHABLE_DELTA_VALUE
 
 	l1.streamKind = streamKind
-	l1.buffer = NewMothOutputBuffer(compression, bufferSize)
+	l1.buffer = NewMothOutputBuffer(compression, compressionLevel, bufferSize)
 	l1.signed = signed
 	return l1
 }
@@ -75,7 +75,7 @@ type LongOutputStreamV2 struct {
 	closed              bool
 }
 
-func NewLongOutputStreamV2(compression metadata.CompressionKind, bufferSize int32, signed bool, streamKind metadata.StreamKind) *LongOutputStreamV2 {
+func NewLongOutputStreamV2(compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, signed bool, streamKind metadata.StreamKind) *LongOutputStreamV2 {
 	l2 := new(LongOutputStreamV2)
 
 	l2.checkpoints = util.NewArrayList[LongStreamCheckpoint]()
@@ -87,7 +87,7 @@ func NewLongOutputStreamV2(compression metadata.CompressionKind, bufferSize int3
 	l2.utils = NewSerializationUtils()
 
 	l2.streamKind = streamKind
-	l2.buffer = NewMothOutputBuffer(compression, bufferSize)
+	l2.buffer = NewMothOutputBuffer(compression, compressionLevel, bufferSize)
 	l2.signed = signed
 	return l2
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -61,7 +62,7 @@ func (mr *MapColumnReader) ReadBlock() block.Block {
 		}
 		if mr.readOffset > 0 {
 			if mr.lengthStream == nil {
-				panic("Value is not null but data stream is not present")
+				panic(common.NewMothCorruptionError(mr.column.GetMothDataSourceId(), "Value is not null but data stream is not present"))
 			}
 			entrySkipSize := mr.lengthStream.Sum(mr.readOffset)
 			kv, _ := util.ToInt32Exact(entrySkipSize)
@@ -73,7 +74,7 @@ func (mr *MapColumnReader) ReadBlock() block.Block {
 	var nullVector []bool = nil
 	if mr.presentStream == nil {
 		if mr.lengthStream == nil {
-			panic("Value is not null but data stream is not present")
+			panic(common.NewMothCorruptionError(mr.column.GetMothDataSourceId(), "Value is not null but data stream is not present"))
 		}
 		mr.lengthStream.Next3(offsetVector, mr.nextBatchSize)
 	} else {
@@ -81,7 +82,7 @@ func (mr *MapColumnReader) ReadBlock() block.Block {
 		nullValues := mr.presentStream.GetUnsetBits(mr.nextBatchSize, nullVector)
 		if nullValues != mr.nextBatchSize {
 			if mr.lengthStream == nil {
-				panic("Value is not null but data stream is not present")
+				panic(common.NewMothCorruptionError(mr.column.GetMothDataSourceId(), "Value is not null but data stream is not present"))
 			}
 			mr.lengthStream.Next3(offsetVector, mr.nextBatchSize-nullValues)
 			UnpackLengthNulls(offsetVector, nullVector, mr.nextBatchSize-nullValues)
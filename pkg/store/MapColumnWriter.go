@@ -25,15 +25,15 @@ type MapColumnWriter struct {
 	closed                   bool
 }
 
-func NewMapColumnWriter(columnId metadata.MothColumnId, compression metadata.CompressionKind, bufferSize int32, keyWriter ColumnWriter, valueWriter ColumnWriter) *MapColumnWriter {
+func NewMapColumnWriter(columnId metadata.MothColumnId, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, keyWriter ColumnWriter, valueWriter ColumnWriter) *MapColumnWriter {
 	mr := new(MapColumnWriter)
 	mr.columnId = columnId
 	mr.compressed = compression != metadata.NONE
 	mr.columnEncoding = metadata.NewColumnEncoding(metadata.DIRECT_V2, 0)
 	mr.keyWriter = keyWriter
 	mr.valueWriter = valueWriter
-	mr.lengthStream = CreateLengthOutputStream(compression, bufferSize)
-	mr.presentStream = NewPresentOutputStream(compression, bufferSize)
+	mr.lengthStream = CreateLengthOutputStream(compression, compressionLevel, bufferSize)
+	mr.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 	mr.rowGroupColumnStatistics = util.NewArrayList[*metadata.ColumnStatistics]()
 	return mr
 }
@@ -16,6 +16,8 @@ type MothColumn struct {
 	nestedColumns *util.ArrayList[*MothColumn]
 	// private final Map<String, String> attributes;
 	attributes map[string]string
+	// the timestamps of the file are stored relative to 1970-01-01 UTC, see TimestampColumnReader
+	legacyTimestamps bool
 }
 
 func NewMothColumn(path string, columnId metadata.MothColumnId, columnName string, columnType metadata.MothTypeKind, mothDataSourceId *common.MothDataSourceId, nestedColumns *util.ArrayList[*MothColumn], attributes map[string]string) *MothColumn {
@@ -58,6 +60,10 @@ func (mn *MothColumn) GetAttributes() map[string]string {
 	return mn.attributes
 }
 
+func (mn *MothColumn) HasLegacyTimestamps() bool {
+	return mn.legacyTimestamps
+}
+
 // @Override
 func (mn *MothColumn) String() string {
 	return util.NewSB().AddString("path", mn.path).AddString("columnId", mn.columnId.String()).AddInt8("streamType", int8(mn.columnType)).AddString("dataSource", mn.mothDataSourceId.String()).String()
@@ -9,3 +9,14 @@ type MothDataSink interface {
 	Write(outputData *util.ArrayList[MothDataOutput])
 	Close()
 }
+
+/**
+ * A data sink that can discard what was written, such as an upload to an object store. A
+ * writer that fails aborts its sink instead of closing it, so no partial file is created.
+ */
+type AbortableMothDataSink interface {
+	// 继承
+	MothDataSink
+
+	Abort()
+}
@@ -19,3 +19,14 @@ type MothDataSource interface {
 
 	String() string
 }
+
+/**
+ * A data source that knows the version of its file, e.g. its modification time or ETag, so the
+ * cached tail of an older version of the file is not used.
+ */
+type VersionedMothDataSource interface {
+	// 继承
+	MothDataSource
+
+	GetModificationStamp() string
+}
@@ -1,8 +1,6 @@
 package store
 
 import (
-	"fmt"
-
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
@@ -12,7 +10,7 @@ var MAX_BUFFER_SIZE int32 = 4 * 1024 * 1024
 
 func CreateMothDecompressor(mothDataSourceId *common.MothDataSourceId, compression metadata.CompressionKind, bufferSize int32) *optional.Optional[MothDecompressor] {
 	if (compression != metadata.NONE) && ((bufferSize <= 0) || (bufferSize > MAX_BUFFER_SIZE)) {
-		panic(fmt.Sprintf("Invalid compression block size: %d", bufferSize))
+		panic(common.NewMothCorruptionError(mothDataSourceId, "Invalid compression block size: %d", bufferSize))
 	}
 	switch compression {
 	case metadata.NONE:
@@ -30,7 +28,7 @@ func CreateMothDecompressor(mothDataSourceId *common.MothDataSourceId, compressi
 		var zstd MothDecompressor = NewMothZstdDecompressor(mothDataSourceId, bufferSize)
 		return optional.Of(zstd)
 	}
-	panic(fmt.Sprintf("Unknown compression type: %d", compression))
+	panic(common.NewMothUnsupportedError(mothDataSourceId, "Unknown compression type: %d", compression))
 }
 
 type MothDecompressor interface {
@@ -43,3 +41,12 @@ type OutputBuffer interface {
 	Initialize(size int32) []byte
 	Grow(size int32) []byte
 }
+
+/**
+ * Decompresses like decompressor.Decompress, but corrupt input is returned as a *common.MothError
+ * instead of panicking.
+ */
+func TryDecompress(decompressor MothDecompressor, input []byte, offset int32, length int32, output OutputBuffer) (size int32, err error) {
+	defer recoverMothError(&err, nil)
+	return decompressor.Decompress(input, offset, length, output), nil
+}
@@ -0,0 +1,15 @@
+package store
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+)
+
+/**
+ * Deferred by the error returning variants of the panicking APIs: stores the recovered failure
+ * of the call in err as a *common.MothError.
+ */
+func recoverMothError(err *error, mothDataSourceId *common.MothDataSourceId) {
+	if failure := recover(); failure != nil {
+		*err = common.AsMothError(failure, mothDataSourceId)
+	}
+}
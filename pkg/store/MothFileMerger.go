@@ -0,0 +1,176 @@
+package store
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/optional"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Merges small moth files into one file. The stripes of compatible files, with the same types,
+ * compression, buffer size, row group size and key column, are copied verbatim with their key
+ * indexes and only the footer, the stripe statistics and the file statistics are rebuilt. Incompatible files are decoded and
+ * re-encoded with a MothWriter, reading every file with the schema of the first file as
+ * described by SchemaEvolution.
+ */
+type MothFileMerger struct {
+	readerOptions *MothReaderOptions
+	writerOptions *MothWriterOptions
+}
+
+func NewMothFileMerger(readerOptions *MothReaderOptions, writerOptions *MothWriterOptions) *MothFileMerger {
+	mr := new(MothFileMerger)
+	mr.readerOptions = readerOptions
+	mr.writerOptions = writerOptions
+	return mr
+}
+
+/**
+ * Merges the files in the order of the sources into the sink and closes the sink. Empty files
+ * are skipped, the sink is left empty when all files are empty. Returns true when the stripes
+ * were copied, false when the rows were re-encoded.
+ */
+func (mr *MothFileMerger) Merge(sources *util.ArrayList[MothDataSource], mothDataSink MothDataSink) bool {
+	readers := util.NewArrayList[*MothReader]()
+	for _, source := range sources.ToArray() {
+		reader := CreateMothReader(source, mr.readerOptions)
+		if reader.IsPresent() {
+			readers.Add(reader.Get())
+		}
+	}
+	if readers.IsEmpty() {
+		mothDataSink.Close()
+		return false
+	}
+	// the stripe footers and indexes of the flavours are framed differently
+	if mr.readerOptions.GetFormatFlavor() == mr.writerOptions.GetFormatFlavor() && isStripeCopyCompatible(readers) {
+		mr.copyStripes(readers, mothDataSink)
+		return true
+	}
+	mr.rewriteRows(readers, mothDataSink)
+	return false
+}
+
+/**
+ * Like Merge, but a failure to merge the files is returned as a *common.MothError instead of
+ * panicking.
+ */
+func (mr *MothFileMerger) TryMerge(sources *util.ArrayList[MothDataSource], mothDataSink MothDataSink) (copied bool, err error) {
+	defer recoverMothError(&err, nil)
+	return mr.Merge(sources, mothDataSink), nil
+}
+
+/**
+ * Returns if the stripes of the files can be concatenated. The statistics of files written
+ * before HIVE-8732 are not trusted, the stripes of encrypted files are bound to the keys of
+ * their file, the key indexes of the stripes must index the same key column, or no column, and
+ * the timestamps must be stored relative to the same epoch.
+ */
+func isStripeCopyCompatible(readers *util.ArrayList[*MothReader]) bool {
+	first := readers.Get(0)
+	for _, reader := range readers.ToArray() {
+		footer := reader.GetFooter()
+		if reader.GetHiveWriterVersion() != metadata.MOTH_HIVE_8732 || footer.GetEncryption().IsPresent() {
+			return false
+		}
+		if reader.GetCompressionKind() != first.GetCompressionKind() || reader.GetBufferSize() != first.GetBufferSize() {
+			return false
+		}
+		if reader.GetKeyColumn() != first.GetKeyColumn() || reader.HasLegacyTimestamps() != first.HasLegacyTimestamps() {
+			return false
+		}
+		rowsInRowGroup, firstRowsInRowGroup := footer.GetRowsInRowGroup(), first.GetFooter().GetRowsInRowGroup()
+		if rowsInRowGroup.IsPresent() != firstRowsInRowGroup.IsPresent() || rowsInRowGroup.OrElse(0) != firstRowsInRowGroup.OrElse(0) {
+			return false
+		}
+		if !reflect.DeepEqual(footer.GetTypes().List().ToArray(), first.GetFooter().GetTypes().List().ToArray()) {
+			return false
+		}
+		if reader.GetMetadata().GetStripeStatsList().Size() != footer.GetStripes().Size() || footer.GetFileStats().IsEmpty() {
+			return false
+		}
+		for _, stripeStats := range reader.GetMetadata().GetStripeStatsList().ToArray() {
+			if stripeStats.IsEmpty() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (mr *MothFileMerger) copyStripes(readers *util.ArrayList[*MothReader], mothDataSink MothDataSink) {
+	defer mothDataSink.Close()
+	first := readers.Get(0)
+	magic := mr.writerOptions.GetFormatFlavor().GetMagicSlice()
+	mothDataSink.Write(util.NewArrayList(CreateDataOutput(magic)))
+
+	var fileRowCount uint64
+	stripes := util.NewArrayList[*metadata.StripeInformation]()
+	stripeStats := util.NewArrayList[*optional.Optional[*metadata.StripeStatistics]]()
+	fileStats := util.NewArrayList[*metadata.ColumnMetadata[*metadata.ColumnStatistics]]()
+	userMetadata := make(map[string]*slice.Slice)
+	for _, reader := range readers.ToArray() {
+		footer := reader.GetFooter()
+		for i, stripe := range footer.GetStripes().ToArray() {
+			offset := uint64(mothDataSink.Size())
+			// the key index of the stripe follows the stripe
+			data := reader.GetMothDataSource().ReadFully(int64(stripe.GetOffset()), util.Int32Exact(reader.getStripeEnd(int32(i))-int64(stripe.GetOffset())))
+			mothDataSink.Write(util.NewArrayList(CreateDataOutput(data)))
+			stripes.Add(metadata.NewStripeInformation2(stripe.GetNumberOfRows(), offset, stripe.GetIndexLength(), stripe.GetDataLength(), stripe.GetFooterLength(), 0))
+		}
+		stripeStats.AddAll(reader.GetMetadata().GetStripeStatsList())
+		fileStats.Add(footer.GetFileStats().Get())
+		fileRowCount += footer.GetNumberOfRows()
+		util.PutAll(userMetadata, footer.GetUserMetadata())
+	}
+
+	metadataWriter := NewCompressedMetadataWriter(metadata.NewMothMetadataWriter2(mr.writerOptions.GetWriterIdentification(), mr.writerOptions.GetFormatFlavor()), first.GetCompressionKind(), mr.writerOptions.GetZstdCompressionLevel(), first.GetBufferSize())
+	outputData := util.NewArrayList[MothDataOutput]()
+	metadataSlice := metadataWriter.WriteMetadata(metadata.NewMetadata(stripeStats))
+	outputData.Add(CreateDataOutput(metadataSlice))
+	footer := metadata.NewFooter2(fileRowCount, first.GetFooter().GetRowsInRowGroup(), stripes, first.GetFooter().GetTypes(), toFileStats(fileStats), userMetadata, optional.Empty[uint32](), optional.Empty[*metadata.Encryption]())
+	footerSlice := metadataWriter.WriteFooter(footer)
+	outputData.Add(CreateDataOutput(footerSlice))
+	postscriptSlice := metadataWriter.WritePostscript(footerSlice.Length(), metadataSlice.Length(), first.GetCompressionKind(), first.GetBufferSize())
+	outputData.Add(CreateDataOutput(postscriptSlice))
+	s := slice.NewBaseBuf(make([]byte, 1))
+	s.WriteUInt8(uint8(postscriptSlice.Length()))
+	outputData.Add(CreateDataOutput(s))
+	mothDataSink.Write(outputData)
+}
+
+func (mr *MothFileMerger) rewriteRows(readers *util.ArrayList[*MothReader], mothDataSink MothDataSink) {
+	first := readers.Get(0)
+	mothTypes := first.GetFooter().GetTypes()
+	rootType := mothTypes.Get(metadata.ROOT_COLUMN)
+	columnNames := first.GetColumnNames()
+	types := util.NewArrayList[block.Type]()
+	for fieldId := util.INT32_ZERO; fieldId < rootType.GetFieldCount(); fieldId++ {
+		types.Add(metadata.ToBlockType(mothTypes, rootType.GetFieldTypeIndex(fieldId)))
+	}
+	userMetadata := make(map[string]string)
+	for _, reader := range readers.ToArray() {
+		for key, value := range reader.GetFooter().GetUserMetadata() {
+			userMetadata[key] = value.String()
+		}
+	}
+	// the writer records the key column of its options
+	delete(userMetadata, MOTH_KEY_COLUMN_METADATA_KEY)
+
+	writer := NewMothWriter(mothDataSink, columnNames, types, mothTypes, first.GetCompressionKind(), mr.writerOptions, userMetadata, NewMothWriterStats())
+	defer writer.Close()
+	for _, reader := range readers.ToArray() {
+		evolution := NewSchemaEvolution(reader, columnNames, types, MAP_BY_NAME)
+		recordReader := reader.CreateRecordReader3(evolution, TRUE, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+		for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+			writer.Write(page.GetLoadedPage())
+		}
+		recordReader.Close()
+	}
+}
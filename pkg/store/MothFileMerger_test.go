@@ -0,0 +1,145 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+type mergeRow struct {
+	Id   int64   `moth:"id"`
+	Name *string `moth:"name"`
+	Tags []int32 `moth:"tags"`
+}
+
+func newMergeRows(start int, count int) []mergeRow {
+	rows := make([]mergeRow, count)
+	for i := range rows {
+		id := start + i
+		rows[i] = mergeRow{Id: int64(id), Tags: []int32{int32(id), int32(-id)}}
+		if id%3 != 0 {
+			name := fmt.Sprintf("name-%d", id)
+			rows[i].Name = &name
+		}
+	}
+	return rows
+}
+
+func writeMergeFile(t *testing.T, rows []mergeRow, compression metadata.CompressionKind) MothDataSource {
+	out := new(memoryWriteCloser)
+	writer := NewRowWriter2[mergeRow](NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), compression, NewMothWriterOptions(), map[string]string{"first": fmt.Sprint(rows[0].Id)}, NewMothWriterStats())
+	writer.Write(rows)
+	if err := writer.TryClose(); err != nil {
+		t.Fatal(err)
+	}
+	return NewMemoryMothDataSource(common.NewMothDataSourceId(fmt.Sprintf("file-%d", rows[0].Id)), slice.NewWithBuf(out.Bytes()))
+}
+
+func mergeFiles(t *testing.T, sources ...MothDataSource) (*MothReader, bool) {
+	out := new(memoryWriteCloser)
+	copied, err := NewMothFileMerger(NewMothReaderOptions(), NewMothWriterOptions()).TryMerge(util.NewArrayList(sources...), NewOutputStreamMothDataSink(mothio.NewOutputStream(out)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("merged"), slice.NewWithBuf(out.Bytes())), NewMothReaderOptions()).Get(), copied
+}
+
+func TestMothFileMerger_CopyStripes(t *testing.T) {
+	empty := NewMemoryMothDataSource(common.NewMothDataSourceId("empty"), slice.NewWithBuf(make([]byte, 0)))
+	reader, copied := mergeFiles(t, writeMergeFile(t, newMergeRows(0, 100), metadata.ZLIB), empty, writeMergeFile(t, newMergeRows(100, 2000), metadata.ZLIB), writeMergeFile(t, newMergeRows(2100, 7), metadata.ZLIB))
+	if !copied {
+		t.Fatal("the stripes of compatible files were not copied")
+	}
+	if stripes := reader.GetFooter().GetStripes().Size(); stripes != 3 {
+		t.Fatalf("merged %d stripes, want 3", stripes)
+	}
+	if stripeStats := reader.GetMetadata().GetStripeStatsList().Size(); stripeStats != 3 {
+		t.Fatalf("merged %d stripe statistics, want 3", stripeStats)
+	}
+	idStats := reader.GetFooter().GetFileStats().Get().Get(reader.GetRootColumn().GetNestedColumns().Get(0).GetColumnId())
+	if idStats.GetNumberOfValues() != 2107 || idStats.GetIntegerStatistics().GetMin() != 0 || idStats.GetIntegerStatistics().GetMax() != 2106 {
+		t.Fatalf("merged id statistics %d values from %d to %d", idStats.GetNumberOfValues(), idStats.GetIntegerStatistics().GetMin(), idStats.GetIntegerStatistics().GetMax())
+	}
+	if first := reader.GetFooter().GetUserMetadata()["first"].String(); first != "2100" {
+		t.Fatalf("merged user metadata first=%s, want 2100", first)
+	}
+	rowReader := NewRowReader[mergeRow](reader)
+	defer rowReader.Close()
+	if rows := rowReader.ReadAll(); !reflect.DeepEqual(rows, newMergeRows(0, 2107)) {
+		t.Fatalf("read %d rows that differ from the merged rows", len(rows))
+	}
+}
+
+func TestMothFileMerger_RewriteRows(t *testing.T) {
+	reader, copied := mergeFiles(t, writeMergeFile(t, newMergeRows(0, 100), metadata.ZLIB), writeMergeFile(t, newMergeRows(100, 50), metadata.NONE))
+	if copied {
+		t.Fatal("copied the stripes of files with different compressions")
+	}
+	if reader.GetCompressionKind() != metadata.ZLIB || reader.GetFooter().GetNumberOfRows() != 150 {
+		t.Fatalf("merged %d rows with %s compression", reader.GetFooter().GetNumberOfRows(), reader.GetCompressionKind())
+	}
+	rowReader := NewRowReader[mergeRow](reader)
+	defer rowReader.Close()
+	if rows := rowReader.ReadAll(); !reflect.DeepEqual(rows, newMergeRows(0, 150)) {
+		t.Fatalf("read %d rows that differ from the merged rows", len(rows))
+	}
+}
+
+func TestMothFileMerger_KeyColumns(t *testing.T) {
+	first := newKeyRows(1200)
+	second := newMergeRows(5000, 700)
+	tests := []struct {
+		name    string
+		sources []MothDataSource
+	}{
+		{"different key columns", []MothDataSource{writeKeyFile(t, first, "id"), writeKeyFile(t, second, "name")}},
+		{"indexed and unindexed", []MothDataSource{writeKeyFile(t, first, "id"), writeKeyFile(t, second, "")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if isStripeCopyCompatible(util.NewArrayList(CreateMothReader(tt.sources[0], NewMothReaderOptions()).Get(), CreateMothReader(tt.sources[1], NewMothReaderOptions()).Get())) {
+				t.Fatal("the stripes of files with different key indexes are copy compatible")
+			}
+			out := new(memoryWriteCloser)
+			writerOptions := NewMothWriterOptions().WithRowGroupMaxRowCount(100).WithKeyColumn("id")
+			copied, err := NewMothFileMerger(NewMothReaderOptions(), writerOptions).TryMerge(util.NewArrayList(tt.sources...), NewOutputStreamMothDataSink(mothio.NewOutputStream(out)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("merged"), slice.NewWithBuf(out.Bytes())), NewMothReaderOptions()).Get()
+			if copied || reader.GetKeyColumn() != "id" {
+				t.Fatalf("merged the files with key column %q", reader.GetKeyColumn())
+			}
+			keyReader := reader.CreateKeyReader(memory.NewSimpleAggregatedMemoryContext())
+			defer keyReader.Close()
+			for _, row := range []mergeRow{first[0], first[1100], second[1], second[699]} {
+				if id, _ := lookupRow(t, keyReader, LongKey(row.Id)); id != row.Id {
+					t.Fatalf("looked up id %d for id %d in the merged file", id, row.Id)
+				}
+			}
+		})
+	}
+}
+
+func TestMothKeyReader_MismatchedKeyIndex(t *testing.T) {
+	// concatenates stripes indexed by name with stripes indexed by id, which Merge refuses to do
+	readers := util.NewArrayList(CreateMothReader(writeKeyFile(t, newKeyRows(300), "name"), NewMothReaderOptions()).Get(), CreateMothReader(writeKeyFile(t, newMergeRows(5000, 300), "id"), NewMothReaderOptions()).Get())
+	out := new(memoryWriteCloser)
+	NewMothFileMerger(NewMothReaderOptions(), NewMothWriterOptions()).copyStripes(readers, NewOutputStreamMothDataSink(mothio.NewOutputStream(out)))
+	reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("mismatched"), slice.NewWithBuf(out.Bytes())), NewMothReaderOptions()).Get()
+	keyReader := reader.CreateKeyReader(memory.NewSimpleAggregatedMemoryContext())
+	defer keyReader.Close()
+	if id, _ := lookupRow(t, keyReader, LongKey(5001)); id != 5001 {
+		t.Fatalf("looked up id %d for id 5001", id)
+	}
+	if _, err := keyReader.TryLookup([]byte("name-1")); err == nil {
+		t.Fatal("looked up a row whose key differs from the key index")
+	}
+}
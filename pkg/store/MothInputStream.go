@@ -1,7 +1,6 @@
 package store
 
 import (
-	"fmt"
 	"io"
 
 	"github.com/mothdb-bd/orc-go/pkg/maths"
@@ -89,7 +88,7 @@ func (mm *MothInputStream) SkipFully(length int64) {
 	for length > 0 {
 		result := mm.Skip(length)
 		if result < 0 {
-			panic(fmt.Sprintf("%s Unexpected end of stream", mm.chunkLoader.GetMothDataSourceId()))
+			panic(common.NewMothCorruptionError(mm.chunkLoader.GetMothDataSourceId(), "Unexpected end of stream"))
 		}
 		length -= result
 	}
@@ -99,7 +98,7 @@ func (mm *MothInputStream) ReadFully(buffer []byte, offset int, length int) {
 	for offset < length {
 		result, err := mm.ReadBS3(buffer, offset, length-offset)
 		if err == io.EOF {
-			panic(fmt.Sprintf("%s Unexpected end of stream", mm.chunkLoader.GetMothDataSourceId()))
+			panic(common.NewMothCorruptionError(mm.chunkLoader.GetMothDataSourceId(), "Unexpected end of stream"))
 		}
 		offset += result
 	}
@@ -111,7 +110,7 @@ func (mm *MothInputStream) ReadFully2(buffer *slice.Slice, offset int, length in
 			mm.advance()
 		}
 		if mm.current == nil {
-			panic(fmt.Sprintf("%s Unexpected end of stream", mm.chunkLoader.GetMothDataSourceId()))
+			panic(common.NewMothCorruptionError(mm.chunkLoader.GetMothDataSourceId(), "Unexpected end of stream"))
 		}
 		chunkSize := maths.MinInt(length, int(mm.current.Remaining()))
 		mm.current.ReadSlice4(buffer, int32(offset), int32(chunkSize))
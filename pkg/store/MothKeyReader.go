@@ -0,0 +1,201 @@
+package store
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+var MOTH_KEY_INSTANCE_SIZE int32 = util.SizeOf(&metadata.Key{})
+
+/**
+ * Reads single rows by their key. The sorted key index of a stripe is read on the first search of
+ * the stripe, a key is found by a binary search of the indexes and its row is read by seeking to
+ * the row group of the row, see MothRecordReader.ReadRows. The key index of an encrypted key
+ * column is only readable with the key of its variant. A reader is not safe for concurrent use.
+ */
+type MothKeyReader struct {
+	reader       *MothReader
+	memoryUsage  memory.AggregatedMemoryContext
+	indexMemory  memory.LocalMemoryContext
+	stripes      []*stripeKeyIndex
+	types        *util.ArrayList[block.Type]
+	keyChannel   int32
+	keyEncrypted bool
+	keyVariant   *DecryptedVariant
+	recordReader *MothRecordReader
+}
+
+/**
+ * The key index of a stripe, the keys are sorted by their bytes. The index is loaded on the first
+ * search of the stripe.
+ */
+type stripeKeyIndex struct {
+	stripeIndex int32
+	firstRow    int64
+	rowCount    int64
+	loaded      bool
+	keys        [][]byte
+	entries     []*metadata.Key
+}
+
+func NewMothKeyReader(reader *MothReader, memoryUsage memory.AggregatedMemoryContext) *MothKeyReader {
+	mothDataSource := reader.GetMothDataSource()
+	if reader.GetKeyColumn() == "" {
+		panic(common.NewMothUnsupportedError(mothDataSource.GetId(), "File has no key index"))
+	}
+	mr := new(MothKeyReader)
+	mr.reader = reader
+	mr.memoryUsage = memoryUsage
+	mr.indexMemory = memoryUsage.NewLocalMemoryContext("MothKeyReader")
+	mothTypes := reader.GetFooter().GetTypes()
+	rootType := mothTypes.Get(metadata.ROOT_COLUMN)
+	mr.types = util.NewArrayList[block.Type]()
+	mr.keyChannel = -1
+	for fieldId := util.INT32_ZERO; fieldId < rootType.GetFieldCount(); fieldId++ {
+		mr.types.Add(metadata.ToBlockType(mothTypes, rootType.GetFieldTypeIndex(fieldId)))
+		if rootType.GetFieldName(fieldId) == reader.GetKeyColumn() {
+			mr.keyChannel = fieldId
+		}
+	}
+	if mr.keyChannel < 0 {
+		panic(common.NewMothCorruptionError(mothDataSource.GetId(), "Key column %s is not a column of the file", reader.GetKeyColumn()))
+	}
+	keyColumnId := rootType.GetFieldTypeIndex(mr.keyChannel)
+	reader.GetFooter().GetEncryption().IfPresent(func(fileEncryption *metadata.Encryption) {
+		for _, variant := range fileEncryption.GetVariants().ToArray() {
+			if variant.GetRoot() != keyColumnId {
+				continue
+			}
+			mr.keyEncrypted = true
+			mr.keyVariant = getDecryptedVariant(reader.decryptedVariants, keyColumnId)
+		}
+	})
+	stripes := reader.GetFooter().GetStripes()
+	mr.stripes = make([]*stripeKeyIndex, stripes.Size())
+	firstRow := util.INT64_ZERO
+	for i, stripe := range stripes.ToArray() {
+		mr.stripes[i] = &stripeKeyIndex{stripeIndex: int32(i), firstRow: firstRow, rowCount: int64(stripe.GetNumberOfRows())}
+		firstRow += int64(stripe.GetNumberOfRows())
+	}
+	return mr
+}
+
+/**
+ * Reads the key index of the stripe and charges it to the memory context of the reader
+ */
+func (mr *MothKeyReader) loadKeyIndex(stripe *stripeKeyIndex) {
+	stripe.loaded = true
+	reader := mr.reader
+	mothDataSource := reader.GetMothDataSource()
+	information := reader.GetFooter().GetStripes().GetByInt32(stripe.stripeIndex)
+	start := int64(information.GetOffset() + information.GetTotalLength())
+	length := reader.getStripeEnd(stripe.stripeIndex) - start
+	if length <= 0 {
+		return
+	}
+	if mr.keyEncrypted && mr.keyVariant == nil {
+		stripe.loaded = false
+		panic(common.NewMothUnsupportedError(mothDataSource.GetId(), "Key column %s is encrypted and its key is not available", reader.GetKeyColumn()))
+	}
+	data := mothDataSource.ReadFully(start, util.Int32Exact(length))
+	if mr.keyVariant != nil {
+		decrypted := data.AvailableBytes()
+		encryption.Crypt(mr.keyVariant.GetLocalKey(), encryption.CreateIv(mr.keyVariant.GetRoot(), metadata.KEY_INDEX, uint64(stripe.stripeIndex+1)), 0, decrypted)
+		data = slice.NewWithBuf(decrypted)
+	}
+	inputStream := NewMothInputStream(CreateChunkLoader(mothDataSource.GetId(), data, reader.decompressor, memory.NewSimpleAggregatedMemoryContext()))
+	keys := reader.metadataReader.ReadKeys(inputStream).ToArray()
+	stripe.entries = keys
+	stripe.keys = make([][]byte, len(keys))
+	retainedBytes := util.INT64_ZERO
+	for j, key := range keys {
+		if key.GetPosition() >= uint32(stripe.rowCount) {
+			panic(common.NewMothCorruptionError(mothDataSource.GetId(), "Invalid key position %d in a stripe of %d rows", key.GetPosition(), stripe.rowCount))
+		}
+		stripe.keys[j] = key.GetKey().AvailableBytes()
+		retainedBytes += int64(MOTH_KEY_INSTANCE_SIZE) + 2*int64(len(stripe.keys[j]))
+	}
+	mr.indexMemory.SetBytes(mr.indexMemory.GetBytes() + retainedBytes)
+}
+
+/**
+ * Returns the file row of the key and its key index entry, nil when the key is not in the file.
+ * When several rows have the key, the last written row is returned.
+ */
+func (mr *MothKeyReader) Find(key []byte) (int64, *metadata.Key) {
+	for i := len(mr.stripes) - 1; i >= 0; i-- {
+		stripe := mr.stripes[i]
+		if !stripe.loaded {
+			mr.loadKeyIndex(stripe)
+		}
+		if len(stripe.keys) == 0 || bytes.Compare(key, stripe.keys[0]) < 0 || bytes.Compare(key, stripe.keys[len(stripe.keys)-1]) > 0 {
+			continue
+		}
+		next := sort.Search(len(stripe.keys), func(j int) bool {
+			return bytes.Compare(stripe.keys[j], key) > 0
+		})
+		if next > 0 && bytes.Equal(stripe.keys[next-1], key) {
+			entry := stripe.entries[next-1]
+			return stripe.firstRow + int64(entry.GetPosition()), entry
+		}
+	}
+	return -1, nil
+}
+
+/**
+ * Returns the row with the key as a loaded page of one row with all top level columns, nil when
+ * the key is not in the file.
+ */
+func (mr *MothKeyReader) Lookup(key []byte) *spi.Page {
+	fileRow, entry := mr.Find(key)
+	if entry == nil {
+		return nil
+	}
+	pages := mr.getRecordReader().ReadRows(fileRow, 1)
+	if pages.IsEmpty() {
+		panic(common.NewMothCorruptionError(mr.reader.GetMothDataSource().GetId(), "Key index points to missing row %d", fileRow))
+	}
+	page := pages.Get(0)
+	// the key index must agree with the row it points to
+	if rowKey := readRowKey(mr.types.Get(int(mr.keyChannel)), page.GetBlock(mr.keyChannel), 0); rowKey == nil || !bytes.Equal(rowKey.AvailableBytes(), key) {
+		panic(common.NewMothCorruptionError(mr.reader.GetMothDataSource().GetId(), "Key index points to row %d with a different %s key", fileRow, mr.reader.GetKeyColumn()))
+	}
+	return page
+}
+
+/**
+ * Like Lookup, but a failure to read the row is returned as a *common.MothError instead of
+ * panicking.
+ */
+func (mr *MothKeyReader) TryLookup(key []byte) (page *spi.Page, err error) {
+	defer recoverMothError(&err, mr.reader.GetMothDataSource().GetId())
+	return mr.Lookup(key), nil
+}
+
+func (mr *MothKeyReader) getRecordReader() *MothRecordReader {
+	if mr.recordReader == nil {
+		mr.recordReader = mr.reader.CreateRecordReader(mr.reader.GetRootColumn().GetNestedColumns(), mr.types, TRUE, time.UTC, mr.memoryUsage, INITIAL_BATCH_SIZE)
+	}
+	return mr.recordReader
+}
+
+/**
+ * Releases the memory of the loaded key indexes and closes the record reader of the rows, which
+ * closes the data source of the file.
+ */
+func (mr *MothKeyReader) Close() {
+	mr.indexMemory.Close()
+	if mr.recordReader != nil {
+		mr.recordReader.Close()
+	}
+}
@@ -0,0 +1,196 @@
+package store
+
+import (
+	"bytes"
+	goerrors "errors"
+	"reflect"
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/errors"
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+func writeKeyFile(t *testing.T, rows []mergeRow, keyColumn string) MothDataSource {
+	out := new(memoryWriteCloser)
+	options := NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100).WithKeyColumn(keyColumn)
+	writer := NewRowWriter2[mergeRow](NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), metadata.ZLIB, options, map[string]string{}, NewMothWriterStats())
+	writer.Write(rows)
+	if err := writer.TryClose(); err != nil {
+		t.Fatal(err)
+	}
+	return NewMemoryMothDataSource(common.NewMothDataSourceId("keys"), slice.NewWithBuf(out.Bytes()))
+}
+
+// the ids of the rows are not written in order
+func newKeyRows(count int) []mergeRow {
+	rows := newMergeRows(0, count)
+	for i := range rows {
+		rows[i].Id = int64(i*7919%count) - 100
+	}
+	return rows
+}
+
+func lookupRow(t *testing.T, keyReader *MothKeyReader, key *slice.Slice) (int64, *string) {
+	page, err := keyReader.TryLookup(key.AvailableBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page == nil {
+		return 0, nil
+	}
+	if page.GetPositionCount() != 1 || page.GetChannelCount() != 3 {
+		t.Fatalf("looked up %d rows of %d columns", page.GetPositionCount(), page.GetChannelCount())
+	}
+	names := page.GetBlock(1)
+	if names.IsNull(0) {
+		name := "null"
+		return block.BIGINT.GetLong(page.GetBlock(0), 0), &name
+	}
+	name := block.VARCHAR.GetSlice(names, 0).String()
+	return block.BIGINT.GetLong(page.GetBlock(0), 0), &name
+}
+
+func TestMothKeyReader(t *testing.T) {
+	rows := newKeyRows(2500)
+	// an update of a row written in the first stripe
+	updated := "updated"
+	rows = append(rows, mergeRow{Id: rows[10].Id, Name: &updated})
+	source := writeKeyFile(t, rows, "id")
+	reader := CreateMothReader(source, NewMothReaderOptions()).Get()
+	if reader.GetKeyColumn() != "id" || reader.GetFooter().GetStripes().Size() != 3 {
+		t.Fatalf("wrote key column %q and %d stripes", reader.GetKeyColumn(), reader.GetFooter().GetStripes().Size())
+	}
+
+	// the key indexes between the stripes are skipped by the record readers
+	rowReader := NewRowReader[mergeRow](CreateMothReader(source, NewMothReaderOptions()).Get())
+	defer rowReader.Close()
+	if got := rowReader.ReadAll(); !reflect.DeepEqual(got, rows) {
+		t.Fatalf("read %d rows that differ from the written rows", len(got))
+	}
+
+	keyReader := reader.CreateKeyReader(memory.NewSimpleAggregatedMemoryContext())
+	defer keyReader.Close()
+	for _, i := range []int{0, 1, 999, 1000, 1001, 1777, 2499, 42} {
+		id, name := lookupRow(t, keyReader, LongKey(rows[i].Id))
+		if id != rows[i].Id || (rows[i].Name == nil) != (*name == "null") || (rows[i].Name != nil && *name != *rows[i].Name) {
+			t.Fatalf("looked up row %d with id %d and name %s", i, id, *name)
+		}
+	}
+	if fileRow, key := keyReader.Find(LongKey(rows[10].Id).AvailableBytes()); fileRow != 2500 || key.GetPosition() != 500 {
+		t.Fatalf("found the updated row at file row %d", fileRow)
+	}
+	if _, name := lookupRow(t, keyReader, LongKey(rows[10].Id)); *name != updated {
+		t.Fatalf("looked up name %s of the updated row", *name)
+	}
+	for _, id := range []int64{-101, 2400, 1 << 40} {
+		if _, name := lookupRow(t, keyReader, LongKey(id)); name != nil {
+			t.Fatalf("looked up missing id %d", id)
+		}
+	}
+}
+
+func TestMothKeyReader_Varchar(t *testing.T) {
+	source := writeKeyFile(t, newKeyRows(1500), "name")
+	keyReader := CreateMothReader(source, NewMothReaderOptions()).Get().CreateKeyReader(memory.NewSimpleAggregatedMemoryContext())
+	defer keyReader.Close()
+	for _, name := range []string{"name-1", "name-1000", "name-1499"} {
+		if _, got := lookupRow(t, keyReader, slice.NewWithString(name)); got == nil || *got != name {
+			t.Fatalf("looked up %v for name %s", got, name)
+		}
+	}
+	// the rows with a null name are not indexed
+	if _, got := lookupRow(t, keyReader, slice.NewWithString("name-3")); got != nil {
+		t.Fatalf("looked up %s for a null name", *got)
+	}
+}
+
+func TestMothKeyReader_LazyKeyIndex(t *testing.T) {
+	rows := newKeyRows(2500)
+	source := writeKeyFile(t, rows, "id").(*MemoryMothDataSource)
+	reader := CreateMothReader(source, NewMothReaderOptions()).Get()
+	memoryUsage := memory.NewSimpleAggregatedMemoryContext()
+	readBytes := source.GetReadBytes()
+	keyReader := reader.CreateKeyReader(memoryUsage)
+	if source.GetReadBytes() != readBytes || memoryUsage.GetBytes() != 0 {
+		t.Fatalf("read %d bytes and reserved %d bytes of key indexes before a lookup", source.GetReadBytes()-readBytes, memoryUsage.GetBytes())
+	}
+	// the last stripe is searched first
+	if fileRow, _ := keyReader.Find(LongKey(rows[2400].Id).AvailableBytes()); fileRow != 2400 {
+		t.Fatalf("found file row %d", fileRow)
+	}
+	if memoryUsage.GetBytes() == 0 || !keyReader.stripes[2].loaded || keyReader.stripes[0].loaded {
+		t.Fatalf("loaded the key indexes %v, %v and %v into %d bytes", keyReader.stripes[0].loaded, keyReader.stripes[1].loaded, keyReader.stripes[2].loaded, memoryUsage.GetBytes())
+	}
+	keyReader.Close()
+	if memoryUsage.GetBytes() != 0 {
+		t.Fatalf("closed key reader retains %d bytes", memoryUsage.GetBytes())
+	}
+}
+
+func TestMothKeyReader_EncryptedKeyColumn(t *testing.T) {
+	keyProvider := encryption.NewInMemoryKeyProvider().AddKey("pii", 1, metadata.AES_CTR_256, []byte("0123456789abcdef0123456789abcdef"))
+	out := new(memoryWriteCloser)
+	options := NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100).WithKeyColumn("name").WithColumnEncryption(map[string]string{"name": "pii"}).WithKeyProvider(keyProvider)
+	writer := NewRowWriter2[mergeRow](NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), metadata.NONE, options, map[string]string{}, NewMothWriterStats())
+	writer.Write(newKeyRows(1500))
+	if err := writer.TryClose(); err != nil {
+		t.Fatal(err)
+	}
+	// the uncompressed file holds no plaintext key
+	if bytes.Contains(out.Bytes(), []byte("name-1499")) {
+		t.Fatal("wrote the keys of the encrypted key column in plaintext")
+	}
+	source := NewMemoryMothDataSource(common.NewMothDataSourceId("keys"), slice.NewWithBuf(out.Bytes()))
+
+	keyReader := CreateMothReader(source, NewMothReaderOptions().WithKeyProvider(keyProvider)).Get().CreateKeyReader(memory.NewSimpleAggregatedMemoryContext())
+	defer keyReader.Close()
+	for _, name := range []string{"name-1", "name-1000", "name-1499"} {
+		if _, got := lookupRow(t, keyReader, slice.NewWithString(name)); got == nil || *got != name {
+			t.Fatalf("looked up %v for name %s", got, name)
+		}
+	}
+
+	// without the key the keys can not be looked up, the masked column does not make the file corrupt
+	keyReader = CreateMothReader(source, NewMothReaderOptions()).Get().CreateKeyReader(memory.NewSimpleAggregatedMemoryContext())
+	defer keyReader.Close()
+	var mothError *common.MothError
+	if _, err := keyReader.TryLookup(slice.NewWithString("name-1").AvailableBytes()); !goerrors.As(err, &mothError) || mothError.GetCode() != errors.NOT_SUPPORTED {
+		t.Fatalf("got %v, want a NOT_SUPPORTED error", err)
+	}
+}
+
+func TestMothKeyReader_Merge(t *testing.T) {
+	first := newKeyRows(1200)
+	second := newMergeRows(5000, 700)
+	reader, copied := mergeFiles(t, writeKeyFile(t, first, "id"), writeKeyFile(t, second, "id"))
+	if !copied || reader.GetKeyColumn() != "id" {
+		t.Fatalf("merged files with key column %q", reader.GetKeyColumn())
+	}
+	keyReader := reader.CreateKeyReader(memory.NewSimpleAggregatedMemoryContext())
+	defer keyReader.Close()
+	for _, row := range []mergeRow{first[0], first[1100], second[0], second[699]} {
+		if id, _ := lookupRow(t, keyReader, LongKey(row.Id)); id != row.Id {
+			t.Fatalf("looked up id %d for id %d in the merged file", id, row.Id)
+		}
+	}
+
+	// the rows are re-encoded when only some files have a key index
+	reader, copied = mergeFiles(t, writeKeyFile(t, first, "id"), writeMergeFile(t, second, metadata.ZLIB))
+	if copied || reader.GetKeyColumn() != "" {
+		t.Fatalf("merged files with and without a key index into key column %q", reader.GetKeyColumn())
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("created a key reader of a file without a key index")
+			}
+		}()
+		reader.CreateKeyReader(memory.NewSimpleAggregatedMemoryContext())
+	}()
+}
@@ -32,7 +32,7 @@ type MothOutputBuffer struct {
 	bufferPosition         int32
 }
 
-func NewMothOutputBuffer(compression metadata.CompressionKind, maxBufferSize int32) *MothOutputBuffer {
+func NewMothOutputBuffer(compression metadata.CompressionKind, compressionLevel int32, maxBufferSize int32) *MothOutputBuffer {
 	mr := new(MothOutputBuffer)
 
 	mr.compressionBuffer = make([]byte, 0)
@@ -41,12 +41,12 @@ func NewMothOutputBuffer(compression metadata.CompressionKind, maxBufferSize int
 	buffer := make([]byte, INITIAL_BUFFER_SIZE)
 	mr.slice = slice.NewBaseBuf(buffer)
 	mr.compressedOutputStream = NewChunkedSliceOutput(MINIMUM_OUTPUT_BUFFER_CHUNK_SIZE, MAXIMUM_OUTPUT_BUFFER_CHUNK_SIZE)
-	mr.compressor = getCompressor(compression)
+	mr.compressor = getCompressor(compression, compressionLevel)
 	return mr
 }
 
 // @Nullable
-func getCompressor(compression metadata.CompressionKind) Compressor {
+func getCompressor(compression metadata.CompressionKind, compressionLevel int32) Compressor {
 	switch compression {
 	case metadata.NONE:
 		return nil
@@ -56,8 +56,8 @@ func getCompressor(compression metadata.CompressionKind) Compressor {
 		return NewDeflateCompressor()
 	case metadata.LZ4:
 		return NewLz4Compressor()
-		// case metadata.ZSTD:
-		// 	return NewZstdCompressor()
+	case metadata.ZSTD:
+		return NewZstdCompressor(compressionLevel)
 	}
 	panic(fmt.Sprintf("Unsupported compression %d", compression))
 }
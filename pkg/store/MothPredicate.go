@@ -18,3 +18,55 @@ type trueMothPredicate struct {
 func (*trueMothPredicate) Matches(numberOfRows int64, allColumnStatistics *metadata.ColumnMetadata[*metadata.ColumnStatistics]) bool {
 	return true
 }
+
+/**
+ * AndMothPredicate matches a section only if all of the predicates match it.
+ */
+type AndMothPredicate struct {
+	// 继承
+	MothPredicate
+
+	predicates []MothPredicate
+}
+
+func NewAndMothPredicate(predicates ...MothPredicate) *AndMothPredicate {
+	ae := new(AndMothPredicate)
+	ae.predicates = predicates
+	return ae
+}
+
+// @Override
+func (ae *AndMothPredicate) Matches(numberOfRows int64, allColumnStatistics *metadata.ColumnMetadata[*metadata.ColumnStatistics]) bool {
+	for _, predicate := range ae.predicates {
+		if !predicate.Matches(numberOfRows, allColumnStatistics) {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+ * OrMothPredicate matches a section if any of the predicates matches it.
+ */
+type OrMothPredicate struct {
+	// 继承
+	MothPredicate
+
+	predicates []MothPredicate
+}
+
+func NewOrMothPredicate(predicates ...MothPredicate) *OrMothPredicate {
+	oe := new(OrMothPredicate)
+	oe.predicates = predicates
+	return oe
+}
+
+// @Override
+func (oe *OrMothPredicate) Matches(numberOfRows int64, allColumnStatistics *metadata.ColumnMetadata[*metadata.ColumnStatistics]) bool {
+	for _, predicate := range oe.predicates {
+		if predicate.Matches(numberOfRows, allColumnStatistics) {
+			return true
+		}
+	}
+	return false
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/slice"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
 	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -31,6 +32,7 @@ type MothReader struct {
 	mothDataSource    MothDataSource
 	metadataReader    *metadata.ExceptionWrappingMetadataReader
 	options           *MothReaderOptions
+	postScript        *metadata.PostScript
 	hiveWriterVersion metadata.HiveWriterVersion
 	bufferSize        int32
 	compressionKind   metadata.CompressionKind
@@ -38,38 +40,81 @@ type MothReader struct {
 	footer            *metadata.Footer
 	metadata          *metadata.Metadata
 	rootColumn        *MothColumn
+	decryptedVariants *util.ArrayList[*DecryptedVariant]
+	// the end of the stripes and their key indexes, where the file tail starts
+	contentLength int64
+	// the data source is read into memory on first use if the file is tiny
+	wrapIfTiny bool
 }
 
 func CreateMothReader(mothDataSource MothDataSource, options *MothReaderOptions) *optional.Optional[*MothReader] {
+	fileTailCache := options.GetFileTailCache()
+	var cacheKey FileTailKey
+	if fileTailCache != nil {
+		cacheKey = NewFileTailKey2(mothDataSource, options.GetFormatFlavor())
+		// a tiny file is read into memory by the first reader of its stripes, not for a cached tail
+		if tail := fileTailCache.get(cacheKey); tail != nil {
+			mr := newMothReader(mothDataSource, options, tail)
+			mr.wrapIfTiny = true
+			return optional.Of(mr)
+		}
+	}
 	mothDataSource = wrapWithCacheIfTiny(mothDataSource, options.GetTinyStripeThreshold())
 	estimatedFileSize := mothDataSource.GetEstimatedSize()
-	if estimatedFileSize > 0 && estimatedFileSize <= int64(len(metadata.MAGIC)) {
-		panic(fmt.Sprintf("Invalid file size %d", estimatedFileSize))
+	if estimatedFileSize > 0 && estimatedFileSize <= int64(len(options.GetFormatFlavor().GetMagic())) {
+		panic(common.NewMothCorruptionError(mothDataSource.GetId(), "Invalid file size %d", estimatedFileSize))
 	}
 	expectedReadSize := maths.Min(estimatedFileSize, EXPECTED_FOOTER_SIZE)
 	fileTail := mothDataSource.ReadTail(util.Int32Exact(expectedReadSize))
 	if fileTail.Length() == 0 {
 		return optional.Empty[*MothReader]()
 	}
-	return optional.Of(NewMothReader(mothDataSource, options, fileTail))
+	tail := readMothFileTail(mothDataSource, options, fileTail)
+	if fileTailCache != nil {
+		fileTailCache.put(cacheKey, tail)
+	}
+	return optional.Of(newMothReader(mothDataSource, options, tail))
+}
+
+/**
+ * Like CreateMothReader, but a corrupt or unreadable file is returned as a *common.MothError
+ * instead of panicking.
+ */
+func TryCreateMothReader(mothDataSource MothDataSource, options *MothReaderOptions) (reader *optional.Optional[*MothReader], err error) {
+	defer recoverMothError(&err, mothDataSource.GetId())
+	return CreateMothReader(mothDataSource, options), nil
 }
+
 func NewMothReader(mothDataSource MothDataSource, options *MothReaderOptions, fileTail *slice.Slice) *MothReader {
-	mr := new(MothReader)
-	mr.options = options
-	mr.mothDataSource = mothDataSource
-	mr.metadataReader = metadata.NewExceptionWrappingMetadataReader(mothDataSource.GetId(), metadata.NewMothMetadataReader())
+	return newMothReader(mothDataSource, options, readMothFileTail(mothDataSource, options, fileTail))
+}
+
+/**
+ * The parsed tail of a file as it is stored in the file, the statistics of encrypted columns are
+ * decrypted by every reader with its own key provider.
+ */
+type mothFileTail struct {
+	postScript *metadata.PostScript
+	footer     *metadata.Footer
+	metadata   *metadata.Metadata
+	// the end of the stripes and their key indexes, where the file tail starts
+	contentLength int64
+	// the size of the parsed postscript, footer and metadata held in memory
+	retainedSize int64
+}
+
+func readMothFileTail(mothDataSource MothDataSource, options *MothReaderOptions, fileTail *slice.Slice) *mothFileTail {
+	metadataReader := metadata.NewExceptionWrappingMetadataReader(mothDataSource.GetId(), metadata.NewMothMetadataReader2(options.GetFormatFlavor()))
 	postScriptSize, _ := fileTail.GetUInt8(fileTail.Size() - util.BYTE_BYTES)
 	if int32(postScriptSize) >= fileTail.SizeInt32() {
-		panic(fmt.Sprintf("Invalid postscript length %d", postScriptSize))
+		panic(common.NewMothCorruptionError(mothDataSource.GetId(), "Invalid postscript length %d", postScriptSize))
 	}
-	var postScript *metadata.PostScript
+	verifyMagic(mothDataSource, options.GetFormatFlavor(), int32(postScriptSize), fileTail)
 	s, _ := fileTail.MakeSlice(fileTail.Size()-util.BYTE_BYTES-int(postScriptSize), int(postScriptSize))
-	postScript = mr.metadataReader.ReadPostScript(s.GetInput())
-	checkMothVersion(mr.mothDataSource, postScript.GetVersion())
-	mr.bufferSize = util.Int32ExactU(postScript.GetCompressionBlockSize())
-	mr.compressionKind = postScript.GetCompression()
-	mr.decompressor = CreateMothDecompressor(mothDataSource.GetId(), mr.compressionKind, mr.bufferSize)
-	mr.hiveWriterVersion = postScript.GetHiveWriterVersion()
+	postScript := metadataReader.ReadPostScript(s.GetInput())
+	checkMothVersion(mothDataSource, postScript.GetVersion())
+	decompressor := CreateMothDecompressor(mothDataSource.GetId(), postScript.GetCompression(), util.Int32ExactU(postScript.GetCompressionBlockSize()))
+	hiveWriterVersion := postScript.GetHiveWriterVersion()
 	footerSize := util.Int32Exact(postScript.GetFooterLength())
 	metadataSize := util.Int32Exact(postScript.GetMetadataLength())
 	var completeFooterSlice *slice.Slice
@@ -79,23 +124,144 @@ func NewMothReader(mothDataSource MothDataSource, options *MothReaderOptions, fi
 	} else {
 		completeFooterSlice, _ = fileTail.MakeSlice(int(fileTail.Length()-completeFooterSize), int(completeFooterSize))
 	}
+	tail := &mothFileTail{postScript: postScript, contentLength: mothDataSource.GetEstimatedSize() - int64(completeFooterSize)}
 	metadataSlice, _ := completeFooterSlice.MakeSlice(0, int(metadataSize))
-	metadataInputStream := NewMothInputStream(CreateChunkLoader(mothDataSource.GetId(), metadataSlice, mr.decompressor, memory.NewSimpleAggregatedMemoryContext()))
-	mr.metadata = mr.metadataReader.ReadMetadata(mr.hiveWriterVersion, metadataInputStream)
+	metadataInputStream := NewMothInputStream(CreateChunkLoader(mothDataSource.GetId(), metadataSlice, decompressor, memory.NewSimpleAggregatedMemoryContext()))
+	tail.metadata = metadataReader.ReadMetadata(hiveWriterVersion, metadataInputStream)
 	footerSlice, _ := completeFooterSlice.MakeSlice(int(metadataSize), int(footerSize))
-	footerInputStream := NewMothInputStream(CreateChunkLoader(mothDataSource.GetId(), footerSlice, mr.decompressor, memory.NewSimpleAggregatedMemoryContext()))
-	mr.footer = mr.metadataReader.ReadFooter(mr.hiveWriterVersion, footerInputStream)
-	if mr.footer.GetTypes().Size() == 0 {
-		panic("File has no columns")
+	footerInputStream := NewMothInputStream(CreateChunkLoader(mothDataSource.GetId(), footerSlice, decompressor, memory.NewSimpleAggregatedMemoryContext()))
+	tail.footer = metadataReader.ReadFooter(hiveWriterVersion, footerInputStream)
+	if tail.footer.GetTypes().Size() == 0 {
+		panic(common.NewMothCorruptionError(mothDataSource.GetId(), "File has no columns"))
 	}
-	mr.rootColumn = createMothColumn("", "", metadata.NewMothColumnId(0), mr.footer.GetTypes(), mothDataSource.GetId())
+	tail.retainedSize = postScript.GetRetainedSizeInBytes() + tail.footer.GetRetainedSizeInBytes() + tail.metadata.GetRetainedSizeInBytes()
+	return tail
+}
+
+func newMothReader(mothDataSource MothDataSource, options *MothReaderOptions, tail *mothFileTail) *MothReader {
+	mr := new(MothReader)
+	mr.options = options
+	mr.mothDataSource = mothDataSource
+	mr.metadataReader = metadata.NewExceptionWrappingMetadataReader(mothDataSource.GetId(), metadata.NewMothMetadataReader2(options.GetFormatFlavor()))
+	mr.postScript = tail.postScript
+	mr.bufferSize = util.Int32ExactU(mr.postScript.GetCompressionBlockSize())
+	mr.compressionKind = mr.postScript.GetCompression()
+	mr.decompressor = CreateMothDecompressor(mothDataSource.GetId(), mr.compressionKind, mr.bufferSize)
+	mr.hiveWriterVersion = mr.postScript.GetHiveWriterVersion()
+	mr.contentLength = tail.contentLength
+	mr.metadata = tail.metadata
+	mr.footer = tail.footer
+	mr.decryptedVariants = GetDecryptedVariants(mr.footer, options.GetKeyProvider())
+	if !mr.decryptedVariants.IsEmpty() {
+		mr.footer = mr.decryptFileStatistics(mr.footer)
+		mr.metadata = clearDecryptedStripeStatistics(mr.metadata, mr.decryptedVariants)
+	}
+	mr.rootColumn = createMothColumn("", "", metadata.NewMothColumnId(0), mr.footer.GetTypes(), mothDataSource.GetId(), mr.HasLegacyTimestamps())
 	return mr
 }
 
+/**
+ * Like NewMothReader, but a corrupt or unreadable file is returned as a *common.MothError instead
+ * of panicking.
+ */
+func TryNewMothReader(mothDataSource MothDataSource, options *MothReaderOptions, fileTail *slice.Slice) (reader *MothReader, err error) {
+	defer recoverMothError(&err, mothDataSource.GetId())
+	return NewMothReader(mothDataSource, options, fileTail), nil
+}
+
+/**
+ * Replaces the masked file statistics of the decrypted columns with the encrypted statistics of
+ * their variant
+ */
+func (mr *MothReader) decryptFileStatistics(footer *metadata.Footer) *metadata.Footer {
+	if footer.GetFileStats().IsEmpty() {
+		return footer
+	}
+	fileStats := footer.GetFileStats().Get()
+	variants := footer.GetEncryption().Get().GetVariants()
+	decryptedStats := make(map[metadata.MothColumnId]*metadata.ColumnStatistics)
+	for _, decryptedVariant := range mr.decryptedVariants.ToArray() {
+		encryptedStats := variants.GetByInt32(decryptedVariant.GetVariantIndex()).GetFileStatistics()
+		if len(encryptedStats) == 0 {
+			continue
+		}
+		data := make([]byte, len(encryptedStats))
+		copy(data, encryptedStats)
+		encryption.Crypt(decryptedVariant.GetLocalKey(), encryption.CreateIv(decryptedVariant.GetRoot(), metadata.FILE_STATISTICS, 0), 0, data)
+		inputStream := NewMothInputStream(CreateChunkLoader(mr.mothDataSource.GetId(), slice.NewWithBuf(data), mr.decompressor, memory.NewSimpleAggregatedMemoryContext()))
+		mr.metadataReader.ReadFileStatistics(mr.hiveWriterVersion, inputStream).IfPresent(func(stats *metadata.ColumnMetadata[*metadata.ColumnStatistics]) {
+			for i := util.INT32_ZERO; i < stats.Size(); i++ {
+				decryptedStats[decryptedVariant.GetRoot()+metadata.MothColumnId(i)] = stats.Get(metadata.MothColumnId(i))
+			}
+		})
+	}
+	columnStatistics := util.NewArrayList[*metadata.ColumnStatistics]()
+	for i := util.INT32_ZERO; i < fileStats.Size(); i++ {
+		columnId := metadata.MothColumnId(i)
+		if stats, ok := decryptedStats[columnId]; ok {
+			columnStatistics.Add(stats)
+		} else {
+			columnStatistics.Add(fileStats.Get(columnId))
+		}
+	}
+	return metadata.NewFooter2(footer.GetNumberOfRows(), footer.GetRowsInRowGroup(), footer.GetStripes(), footer.GetTypes(), optional.Of(metadata.NewColumnMetadata(columnStatistics)), footer.GetUserMetadata(), footer.GetWriterId(), footer.GetEncryption())
+}
+
+/**
+ * The stripe statistics of encrypted columns describe the masked copy, so they can not be used
+ * to prune stripes for readers of the decrypted columns
+ */
+func clearDecryptedStripeStatistics(fileMetadata *metadata.Metadata, decryptedVariants *util.ArrayList[*DecryptedVariant]) *metadata.Metadata {
+	stripeStatsList := util.NewArrayList[*optional.Optional[*metadata.StripeStatistics]]()
+	for _, stripeStats := range fileMetadata.GetStripeStatsList().ToArray() {
+		stripeStatsList.Add(optional.Map(stripeStats, func(stats *metadata.StripeStatistics) *metadata.StripeStatistics {
+			columnStatistics := util.NewArrayList[*metadata.ColumnStatistics]()
+			for i := util.INT32_ZERO; i < stats.GetColumnStatistics().Size(); i++ {
+				columnId := metadata.MothColumnId(i)
+				if getDecryptedVariant(decryptedVariants, columnId) != nil {
+					columnStatistics.Add(nil)
+				} else {
+					columnStatistics.Add(stats.GetColumnStatistics().Get(columnId))
+				}
+			}
+			return metadata.NewStripeStatistics(metadata.NewColumnMetadata(columnStatistics))
+		}))
+	}
+	return metadata.NewMetadata(stripeStatsList)
+}
+
+/**
+ * The mask of the values read for a column, nil when the column is not encrypted or the key
+ * provider holds the key of the column. Readers without the key read the masked copy of
+ * encrypted columns.
+ */
+func (mr *MothReader) GetColumnMask(columnId metadata.MothColumnId) *metadata.DataMask {
+	if mr.footer.GetEncryption().IsEmpty() || getDecryptedVariant(mr.decryptedVariants, columnId) != nil {
+		return nil
+	}
+	fileEncryption := mr.footer.GetEncryption().Get()
+	for _, variant := range fileEncryption.GetVariants().ToArray() {
+		root := variant.GetRoot()
+		if columnId < root || columnId >= root+metadata.MothColumnId(metadata.GetSubtreeColumnCount(mr.footer.GetTypes(), root)) {
+			continue
+		}
+		for _, dataMask := range fileEncryption.GetMasks().ToArray() {
+			if dataMask.GetColumns().Contains(root) {
+				return dataMask
+			}
+		}
+	}
+	return nil
+}
+
 func (mr *MothReader) GetColumnNames() *util.ArrayList[string] {
 	return mr.footer.GetTypes().Get(metadata.ROOT_COLUMN).GetFieldNames()
 }
 
+func (mr *MothReader) GetPostScript() *metadata.PostScript {
+	return mr.postScript
+}
+
 func (mr *MothReader) GetFooter() *metadata.Footer {
 	return mr.footer
 }
@@ -116,12 +282,102 @@ func (mr *MothReader) GetCompressionKind() metadata.CompressionKind {
 	return mr.compressionKind
 }
 
+func (mr *MothReader) GetHiveWriterVersion() metadata.HiveWriterVersion {
+	return mr.hiveWriterVersion
+}
+
+func (mr *MothReader) GetMothDataSource() MothDataSource {
+	if mr.wrapIfTiny {
+		mr.mothDataSource = wrapWithCacheIfTiny(mr.mothDataSource, mr.options.GetTinyStripeThreshold())
+		mr.wrapIfTiny = false
+	}
+	return mr.mothDataSource
+}
+
+/**
+ * Returns if the file was written by writer version 1.0.0 of this package, which stored the
+ * seconds of timestamps relative to 1970-01-01 UTC instead of the ORC epoch.
+ */
+func (mr *MothReader) HasLegacyTimestamps() bool {
+	version, ok := mr.footer.GetUserMetadata()[MOTHDB_MOTH_WRITER_VERSION_METADATA_KEY]
+	return ok && version.String() == MOTHDB_LEGACY_TIMESTAMP_WRITER_VERSION
+}
+
+/**
+ * Returns the column whose values are the keys of the key index of the file, empty when the file
+ * has no key index.
+ */
+func (mr *MothReader) GetKeyColumn() string {
+	keyColumn, ok := mr.footer.GetUserMetadata()[MOTH_KEY_COLUMN_METADATA_KEY]
+	if !ok {
+		return ""
+	}
+	return keyColumn.String()
+}
+
+/**
+ * Returns the end of the stripe with its key index, the offset of the next stripe or of the file
+ * tail.
+ */
+func (mr *MothReader) getStripeEnd(stripeIndex int32) int64 {
+	stripes := mr.footer.GetStripes()
+	if stripeIndex+1 < stripes.SizeInt32() {
+		return int64(stripes.GetByInt32(stripeIndex + 1).GetOffset())
+	}
+	return mr.contentLength
+}
+
+/**
+ * Creates a reader of the rows with a key, the file must have been written with a key column.
+ */
+func (mr *MothReader) CreateKeyReader(memoryUsage memory.AggregatedMemoryContext) *MothKeyReader {
+	return NewMothKeyReader(mr, memoryUsage)
+}
+
+/**
+ * Reads the footer of a stripe, which holds the stream layout and the column encodings of the
+ * stripe. The streams of encrypted columns are only listed in the encryption variants.
+ */
+func (mr *MothReader) ReadStripeFooter(stripe *metadata.StripeInformation, legacyFileTimeZone *time.Location) *metadata.StripeFooter {
+	offset := stripe.GetOffset() + stripe.GetIndexLength() + stripe.GetDataLength()
+	tailBuffer := mr.GetMothDataSource().ReadFully(int64(offset), util.Int32Exact(int64(stripe.GetFooterLength())))
+	inputStream := NewMothInputStream(CreateChunkLoader(mr.mothDataSource.GetId(), tailBuffer, mr.decompressor, memory.NewSimpleAggregatedMemoryContext()))
+	return mr.metadataReader.ReadStripeFooter(mr.footer.GetTypes(), inputStream, legacyFileTimeZone)
+}
+
+/**
+ * Reads the row group indexes of the columns that have a ROW_INDEX stream in the stripe footer.
+ * Bloom filters are not attached to the statistics.
+ */
+func (mr *MothReader) ReadRowGroupIndexes(stripe *metadata.StripeInformation, stripeFooter *metadata.StripeFooter) map[metadata.MothColumnId]*util.ArrayList[*metadata.RowGroupIndex] {
+	rowGroupIndexes := make(map[metadata.MothColumnId]*util.ArrayList[*metadata.RowGroupIndex])
+	offset := int64(stripe.GetOffset())
+	for _, stream := range stripeFooter.GetStreams().ToArray() {
+		if stream.GetStreamKind() == metadata.ROW_INDEX && stream.GetLength() > 0 {
+			data := mr.GetMothDataSource().ReadFully(offset, stream.GetLength())
+			inputStream := NewMothInputStream(CreateChunkLoader(mr.mothDataSource.GetId(), data, mr.decompressor, memory.NewSimpleAggregatedMemoryContext()))
+			rowGroupIndexes[stream.GetColumnId()] = mr.metadataReader.ReadRowIndexes(mr.hiveWriterVersion, inputStream)
+		}
+		offset += int64(stream.GetLength())
+	}
+	return rowGroupIndexes
+}
+
 func (mr *MothReader) CreateRecordReader(readColumns *util.ArrayList[*MothColumn], readTypes *util.ArrayList[block.Type], predicate MothPredicate, legacyFileTimeZone *time.Location, memoryUsage memory.AggregatedMemoryContext, initialBatchSize int32) *MothRecordReader {
 	return mr.CreateRecordReader2(readColumns, readTypes, util.NCopysList(readColumns.Size(), FullyProjectedLayout()), predicate, 0, mr.mothDataSource.GetEstimatedSize(), legacyFileTimeZone, memoryUsage, initialBatchSize, NewFieldMapperFactory())
 }
 
 func (mr *MothReader) CreateRecordReader2(readColumns *util.ArrayList[*MothColumn], readTypes *util.ArrayList[block.Type], readLayouts util.List[ProjectedLayout], predicate MothPredicate, offset int64, length int64, legacyFileTimeZone *time.Location, memoryUsage memory.AggregatedMemoryContext, initialBatchSize int32, fieldMapperFactory FieldMapperFactory) *MothRecordReader {
-	return NewMothRecordReader(readColumns, readTypes, readLayouts, predicate, int64(mr.footer.GetNumberOfRows()), mr.footer.GetStripes(), mr.footer.GetFileStats(), mr.metadata.GetStripeStatsList(), mr.mothDataSource, offset, length, mr.footer.GetTypes(), mr.decompressor, mr.footer.GetRowsInRowGroup(), legacyFileTimeZone, mr.hiveWriterVersion, mr.metadataReader, mr.options, mr.footer.GetUserMetadata(), memoryUsage, initialBatchSize, fieldMapperFactory)
+	return NewMothRecordReader(readColumns, readTypes, readLayouts, predicate, int64(mr.footer.GetNumberOfRows()), mr.footer.GetStripes(), mr.footer.GetFileStats(), mr.metadata.GetStripeStatsList(), mr.GetMothDataSource(), offset, length, mr.footer.GetTypes(), mr.decompressor, mr.footer.GetRowsInRowGroup(), legacyFileTimeZone, mr.hiveWriterVersion, mr.metadataReader, mr.decryptedVariants, mr.options, mr.footer.GetUserMetadata(), memoryUsage, initialBatchSize, fieldMapperFactory)
+}
+
+/**
+ * Creates a record reader of a requested schema that may differ from the schema of the file, the
+ * columns missing from the file are read as nulls.
+ */
+func (mr *MothReader) CreateRecordReader3(evolution *SchemaEvolution, predicate MothPredicate, legacyFileTimeZone *time.Location, memoryUsage memory.AggregatedMemoryContext, initialBatchSize int32) *MothRecordReader {
+	readColumns := evolution.GetReadColumns()
+	return mr.CreateRecordReader2(readColumns, evolution.GetReadTypes(), util.NCopysList(readColumns.Size(), FullyProjectedLayout()), predicate, 0, mr.mothDataSource.GetEstimatedSize(), legacyFileTimeZone, memoryUsage, initialBatchSize, evolution.GetFieldMapperFactory())
 }
 
 func wrapWithCacheIfTiny(dataSource MothDataSource, maxCacheSize util.DataSize) MothDataSource {
@@ -139,24 +395,45 @@ func wrapWithCacheIfTiny(dataSource MothDataSource, maxCacheSize util.DataSize)
 	return NewMemoryMothDataSource(dataSource.GetId(), data)
 }
 
-func createMothColumn(parentStreamName string, fieldName string, columnId metadata.MothColumnId, types *metadata.ColumnMetadata[*metadata.MothType], mothDataSourceId *common.MothDataSourceId) *MothColumn {
+func createMothColumn(parentStreamName string, fieldName string, columnId metadata.MothColumnId, types *metadata.ColumnMetadata[*metadata.MothType], mothDataSourceId *common.MothDataSourceId, legacyTimestamps bool) *MothColumn {
 	path := util.Ternary(len(fieldName) == 0, parentStreamName, parentStreamName+"."+fieldName)
 	mothType := types.Get(columnId)
 	nestedColumns := util.NewArrayList[*MothColumn]()
 	if mothType.GetMothTypeKind() == metadata.STRUCT {
 		for fieldId := util.INT32_ZERO; fieldId < mothType.GetFieldCount(); fieldId++ {
-			nestedColumns.Add(createMothColumn(path, mothType.GetFieldName(fieldId), mothType.GetFieldTypeIndex(fieldId), types, mothDataSourceId))
+			nestedColumns.Add(createMothColumn(path, mothType.GetFieldName(fieldId), mothType.GetFieldTypeIndex(fieldId), types, mothDataSourceId, legacyTimestamps))
 		}
 	} else if mothType.GetMothTypeKind() == metadata.LIST {
-		nestedColumns.Add(createMothColumn(path, "item", mothType.GetFieldTypeIndex(0), types, mothDataSourceId))
+		nestedColumns.Add(createMothColumn(path, "item", mothType.GetFieldTypeIndex(0), types, mothDataSourceId, legacyTimestamps))
 	} else if mothType.GetMothTypeKind() == metadata.MAP {
-		nestedColumns.Add(createMothColumn(path, "key", mothType.GetFieldTypeIndex(0), types, mothDataSourceId), createMothColumn(path, "value", mothType.GetFieldTypeIndex(1), types, mothDataSourceId))
+		nestedColumns.Add(createMothColumn(path, "key", mothType.GetFieldTypeIndex(0), types, mothDataSourceId, legacyTimestamps), createMothColumn(path, "value", mothType.GetFieldTypeIndex(1), types, mothDataSourceId, legacyTimestamps))
 	} else if mothType.GetMothTypeKind() == metadata.UNION {
 		for fieldId := util.INT32_ZERO; fieldId < mothType.GetFieldCount(); fieldId++ {
-			nestedColumns.Add(createMothColumn(path, "field"+strconv.Itoa(int(fieldId)), mothType.GetFieldTypeIndex(fieldId), types, mothDataSourceId))
+			nestedColumns.Add(createMothColumn(path, "field"+strconv.Itoa(int(fieldId)), mothType.GetFieldTypeIndex(fieldId), types, mothDataSourceId, legacyTimestamps))
 		}
 	}
-	return NewMothColumn(path, columnId, fieldName, mothType.GetMothTypeKind(), mothDataSourceId, nestedColumns, mothType.GetAttributes())
+	column := NewMothColumn(path, columnId, fieldName, mothType.GetMothTypeKind(), mothDataSourceId, nestedColumns, mothType.GetAttributes())
+	column.legacyTimestamps = legacyTimestamps
+	return column
+}
+
+/**
+ * Checks the magic of the format flavour at the end of the postscript. Files written before Hive
+ * 0.12 have no magic in the postscript, for those the magic at the start of the file is checked.
+ */
+func verifyMagic(mothDataSource MothDataSource, formatFlavor metadata.FormatFlavor, postScriptSize int32, fileTail *slice.Slice) {
+	magic := formatFlavor.GetMagicSlice()
+	magicLength := magic.Size()
+	if postScriptSize < int32(magicLength)+1 {
+		panic(common.NewMothCorruptionError(mothDataSource.GetId(), "Invalid postscript length %d", postScriptSize))
+	}
+	if fileTail.Equal2(fileTail.Size()-util.BYTE_BYTES-magicLength, magic, 0, magicLength) {
+		return
+	}
+	if mothDataSource.GetEstimatedSize() > int64(magicLength) && mothDataSource.ReadFully(0, int32(magicLength)).Equal2(0, magic, 0, magicLength) {
+		return
+	}
+	panic(common.NewMothCorruptionError(mothDataSource.GetId(), "Invalid postscript, the file is not a %s file", formatFlavor))
 }
 
 func checkMothVersion(mothDataSource MothDataSource, version []uint32) {
@@ -173,11 +450,40 @@ func checkMothVersion(mothDataSource MothDataSource, version []uint32) {
 	}
 }
 
-func validateFile(input MothDataSource, readTypes *util.ArrayList[block.Type]) {
-	mothReader := CreateMothReader(input, NewMothReaderOptions()).OrElseThrow("File is empty")
-	mothRecordReader := mothReader.CreateRecordReader(mothReader.GetRootColumn().GetNestedColumns(), readTypes, TRUE, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
-	for page := mothRecordReader.NextPage(); page != nil; page = mothRecordReader.NextPage() {
-		page.GetLoadedPage()
+/**
+ * Reads the file and compares it with the validation recorded by the writer, panics with a
+ * CORRUPT_FILE error listing the mismatches.
+ */
+func validateFile(validation *MothWriteValidation, input MothDataSource, options *MothReaderOptions) {
+	mothReader := CreateMothReader(input, options).OrElseThrow("File is empty")
+	mismatches := validation.validateMetadata(mothReader.GetFooter(), mothReader.GetMetadata())
+	if len(mismatches) == 0 {
+		stripeEnds := make([]int64, 0)
+		stripeEnd := util.INT64_ZERO
+		for _, stripe := range mothReader.GetFooter().GetStripes().ToArray() {
+			stripeEnd += int64(stripe.GetNumberOfRows())
+			stripeEnds = append(stripeEnds, stripeEnd)
+		}
+		checksums := make([]*WriteChecksum, 0)
+		checksum := NewWriteChecksumBuilder(validation.GetTypes())
+		mothRecordReader := mothReader.CreateRecordReader(mothReader.GetRootColumn().GetNestedColumns(), validation.GetTypes(), TRUE, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+		defer mothRecordReader.Close()
+		// pages do not cross row groups, so every page belongs to a single stripe
+		for page := mothRecordReader.NextPage(); page != nil; page = mothRecordReader.NextPage() {
+			for len(checksums) < len(stripeEnds) && mothRecordReader.GetFilePosition() >= stripeEnds[len(checksums)] {
+				checksums = append(checksums, checksum.Build())
+				checksum = NewWriteChecksumBuilder(validation.GetTypes())
+			}
+			checksum.AddPage(page)
+		}
+		for len(checksums) < len(stripeEnds) {
+			checksums = append(checksums, checksum.Build())
+			checksum = NewWriteChecksumBuilder(validation.GetTypes())
+		}
+		mismatches = validation.validateChecksums(checksums)
+	}
+	if len(mismatches) > 0 {
+		panic(common.NewMothCorruptionError(input.GetId(), "Write validation failed: %s", strings.Join(mismatches, "; ")))
 	}
 }
 
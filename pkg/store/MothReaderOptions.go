@@ -1,16 +1,23 @@
 package store
 
-import "github.com/mothdb-bd/orc-go/pkg/util"
+import (
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
 
 var (
-	DEFAULT_BLOOM_FILTERS_ENABLED  bool          = false
-	DEFAULT_MAX_MERGE_DISTANCE     util.DataSize = util.Ofds(1, util.MB)
-	DEFAULT_MAX_BUFFER_SIZE        util.DataSize = util.Ofds(8, util.MB)
-	DEFAULT_TINY_STRIPE_THRESHOLD  util.DataSize = util.Ofds(8, util.MB)
-	DEFAULT_STREAM_BUFFER_SIZE     util.DataSize = util.Ofds(8, util.MB)
-	DEFAULT_MAX_BLOCK_SIZE         util.DataSize = util.Ofds(16, util.MB)
-	DEFAULT_LAZY_READ_SMALL_RANGES bool          = true
-	DEFAULT_NESTED_LAZY            bool          = true
+	DEFAULT_BLOOM_FILTERS_ENABLED  bool                  = false
+	DEFAULT_MAX_MERGE_DISTANCE     util.DataSize         = util.Ofds(1, util.MB)
+	DEFAULT_MAX_BUFFER_SIZE        util.DataSize         = util.Ofds(8, util.MB)
+	DEFAULT_TINY_STRIPE_THRESHOLD  util.DataSize         = util.Ofds(8, util.MB)
+	DEFAULT_STREAM_BUFFER_SIZE     util.DataSize         = util.Ofds(8, util.MB)
+	DEFAULT_MAX_BLOCK_SIZE         util.DataSize         = util.Ofds(16, util.MB)
+	DEFAULT_LAZY_READ_SMALL_RANGES bool                  = true
+	DEFAULT_PREFETCH_LAZY_RANGES   bool                  = false
+	DEFAULT_NESTED_LAZY            bool                  = true
+	DEFAULT_STRIPE_PREFETCH_COUNT  int32                 = 0
+	DEFAULT_READER_FORMAT_FLAVOR   metadata.FormatFlavor = metadata.MOTH_FLAVOR
 )
 
 type MothReaderOptions struct {
@@ -22,6 +29,11 @@ type MothReaderOptions struct {
 	maxBlockSize        util.DataSize
 	lazyReadSmallRanges bool
 	nestedLazy          bool
+	keyProvider         encryption.KeyProvider
+	stripePrefetchCount int32
+	formatFlavor        metadata.FormatFlavor
+	fileTailCache       *FileTailCache
+	prefetchLazyRanges  bool
 }
 
 func NewMothReaderOptions() *MothReaderOptions {
@@ -34,9 +46,12 @@ func NewMothReaderOptions() *MothReaderOptions {
 	ms.maxBlockSize = DEFAULT_MAX_BLOCK_SIZE
 	ms.lazyReadSmallRanges = DEFAULT_LAZY_READ_SMALL_RANGES
 	ms.nestedLazy = DEFAULT_NESTED_LAZY
+	ms.stripePrefetchCount = DEFAULT_STRIPE_PREFETCH_COUNT
+	ms.formatFlavor = DEFAULT_READER_FORMAT_FLAVOR
+	ms.prefetchLazyRanges = DEFAULT_PREFETCH_LAZY_RANGES
 	return ms
 }
-func NewMothReaderOptions2(bloomFiltersEnabled bool, maxMergeDistance util.DataSize, maxBufferSize util.DataSize, tinyStripeThreshold util.DataSize, streamBufferSize util.DataSize, maxBlockSize util.DataSize, lazyReadSmallRanges bool, nestedLazy bool) *MothReaderOptions {
+func NewMothReaderOptions2(bloomFiltersEnabled bool, maxMergeDistance util.DataSize, maxBufferSize util.DataSize, tinyStripeThreshold util.DataSize, streamBufferSize util.DataSize, maxBlockSize util.DataSize, lazyReadSmallRanges bool, nestedLazy bool, keyProvider encryption.KeyProvider, stripePrefetchCount int32, formatFlavor metadata.FormatFlavor, fileTailCache *FileTailCache, prefetchLazyRanges bool) *MothReaderOptions {
 	ms := new(MothReaderOptions)
 	ms.maxMergeDistance = maxMergeDistance
 	ms.maxBufferSize = maxBufferSize
@@ -46,6 +61,11 @@ func NewMothReaderOptions2(bloomFiltersEnabled bool, maxMergeDistance util.DataS
 	ms.lazyReadSmallRanges = lazyReadSmallRanges
 	ms.bloomFiltersEnabled = bloomFiltersEnabled
 	ms.nestedLazy = nestedLazy
+	ms.keyProvider = keyProvider
+	ms.stripePrefetchCount = stripePrefetchCount
+	ms.formatFlavor = formatFlavor
+	ms.fileTailCache = fileTailCache
+	ms.prefetchLazyRanges = prefetchLazyRanges
 	return ms
 }
 
@@ -81,36 +101,98 @@ func (ms *MothReaderOptions) IsNestedLazy() bool {
 	return ms.nestedLazy
 }
 
+/**
+ * Returns whether the small ranges read lazily are also fetched in the background as soon as a
+ * stripe is read, off by default.
+ */
+func (ms *MothReaderOptions) IsPrefetchLazyRanges() bool {
+	return ms.prefetchLazyRanges
+}
+
+/**
+ * Provider of the master keys for encrypted columns, columns whose key it does not hold are
+ * read from their masked copy.
+ */
+func (ms *MothReaderOptions) GetKeyProvider() encryption.KeyProvider {
+	return ms.keyProvider
+}
+
+/**
+ * Number of stripes read and decompressed ahead of the current one on a pool of
+ * goroutines, zero reads every stripe on the caller's goroutine. The prefetched stripes are
+ * reserved in the memory context of the record reader, a stripe is only read ahead when the
+ * context grants its size.
+ */
+func (ms *MothReaderOptions) GetStripePrefetchCount() int32 {
+	return ms.stripePrefetchCount
+}
+
+/**
+ * Flavour of the files read, the magic in the postscript or at the start of the file must match
+ * it.
+ */
+func (ms *MothReaderOptions) GetFormatFlavor() metadata.FormatFlavor {
+	return ms.formatFlavor
+}
+
+/**
+ * Cache of the parsed file tails shared by the readers, nil when the tail is read and parsed by
+ * every reader.
+ */
+func (ms *MothReaderOptions) GetFileTailCache() *FileTailCache {
+	return ms.fileTailCache
+}
+
 func (ms *MothReaderOptions) WithBloomFiltersEnabled(bloomFiltersEnabled bool) *MothReaderOptions {
-	return NewMothReaderOptions2(bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy)
+	return NewMothReaderOptions2(bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy, ms.keyProvider, ms.stripePrefetchCount, ms.formatFlavor, ms.fileTailCache, ms.prefetchLazyRanges)
 }
 
 func (ms *MothReaderOptions) WithMaxMergeDistance(maxMergeDistance util.DataSize) *MothReaderOptions {
-	return NewMothReaderOptions2(ms.bloomFiltersEnabled, maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy)
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy, ms.keyProvider, ms.stripePrefetchCount, ms.formatFlavor, ms.fileTailCache, ms.prefetchLazyRanges)
 }
 
 func (ms *MothReaderOptions) WithMaxBufferSize(maxBufferSize util.DataSize) *MothReaderOptions {
-	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy)
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy, ms.keyProvider, ms.stripePrefetchCount, ms.formatFlavor, ms.fileTailCache, ms.prefetchLazyRanges)
 }
 
 func (ms *MothReaderOptions) WithTinyStripeThreshold(tinyStripeThreshold util.DataSize) *MothReaderOptions {
-	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy)
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy, ms.keyProvider, ms.stripePrefetchCount, ms.formatFlavor, ms.fileTailCache, ms.prefetchLazyRanges)
 }
 
 func (ms *MothReaderOptions) WithStreamBufferSize(streamBufferSize util.DataSize) *MothReaderOptions {
-	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy)
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy, ms.keyProvider, ms.stripePrefetchCount, ms.formatFlavor, ms.fileTailCache, ms.prefetchLazyRanges)
 }
 
 func (ms *MothReaderOptions) WithMaxReadBlockSize(maxBlockSize util.DataSize) *MothReaderOptions {
-	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy)
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy, ms.keyProvider, ms.stripePrefetchCount, ms.formatFlavor, ms.fileTailCache, ms.prefetchLazyRanges)
 }
 
 // @Deprecated
 func (ms *MothReaderOptions) WithLazyReadSmallRanges(lazyReadSmallRanges bool) *MothReaderOptions {
-	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, lazyReadSmallRanges, ms.nestedLazy)
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, lazyReadSmallRanges, ms.nestedLazy, ms.keyProvider, ms.stripePrefetchCount, ms.formatFlavor, ms.fileTailCache, ms.prefetchLazyRanges)
 }
 
 // @Deprecated
 func (ms *MothReaderOptions) WithNestedLazy(nestedLazy bool) *MothReaderOptions {
-	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, nestedLazy)
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, nestedLazy, ms.keyProvider, ms.stripePrefetchCount, ms.formatFlavor, ms.fileTailCache, ms.prefetchLazyRanges)
+}
+
+func (ms *MothReaderOptions) WithKeyProvider(keyProvider encryption.KeyProvider) *MothReaderOptions {
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy, keyProvider, ms.stripePrefetchCount, ms.formatFlavor, ms.fileTailCache, ms.prefetchLazyRanges)
+}
+
+func (ms *MothReaderOptions) WithStripePrefetchCount(stripePrefetchCount int32) *MothReaderOptions {
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy, ms.keyProvider, stripePrefetchCount, ms.formatFlavor, ms.fileTailCache, ms.prefetchLazyRanges)
+}
+
+func (ms *MothReaderOptions) WithFormatFlavor(formatFlavor metadata.FormatFlavor) *MothReaderOptions {
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy, ms.keyProvider, ms.stripePrefetchCount, formatFlavor, ms.fileTailCache, ms.prefetchLazyRanges)
+}
+
+func (ms *MothReaderOptions) WithFileTailCache(fileTailCache *FileTailCache) *MothReaderOptions {
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy, ms.keyProvider, ms.stripePrefetchCount, ms.formatFlavor, fileTailCache, ms.prefetchLazyRanges)
+}
+
+func (ms *MothReaderOptions) WithPrefetchLazyRanges(prefetchLazyRanges bool) *MothReaderOptions {
+	return NewMothReaderOptions2(ms.bloomFiltersEnabled, ms.maxMergeDistance, ms.maxBufferSize, ms.tinyStripeThreshold, ms.streamBufferSize, ms.maxBlockSize, ms.lazyReadSmallRanges, ms.nestedLazy, ms.keyProvider, ms.stripePrefetchCount, ms.formatFlavor, ms.fileTailCache, prefetchLazyRanges)
 }
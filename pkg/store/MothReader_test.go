@@ -0,0 +1,216 @@
+package store
+
+import (
+	"encoding/binary"
+	goerrors "errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"time"
+
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/mothdb-bd/orc-go/pkg/errors"
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/store/proto"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func TestTryCreateMothReader(t *testing.T) {
+	data := writeTestFile(100, NewMothWriterOptions(), metadata.ZLIB).AvailableBytes()
+	garbage := make([]byte, 100)
+	for i := range garbage {
+		garbage[i] = 0xff
+	}
+	// the postscript is intact, the footer it points to is not
+	badFooter := append([]byte{}, data...)
+	for i := len(badFooter) - 60; i < len(badFooter)-30; i++ {
+		badFooter[i] = 0xff
+	}
+	tests := []struct {
+		name string
+		data []byte
+		code *errors.StandardErrorCode
+	}{
+		{"valid", data, nil},
+		{"garbage", garbage, errors.CORRUPT_FILE},
+		{"too small", []byte{'M', 'O'}, errors.CORRUPT_FILE},
+		{"corrupt footer", badFooter, errors.CORRUPT_FILE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := TryCreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), slice.NewWithBuf(tt.data)), NewMothReaderOptions())
+			if tt.code == nil {
+				if err != nil || reader.IsEmpty() {
+					t.Fatalf("got error %v", err)
+				}
+				return
+			}
+			var mothError *common.MothError
+			if !goerrors.As(err, &mothError) {
+				t.Fatalf("got %v, want a moth error", err)
+			}
+			if mothError.GetCode() != tt.code || mothError.GetMothDataSourceId().String() != "test" {
+				t.Fatalf("got %s, want code %s for data source test", mothError.Error(), tt.code.GetName())
+			}
+		})
+	}
+}
+
+func TestOpenFileMothDataSource(t *testing.T) {
+	_, err := OpenFileMothDataSource(filepath.Join(t.TempDir(), "missing.moth"), NewMothReaderOptions())
+	var mothError *common.MothError
+	if !goerrors.As(err, &mothError) || mothError.GetCode() != errors.IO_ERROR {
+		t.Fatalf("got %v, want an IO_ERROR", err)
+	}
+	if !goerrors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("got %v, want it to wrap fs.ErrNotExist", err)
+	}
+}
+
+func TestMothReader_FormatFlavor(t *testing.T) {
+	mothData := writeTestFile(2500, NewMothWriterOptions().WithStripeMaxRowCount(1000), metadata.ZLIB)
+	orcData := writeTestFile(2500, NewMothWriterOptions().WithStripeMaxRowCount(1000).WithFormatFlavor(metadata.ORC_FLAVOR), metadata.ZLIB)
+	if header := string(orcData.AvailableBytes()[:3]); header != "ORC" {
+		t.Fatalf("ORC file starts with %q", header)
+	}
+	// the postscript of an ORC file is a bare protobuf message
+	orcBytes := orcData.AvailableBytes()
+	postScript := &proto.PostScript{}
+	if err := protobuf.Unmarshal(orcBytes[len(orcBytes)-1-int(orcBytes[len(orcBytes)-1]):len(orcBytes)-1], postScript); err != nil || postScript.GetMagic() != "ORC" {
+		t.Fatalf("ORC postscript with magic %q: %v", postScript.GetMagic(), err)
+	}
+	// files without a magic in the postscript are recognized by the magic at the start of the file
+	noPostScriptMagic := append([]byte{}, orcData.AvailableBytes()...)
+	copy(noPostScriptMagic[len(noPostScriptMagic)-4:], "XXX")
+
+	tests := []struct {
+		name   string
+		data   *slice.Slice
+		flavor metadata.FormatFlavor
+		valid  bool
+	}{
+		{"moth", mothData, metadata.MOTH_FLAVOR, true},
+		{"orc", orcData, metadata.ORC_FLAVOR, true},
+		{"orc header magic", slice.NewWithBuf(noPostScriptMagic), metadata.ORC_FLAVOR, true},
+		{"orc read as moth", orcData, metadata.MOTH_FLAVOR, false},
+		{"moth read as orc", mothData, metadata.ORC_FLAVOR, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := NewMothReaderOptions().WithFormatFlavor(tt.flavor)
+			reader, err := TryCreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), tt.data), options)
+			if !tt.valid {
+				var mothError *common.MothError
+				if !goerrors.As(err, &mothError) || mothError.GetCode() != errors.CORRUPT_FILE {
+					t.Fatalf("got %v, want a CORRUPT_FILE error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			footer := reader.Get().GetFooter()
+			if !footer.GetWriterId().IsPresent() || footer.GetWriterId().Get() != tt.flavor.GetWriterId() {
+				t.Fatalf("got writer id %v, want %d", footer.GetWriterId(), tt.flavor.GetWriterId())
+			}
+			if ids := readIds(tt.data, options, TRUE); len(ids) != 2500 || ids[2499] != 2499 {
+				t.Fatalf("read %d rows", len(ids))
+			}
+		})
+	}
+}
+
+// protobuf wire format helpers for TestMothReader_OrcSpecFile
+func pbVarint(field int, value uint64) []byte {
+	return binary.AppendUvarint(binary.AppendUvarint(nil, uint64(field<<3)), value)
+}
+
+func pbBytes(field int, value []byte) []byte {
+	return append(binary.AppendUvarint(binary.AppendUvarint(nil, uint64(field<<3|2)), uint64(len(value))), value...)
+}
+
+func pbPacked(field int, values ...uint64) []byte {
+	packed := make([]byte, 0)
+	for _, value := range values {
+		packed = binary.AppendUvarint(packed, value)
+	}
+	return pbBytes(field, packed)
+}
+
+func TestMothReader_OrcSpecFile(t *testing.T) {
+	// an uncompressed ORC file of one stripe with DIRECT (RLE v1) encodings, assembled from the
+	// ORC specification, the timestamps are written like the Java writer in America/Los_Angeles
+	streams := [][]byte{
+		// id: a run of 3 values with delta 1 from 1
+		{0x00, 0x01, 0x02},
+		// name: DATA and LENGTH, literals 5, 4, 5
+		[]byte("alphabetagamma"),
+		{0xfd, 0x05, 0x04, 0x05},
+		// ts: DATA, literals of the zigzag seconds since 2015-01-01 00:00:00 in the writer time
+		// zone, 2021-06-01 12:00:00 PDT, 2014-12-31 23:59:59 PST and 2015-01-01 00:00:00 PST
+		append(binary.AppendUvarint([]byte{0xfd}, 202474800<<1), 0x01, 0x00),
+		// ts: SECONDARY, the nanos 500000000 are 5 with 7 zeros removed, (5 << 3) | 7
+		{0xfd, 0x2f, 0x00, 0x00},
+	}
+	streamKinds := []struct{ kind, column uint64 }{{1, 1}, {1, 2}, {2, 2}, {1, 3}, {5, 3}}
+	data := []byte("ORC")
+	stripeFooter := make([]byte, 0)
+	for i, stream := range streams {
+		data = append(data, stream...)
+		stripeFooter = append(stripeFooter, pbBytes(1, append(append(pbVarint(1, streamKinds[i].kind), pbVarint(2, streamKinds[i].column)...), pbVarint(3, uint64(len(stream)))...))...)
+	}
+	dataLength := len(data) - 3
+	for column := 0; column < 4; column++ {
+		stripeFooter = append(stripeFooter, pbBytes(2, pbVarint(1, 0))...)
+	}
+	stripeFooter = append(stripeFooter, pbBytes(3, []byte("America/Los_Angeles"))...)
+	data = append(data, stripeFooter...)
+
+	footer := append(pbVarint(1, 3), pbVarint(2, uint64(len(data)-3))...)
+	stripe := append(append(append(append(pbVarint(1, 3), pbVarint(2, 0)...), pbVarint(3, uint64(dataLength))...), pbVarint(4, uint64(len(stripeFooter)))...), pbVarint(5, 3)...)
+	footer = append(footer, pbBytes(3, stripe)...)
+	root := append(pbVarint(1, 12), pbPacked(2, 1, 2, 3)...)
+	for _, name := range []string{"id", "name", "ts"} {
+		root = append(root, pbBytes(3, []byte(name))...)
+	}
+	footer = append(footer, pbBytes(4, root)...)
+	for _, kind := range []uint64{4, 7, 9} {
+		footer = append(footer, pbBytes(4, pbVarint(1, kind))...)
+	}
+	footer = append(append(footer, pbVarint(6, 3)...), pbVarint(9, 1)...)
+	data = append(data, footer...)
+
+	postScript := append(append(append(append(pbVarint(1, uint64(len(footer))), pbVarint(2, 0)...), pbPacked(4, 0, 12)...), pbVarint(5, 0)...), pbVarint(6, 6)...)
+	postScript = append(postScript, pbBytes(8000, []byte("ORC"))...)
+	data = append(append(data, postScript...), byte(len(postScript)))
+
+	options := NewMothReaderOptions().WithFormatFlavor(metadata.ORC_FLAVOR)
+	reader, err := TryCreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("orc"), slice.NewWithBuf(data)), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reader.Get().HasLegacyTimestamps() {
+		t.Fatal("an ORC file has legacy timestamps")
+	}
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR, block.TIMESTAMP_MICROS)
+	recordReader := reader.Get().CreateRecordReader(reader.Get().GetRootColumn().GetNestedColumns(), types, TRUE, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+	defer recordReader.Close()
+	got := make([]string, 0)
+	for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+		page = page.GetLoadedPage()
+		for position := util.INT32_ZERO; position < page.GetPositionCount(); position++ {
+			ts := time.UnixMicro(block.TIMESTAMP_MICROS.GetLong(page.GetBlock(2), position)).UTC().Format("2006-01-02 15:04:05.999")
+			got = append(got, fmt.Sprintf("%d %s %s", block.BIGINT.GetLong(page.GetBlock(0), position), block.VARCHAR.GetSlice(page.GetBlock(1), position).String(), ts))
+		}
+	}
+	// the timestamps are read as the wall clock time of the writer
+	want := []string{"1 alpha 2021-06-01 12:00:00.5", "2 beta 2014-12-31 23:59:59", "3 gamma 2015-01-01 00:00:00"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("read %q, want %q", got, want)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/slice"
 	"github.com/mothdb-bd/orc-go/pkg/spi"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -19,6 +20,8 @@ var MR_INSTANCE_SIZE int32 = util.SizeOf(&MothRecordReader{})
 
 type MothRecordReader struct {
 	mothDataSource             MothDataSource
+	readColumns                *util.ArrayList[*MothColumn]
+	readTypes                  *util.ArrayList[block.Type]
 	columnReaders              []ColumnReader
 	currentBytesPerCell        []int64
 	maxBytesPerCell            []int64
@@ -29,24 +32,30 @@ type MothRecordReader struct {
 	mothTypes                  *metadata.ColumnMetadata[*metadata.MothType]
 	currentPosition            int64
 	currentStripePosition      int64
+	stripeReaderPositions      *util.ArrayList[int64]
 	currentBatchSize           int32
 	nextBatchSize              int32
 	maxBatchSize               int32
 	stripes                    *util.ArrayList[*metadata.StripeInformation]
 	stripeReader               *StripeReader
+	stripePrefetcher           *StripePrefetcher
 	currentStripe              int32
 	currentStripeMemoryContext memory.AggregatedMemoryContext
 	fileRowCount               int64
 	stripeFilePositions        *util.ArrayList[int64]
 	filePosition               int64
-	rowGroups                  util.Iterator[*RowGroup]
+	rowGroups                  *util.ArrayList[*RowGroup]
 	currentRowGroup            int32
 	currentGroupRowCount       int64
 	nextRowInGroup             int64
 	userMetadata               map[string]*slice.Slice
 	memoryUsage                memory.AggregatedMemoryContext
 	mothDataSourceMemoryUsage  memory.LocalMemoryContext
+	pageMemoryUsage            memory.LocalMemoryContext
 	blockFactory               *MothBlockFactory
+	deleteFilter               *DeleteFilter
+	deleteKeyChannel           int32
+	deleteVersionChannel       int32
 }
 
 type StripeInfoCmp struct {
@@ -57,15 +66,18 @@ func (sp *StripeInfoCmp) Cmp(i, j *StripeInfo) int {
 	return int(i.GetStripe().GetOffset() - i.GetStripe().GetOffset())
 }
 
-func NewMothRecordReader(readColumns *util.ArrayList[*MothColumn], readTypes *util.ArrayList[block.Type], readLayouts util.List[ProjectedLayout], predicate MothPredicate, numberOfRows int64, fileStripes *util.ArrayList[*metadata.StripeInformation], fileStats *optional.Optional[*metadata.ColumnMetadata[*metadata.ColumnStatistics]], stripeStats *util.ArrayList[*optional.Optional[*metadata.StripeStatistics]], mothDataSource MothDataSource, splitOffset int64, splitLength int64, mothTypes *metadata.ColumnMetadata[*metadata.MothType], decompressor *optional.Optional[MothDecompressor], rowsInRowGroup *optional.OptionalInt, legacyFileTimeZone *time.Location, hiveWriterVersion metadata.HiveWriterVersion, metadataReader metadata.MetadataReader, options *MothReaderOptions, userMetadata map[string]*slice.Slice, memoryUsage memory.AggregatedMemoryContext, initialBatchSize int32, fieldMapperFactory FieldMapperFactory) *MothRecordReader {
+func NewMothRecordReader(readColumns *util.ArrayList[*MothColumn], readTypes *util.ArrayList[block.Type], readLayouts util.List[ProjectedLayout], predicate MothPredicate, numberOfRows int64, fileStripes *util.ArrayList[*metadata.StripeInformation], fileStats *optional.Optional[*metadata.ColumnMetadata[*metadata.ColumnStatistics]], stripeStats *util.ArrayList[*optional.Optional[*metadata.StripeStatistics]], mothDataSource MothDataSource, splitOffset int64, splitLength int64, mothTypes *metadata.ColumnMetadata[*metadata.MothType], decompressor *optional.Optional[MothDecompressor], rowsInRowGroup *optional.OptionalInt, legacyFileTimeZone *time.Location, hiveWriterVersion metadata.HiveWriterVersion, metadataReader metadata.MetadataReader, decryptedVariants *util.ArrayList[*DecryptedVariant], options *MothReaderOptions, userMetadata map[string]*slice.Slice, memoryUsage memory.AggregatedMemoryContext, initialBatchSize int32, fieldMapperFactory FieldMapperFactory) *MothRecordReader {
 	mr := new(MothRecordReader)
-	mr.rowGroups = util.NewArrayList[*RowGroup]().Iter()
+	mr.rowGroups = util.NewArrayList[*RowGroup]()
 	mr.maxBatchSize = MAX_BATCH_SIZE
 	mr.currentStripe = -1
 	mr.currentRowGroup = -1
 
 	mr.mothTypes = mothTypes
+	mr.readColumns = readColumns
+	mr.readTypes = readTypes
 	mr.memoryUsage = memoryUsage.NewAggregatedMemoryContext()
+	mr.pageMemoryUsage = mr.memoryUsage.NewLocalMemoryContext("MothRecordReader")
 	mr.blockFactory = NewMothBlockFactory(options.IsNestedLazy())
 	mr.maxBlockBytes = int64(options.GetMaxBlockSize().Bytes())
 	stripeInfos := util.NewCmpListWithValues[*StripeInfo](new(StripeInfoCmp))
@@ -82,12 +94,14 @@ func NewMothRecordReader(readColumns *util.ArrayList[*MothColumn], readTypes *ut
 	fileRowCount := util.INT64_ZERO
 	stripes := util.NewArrayList[*metadata.StripeInformation]()
 	stripeFilePositions := util.NewArrayList[int64]()
+	stripeReaderPositions := util.NewArrayList[int64]()
 	if fileStats.IsEmpty() || predicate.Matches(numberOfRows, fileStats.Get()) {
 		for _, info := range stripeInfos.ToArray() {
 			stripe := info.GetStripe()
 			if splitContainsStripe(splitOffset, splitLength, stripe) && isStripeIncluded(stripe, info.GetStats(), predicate) {
 				stripes.Add(stripe)
 				stripeFilePositions.Add(fileRowCount)
+				stripeReaderPositions.Add(totalRowCount)
 				totalRowCount += int64(stripe.GetNumberOfRows())
 			}
 			fileRowCount += int64(stripe.GetNumberOfRows())
@@ -96,6 +110,7 @@ func NewMothRecordReader(readColumns *util.ArrayList[*MothColumn], readTypes *ut
 	mr.totalRowCount = totalRowCount
 	mr.stripes = stripes
 	mr.stripeFilePositions = stripeFilePositions
+	mr.stripeReaderPositions = stripeReaderPositions
 	mothDataSource = wrapWithCacheIfTinyStripes(mothDataSource, mr.stripes, options.GetMaxMergeDistance(), options.GetTinyStripeThreshold())
 	mr.mothDataSource = mothDataSource
 	mr.mothDataSourceMemoryUsage = memoryUsage.NewLocalMemoryContext("MothDataSource")
@@ -105,7 +120,10 @@ func NewMothRecordReader(readColumns *util.ArrayList[*MothColumn], readTypes *ut
 	mr.userMetadata = userMetadata
 	mr.currentStripeMemoryContext = mr.memoryUsage.NewAggregatedMemoryContext()
 	streamReadersMemoryContext := mr.memoryUsage.NewAggregatedMemoryContext()
-	mr.stripeReader = NewStripeReader(mothDataSource, legacyFileTimeZone, decompressor, mothTypes, util.NewSetWithItems(util.SET_NonThreadSafe, readColumns.ToArray()...), rowsInRowGroup, predicate, hiveWriterVersion, metadataReader)
+	mr.stripeReader = NewStripeReader(mothDataSource, legacyFileTimeZone, decompressor, mothTypes, util.NewSetWithItems(util.SET_NonThreadSafe, fileColumns(readColumns)...), rowsInRowGroup, predicate, options.IsBloomFiltersEnabled(), hiveWriterVersion, metadataReader, decryptedVariants, options.GetStripePrefetchCount() > 0)
+	if options.GetStripePrefetchCount() > 0 && !mr.stripes.IsEmpty() {
+		mr.stripePrefetcher = NewStripePrefetcher(mr.stripeReader, mr.stripes, mr.memoryUsage, options.GetStripePrefetchCount())
+	}
 	mr.columnReaders = createColumnReaders(readColumns, readTypes, readLayouts, streamReadersMemoryContext, mr.blockFactory, fieldMapperFactory)
 	mr.currentBytesPerCell = make([]int64, len(mr.columnReaders))
 	mr.maxBytesPerCell = make([]int64, len(mr.columnReaders))
@@ -172,6 +190,9 @@ func (mr *MothRecordReader) GetColumnTypes() *metadata.ColumnMetadata[*metadata.
 func (mr *MothRecordReader) Close() {
 	// closer := Closer.create()
 	// closer.register(mr.mothDataSource)
+	if mr.stripePrefetcher != nil {
+		mr.stripePrefetcher.Close()
+	}
 	mr.mothDataSource.Close()
 	for _, column := range mr.columnReaders {
 		if column != nil {
@@ -179,9 +200,61 @@ func (mr *MothRecordReader) Close() {
 			column.Close()
 		}
 	}
+	mr.pageMemoryUsage.Close()
+	mr.mothDataSourceMemoryUsage.Close()
+	mr.currentStripeMemoryContext.Close()
+	mr.memoryUsage.Close()
+}
+
+/**
+ * Skips the rows deleted by the delete filter. The keys of the rows are read from the column at
+ * keyChannel, a varchar, char, varbinary or integer column, and their versions from the integer
+ * column at versionChannel. Without a version column, versionChannel -1, the rows are deleted by
+ * any delete of their key.
+ */
+func (mr *MothRecordReader) SetDeleteFilter(deleteFilter *DeleteFilter, keyChannel int32, versionChannel int32) {
+	keyType := mr.readTypes.GetByInt32(keyChannel)
+	util.CheckArgument2(isRowKeyType(keyType), fmt.Sprintf("Unsupported key type: %s", keyType.GetDisplayName()))
+	if versionChannel >= 0 {
+		switch versionType := mr.readTypes.GetByInt32(versionChannel); versionType.(type) {
+		case *block.BigintType, *block.IntegerType:
+		default:
+			panic(fmt.Sprintf("Unsupported version type: %s", versionType.GetDisplayName()))
+		}
+	}
+	mr.deleteFilter = deleteFilter
+	mr.deleteKeyChannel = keyChannel
+	mr.deleteVersionChannel = versionChannel
 }
 
+/**
+ * Returns the next page without the deleted rows, pages whose rows are all deleted are skipped.
+ */
 func (mr *MothRecordReader) NextPage() *spi.Page {
+	for {
+		page := mr.nextPage()
+		if page == nil {
+			return nil
+		}
+		page = mr.filterDeletedRows(page)
+		if page.GetPositionCount() > 0 {
+			return page
+		}
+	}
+}
+
+func (mr *MothRecordReader) filterDeletedRows(page *spi.Page) *spi.Page {
+	if mr.deleteFilter == nil {
+		return page
+	}
+	var versionType block.Type
+	if mr.deleteVersionChannel >= 0 {
+		versionType = mr.readTypes.GetByInt32(mr.deleteVersionChannel)
+	}
+	return mr.deleteFilter.filterPage(page, mr.readTypes.GetByInt32(mr.deleteKeyChannel), mr.deleteKeyChannel, versionType, mr.deleteVersionChannel)
+}
+
+func (mr *MothRecordReader) nextPage() *spi.Page {
 	mr.filePosition += int64(mr.currentBatchSize)
 	mr.currentPosition += int64(mr.currentBatchSize)
 	mr.currentBatchSize = 0
@@ -195,6 +268,7 @@ func (mr *MothRecordReader) NextPage() *spi.Page {
 	mr.currentBatchSize = maths.MinInt32(mr.nextBatchSize, mr.maxBatchSize)
 	mr.nextBatchSize = maths.MinInt32(mr.currentBatchSize*BATCH_SIZE_GROWTH_FACTOR, MAX_BATCH_SIZE)
 	mr.currentBatchSize = util.Int32Exact(maths.MinInt64s(int64(mr.currentBatchSize), mr.currentGroupRowCount-mr.nextRowInGroup))
+	mr.reservePageMemory()
 	for _, column := range mr.columnReaders {
 		if column != nil {
 			column.PrepareNextRead(mr.currentBatchSize)
@@ -215,6 +289,59 @@ func (mr *MothRecordReader) NextPage() *spi.Page {
 	return page
 }
 
+/**
+ * Like NextPage, but the page is loaded and a failure is returned as a *common.MothError naming
+ * the stripe and the column that were read, instead of panicking. The page is nil at the end of
+ * the reader. The reader can not be used after a failure.
+ */
+func (mr *MothRecordReader) TryNextPage() (page *spi.Page, err error) {
+	columnPath := ""
+	defer func() {
+		if failure := recover(); failure != nil {
+			mothError := common.AsMothError(failure, mr.mothDataSource.GetId())
+			if mr.currentStripe >= 0 && mr.currentStripe < mr.stripes.SizeInt32() {
+				mothError.SetStripeOffset(int64(mr.stripes.GetByInt32(mr.currentStripe).GetOffset()))
+			}
+			mothError.SetColumnPath(columnPath)
+			page, err = nil, mothError
+		}
+	}()
+	page = mr.NextPage()
+	if page == nil {
+		return nil, nil
+	}
+	blocks := make([]block.Block, page.GetChannelCount())
+	for i := range blocks {
+		if column := mr.readColumns.Get(i); column != nil {
+			columnPath = column.GetPath()
+		}
+		blocks[i] = page.GetBlock(int32(i)).GetLoadedBlock()
+	}
+	return spi.NewPage3(page.GetPositionCount(), blocks...), nil
+}
+
+/**
+ * Reserves the memory of the next page, estimated from the largest rows read so far. When the
+ * memory pool rejects the reservation, the batch is halved until it fits, down to a single row
+ * that is read even when the pool is exhausted. The batches grow again from the reduced size,
+ * so the reader follows the memory released by the other users of the pool.
+ */
+func (mr *MothRecordReader) reservePageMemory() {
+	bytesPerRow := maths.Max(mr.maxCombinedBytesPerRow, mr.rowGroups.GetByInt32(mr.currentRowGroup).GetMinAverageRowBytes())
+	if mr.pageMemoryUsage.TrySetBytes(int64(mr.currentBatchSize) * bytesPerRow) {
+		return
+	}
+	reserved := false
+	for mr.currentBatchSize > 1 && !reserved {
+		mr.currentBatchSize /= 2
+		reserved = mr.pageMemoryUsage.TrySetBytes(int64(mr.currentBatchSize) * bytesPerRow)
+	}
+	if !reserved {
+		mr.pageMemoryUsage.SetBytes(bytesPerRow)
+	}
+	mr.nextBatchSize = maths.MinInt32(mr.currentBatchSize*BATCH_SIZE_GROWTH_FACTOR, MAX_BATCH_SIZE)
+}
+
 func (mr *MothRecordReader) blockLoaded(columnIndex int32, block block.Block) {
 	if block.GetPositionCount() <= 0 {
 		return
@@ -234,16 +361,15 @@ func (mr *MothRecordReader) GetUserMetadata() map[string]*slice.Slice {
 
 func (mr *MothRecordReader) advanceToNextRowGroup() bool {
 	mr.nextRowInGroup = 0
-	for !mr.rowGroups.HasNext() && mr.currentStripe < mr.stripes.SizeInt32() {
+	for mr.currentRowGroup+1 >= mr.rowGroups.SizeInt32() && mr.currentStripe < mr.stripes.SizeInt32() {
 		mr.advanceToNextStripe()
-		mr.currentRowGroup = -1
 	}
-	if !mr.rowGroups.HasNext() {
+	if mr.currentRowGroup+1 >= mr.rowGroups.SizeInt32() {
 		mr.currentGroupRowCount = 0
 		return false
 	}
 	mr.currentRowGroup++
-	currentRowGroup := mr.rowGroups.Next()
+	currentRowGroup := mr.rowGroups.GetByInt32(mr.currentRowGroup)
 	mr.currentGroupRowCount = currentRowGroup.GetRowCount()
 	if currentRowGroup.GetMinAverageRowBytes() > 0 {
 		mr.maxBatchSize = util.Int32Exact(maths.Min(int64(mr.maxBatchSize), maths.Max(1, mr.maxBlockBytes/currentRowGroup.GetMinAverageRowBytes())))
@@ -260,18 +386,36 @@ func (mr *MothRecordReader) advanceToNextRowGroup() bool {
 }
 
 func (mr *MothRecordReader) advanceToNextStripe() {
+	mr.openStripe(mr.currentStripe + 1)
+}
+
+/**
+ * Reads the stripe with the given index in the included stripes and starts the column readers
+ * on it, its first row group is started by advanceToNextRowGroup. The prefetcher only returns
+ * the stripes in order, it is stopped when a seek opens any other stripe and the remaining
+ * stripes are read on demand.
+ */
+func (mr *MothRecordReader) openStripe(stripeIndex int32) {
 	mr.currentStripeMemoryContext.Close()
 	mr.currentStripeMemoryContext = mr.memoryUsage.NewAggregatedMemoryContext()
-	mr.rowGroups = util.NewArrayList[*RowGroup]().Iter()
-	mr.currentStripe++
+	mr.rowGroups = util.NewArrayList[*RowGroup]()
+	mr.currentRowGroup = -1
+	mr.currentStripe = stripeIndex
 	if mr.currentStripe >= mr.stripes.SizeInt32() {
 		return
 	}
-	if mr.currentStripe > 0 {
-		mr.currentStripePosition += int64(mr.stripes.GetByInt32(mr.currentStripe - 1).GetNumberOfRows())
+	mr.currentStripePosition = mr.stripeReaderPositions.GetByInt32(mr.currentStripe)
+	if mr.stripePrefetcher != nil && mr.stripePrefetcher.GetNextStripe() != mr.currentStripe {
+		mr.stripePrefetcher.Close()
+		mr.stripePrefetcher = nil
+	}
+	var stripe *Stripe
+	if mr.stripePrefetcher != nil {
+		stripe, mr.currentStripeMemoryContext = mr.stripePrefetcher.Next()
+	} else {
+		stripeInformation := mr.stripes.GetByInt32(mr.currentStripe)
+		stripe = mr.stripeReader.ReadStripe(stripeInformation, mr.currentStripeMemoryContext)
 	}
-	stripeInformation := mr.stripes.GetByInt32(mr.currentStripe)
-	stripe := mr.stripeReader.ReadStripe(stripeInformation, mr.currentStripeMemoryContext)
 	if stripe != nil {
 		dictionaryStreamSources := stripe.GetDictionaryStreamSources()
 		columnEncodings := stripe.GetColumnEncodings()
@@ -281,16 +425,135 @@ func (mr *MothRecordReader) advanceToNextStripe() {
 				column.StartStripe(fileTimeZone, dictionaryStreamSources, columnEncodings)
 			}
 		}
-		mr.rowGroups = stripe.GetRowGroups().Iter()
+		mr.rowGroups = stripe.GetRowGroups()
 	}
 	mr.mothDataSourceMemoryUsage.SetBytes(mr.mothDataSource.GetRetainedSize())
 }
 
+/**
+ * Positions the reader so the next page starts at the given file row. Rows the reader does not
+ * return, because they are outside of the split or their stripe or row group was pruned by the
+ * predicate, are skipped: the next page starts at the first returned row after rowNumber. The
+ * stripe and row group owning the row are located by their row offsets and the streams are
+ * positioned at the checkpoints of the row group, only the rows in front of rowNumber within
+ * the row group are skipped. The pages returned before the seek must be loaded before seeking.
+ */
+func (mr *MothRecordReader) SeekToRow(rowNumber int64) {
+	util.CheckArgument2(rowNumber >= 0, "rowNumber is negative")
+	mr.currentBatchSize = 0
+	stripeIndex := util.INT32_ZERO
+	for stripeIndex < mr.stripes.SizeInt32() && mr.stripeFilePositions.GetByInt32(stripeIndex)+int64(mr.stripes.GetByInt32(stripeIndex).GetNumberOfRows()) <= rowNumber {
+		stripeIndex++
+	}
+	if stripeIndex >= mr.stripes.SizeInt32() {
+		mr.openStripe(stripeIndex)
+		mr.currentGroupRowCount = 0
+		mr.nextRowInGroup = 0
+		mr.filePosition = mr.fileRowCount
+		mr.currentPosition = mr.totalRowCount
+		return
+	}
+
+	// the streams only move forward, an earlier row of the current stripe needs the stripe to be read again
+	rowInStripe := maths.Max(0, rowNumber-mr.stripeFilePositions.GetByInt32(stripeIndex))
+	if stripeIndex != mr.currentStripe || rowInStripe < mr.nextRowInStripe() {
+		mr.openStripe(stripeIndex)
+	}
+	rowGroup := mr.currentRowGroup
+	if rowGroup < 0 {
+		rowGroup = 0
+	}
+	for rowGroup < mr.rowGroups.SizeInt32() && mr.rowGroups.GetByInt32(rowGroup).GetRowOffset()+mr.rowGroups.GetByInt32(rowGroup).GetRowCount() <= rowInStripe {
+		rowGroup++
+	}
+	if rowGroup >= mr.rowGroups.SizeInt32() {
+		// no row group of the stripe is left, the next page starts in the next stripe
+		mr.currentRowGroup = rowGroup - 1
+		mr.nextRowInGroup = mr.currentGroupRowCount
+		mr.filePosition = mr.stripeFilePositions.GetByInt32(stripeIndex) + int64(mr.stripes.GetByInt32(stripeIndex).GetNumberOfRows())
+		mr.currentPosition = mr.currentStripePosition + int64(mr.stripes.GetByInt32(stripeIndex).GetNumberOfRows())
+		return
+	}
+	if rowGroup != mr.currentRowGroup {
+		mr.currentRowGroup = rowGroup - 1
+		mr.advanceToNextRowGroup()
+	}
+	rowGroupOffset := mr.rowGroups.GetByInt32(rowGroup).GetRowOffset()
+	if skip := rowInStripe - rowGroupOffset - mr.nextRowInGroup; skip > 0 {
+		for _, column := range mr.columnReaders {
+			if column != nil {
+				column.PrepareNextRead(util.Int32Exact(skip))
+			}
+		}
+		mr.nextRowInGroup += skip
+	}
+	mr.filePosition = mr.stripeFilePositions.GetByInt32(stripeIndex) + rowGroupOffset + mr.nextRowInGroup
+	mr.currentPosition = mr.currentStripePosition + rowGroupOffset + mr.nextRowInGroup
+}
+
+/**
+ * Returns the row within the current stripe the next page starts at.
+ */
+func (mr *MothRecordReader) nextRowInStripe() int64 {
+	if mr.currentRowGroup < 0 || mr.currentRowGroup >= mr.rowGroups.SizeInt32() {
+		return 0
+	}
+	return mr.rowGroups.GetByInt32(mr.currentRowGroup).GetRowOffset() + mr.nextRowInGroup
+}
+
+/**
+ * Reads the rows between the file rows start and start + count. The pages are loaded and do not
+ * span row groups. Rows the reader does not return are left out, as with SeekToRow, so fewer
+ * rows are returned when the range contains pruned or deleted rows or ends after the file.
+ */
+func (mr *MothRecordReader) ReadRows(start int64, count int32) *util.ArrayList[*spi.Page] {
+	util.CheckArgument2(count >= 0, "count is negative")
+	mr.SeekToRow(start)
+	pages := util.NewArrayList[*spi.Page]()
+	end := start + int64(count)
+	for {
+		remaining := end - (mr.filePosition + int64(mr.currentBatchSize))
+		if remaining <= 0 {
+			return pages
+		}
+		mr.nextBatchSize = util.Int32Exact(maths.MinInt64s(remaining, int64(MAX_BATCH_SIZE)))
+		page := mr.nextPage()
+		if page == nil || mr.filePosition >= end {
+			return pages
+		}
+		page = page.GetLoadedPage()
+		if rows := end - mr.filePosition; rows < int64(page.GetPositionCount()) {
+			page = page.GetRegion(0, util.Int32Exact(rows))
+		}
+		if page = mr.filterDeletedRows(page); page.GetPositionCount() > 0 {
+			pages.Add(page)
+		}
+	}
+}
+
+/**
+ * Returns the read columns that exist in the file, a nil read column is missing from the file and
+ * is read as nulls.
+ */
+func fileColumns(readColumns *util.ArrayList[*MothColumn]) []*MothColumn {
+	columns := make([]*MothColumn, 0, readColumns.Size())
+	for _, column := range readColumns.ToArray() {
+		if column != nil {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
 func createColumnReaders(columns *util.ArrayList[*MothColumn], readTypes *util.ArrayList[block.Type], readLayouts util.List[ProjectedLayout], memoryContext memory.AggregatedMemoryContext, blockFactory *MothBlockFactory, fieldMapperFactory FieldMapperFactory) []ColumnReader {
 	columnReaders := make([]ColumnReader, columns.Size())
 	for columnIndex := 0; columnIndex < columns.Size(); columnIndex++ {
 		readType := readTypes.Get(columnIndex)
 		column := columns.Get(columnIndex)
+		if column == nil {
+			columnReaders[columnIndex] = NewNullColumnReader(readType)
+			continue
+		}
 		projectedLayout := readLayouts.Get(columnIndex)
 		columnReaders[columnIndex] = CreateColumnReader(readType, column, projectedLayout, memoryContext, blockFactory, fieldMapperFactory)
 	}
@@ -0,0 +1,222 @@
+package store
+
+import (
+	goerrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/errors"
+	"github.com/mothdb-bd/orc-go/pkg/maths"
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/spi/predicate"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func createRecordReader(dataSource MothDataSource, options *MothReaderOptions, mothPredicate MothPredicate) *MothRecordReader {
+	reader := CreateMothReader(dataSource, options).Get()
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+	return reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns(), types, mothPredicate, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+}
+
+func pageIds(t *testing.T, pages ...*spi.Page) []int64 {
+	ids := make([]int64, 0)
+	for _, page := range pages {
+		idBlock := page.GetBlock(0).GetLoadedBlock()
+		nameBlock := page.GetBlock(1).GetLoadedBlock()
+		for position := util.INT32_ZERO; position < page.GetPositionCount(); position++ {
+			id := block.BIGINT.GetLong(idBlock, position)
+			if name, want := block.VARCHAR.GetSlice(nameBlock, position).String(), fmt.Sprintf("name-%06d", id); name != want {
+				t.Fatalf("got name %s for id %d, want %s", name, id, want)
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func idRange(start int64, end int64) []int64 {
+	ids := make([]int64, 0)
+	for id := start; id < end; id++ {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestMothRecordReader_ReadRows(t *testing.T) {
+	rowGroupData := writeTestFile(10500, NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100), metadata.ZLIB)
+	stripeData := writeTestFile(10500, NewMothWriterOptions().WithStripeMaxRowCount(1000), metadata.NONE)
+	path := filepath.Join(t.TempDir(), "test.moth")
+	if err := os.WriteFile(path, rowGroupData.AvailableBytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	memorySource := func(data *slice.Slice) func(options *MothReaderOptions) MothDataSource {
+		return func(options *MothReaderOptions) MothDataSource {
+			return NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data)
+		}
+	}
+	fileSource := func(options *MothReaderOptions) MothDataSource {
+		return NewFileMothDataSource(path, options)
+	}
+	between := NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(1), predicate.NewDomain(predicate.ValueSetOfRanges(block.BIGINT, predicate.NewRange(block.BIGINT, int64(2590), true, int64(2610), true)), false)).Build()
+
+	type read struct {
+		start int64
+		count int32
+		want  []int64
+	}
+	reads := []read{
+		{0, 3, idRange(0, 3)},
+		{150, 20, idRange(150, 170)},
+		{160, 5, idRange(160, 165)},
+		{165, 0, []int64{}},
+		{165, 1, idRange(165, 166)},
+		{990, 20, idRange(990, 1010)},
+		{5, 300, idRange(5, 305)},
+		{7777, 1, idRange(7777, 7778)},
+		{10490, 100, idRange(10490, 10500)},
+		{10500, 1, []int64{}},
+		{42, 2, idRange(42, 44)},
+	}
+	tests := []struct {
+		name       string
+		dataSource func(options *MothReaderOptions) MothDataSource
+		options    *MothReaderOptions
+		predicate  MothPredicate
+		reads      []read
+	}{
+		{"row groups", memorySource(rowGroupData), NewMothReaderOptions(), TRUE, reads},
+		{"stripes", memorySource(stripeData), NewMothReaderOptions(), TRUE, reads},
+		{"prefetch", memorySource(rowGroupData), NewMothReaderOptions().WithStripePrefetchCount(2), TRUE, reads},
+		{"file", fileSource, NewMothReaderOptions().WithTinyStripeThreshold(util.Ofds(1, util.B)).WithMaxMergeDistance(util.Ofds(1, util.B)), TRUE, reads},
+		{"pruned", memorySource(rowGroupData), NewMothReaderOptions(), between, []read{
+			{0, 3000, idRange(2500, 2700)},
+			{2650, 10, idRange(2650, 2660)},
+			{2000, 510, idRange(2500, 2510)},
+			{2700, 100, []int64{}},
+			{2599, 2, idRange(2599, 2601)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recordReader := createRecordReader(tt.dataSource(tt.options), tt.options, tt.predicate)
+			defer recordReader.Close()
+			for _, r := range tt.reads {
+				if got := pageIds(t, recordReader.ReadRows(r.start, r.count).ToArray()...); !reflect.DeepEqual(got, r.want) {
+					t.Fatalf("ReadRows(%d, %d) returned %d rows starting with %v, want %d rows starting with %v", r.start, r.count, len(got), got[:maths.MinInt(len(got), 3)], len(r.want), r.want[:maths.MinInt(len(r.want), 3)])
+				}
+			}
+		})
+	}
+}
+
+func TestMothRecordReader_SeekToRow(t *testing.T) {
+	data := writeTestFile(10500, NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100), metadata.ZLIB)
+	recordReader := createRecordReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), NewMothReaderOptions(), TRUE)
+	defer recordReader.Close()
+
+	// read part of the first page, then continue streaming from the seek position
+	if page := recordReader.NextPage(); page == nil {
+		t.Fatal("NextPage returned no page")
+	}
+	recordReader.SeekToRow(4321)
+	if got := recordReader.GetFilePosition(); got != 4321 {
+		t.Fatalf("file position %d after seek, want 4321", got)
+	}
+	ids := make([]int64, 0)
+	for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+		ids = append(ids, pageIds(t, page)...)
+	}
+	if want := idRange(4321, 10500); !reflect.DeepEqual(ids, want) {
+		t.Fatalf("read %d rows after seek, want %d", len(ids), len(want))
+	}
+
+	recordReader.SeekToRow(20000)
+	if page := recordReader.NextPage(); page != nil {
+		t.Fatalf("NextPage returned %d rows after seeking past the end", page.GetPositionCount())
+	}
+	recordReader.SeekToRow(10499)
+	if got := pageIds(t, recordReader.NextPage()); !reflect.DeepEqual(got, []int64{10499}) {
+		t.Fatalf("read %v after seeking to the last row", got)
+	}
+}
+
+func TestMothRecordReader_TryNextPage(t *testing.T) {
+	data := writeTestFile(3000, NewMothWriterOptions().WithStripeMaxRowCount(1000), metadata.ZLIB).AvailableBytes()
+	reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), slice.NewWithBuf(data)), NewMothReaderOptions()).Get()
+	stripe := reader.GetFooter().GetStripes().Get(1)
+	corrupt := append([]byte{}, data...)
+	for i := stripe.GetOffset() + stripe.GetIndexLength(); i < stripe.GetOffset()+stripe.GetIndexLength()+stripe.GetDataLength(); i++ {
+		corrupt[i] = 0xff
+	}
+
+	recordReader := createRecordReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), slice.NewWithBuf(corrupt)), NewMothReaderOptions(), TRUE)
+	defer recordReader.Close()
+	rows := 0
+	for {
+		page, err := recordReader.TryNextPage()
+		if err != nil {
+			var mothError *common.MothError
+			if !goerrors.As(err, &mothError) {
+				t.Fatalf("got %v, want a moth error", err)
+			}
+			if mothError.GetCode() != errors.CORRUPT_FILE || mothError.GetMothDataSourceId().String() != "test" || mothError.GetStripeOffset() != int64(stripe.GetOffset()) || mothError.GetColumnPath() != ".id" {
+				t.Fatalf("got %s, want a corruption of column .id in the stripe at %d", err.Error(), stripe.GetOffset())
+			}
+			break
+		}
+		if page == nil {
+			t.Fatal("read all rows of a corrupt file")
+		}
+		rows += int(page.GetPositionCount())
+	}
+	if rows != 1000 {
+		t.Fatalf("read %d rows before the corrupt stripe, want 1000", rows)
+	}
+}
+
+func TestMothRecordReader_MemoryLimit(t *testing.T) {
+	data := writeTestFile(30000, NewMothWriterOptions(), metadata.ZLIB)
+	tests := []struct {
+		name string
+		pool memory.AggregatedMemoryContext
+		// bounds of the largest page
+		minPageRows int32
+		maxPageRows int32
+	}{
+		{"unbounded", memory.NewSimpleAggregatedMemoryContext(), MAX_BATCH_SIZE, MAX_BATCH_SIZE},
+		{"bounded", memory.NewBoundedAggregatedMemoryContext(300 * 1024), 2, MAX_BATCH_SIZE / 4},
+		{"exhausted", memory.NewBoundedAggregatedMemoryContext(0), 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), NewMothReaderOptions()).Get()
+			types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+			recordReader := reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns(), types, TRUE, time.UTC, tt.pool, INITIAL_BATCH_SIZE)
+			ids := make([]int64, 0)
+			maxPageRows := util.INT32_ZERO
+			for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+				ids = append(ids, pageIds(t, page)...)
+				maxPageRows = maths.MaxInt32(maxPageRows, page.GetPositionCount())
+			}
+			recordReader.Close()
+			if !reflect.DeepEqual(ids, idRange(0, 30000)) {
+				t.Fatalf("read %d rows, want 30000", len(ids))
+			}
+			if maxPageRows < tt.minPageRows || maxPageRows > tt.maxPageRows {
+				t.Fatalf("read pages of up to %d rows, want between %d and %d", maxPageRows, tt.minPageRows, tt.maxPageRows)
+			}
+			if tt.pool.GetBytes() != 0 {
+				t.Fatalf("closed reader retains %d bytes", tt.pool.GetBytes())
+			}
+		})
+	}
+}
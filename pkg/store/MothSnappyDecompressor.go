@@ -1,8 +1,6 @@
 package store
 
 import (
-	"fmt"
-
 	"github.com/golang/snappy"
 	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/util"
@@ -33,7 +31,7 @@ func (mr *MothSnappyDecompressor) Decompress(input []byte, offset int32, length
 	uncompressedLength, _ := snappy.DecodedLen(totalBuf)
 
 	if int32(uncompressedLength) > mr.maxBufferSize {
-		panic(fmt.Sprintf("Snappy requires buffer (%d) larger than max size (%d)", uncompressedLength, mr.maxBufferSize))
+		panic(common.NewMothCorruptionError(mr.mothDataSourceId, "Snappy requires buffer (%d) larger than max size (%d)", uncompressedLength, mr.maxBufferSize))
 	}
 	buffer := output.Initialize(int32(uncompressedLength) + util.INT64_BYTES)
 
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+// files written and read by the tests of the package
+
+type memoryWriteCloser struct {
+	bytes.Buffer
+}
+
+func (mc *memoryWriteCloser) Close() error {
+	return nil
+}
+
+// writeTestFile writes rowCount rows of (id BIGINT, name VARCHAR) where id is the row number.
+func writeTestFile(rowCount int, options *MothWriterOptions, compression metadata.CompressionKind) *slice.Slice {
+	out := new(memoryWriteCloser)
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+	columnNames := util.NewArrayList("id", "name")
+	writer := NewMothWriter(NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), columnNames, types, metadata.CreateRootMothType(columnNames, types), compression, options, util.EmptyMap[string, string](), NewMothWriterStats())
+	pb := spi.NewPageBuilder(types)
+	for i := 0; i < rowCount; i++ {
+		pb.DeclarePosition()
+		block.WriteNativeValue(block.BIGINT, pb.GetBlockBuilder(0), int64(i))
+		block.WriteNativeValue(block.VARCHAR, pb.GetBlockBuilder(1), fmt.Sprintf("name-%06d", i))
+		if pb.GetPositionCount() == 1000 {
+			writer.Write(pb.Build())
+			pb = spi.NewPageBuilder(types)
+		}
+	}
+	if !pb.IsEmpty() {
+		writer.Write(pb.Build())
+	}
+	writer.Close()
+	return slice.NewWithBuf(out.Bytes())
+}
+
+func readIds(data *slice.Slice, options *MothReaderOptions, mothPredicate MothPredicate) []int64 {
+	reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), options).Get()
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+	recordReader := reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns(), types, mothPredicate, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+	defer recordReader.Close()
+	ids := make([]int64, 0)
+	for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+		b := page.GetBlock(0).GetLoadedBlock()
+		for position := util.INT32_ZERO; position < b.GetPositionCount(); position++ {
+			ids = append(ids, block.BIGINT.GetLong(b, position))
+		}
+	}
+	return ids
+}
@@ -0,0 +1,319 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+
+	"github.com/mothdb-bd/orc-go/pkg/optional"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * What a MothWriter wrote: the types, the row counts and statistics of the file and its stripes,
+ * and a checksum of the values of every column in every stripe. MothWriter.Validate compares it
+ * with the file read back.
+ */
+type MothWriteValidation struct {
+	columnNames      *util.ArrayList[string]
+	types            *util.ArrayList[block.Type]
+	mothTypes        *metadata.ColumnMetadata[*metadata.MothType]
+	rowCount         int64
+	stripeChecksums  *util.ArrayList[*WriteChecksum]
+	stripeStatistics *util.ArrayList[*metadata.StripeStatistics]
+	fileStatistics   *optional.Optional[*metadata.ColumnMetadata[*metadata.ColumnStatistics]]
+	// columns whose statistics can not be compared, the file holds the statistics of their masked copy
+	encryptedColumns util.SetInterface[metadata.MothColumnId]
+}
+
+func (mn *MothWriteValidation) GetColumnNames() *util.ArrayList[string] {
+	return mn.columnNames
+}
+
+func (mn *MothWriteValidation) GetTypes() *util.ArrayList[block.Type] {
+	return mn.types
+}
+
+func (mn *MothWriteValidation) GetRowCount() int64 {
+	return mn.rowCount
+}
+
+func (mn *MothWriteValidation) GetStripeChecksums() *util.ArrayList[*WriteChecksum] {
+	return mn.stripeChecksums
+}
+
+/**
+ * Compares the footer and metadata of the file with what was written, returns a description of
+ * every mismatch.
+ */
+func (mn *MothWriteValidation) validateMetadata(footer *metadata.Footer, fileMetadata *metadata.Metadata) []string {
+	mismatches := make([]string, 0)
+	if footer.GetTypes().Size() != mn.mothTypes.Size() {
+		return append(mismatches, fmt.Sprintf("file has %d columns, %d were written", footer.GetTypes().Size(), mn.mothTypes.Size()))
+	}
+	for i := util.INT32_ZERO; i < mn.mothTypes.Size(); i++ {
+		columnId := metadata.NewMothColumnId(uint32(i))
+		if actual, expected := describeMothType(footer.GetTypes().Get(columnId)), describeMothType(mn.mothTypes.Get(columnId)); actual != expected {
+			mismatches = append(mismatches, fmt.Sprintf("column %d has type %s, %s was written", i, actual, expected))
+		}
+	}
+	if footer.GetNumberOfRows() != uint64(mn.rowCount) {
+		mismatches = append(mismatches, fmt.Sprintf("file has %d rows, %d were written", footer.GetNumberOfRows(), mn.rowCount))
+	}
+	if footer.GetStripes().Size() != mn.stripeChecksums.Size() {
+		return append(mismatches, fmt.Sprintf("file has %d stripes, %d were written", footer.GetStripes().Size(), mn.stripeChecksums.Size()))
+	}
+	for i, stripe := range footer.GetStripes().ToArray() {
+		if int64(stripe.GetNumberOfRows()) != mn.stripeChecksums.Get(i).GetRowCount() {
+			mismatches = append(mismatches, fmt.Sprintf("stripe %d has %d rows, %d were written", i, stripe.GetNumberOfRows(), mn.stripeChecksums.Get(i).GetRowCount()))
+		}
+	}
+	if footer.GetFileStats().IsPresent() != mn.fileStatistics.IsPresent() {
+		mismatches = append(mismatches, "file statistics are missing")
+	} else if mn.fileStatistics.IsPresent() {
+		mismatches = mn.validateStatistics(mismatches, "file", footer.GetFileStats().Get(), mn.fileStatistics.Get())
+	}
+	stripeStatsList := fileMetadata.GetStripeStatsList()
+	if stripeStatsList.Size() != mn.stripeStatistics.Size() {
+		return append(mismatches, fmt.Sprintf("file has statistics of %d stripes, %d were written", stripeStatsList.Size(), mn.stripeStatistics.Size()))
+	}
+	for i, stripeStats := range stripeStatsList.ToArray() {
+		if stripeStats.IsEmpty() {
+			mismatches = append(mismatches, fmt.Sprintf("statistics of stripe %d are missing", i))
+			continue
+		}
+		mismatches = mn.validateStatistics(mismatches, fmt.Sprintf("stripe %d", i), stripeStats.Get().GetColumnStatistics(), mn.stripeStatistics.Get(i).GetColumnStatistics())
+	}
+	return mismatches
+}
+
+func (mn *MothWriteValidation) validateStatistics(mismatches []string, name string, actual *metadata.ColumnMetadata[*metadata.ColumnStatistics], expected *metadata.ColumnMetadata[*metadata.ColumnStatistics]) []string {
+	if actual.Size() != expected.Size() {
+		return append(mismatches, fmt.Sprintf("%s has statistics of %d columns, %d were written", name, actual.Size(), expected.Size()))
+	}
+	for i := util.INT32_ZERO; i < expected.Size(); i++ {
+		columnId := metadata.NewMothColumnId(uint32(i))
+		if mn.encryptedColumns.Has(columnId) {
+			continue
+		}
+		if hashStatistics(actual.Get(columnId)) != hashStatistics(expected.Get(columnId)) {
+			mismatches = append(mismatches, fmt.Sprintf("%s statistics of column %d do not match", name, i))
+		}
+	}
+	return mismatches
+}
+
+func hashStatistics(statistics *metadata.ColumnStatistics) uint64 {
+	hasher := fnv.New64a()
+	if statistics != nil {
+		// bloom filters are stored in the index streams, not with the statistics
+		statistics.WithBloomFilter(nil).AddHash(metadata.NewStatisticsHasher(hasher))
+	}
+	return hasher.Sum64()
+}
+
+func describeMothType(mothType *metadata.MothType) string {
+	return fmt.Sprintf("%s%v%v", mothType.GetMothTypeKind(), mothType.GetFieldNames().ToArray(), mothType.GetFieldTypeIndexes().ToArray())
+}
+
+/**
+ * Compares the checksums of the values read from each stripe with the written ones.
+ */
+func (mn *MothWriteValidation) validateChecksums(actual []*WriteChecksum) []string {
+	mismatches := make([]string, 0)
+	for i, expected := range mn.stripeChecksums.ToArray() {
+		if i >= len(actual) {
+			return append(mismatches, fmt.Sprintf("read %d stripes, %d were written", len(actual), mn.stripeChecksums.Size()))
+		}
+		if actual[i].GetRowCount() != expected.GetRowCount() {
+			mismatches = append(mismatches, fmt.Sprintf("read %d rows from stripe %d, %d were written", actual[i].GetRowCount(), i, expected.GetRowCount()))
+			continue
+		}
+		for channel := range expected.columnHashes {
+			if actual[i].columnHashes[channel] != expected.columnHashes[channel] {
+				mismatches = append(mismatches, fmt.Sprintf("values of column %s in stripe %d do not match", mn.columnNames.Get(channel), i))
+			}
+		}
+	}
+	return mismatches
+}
+
+type MothWriteValidationBuilder struct {
+	columnNames      *util.ArrayList[string]
+	types            *util.ArrayList[block.Type]
+	mothTypes        *metadata.ColumnMetadata[*metadata.MothType]
+	rowCount         int64
+	checksum         *WriteChecksumBuilder
+	stripeChecksums  *util.ArrayList[*WriteChecksum]
+	stripeStatistics *util.ArrayList[*metadata.StripeStatistics]
+	fileStatistics   *optional.Optional[*metadata.ColumnMetadata[*metadata.ColumnStatistics]]
+	encryptedColumns util.SetInterface[metadata.MothColumnId]
+}
+
+func NewMothWriteValidationBuilder(columnNames *util.ArrayList[string], types *util.ArrayList[block.Type], mothTypes *metadata.ColumnMetadata[*metadata.MothType]) *MothWriteValidationBuilder {
+	mr := new(MothWriteValidationBuilder)
+	mr.columnNames = columnNames
+	mr.types = types
+	mr.mothTypes = mothTypes
+	mr.checksum = NewWriteChecksumBuilder(types)
+	mr.stripeChecksums = util.NewArrayList[*WriteChecksum]()
+	mr.stripeStatistics = util.NewArrayList[*metadata.StripeStatistics]()
+	mr.fileStatistics = optional.Empty[*metadata.ColumnMetadata[*metadata.ColumnStatistics]]()
+	mr.encryptedColumns = util.NewSet[metadata.MothColumnId](util.SET_NonThreadSafe)
+	return mr
+}
+
+/**
+ * Excludes the columns of an encrypted subtree from the statistics validation.
+ */
+func (mr *MothWriteValidationBuilder) AddEncryptedColumn(root metadata.MothColumnId, columnCount int32) {
+	for i := util.INT32_ZERO; i < columnCount; i++ {
+		mr.encryptedColumns.Add(metadata.NewMothColumnId(root.GetId() + uint32(i)))
+	}
+}
+
+func (mr *MothWriteValidationBuilder) AddPage(page *spi.Page) {
+	mr.checksum.AddPage(page)
+	mr.rowCount += int64(page.GetPositionCount())
+}
+
+func (mr *MothWriteValidationBuilder) FinishStripe(statistics *metadata.StripeStatistics) {
+	mr.stripeChecksums.Add(mr.checksum.Build())
+	mr.stripeStatistics.Add(statistics)
+	mr.checksum = NewWriteChecksumBuilder(mr.types)
+}
+
+func (mr *MothWriteValidationBuilder) SetFileStatistics(fileStatistics *optional.Optional[*metadata.ColumnMetadata[*metadata.ColumnStatistics]]) {
+	mr.fileStatistics = fileStatistics
+}
+
+func (mr *MothWriteValidationBuilder) Build() *MothWriteValidation {
+	mn := new(MothWriteValidation)
+	mn.columnNames = mr.columnNames
+	mn.types = mr.types
+	mn.mothTypes = mr.mothTypes
+	mn.rowCount = mr.rowCount
+	mn.stripeChecksums = mr.stripeChecksums
+	mn.stripeStatistics = mr.stripeStatistics
+	mn.fileStatistics = mr.fileStatistics
+	mn.encryptedColumns = mr.encryptedColumns
+	return mn
+}
+
+/**
+ * Order dependent checksum of the values of each column of a sequence of rows.
+ */
+type WriteChecksum struct {
+	rowCount     int64
+	columnHashes []uint64
+}
+
+func (wm *WriteChecksum) GetRowCount() int64 {
+	return wm.rowCount
+}
+
+func (wm *WriteChecksum) GetColumnHash(channel int) uint64 {
+	return wm.columnHashes[channel]
+}
+
+type WriteChecksumBuilder struct {
+	types        *util.ArrayList[block.Type]
+	rowCount     int64
+	columnHashes []hash.Hash64
+	buffer       []byte
+}
+
+func NewWriteChecksumBuilder(types *util.ArrayList[block.Type]) *WriteChecksumBuilder {
+	wr := new(WriteChecksumBuilder)
+	wr.types = types
+	wr.columnHashes = make([]hash.Hash64, types.Size())
+	for channel := range wr.columnHashes {
+		wr.columnHashes[channel] = fnv.New64a()
+	}
+	wr.buffer = make([]byte, util.INT64_BYTES+1)
+	return wr
+}
+
+func (wr *WriteChecksumBuilder) AddPage(page *spi.Page) {
+	for channel, columnHash := range wr.columnHashes {
+		b := page.GetBlock(int32(channel)).GetLoadedBlock()
+		for position := util.INT32_ZERO; position < b.GetPositionCount(); position++ {
+			hashValue(columnHash, wr.buffer, wr.types.Get(channel), b, position)
+		}
+	}
+	wr.rowCount += int64(page.GetPositionCount())
+}
+
+func (wr *WriteChecksumBuilder) Build() *WriteChecksum {
+	wm := new(WriteChecksum)
+	wm.rowCount = wr.rowCount
+	wm.columnHashes = make([]uint64, len(wr.columnHashes))
+	for channel, columnHash := range wr.columnHashes {
+		wm.columnHashes[channel] = columnHash.Sum64()
+	}
+	return wm
+}
+
+/**
+ * Adds the value at the position to the hash, nulls and the boundaries of nested values are
+ * hashed too, so that moving a value between rows changes the checksum.
+ */
+func hashValue(hasher hash.Hash64, buffer []byte, kind block.Type, b block.Block, position int32) {
+	buffer[0] = 0
+	if b.IsNull(position) {
+		hasher.Write(buffer[:1])
+		return
+	}
+	buffer[0] = 1
+	switch t := kind.(type) {
+	case *block.BooleanType:
+		buffer[1] = 0
+		if t.GetBoolean(b, position) {
+			buffer[1] = 1
+		}
+		hasher.Write(buffer[:2])
+	case *block.VarcharType, *block.CharType, *block.VarbinaryType:
+		value := kind.GetSlice(b, position).AvailableBytes()
+		binary.LittleEndian.PutUint64(buffer[1:], uint64(len(value)))
+		hasher.Write(buffer)
+		hasher.Write(value)
+	case *block.ArrayType:
+		hashNestedValues(hasher, buffer, t.GetObject(b, position).(block.Block), func(int32) block.Type {
+			return t.GetElementType()
+		})
+	case *block.MapType:
+		hashNestedValues(hasher, buffer, t.GetObject(b, position).(block.Block), func(i int32) block.Type {
+			return util.Ternary(i%2 == 0, t.GetKeyType(), t.GetValueType())
+		})
+	case *block.RowType:
+		hashNestedValues(hasher, buffer, t.GetObject(b, position).(block.Block), func(i int32) block.Type {
+			return t.GetFields().GetByInt32(i).GetType()
+		})
+	default:
+		switch kind.GetGoKind() {
+		case reflect.Int64:
+			binary.LittleEndian.PutUint64(buffer[1:], uint64(kind.GetLong(b, position)))
+			hasher.Write(buffer)
+		case reflect.Float64:
+			binary.LittleEndian.PutUint64(buffer[1:], math.Float64bits(kind.GetDouble(b, position)))
+			hasher.Write(buffer)
+		default:
+			hasher.Write(buffer[:1])
+			fmt.Fprint(hasher, block.ReadNativeValue(kind, b, position))
+		}
+	}
+}
+
+func hashNestedValues(hasher hash.Hash64, buffer []byte, values block.Block, valueType func(i int32) block.Type) {
+	binary.LittleEndian.PutUint64(buffer[1:], uint64(values.GetPositionCount()))
+	hasher.Write(buffer)
+	for i := util.INT32_ZERO; i < values.GetPositionCount(); i++ {
+		hashValue(hasher, buffer, valueType(i), values, i)
+	}
+}
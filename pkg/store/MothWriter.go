@@ -1,15 +1,18 @@
 package store
 
 import (
+	"fmt"
 	"sort"
 	"time"
 
 	"github.com/mothdb-bd/orc-go/pkg/basic"
 	"github.com/mothdb-bd/orc-go/pkg/maths"
+	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/slice"
 	"github.com/mothdb-bd/orc-go/pkg/spi"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -18,6 +21,13 @@ var (
 	MOTHWRITER_INSTANCE_SIZE                int32  = util.SizeOf(&MothWriter{})
 	MOTHDB_MOTH_WRITER_VERSION_METADATA_KEY string = "moth.writer.version"
 	MOTHDB_MOTH_WRITER_VERSION              string
+	MOTH_KEY_COLUMN_METADATA_KEY            string = "moth.key.column"
+
+	/**
+	 * Files written by this writer version store the seconds of timestamps relative to
+	 * 1970-01-01 UTC instead of the ORC epoch 2015-01-01 UTC, see TimestampColumnReader.
+	 */
+	MOTHDB_LEGACY_TIMESTAMP_WRITER_VERSION string = "1.0.0"
 )
 
 type MothWriter struct {
@@ -46,10 +56,26 @@ type MothWriter struct {
 	fileRowCount                   int64
 	fileStats                      *optional.Optional[*metadata.ColumnMetadata[*metadata.ColumnStatistics]]
 	fileStatsRetainedBytes         int64
+	keyProvider                    encryption.KeyProvider
+	encryptionKeys                 *util.ArrayList[*metadata.EncryptionKey]
+	encryptedColumns               *util.ArrayList[*EncryptedColumnWriter]
+	encryptedChannels              map[int32]*EncryptedColumnWriter
+	// column writers of the file and of the encryption variants
+	allColumnWriters  *util.ArrayList[ColumnWriter]
+	writerParallelism int32
+	formatFlavor      metadata.FormatFlavor
+	// nil when write validation is disabled
+	validationBuilder *MothWriteValidationBuilder
+	validation        *MothWriteValidation
+	// nil when the options have no memory context
+	memoryUsage memory.LocalMemoryContext
+	// -1 when no key index is written
+	keyChannel int32
+	stripeKeys *util.ArrayList[*metadata.Key]
 }
 
 func init() {
-	version := "1.0.0"
+	version := "1.1.0"
 	MOTHDB_MOTH_WRITER_VERSION = version
 }
 func NewMothWriter(mothDataSink MothDataSink, columnNames *util.ArrayList[string], types *util.ArrayList[block.Type], mothTypes *metadata.ColumnMetadata[*metadata.MothType], compression metadata.CompressionKind, options *MothWriterOptions, userMetadata map[string]string, stats *MothWriterStats) *MothWriter {
@@ -66,43 +92,124 @@ func NewMothWriter(mothDataSink MothDataSink, columnNames *util.ArrayList[string
 	mr.stripeMaxRowCount = options.GetStripeMaxRowCount()
 	mr.rowGroupMaxRowCount = options.GetRowGroupMaxRowCount()
 	mr.maxCompressionBufferSize = util.Int32Exact(int64(options.GetMaxCompressionBufferSize().Bytes()))
+	mr.writerParallelism = options.GetWriterParallelism()
+	mr.formatFlavor = options.GetFormatFlavor()
+	if options.GetMemoryContext() != nil {
+		mr.memoryUsage = options.GetMemoryContext().NewLocalMemoryContext("MothWriter")
+	}
+	if options.IsWriteValidation() {
+		mr.validationBuilder = NewMothWriteValidationBuilder(columnNames, types, mothTypes)
+	}
 
 	mr.userMetadata = make(map[string]string)
 	util.PutAll(mr.userMetadata, userMetadata)
 	mr.userMetadata[MOTHDB_MOTH_WRITER_VERSION_METADATA_KEY] = MOTHDB_MOTH_WRITER_VERSION
-	mr.metadataWriter = NewCompressedMetadataWriter(metadata.NewMothMetadataWriter(options.GetWriterIdentification()), compression, mr.maxCompressionBufferSize)
+	mr.metadataWriter = NewCompressedMetadataWriter(metadata.NewMothMetadataWriter2(options.GetWriterIdentification(), options.GetFormatFlavor()), compression, options.GetZstdCompressionLevel(), mr.maxCompressionBufferSize)
 
 	mr.stats = stats
 	mr.mothTypes = mothTypes
 	rootType := mothTypes.Get(metadata.ROOT_COLUMN)
 
+	mr.keyProvider = options.GetKeyProvider()
+	mr.encryptionKeys = util.NewArrayList[*metadata.EncryptionKey]()
+	mr.encryptedColumns = util.NewArrayList[*EncryptedColumnWriter]()
+	mr.encryptedChannels = make(map[int32]*EncryptedColumnWriter)
+
 	columnWriters := util.NewArrayList[ColumnWriter]()
+	allColumnWriters := util.NewArrayList[ColumnWriter]()
 	sliceColumnWriters := util.NewSet[*SliceDictionaryColumnWriter](util.SET_NonThreadSafe)
 	for fieldId := util.INT32_ZERO; fieldId < types.SizeInt32(); fieldId++ {
 		fieldColumnIndex := rootType.GetFieldTypeIndex(fieldId)
 		fieldType := types.GetByInt32(fieldId)
-		columnWriter := CreateColumnWriter(fieldColumnIndex, mothTypes, fieldType, compression, mr.maxCompressionBufferSize, options.GetMaxStringStatisticsLimit(), getBloomFilterBuilder(options, columnNames.GetByInt32(fieldId)))
+		columnName := columnNames.GetByInt32(fieldId)
+		columnWriter := CreateColumnWriter(fieldColumnIndex, mothTypes, fieldType, compression, options.GetZstdCompressionLevel(), mr.maxCompressionBufferSize, options.GetMaxStringStatisticsLimit(), getBloomFilterBuilder(options, columnName))
 		columnWriters.Add(columnWriter)
-
-		sr, flag := columnWriter.(*SliceDictionaryColumnWriter)
-		if flag {
-			sliceColumnWriters.Add(sr)
-		} else {
-			for _, nestedColumnWriter := range columnWriter.GetNestedColumnWriters().ToArray() {
-				nr, flag := nestedColumnWriter.(*SliceDictionaryColumnWriter)
-				if flag {
-					sliceColumnWriters.Add(nr)
-				}
+		allColumnWriters.Add(columnWriter)
+		addSliceColumnWriters(sliceColumnWriters, columnWriter)
+
+		keyName := options.GetEncryptionKeyName(columnName)
+		if keyName != "" {
+			// the file column only holds the masked values, the real values go to the encryption variant
+			encryptedWriter := CreateColumnWriter(fieldColumnIndex, mothTypes, fieldType, compression, options.GetZstdCompressionLevel(), mr.maxCompressionBufferSize, options.GetMaxStringStatisticsLimit(), getBloomFilterBuilder(options, columnName))
+			allColumnWriters.Add(encryptedWriter)
+			addSliceColumnWriters(sliceColumnWriters, encryptedWriter)
+			key, keyIndex := mr.getEncryptionKey(columnName, keyName)
+			encryptedColumn := NewEncryptedColumnWriter(fieldId, fieldColumnIndex, metadata.GetSubtreeColumnCount(mothTypes, fieldColumnIndex), keyIndex, mr.keyProvider.CreateLocalKey(key), encryptedWriter, options.GetColumnMask(columnName), fieldType)
+			mr.encryptedColumns.Add(encryptedColumn)
+			mr.encryptedChannels[fieldId] = encryptedColumn
+			if mr.validationBuilder != nil {
+				mr.validationBuilder.AddEncryptedColumn(fieldColumnIndex, metadata.GetSubtreeColumnCount(mothTypes, fieldColumnIndex))
 			}
 		}
 	}
+	for _, columnName := range options.GetEncryptedColumnNames() {
+		if !columnNames.Contains(columnName) {
+			panic(fmt.Sprintf("Encrypted column %s does not exist", columnName))
+		}
+	}
+	for _, columnName := range options.GetMaskedColumnNames() {
+		if options.GetEncryptionKeyName(columnName) == "" {
+			panic(fmt.Sprintf("Masked column %s is not encrypted", columnName))
+		}
+	}
+	mr.keyChannel = -1
+	if keyColumn := options.GetKeyColumn(); keyColumn != "" {
+		for fieldId, columnName := range columnNames.ToArray() {
+			if columnName == keyColumn {
+				mr.keyChannel = int32(fieldId)
+			}
+		}
+		if mr.keyChannel < 0 {
+			panic(fmt.Sprintf("Key column %s does not exist", keyColumn))
+		}
+		if !isRowKeyType(types.GetByInt32(mr.keyChannel)) {
+			panic(fmt.Sprintf("Unsupported key type: %s", types.GetByInt32(mr.keyChannel).GetDisplayName()))
+		}
+		mr.stripeKeys = util.NewArrayList[*metadata.Key]()
+		mr.userMetadata[MOTH_KEY_COLUMN_METADATA_KEY] = keyColumn
+	}
 	mr.columnWriters = columnWriters
+	mr.allColumnWriters = allColumnWriters
 	mr.dictionaryCompressionOptimizer = NewDictionaryCompressionOptimizer(sliceColumnWriters, stripeMinBytes, mr.stripeMaxBytes, mr.stripeMaxRowCount, util.Int32ExactU(options.GetDictionaryMaxMemory().Bytes()))
 	mr.previouslyRecordedSizeInBytes = mr.GetRetainedBytes()
 	stats.UpdateSizeInBytes(mr.previouslyRecordedSizeInBytes)
 	return mr
 }
 
+func addSliceColumnWriters(sliceColumnWriters util.SetInterface[*SliceDictionaryColumnWriter], columnWriter ColumnWriter) {
+	sr, flag := columnWriter.(*SliceDictionaryColumnWriter)
+	if flag {
+		sliceColumnWriters.Add(sr)
+	} else {
+		for _, nestedColumnWriter := range columnWriter.GetNestedColumnWriters().ToArray() {
+			nr, flag := nestedColumnWriter.(*SliceDictionaryColumnWriter)
+			if flag {
+				sliceColumnWriters.Add(nr)
+			}
+		}
+	}
+}
+
+/**
+ * Returns the current version of the master key and its index in the file encryption keys
+ */
+func (mr *MothWriter) getEncryptionKey(columnName string, keyName string) (*metadata.EncryptionKey, int32) {
+	if mr.keyProvider == nil {
+		panic(fmt.Sprintf("Column %s is encrypted but no key provider is set", columnName))
+	}
+	for i := util.INT32_ZERO; i < mr.encryptionKeys.SizeInt32(); i++ {
+		if mr.encryptionKeys.GetByInt32(i).GetKeyName() == keyName {
+			return mr.encryptionKeys.GetByInt32(i), i
+		}
+	}
+	key := mr.keyProvider.GetCurrentKeyVersion(keyName)
+	if key == nil {
+		panic(fmt.Sprintf("Unknown encryption key %s for column %s", keyName, columnName))
+	}
+	mr.encryptionKeys.Add(key)
+	return key, mr.encryptionKeys.SizeInt32() - 1
+}
+
 func (mr *MothWriter) GetWrittenBytes() int64 {
 	return mr.mothDataSink.Size()
 }
@@ -119,6 +226,16 @@ func (mr *MothWriter) GetRetainedBytes() int64 {
 	return int64(MOTHWRITER_INSTANCE_SIZE) + mr.columnWritersRetainedBytes + mr.closedStripesRetainedBytes + mr.mothDataSink.GetRetainedSizeInBytes() + mr.fileStatsRetainedBytes
 }
 
+/**
+ * Like Write, but a failure to write the page is returned as a *common.MothError instead of
+ * panicking. The writer can not be used after a failure, other than to be closed.
+ */
+func (mr *MothWriter) TryWrite(page *spi.Page) (err error) {
+	defer recoverMothError(&err, nil)
+	mr.Write(page)
+	return nil
+}
+
 func (mr *MothWriter) Write(page *spi.Page) {
 	if page.GetPositionCount() == 0 {
 		return
@@ -145,13 +262,38 @@ func (mr *MothWriter) Write(page *spi.Page) {
 
 func (mr *MothWriter) writeChunk(chunk *spi.Page) {
 	if mr.rowGroupRowCount == 0 {
-		mr.columnWriters.ForEach(ColumnWriter.BeginRowGroup)
+		mr.allColumnWriters.ForEach(ColumnWriter.BeginRowGroup)
+	}
+	blocks := make([]block.Block, chunk.GetChannelCount())
+	for channel := range blocks {
+		blocks[channel] = chunk.GetBlock(int32(channel))
+		if mr.writerParallelism > 1 || int32(channel) == mr.keyChannel {
+			// lazy blocks notify their loader, load them before the columns are written concurrently
+			// and before the keys are indexed
+			blocks[channel] = blocks[channel].GetLoadedBlock()
+		}
+	}
+	if mr.keyChannel >= 0 {
+		mr.addKeys(blocks[mr.keyChannel])
+	}
+	runParallel(len(blocks), mr.writerParallelism, func(channel int) {
+		b := blocks[channel]
+		encryptedColumn, encrypted := mr.encryptedChannels[int32(channel)]
+		if encrypted {
+			encryptedColumn.GetColumnWriter().WriteBlock(b)
+			b = encryptedColumn.MaskBlock(b)
+		}
+		mr.columnWriters.Get(channel).WriteBlock(b)
+	})
+	if mr.validationBuilder != nil {
+		mr.validationBuilder.AddPage(chunk)
 	}
 	mr.bufferedBytes = 0
 	for channel := util.INT32_ZERO; channel < chunk.GetChannelCount(); channel++ {
-		writer := mr.columnWriters.GetByInt32(channel)
-		writer.WriteBlock(chunk.GetBlock(channel))
-		mr.bufferedBytes += int32(writer.GetBufferedBytes())
+		if encryptedColumn, encrypted := mr.encryptedChannels[channel]; encrypted {
+			mr.bufferedBytes += int32(encryptedColumn.GetColumnWriter().GetBufferedBytes())
+		}
+		mr.bufferedBytes += int32(mr.columnWriters.GetByInt32(channel).GetBufferedBytes())
 	}
 	mr.rowGroupRowCount += chunk.GetPositionCount()
 	util.CheckState(mr.rowGroupRowCount <= mr.rowGroupMaxRowCount)
@@ -160,25 +302,44 @@ func (mr *MothWriter) writeChunk(chunk *spi.Page) {
 		mr.finishRowGroup()
 	}
 	mr.dictionaryCompressionOptimizer.Optimize(mr.bufferedBytes, mr.stripeRowCount)
-	mr.bufferedBytes = util.Int32Exact(mr.columnWriters.Stream().MapToLong(ColumnWriter.GetBufferedBytes).Sum())
+	mr.bufferedBytes = util.Int32Exact(mr.allColumnWriters.Stream().MapToLong(ColumnWriter.GetBufferedBytes).Sum())
 	if mr.stripeRowCount == mr.stripeMaxRowCount {
 		mr.flushStripe(MAX_ROWS)
 	} else if mr.bufferedBytes > mr.stripeMaxBytes {
 		mr.flushStripe(MAX_BYTES)
 	} else if mr.dictionaryCompressionOptimizer.IsFull(int64(mr.bufferedBytes)) {
 		mr.flushStripe(DICTIONARY_FULL)
+	} else if mr.memoryUsage != nil && !mr.memoryUsage.TrySetBytes(int64(mr.bufferedBytes)) {
+		mr.flushStripe(MEMORY_LIMIT)
+	}
+	if mr.memoryUsage != nil {
+		// the data left after a flush is kept even when the memory context is exhausted
+		mr.memoryUsage.SetBytes(int64(mr.bufferedBytes))
 	}
 
-	for i := 0; i < mr.columnWriters.Size(); i++ {
-		cw := mr.columnWriters.Get(i)
+	for i := 0; i < mr.allColumnWriters.Size(); i++ {
+		cw := mr.allColumnWriters.Get(i)
 		mr.columnWritersRetainedBytes += cw.GetRetainedBytes()
 	}
 	// mr.columnWritersRetainedBytes = mr.columnWriters.Stream().MapToLong(ColumnWriter.GetRetainedBytes).Sum()
 }
 
+/**
+ * Adds the keys of the rows of a chunk to the key index of the stripe, null keys are not indexed.
+ */
+func (mr *MothWriter) addKeys(keys block.Block) {
+	keyType := mr.types.GetByInt32(mr.keyChannel)
+	ts := uint64(time.Now().UnixMilli())
+	for position := util.INT32_ZERO; position < keys.GetPositionCount(); position++ {
+		if key := readRowKey(keyType, keys, position); key != nil {
+			mr.stripeKeys.Add(metadata.NewKey(key, uint32(mr.stripeRowCount+position), ts))
+		}
+	}
+}
+
 func (mr *MothWriter) finishRowGroup() {
 	columnStatistics := util.EmptyMap[metadata.MothColumnId, *metadata.ColumnStatistics]()
-	mr.columnWriters.ForEach(func(columnWriter ColumnWriter) {
+	mr.allColumnWriters.ForEach(func(columnWriter ColumnWriter) {
 		util.PutAll(columnStatistics, columnWriter.FinishRowGroup())
 	})
 	mr.rowGroupRowCount = 0
@@ -188,32 +349,59 @@ func (mr *MothWriter) flushStripe(flushReason FlushReason) {
 	outputData := util.NewArrayList[MothDataOutput]()
 	stripeStartOffset := mr.mothDataSink.Size()
 	if mr.closedStripes.IsEmpty() {
-		outputData.Add(CreateDataOutput(metadata.MAGIC_SLICE))
-		stripeStartOffset += metadata.MAGIC_SLICE.LenInt64()
+		magic := mr.formatFlavor.GetMagicSlice()
+		outputData.Add(CreateDataOutput(magic))
+		stripeStartOffset += magic.LenInt64()
 	}
 	outputData.AddAll(mr.bufferStripeData(stripeStartOffset, flushReason))
+	if mr.keyChannel >= 0 && !mr.stripeKeys.IsEmpty() {
+		outputData.Add(CreateDataOutput(mr.bufferKeyIndex()))
+	}
 	if flushReason == CLOSED {
 		outputData.AddAll(mr.bufferFileFooter())
 	}
 	mr.mothDataSink.Write(outputData)
-	mr.columnWriters.ForEach(ColumnWriter.Reset)
+	mr.allColumnWriters.ForEach(ColumnWriter.Reset)
 	mr.dictionaryCompressionOptimizer.Reset()
 	mr.rowGroupRowCount = 0
 	mr.stripeRowCount = 0
-	mr.bufferedBytes = util.Int32Exact(mr.columnWriters.Stream().MapToLong(ColumnWriter.GetBufferedBytes).Sum())
+	mr.bufferedBytes = util.Int32Exact(mr.allColumnWriters.Stream().MapToLong(ColumnWriter.GetBufferedBytes).Sum())
+}
+
+/**
+ * Returns the key index of the stripe, the keys sorted by their bytes and the rows with the same
+ * key in the order they were written. The index follows the stripe, in the space before the next
+ * stripe that the stripe information does not cover. The index of an encrypted key column is
+ * encrypted with the key of its variant.
+ */
+func (mr *MothWriter) bufferKeyIndex() *slice.Slice {
+	keys := mr.stripeKeys.ToArray()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].GetKey().CompareTo(keys[j].GetKey()) < 0
+	})
+	mr.stripeKeys = util.NewArrayList[*metadata.Key]()
+	keyIndex := mr.metadataWriter.WriteKeys(util.NewArrayList(keys...))
+	// the keys of an encrypted key column are not written in plaintext
+	if encryptedColumn, encrypted := mr.encryptedChannels[mr.keyChannel]; encrypted {
+		keyIndex = encryptedColumn.EncryptKeyIndex(keyIndex, uint64(mr.closedStripes.Size()))
+	}
+	return keyIndex
 }
 
 func (mr *MothWriter) bufferStripeData(stripeStartOffset int64, flushReason FlushReason) *util.ArrayList[MothDataOutput] {
 	if mr.stripeRowCount == 0 {
 		util.Verify2(flushReason == CLOSED, "An empty stripe is not allowed")
-		mr.columnWriters.ForEach(ColumnWriter.Close)
+		mr.allColumnWriters.ForEach(ColumnWriter.Close)
 		return util.EMPTY_LIST[MothDataOutput]()
 	}
 	if mr.rowGroupRowCount > 0 {
 		mr.finishRowGroup()
 	}
 	mr.dictionaryCompressionOptimizer.FinalOptimize(mr.bufferedBytes)
-	mr.columnWriters.ForEach(ColumnWriter.Close)
+	// closing a column writer flushes and compresses its streams
+	runParallel(mr.allColumnWriters.Size(), mr.writerParallelism, func(i int) {
+		mr.allColumnWriters.Get(i).Close()
+	})
 	outputData := util.NewArrayList[MothDataOutput]()
 	allStreams := util.NewArrayList[*metadata.Stream]() // mr.columnWriters.Size() * 3
 	indexLength := util.INT64_ZERO
@@ -229,6 +417,21 @@ func (mr *MothWriter) bufferStripeData(stripeStartOffset int64, flushReason Flus
 			indexLength += bloomFilter.Size()
 		}
 	}
+	// the encrypted streams of each variant follow the unencrypted streams of their area, the
+	// unencrypted stripe footer only records their total length
+	stripeId := uint64(mr.closedStripes.Size() + 1)
+	variantStreams := make([]*util.ArrayList[*metadata.Stream], mr.encryptedColumns.Size())
+	for i, encryptedColumn := range mr.encryptedColumns.ToArray() {
+		variantStreams[i] = util.NewArrayList[*metadata.Stream]()
+		variantLength := util.INT64_ZERO
+		for _, indexStream := range encryptedColumn.GetIndexStreams(mr.metadataWriter, stripeId).ToArray() {
+			outputData.Add(indexStream)
+			variantStreams[i].Add(indexStream.GetStream())
+			variantLength += indexStream.Size()
+		}
+		allStreams.Add(metadata.NewStream(encryptedColumn.GetRoot(), metadata.ENCRYPTED_INDEX, util.Int32Exact(variantLength), false))
+		indexLength += variantLength
+	}
 	dataLength := util.INT64_ZERO
 	dataStreams := util.NewCmpList[*StreamDataOutput](NewStreamDataOutputCmp()) //. columnWriters.size() * 2
 	for _, columnWriter := range mr.columnWriters.ToArray() {
@@ -243,6 +446,19 @@ func (mr *MothWriter) bufferStripeData(stripeStartOffset int64, flushReason Flus
 		outputData.Add(dataStream)
 		allStreams.Add(dataStream.GetStream())
 	}
+	stripeEncryption := util.NewArrayList[*metadata.StripeEncryptionVariant]()
+	for i, encryptedColumn := range mr.encryptedColumns.ToArray() {
+		variantLength := util.INT64_ZERO
+		for _, dataStream := range encryptedColumn.GetDataStreams(stripeId).ToArray() {
+			outputData.Add(dataStream)
+			variantStreams[i].Add(dataStream.GetStream())
+			variantLength += dataStream.Size()
+		}
+		allStreams.Add(metadata.NewStream(encryptedColumn.GetRoot(), metadata.ENCRYPTED_DATA, util.Int32Exact(variantLength), false))
+		dataLength += variantLength
+		stripeEncryption.Add(metadata.NewStripeEncryptionVariant(variantStreams[i], encryptedColumn.GetColumnEncodings()))
+		encryptedColumn.FinishStripe()
+	}
 	columnEncodings := util.EmptyMap[metadata.MothColumnId, *metadata.ColumnEncoding]()
 	mr.columnWriters.ForEach(func(columnWriter ColumnWriter) {
 		util.PutAll(columnEncodings, columnWriter.GetColumnEncodings())
@@ -253,12 +469,19 @@ func (mr *MothWriter) bufferStripeData(stripeStartOffset int64, flushReason Flus
 	})
 	columnEncodings[metadata.ROOT_COLUMN] = metadata.NewColumnEncoding(metadata.DIRECT, 0)
 	columnStatistics[metadata.ROOT_COLUMN] = metadata.NewColumnStatistics(int64(mr.stripeRowCount), 0, nil, nil, nil, nil, nil, nil, nil, nil, nil)
-	stripeFooter := metadata.NewStripeFooter(allStreams, toColumnMetadata(columnEncodings, mr.mothTypes.Size()), time.UTC)
+	stripeFooter := metadata.NewStripeFooter2(allStreams, toColumnMetadata(columnEncodings, mr.mothTypes.Size()), time.UTC, stripeEncryption)
 	footer := mr.metadataWriter.WriteStripeFooter(stripeFooter)
 	outputData.Add(CreateDataOutput(footer))
 	statistics := metadata.NewStripeStatistics(toColumnMetadata(columnStatistics, mr.mothTypes.Size()))
-	stripeInformation := metadata.NewStripeInformation(mr.stripeRowCount, uint64(stripeStartOffset), uint64(indexLength), uint64(dataLength), uint64(footer.Size()))
+	encryptStripeId := uint64(0)
+	if !mr.encryptedColumns.IsEmpty() {
+		encryptStripeId = stripeId
+	}
+	stripeInformation := metadata.NewStripeInformation2(mr.stripeRowCount, uint64(stripeStartOffset), uint64(indexLength), uint64(dataLength), uint64(footer.Size()), encryptStripeId)
 	closedStripe := NewClosedStripe(stripeInformation, statistics)
+	if mr.validationBuilder != nil {
+		mr.validationBuilder.FinishStripe(statistics)
+	}
 	mr.closedStripes.Add(closedStripe)
 	mr.closedStripesRetainedBytes += closedStripe.GetRetainedSizeInBytes()
 	mr.stats.RecordStripeWritten(flushReason, int64(stripeInformation.GetTotalLength()), stripeInformation.GetNumberOfRows(), mr.dictionaryCompressionOptimizer.GetDictionaryMemoryBytes())
@@ -273,9 +496,51 @@ func (mr *MothWriter) Close() {
 	mr.closed = true
 	mr.stats.UpdateSizeInBytes(-mr.previouslyRecordedSizeInBytes)
 	mr.previouslyRecordedSizeInBytes = 0
+	// the sink is closed even when the last stripe can not be written, an abortable sink is
+	// aborted instead
+	defer func() {
+		if failure := recover(); failure != nil {
+			if sink, ok := mr.mothDataSink.(AbortableMothDataSink); ok {
+				sink.Abort()
+			} else {
+				mr.mothDataSink.Close()
+			}
+			panic(failure)
+		}
+		mr.mothDataSink.Close()
+	}()
+	if mr.memoryUsage != nil {
+		defer mr.memoryUsage.Close()
+	}
 	mr.flushStripe(CLOSED)
-	mr.mothDataSink.Close()
 	mr.bufferedBytes = 0
+	if mr.validationBuilder != nil {
+		mr.validation = mr.validationBuilder.Build()
+		mr.validationBuilder = nil
+	}
+}
+
+/**
+ * Reads the closed file back and compares its types, row counts, statistics and the checksums
+ * of its values with what was written. Mismatches are returned as a CORRUPT_FILE
+ * *common.MothError. The writer must have been created with write validation enabled.
+ */
+func (mr *MothWriter) Validate(input MothDataSource) (err error) {
+	util.CheckState2(mr.validation != nil, "Write validation is not enabled or the writer is not closed")
+	defer recoverMothError(&err, input.GetId())
+	options := NewMothReaderOptions().WithKeyProvider(mr.keyProvider).WithFormatFlavor(mr.formatFlavor)
+	validateFile(mr.validation, input, options)
+	return nil
+}
+
+/**
+ * Like Close, but a failure to write the file is returned as a *common.MothError instead of
+ * panicking.
+ */
+func (mr *MothWriter) TryClose() (err error) {
+	defer recoverMothError(&err, nil)
+	mr.Close()
+	return nil
 }
 
 func (mr *MothWriter) UpdateUserMetadata(updatedProperties map[string]string) {
@@ -288,6 +553,9 @@ func (mr *MothWriter) bufferFileFooter() *util.ArrayList[MothDataOutput] {
 	metadataSlice := mr.metadataWriter.WriteMetadata(ma)
 	outputData.Add(CreateDataOutput(metadataSlice))
 	mr.fileStats = toFileStats(util.MapStream(util.MapStream(mr.closedStripes.Stream(), (*ClosedStripe).GetStatistics), (*metadata.StripeStatistics).GetColumnStatistics).ToList())
+	if mr.validationBuilder != nil {
+		mr.validationBuilder.SetFileStatistics(mr.fileStats)
+	}
 	mr.fileStatsRetainedBytes = optional.Map(mr.fileStats, func(stats *metadata.ColumnMetadata[*metadata.ColumnStatistics]) int64 {
 		return stats.Stream().MapToLong((*metadata.ColumnStatistics).GetRetainedSizeInBytes).Sum()
 	}).OrElse(0)
@@ -297,7 +565,7 @@ func (mr *MothWriter) bufferFileFooter() *util.ArrayList[MothDataOutput] {
 		userMetadata[k], _ = slice.NewByString(v)
 	}
 
-	footer := metadata.NewFooter(uint64(mr.fileRowCount), util.Ternary(mr.rowGroupMaxRowCount == 0, optional.OptionalIntEmpty(), optional.OptionalIntof(mr.rowGroupMaxRowCount)), util.MapStream(mr.closedStripes.Stream(), (*ClosedStripe).GetStripeInformation).ToList(), mr.mothTypes, mr.fileStats, userMetadata, optional.Empty[uint32]())
+	footer := metadata.NewFooter2(uint64(mr.fileRowCount), util.Ternary(mr.rowGroupMaxRowCount == 0, optional.OptionalIntEmpty(), optional.OptionalIntof(mr.rowGroupMaxRowCount)), util.MapStream(mr.closedStripes.Stream(), (*ClosedStripe).GetStripeInformation).ToList(), mr.mothTypes, mr.fileStats, userMetadata, optional.Empty[uint32](), mr.getFileEncryption())
 	mr.closedStripes.Clear()
 	mr.closedStripesRetainedBytes = 0
 	footerSlice := mr.metadataWriter.WriteFooter(footer)
@@ -311,6 +579,19 @@ func (mr *MothWriter) bufferFileFooter() *util.ArrayList[MothDataOutput] {
 	return outputData
 }
 
+func (mr *MothWriter) getFileEncryption() *optional.Optional[*metadata.Encryption] {
+	if mr.encryptedColumns.IsEmpty() {
+		return optional.Empty[*metadata.Encryption]()
+	}
+	masks := util.NewArrayList[*metadata.DataMask]()
+	variants := util.NewArrayList[*metadata.EncryptionVariant]()
+	for _, encryptedColumn := range mr.encryptedColumns.ToArray() {
+		masks.Add(encryptedColumn.GetDataMask())
+		variants.Add(encryptedColumn.GetEncryptionVariant(mr.metadataWriter))
+	}
+	return optional.Of(metadata.NewEncryption(masks, mr.encryptionKeys, variants, mr.keyProvider.GetKind()))
+}
+
 func (mr *MothWriter) GetFileRowCount() int64 {
 	return mr.fileRowCount
 }
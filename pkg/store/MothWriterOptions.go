@@ -1,19 +1,25 @@
 package store
 
 import (
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
 var ( //@VisibleForTesting
-	DEFAULT_MAX_STRING_STATISTICS_LIMIT util.DataSize = util.Ofds(64, util.B) //@VisibleForTesting
-	DEFAULT_MAX_COMPRESSION_BUFFER_SIZE util.DataSize = util.Ofds(256, util.KB)
-	DEFAULT_BLOOM_FILTER_FPP            float64       = 0.05
-	DEFAULT_STRIPE_MIN_SIZE             util.DataSize = util.Ofds(32, util.MB)
-	DEFAULT_STRIPE_MAX_SIZE             util.DataSize = util.Ofds(64, util.MB)
-	DEFAULT_STRIPE_MAX_ROW_COUNT        int32         = 10_000_000
-	DEFAULT_ROW_GROUP_MAX_ROW_COUNT     int32         = 10_000
-	DEFAULT_DICTIONARY_MAX_MEMORY       util.DataSize = util.Ofds(16, util.MB)
+	DEFAULT_MAX_STRING_STATISTICS_LIMIT util.DataSize         = util.Ofds(64, util.B) //@VisibleForTesting
+	DEFAULT_MAX_COMPRESSION_BUFFER_SIZE util.DataSize         = util.Ofds(256, util.KB)
+	DEFAULT_BLOOM_FILTER_FPP            float64               = 0.05
+	DEFAULT_STRIPE_MIN_SIZE             util.DataSize         = util.Ofds(32, util.MB)
+	DEFAULT_STRIPE_MAX_SIZE             util.DataSize         = util.Ofds(64, util.MB)
+	DEFAULT_STRIPE_MAX_ROW_COUNT        int32                 = 10_000_000
+	DEFAULT_ROW_GROUP_MAX_ROW_COUNT     int32                 = 10_000
+	DEFAULT_DICTIONARY_MAX_MEMORY       util.DataSize         = util.Ofds(16, util.MB)
+	DEFAULT_ZSTD_COMPRESSION_LEVEL      int32                 = 3
+	DEFAULT_WRITER_PARALLELISM          int32                 = 1
+	DEFAULT_FORMAT_FLAVOR               metadata.FormatFlavor = metadata.MOTH_FLAVOR
+	DEFAULT_WRITE_VALIDATION            bool                  = false
 )
 
 type MothWriterOptions struct {
@@ -27,12 +33,21 @@ type MothWriterOptions struct {
 	maxCompressionBufferSize util.DataSize
 	bloomFilterColumns       util.SetInterface[string]
 	bloomFilterFpp           float64
+	zstdCompressionLevel     int32
+	columnEncryption         map[string]string
+	columnMasks              map[string]*metadata.DataMask
+	keyProvider              encryption.KeyProvider
+	writerParallelism        int32
+	formatFlavor             metadata.FormatFlavor
+	writeValidation          bool
+	memoryContext            memory.AggregatedMemoryContext
+	keyColumn                string
 }
 
 func NewMothWriterOptions() *MothWriterOptions {
-	return NewMothWriterOptions2(metadata.MOTH, DEFAULT_STRIPE_MIN_SIZE, DEFAULT_STRIPE_MAX_SIZE, DEFAULT_STRIPE_MAX_ROW_COUNT, DEFAULT_ROW_GROUP_MAX_ROW_COUNT, DEFAULT_DICTIONARY_MAX_MEMORY, DEFAULT_MAX_STRING_STATISTICS_LIMIT, DEFAULT_MAX_COMPRESSION_BUFFER_SIZE, util.EmptySet[string](), DEFAULT_BLOOM_FILTER_FPP)
+	return NewMothWriterOptions2(metadata.MOTH, DEFAULT_STRIPE_MIN_SIZE, DEFAULT_STRIPE_MAX_SIZE, DEFAULT_STRIPE_MAX_ROW_COUNT, DEFAULT_ROW_GROUP_MAX_ROW_COUNT, DEFAULT_DICTIONARY_MAX_MEMORY, DEFAULT_MAX_STRING_STATISTICS_LIMIT, DEFAULT_MAX_COMPRESSION_BUFFER_SIZE, util.EmptySet[string](), DEFAULT_BLOOM_FILTER_FPP, DEFAULT_ZSTD_COMPRESSION_LEVEL, util.EmptyMap[string, string](), util.EmptyMap[string, *metadata.DataMask](), nil, DEFAULT_WRITER_PARALLELISM, DEFAULT_FORMAT_FLAVOR, DEFAULT_WRITE_VALIDATION, nil, "")
 }
-func NewMothWriterOptions2(writerIdentification metadata.WriterIdentification, stripeMinSize util.DataSize, stripeMaxSize util.DataSize, stripeMaxRowCount int32, rowGroupMaxRowCount int32, dictionaryMaxMemory util.DataSize, maxStringStatisticsLimit util.DataSize, maxCompressionBufferSize util.DataSize, bloomFilterColumns util.SetInterface[string], bloomFilterFpp float64) *MothWriterOptions {
+func NewMothWriterOptions2(writerIdentification metadata.WriterIdentification, stripeMinSize util.DataSize, stripeMaxSize util.DataSize, stripeMaxRowCount int32, rowGroupMaxRowCount int32, dictionaryMaxMemory util.DataSize, maxStringStatisticsLimit util.DataSize, maxCompressionBufferSize util.DataSize, bloomFilterColumns util.SetInterface[string], bloomFilterFpp float64, zstdCompressionLevel int32, columnEncryption map[string]string, columnMasks map[string]*metadata.DataMask, keyProvider encryption.KeyProvider, writerParallelism int32, formatFlavor metadata.FormatFlavor, writeValidation bool, memoryContext memory.AggregatedMemoryContext, keyColumn string) *MothWriterOptions {
 	ms := new(MothWriterOptions)
 	ms.writerIdentification = writerIdentification
 	ms.stripeMinSize = stripeMinSize
@@ -44,6 +59,15 @@ func NewMothWriterOptions2(writerIdentification metadata.WriterIdentification, s
 	ms.maxCompressionBufferSize = maxCompressionBufferSize
 	ms.bloomFilterColumns = bloomFilterColumns
 	ms.bloomFilterFpp = bloomFilterFpp
+	ms.zstdCompressionLevel = zstdCompressionLevel
+	ms.columnEncryption = columnEncryption
+	ms.columnMasks = columnMasks
+	ms.keyProvider = keyProvider
+	ms.writerParallelism = writerParallelism
+	ms.formatFlavor = formatFlavor
+	ms.writeValidation = writeValidation
+	ms.memoryContext = memoryContext
+	ms.keyColumn = keyColumn
 	return ms
 }
 
@@ -127,9 +151,141 @@ func (ms *MothWriterOptions) WithBloomFilterFpp(bloomFilterFpp float64) *MothWri
 	return BuilderFrom(ms).SetBloomFilterFpp(bloomFilterFpp).Build()
 }
 
+/**
+ * Level used when the file is written with ZSTD compression, from 1 (fastest) to 22 (smallest).
+ * The level is mapped onto the four levels of the encoder, see NewZstdCompressor.
+ */
+func (ms *MothWriterOptions) GetZstdCompressionLevel() int32 {
+	return ms.zstdCompressionLevel
+}
+
+func (ms *MothWriterOptions) WithZstdCompressionLevel(zstdCompressionLevel int32) *MothWriterOptions {
+	return BuilderFrom(ms).SetZstdCompressionLevel(zstdCompressionLevel).Build()
+}
+
+/**
+ * Name of the master key the column is encrypted with, or an empty string when the column is
+ * not encrypted.
+ */
+func (ms *MothWriterOptions) GetEncryptionKeyName(columnName string) string {
+	return ms.columnEncryption[columnName]
+}
+
+func (ms *MothWriterOptions) GetEncryptedColumnNames() []string {
+	columnNames := make([]string, 0, len(ms.columnEncryption))
+	for columnName := range ms.columnEncryption {
+		columnNames = append(columnNames, columnName)
+	}
+	return columnNames
+}
+
+/**
+ * Encrypts top level columns, the map goes from column name to the name of the master key in
+ * the key provider.
+ */
+func (ms *MothWriterOptions) WithColumnEncryption(columnEncryption map[string]string) *MothWriterOptions {
+	return BuilderFrom(ms).SetColumnEncryption(columnEncryption).Build()
+}
+
+/**
+ * Mask of the unencrypted copy of an encrypted column, columns without a configured mask are
+ * nullified. The columns of the returned mask are not set.
+ */
+func (ms *MothWriterOptions) GetColumnMask(columnName string) *metadata.DataMask {
+	dataMask, ok := ms.columnMasks[columnName]
+	if !ok {
+		return metadata.NewDataMask(NULLIFY_MASK, util.NewArrayList[string](), util.NewArrayList[metadata.MothColumnId]())
+	}
+	return dataMask
+}
+
+func (ms *MothWriterOptions) GetMaskedColumnNames() []string {
+	columnNames := make([]string, 0, len(ms.columnMasks))
+	for columnName := range ms.columnMasks {
+		columnNames = append(columnNames, columnName)
+	}
+	return columnNames
+}
+
+/**
+ * Masks the unencrypted copy of encrypted columns, the map goes from column name to the mask,
+ * see CreateColumnMask for the supported masks.
+ */
+func (ms *MothWriterOptions) WithColumnMasks(columnMasks map[string]*metadata.DataMask) *MothWriterOptions {
+	return BuilderFrom(ms).SetColumnMasks(columnMasks).Build()
+}
+
+func (ms *MothWriterOptions) GetKeyProvider() encryption.KeyProvider {
+	return ms.keyProvider
+}
+
+func (ms *MothWriterOptions) WithKeyProvider(keyProvider encryption.KeyProvider) *MothWriterOptions {
+	return BuilderFrom(ms).SetKeyProvider(keyProvider).Build()
+}
+
+/**
+ * Number of goroutines encoding and compressing the columns of a chunk, one writes all columns
+ * on the caller's goroutine. The written file does not depend on the parallelism.
+ */
+func (ms *MothWriterOptions) GetWriterParallelism() int32 {
+	return ms.writerParallelism
+}
+
+func (ms *MothWriterOptions) WithWriterParallelism(writerParallelism int32) *MothWriterOptions {
+	return BuilderFrom(ms).SetWriterParallelism(writerParallelism).Build()
+}
+
+/**
+ * Flavour of the written file, ORC_FLAVOR writes files readable by the Apache ORC readers.
+ */
+func (ms *MothWriterOptions) GetFormatFlavor() metadata.FormatFlavor {
+	return ms.formatFlavor
+}
+
+func (ms *MothWriterOptions) WithFormatFlavor(formatFlavor metadata.FormatFlavor) *MothWriterOptions {
+	return BuilderFrom(ms).SetFormatFlavor(formatFlavor).Build()
+}
+
+/**
+ * Whether the writer records checksums of the written values and the expected statistics, so
+ * that MothWriter.Validate can verify the finished file.
+ */
+func (ms *MothWriterOptions) IsWriteValidation() bool {
+	return ms.writeValidation
+}
+
+func (ms *MothWriterOptions) WithWriteValidation(writeValidation bool) *MothWriterOptions {
+	return BuilderFrom(ms).SetWriteValidation(writeValidation).Build()
+}
+
+/**
+ * Memory context the writer reserves its buffered stripe data in, nil when the buffered data is
+ * not reserved. A stripe is flushed early when the context rejects the reservation, e.g. when
+ * the writers sharing a memory.BoundedAggregatedMemoryContext reach its limit.
+ */
+func (ms *MothWriterOptions) GetMemoryContext() memory.AggregatedMemoryContext {
+	return ms.memoryContext
+}
+
+func (ms *MothWriterOptions) WithMemoryContext(memoryContext memory.AggregatedMemoryContext) *MothWriterOptions {
+	return BuilderFrom(ms).SetMemoryContext(memoryContext).Build()
+}
+
+/**
+ * Top level column whose values are the keys of the rows, empty when no key index is written.
+ * The writer writes a sorted index of the keys of each stripe, used by MothReader.Lookup.
+ */
+func (ms *MothWriterOptions) GetKeyColumn() string {
+	return ms.keyColumn
+}
+
+func (ms *MothWriterOptions) WithKeyColumn(keyColumn string) *MothWriterOptions {
+	return BuilderFrom(ms).SetKeyColumn(keyColumn).Build()
+}
+
 // @Override
 func (ms *MothWriterOptions) String() string {
-	return util.NewSB().AddUInt64("stripeMinSize", uint64(ms.stripeMinSize)).AddUInt64("stripeMaxSize", uint64(ms.stripeMaxSize)).AddInt32("stripeMaxRowCount", ms.stripeMaxRowCount).AddInt32("rowGroupMaxRowCount", ms.rowGroupMaxRowCount).AddUInt64("dictionaryMaxMemory", uint64(ms.dictionaryMaxMemory)).AddUInt64("maxStringStatisticsLimit", uint64(ms.maxStringStatisticsLimit)).AddUInt64("maxCompressionBufferSize", uint64(ms.maxCompressionBufferSize)).AddString("bloomFilterColumns", ms.bloomFilterColumns.String()).AddFloat64("bloomFilterFpp", ms.bloomFilterFpp).String()
+	return util.NewSB().AddUInt64("stripeMinSize", uint64(ms.stripeMinSize)).AddUInt64("stripeMaxSize", uint64(ms.stripeMaxSize)).AddInt32("stripeMaxRowCount", ms.stripeMaxRowCount).AddInt32("rowGroupMaxRowCount", ms.rowGroupMaxRowCount).AddUInt64("dictionaryMaxMemory", uint64(ms.dictionaryMaxMemory)).AddUInt64("maxStringStatisticsLimit", uint64(ms.maxStringStatisticsLimit)).AddUInt64("maxCompressionBufferSize", uint64(ms.maxCompressionBufferSize)).AddString("bloomFilterColumns", ms.bloomFilterColumns.String()).AddFloat64("bloomFilterFpp", ms.bloomFilterFpp).AddInt32("zstdCompressionLevel", ms.zstdCompressionLevel).AddInt32("writerParallelism", ms.writerParallelism).AddString("formatFlavor", ms.formatFlavor.String()).AddBool("writeValidation", ms.writeValidation).AddString("keyColumn", ms.keyColumn).String()
 }
 
 func Build() *Builder {
@@ -151,6 +307,15 @@ type Builder struct {
 	maxCompressionBufferSize util.DataSize
 	bloomFilterColumns       util.SetInterface[string]
 	bloomFilterFpp           float64
+	zstdCompressionLevel     int32
+	columnEncryption         map[string]string
+	columnMasks              map[string]*metadata.DataMask
+	keyProvider              encryption.KeyProvider
+	writerParallelism        int32
+	formatFlavor             metadata.FormatFlavor
+	writeValidation          bool
+	memoryContext            memory.AggregatedMemoryContext
+	keyColumn                string
 }
 
 func NewBuilder(options *MothWriterOptions) *Builder {
@@ -165,6 +330,15 @@ func NewBuilder(options *MothWriterOptions) *Builder {
 	br.maxCompressionBufferSize = options.maxCompressionBufferSize
 	br.bloomFilterColumns = options.bloomFilterColumns
 	br.bloomFilterFpp = options.bloomFilterFpp
+	br.zstdCompressionLevel = options.zstdCompressionLevel
+	br.columnEncryption = options.columnEncryption
+	br.columnMasks = options.columnMasks
+	br.keyProvider = options.keyProvider
+	br.writerParallelism = options.writerParallelism
+	br.formatFlavor = options.formatFlavor
+	br.writeValidation = options.writeValidation
+	br.memoryContext = options.memoryContext
+	br.keyColumn = options.keyColumn
 	return br
 }
 
@@ -218,6 +392,52 @@ func (br *Builder) SetBloomFilterFpp(bloomFilterFpp float64) *Builder {
 	return br
 }
 
+func (br *Builder) SetZstdCompressionLevel(zstdCompressionLevel int32) *Builder {
+	br.zstdCompressionLevel = zstdCompressionLevel
+	return br
+}
+
+func (br *Builder) SetColumnEncryption(columnEncryption map[string]string) *Builder {
+	br.columnEncryption = columnEncryption
+	return br
+}
+
+func (br *Builder) SetColumnMasks(columnMasks map[string]*metadata.DataMask) *Builder {
+	br.columnMasks = columnMasks
+	return br
+}
+
+func (br *Builder) SetKeyProvider(keyProvider encryption.KeyProvider) *Builder {
+	br.keyProvider = keyProvider
+	return br
+}
+
+func (br *Builder) SetWriterParallelism(writerParallelism int32) *Builder {
+	util.CheckArgument2(writerParallelism >= 1, "writerParallelism must be at least 1")
+	br.writerParallelism = writerParallelism
+	return br
+}
+
+func (br *Builder) SetFormatFlavor(formatFlavor metadata.FormatFlavor) *Builder {
+	br.formatFlavor = formatFlavor
+	return br
+}
+
+func (br *Builder) SetWriteValidation(writeValidation bool) *Builder {
+	br.writeValidation = writeValidation
+	return br
+}
+
+func (br *Builder) SetMemoryContext(memoryContext memory.AggregatedMemoryContext) *Builder {
+	br.memoryContext = memoryContext
+	return br
+}
+
+func (br *Builder) SetKeyColumn(keyColumn string) *Builder {
+	br.keyColumn = keyColumn
+	return br
+}
+
 func (br *Builder) Build() *MothWriterOptions {
-	return NewMothWriterOptions2(br.writerIdentification, br.stripeMinSize, br.stripeMaxSize, br.stripeMaxRowCount, br.rowGroupMaxRowCount, br.dictionaryMaxMemory, br.maxStringStatisticsLimit, br.maxCompressionBufferSize, br.bloomFilterColumns, br.bloomFilterFpp)
+	return NewMothWriterOptions2(br.writerIdentification, br.stripeMinSize, br.stripeMaxSize, br.stripeMaxRowCount, br.rowGroupMaxRowCount, br.dictionaryMaxMemory, br.maxStringStatisticsLimit, br.maxCompressionBufferSize, br.bloomFilterColumns, br.bloomFilterFpp, br.zstdCompressionLevel, br.columnEncryption, br.columnMasks, br.keyProvider, br.writerParallelism, br.formatFlavor, br.writeValidation, br.memoryContext, br.keyColumn)
 }
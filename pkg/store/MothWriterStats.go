@@ -13,6 +13,7 @@ type MothWriterStats struct {
 	maxBytesFlush       *MothWriterFlushStats
 	dictionaryFullFlush *MothWriterFlushStats
 	closedFlush         *MothWriterFlushStats
+	memoryLimitFlush    *MothWriterFlushStats
 
 	// atomic
 	writerSizeInBytes *int64
@@ -20,7 +21,7 @@ type MothWriterStats struct {
 
 // public enum FlushReason
 // {
-// 	MAX_ROWS, MAX_BYTES, DICTIONARY_FULL, CLOSED
+// 	MAX_ROWS, MAX_BYTES, DICTIONARY_FULL, CLOSED, MEMORY_LIMIT
 // }
 
 type FlushReason int8
@@ -30,6 +31,8 @@ const (
 	MAX_BYTES
 	DICTIONARY_FULL
 	CLOSED
+	// the memory context of the writer rejected the buffered stripe data
+	MEMORY_LIMIT
 )
 
 func NewMothWriterStats() *MothWriterStats {
@@ -39,6 +42,7 @@ func NewMothWriterStats() *MothWriterStats {
 	ms.maxBytesFlush = NewMothWriterFlushStats("MAX_BYTES")
 	ms.dictionaryFullFlush = NewMothWriterFlushStats("DICTIONARY_FULL")
 	ms.closedFlush = NewMothWriterFlushStats("CLOSED")
+	ms.memoryLimitFlush = NewMothWriterFlushStats("MEMORY_LIMIT")
 	ms.writerSizeInBytes = new(int64)
 	return ms
 }
@@ -82,6 +86,12 @@ func (ms *MothWriterStats) GetClosedFlush() *MothWriterFlushStats {
 	return ms.closedFlush
 }
 
+// @Managed
+// @Nested
+func (ms *MothWriterStats) GetMemoryLimitFlush() *MothWriterFlushStats {
+	return ms.memoryLimitFlush
+}
+
 // @Managed
 func (ms *MothWriterStats) GetWriterSizeInBytes() int64 {
 	return *ms.writerSizeInBytes
@@ -97,11 +107,13 @@ func (ms *MothWriterStats) getFlushStats(flushReason FlushReason) *MothWriterFlu
 		return ms.dictionaryFullFlush
 	case CLOSED:
 		return ms.closedFlush
+	case MEMORY_LIMIT:
+		return ms.memoryLimitFlush
 	}
 	panic(fmt.Sprintf("unknown flush reason %d", flushReason))
 }
 
 // @Override
 func (ms *MothWriterStats) String() string {
-	return util.NewSB().AddString("allFlush", ms.allFlush.String()).AddString("maxRowsFlush", ms.maxRowsFlush.String()).AddString("maxBytesFlush", ms.maxBytesFlush.String()).AddString("dictionaryFullFlush", ms.dictionaryFullFlush.String()).AddString("closedFlush", ms.closedFlush.String()).AddInt64("writerSizeInBytes", *ms.writerSizeInBytes).String()
+	return util.NewSB().AddString("allFlush", ms.allFlush.String()).AddString("maxRowsFlush", ms.maxRowsFlush.String()).AddString("maxBytesFlush", ms.maxBytesFlush.String()).AddString("dictionaryFullFlush", ms.dictionaryFullFlush.String()).AddString("closedFlush", ms.closedFlush.String()).AddString("memoryLimitFlush", ms.memoryLimitFlush.String()).AddInt64("writerSizeInBytes", *ms.writerSizeInBytes).String()
 }
@@ -0,0 +1,292 @@
+package store
+
+import (
+	"bytes"
+	goerrors "errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/errors"
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+// writeWideFile writes rowCount rows of columnCount alternating BIGINT and VARCHAR columns.
+func writeWideFile(columnCount int, rowCount int, options *MothWriterOptions, compression metadata.CompressionKind) []byte {
+	out := new(memoryWriteCloser)
+	types := util.NewArrayList[block.Type]()
+	columnNames := util.NewArrayList[string]()
+	for column := 0; column < columnCount; column++ {
+		columnNames.Add(fmt.Sprintf("c%d", column))
+		if column%2 == 0 {
+			types.Add(block.BIGINT)
+		} else {
+			types.Add(block.VARCHAR)
+		}
+	}
+	writer := NewMothWriter(NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), columnNames, types, metadata.CreateRootMothType(columnNames, types), compression, options, util.EmptyMap[string, string](), NewMothWriterStats())
+	pb := spi.NewPageBuilder(types)
+	for row := 0; row < rowCount; row++ {
+		pb.DeclarePosition()
+		for column := 0; column < columnCount; column++ {
+			if column%2 == 0 {
+				block.WriteNativeValue(block.BIGINT, pb.GetBlockBuilder(int32(column)), int64(row*column))
+			} else {
+				block.WriteNativeValue(block.VARCHAR, pb.GetBlockBuilder(int32(column)), fmt.Sprintf("value-%d-%d", column, row%(column*7)))
+			}
+		}
+		if pb.GetPositionCount() == 1000 {
+			writer.Write(pb.Build())
+			pb = spi.NewPageBuilder(types)
+		}
+	}
+	if !pb.IsEmpty() {
+		writer.Write(pb.Build())
+	}
+	writer.Close()
+	return out.Bytes()
+}
+
+func TestMothWriter_WriterParallelism(t *testing.T) {
+	options := NewMothWriterOptions().WithStripeMaxRowCount(2000).WithRowGroupMaxRowCount(500)
+	tests := []struct {
+		name        string
+		options     *MothWriterOptions
+		compression metadata.CompressionKind
+	}{
+		{"uncompressed", options, metadata.NONE},
+		{"zlib", options, metadata.ZLIB},
+		{"snappy", options, metadata.SNAPPY},
+		{"zstd", options, metadata.ZSTD},
+		{"bloom filters", options.WithBloomFilterColumns(util.NewSetWithItems(util.SET_NonThreadSafe, "c1", "c2")), metadata.ZLIB},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := writeWideFile(24, 4500, tt.options, tt.compression)
+			for _, parallelism := range []int32{4, 64} {
+				got := writeWideFile(24, 4500, tt.options.WithWriterParallelism(parallelism), tt.compression)
+				if !bytes.Equal(got, want) {
+					t.Fatalf("parallelism %d wrote %d bytes that differ from the %d sequentially written bytes", parallelism, len(got), len(want))
+				}
+			}
+		})
+	}
+}
+
+var errDiskFull = goerrors.New("disk full")
+
+// failingWriteCloser accepts limit bytes and fails all writes after that.
+type failingWriteCloser struct {
+	limit int
+}
+
+func (fc *failingWriteCloser) Write(p []byte) (int, error) {
+	if len(p) > fc.limit {
+		return 0, errDiskFull
+	}
+	fc.limit -= len(p)
+	return len(p), nil
+}
+
+func (fc *failingWriteCloser) Close() error {
+	return nil
+}
+
+func TestMothWriter_TryClose(t *testing.T) {
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+	columnNames := util.NewArrayList("id", "name")
+	writer := NewMothWriter(NewOutputStreamMothDataSink(mothio.NewOutputStream(&failingWriteCloser{limit: 10})), columnNames, types, metadata.CreateRootMothType(columnNames, types), metadata.ZLIB, NewMothWriterOptions(), util.EmptyMap[string, string](), NewMothWriterStats())
+	pb := spi.NewPageBuilder(types)
+	for row := 0; row < 1000; row++ {
+		pb.DeclarePosition()
+		block.WriteNativeValue(block.BIGINT, pb.GetBlockBuilder(0), int64(row))
+		block.WriteNativeValue(block.VARCHAR, pb.GetBlockBuilder(1), fmt.Sprintf("name-%d", row))
+	}
+	if err := writer.TryWrite(pb.Build()); err != nil {
+		t.Fatalf("buffered write failed: %v", err)
+	}
+	err := writer.TryClose()
+	var mothError *common.MothError
+	if !goerrors.As(err, &mothError) || mothError.GetCode() != errors.IO_ERROR {
+		t.Fatalf("got %v, want an IO_ERROR", err)
+	}
+	if !goerrors.Is(err, errDiskFull) {
+		t.Fatalf("got %v, want it to wrap the write failure", err)
+	}
+}
+
+func writeTimestamps(micros []int64) []byte {
+	out := new(memoryWriteCloser)
+	types := util.NewArrayList[block.Type](block.TIMESTAMP_MICROS, block.VARCHAR)
+	columnNames := util.NewArrayList("ts", "name")
+	writer := NewMothWriter(NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), columnNames, types, metadata.CreateRootMothType(columnNames, types), metadata.NONE, NewMothWriterOptions(), util.EmptyMap[string, string](), NewMothWriterStats())
+	pb := spi.NewPageBuilder(types)
+	for _, value := range micros {
+		pb.DeclarePosition()
+		block.WriteNativeValue(block.TIMESTAMP_MICROS, pb.GetBlockBuilder(0), value)
+		block.WriteNativeValue(block.VARCHAR, pb.GetBlockBuilder(1), "name")
+	}
+	writer.Write(pb.Build())
+	writer.Close()
+	return out.Bytes()
+}
+
+func readTimestamps(data []byte) []int64 {
+	reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), slice.NewWithBuf(data)), NewMothReaderOptions()).Get()
+	recordReader := reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns().SubList(0, 1), util.NewArrayList[block.Type](block.TIMESTAMP_MICROS), TRUE, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+	defer recordReader.Close()
+	got := make([]int64, 0)
+	for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+		tsBlock := page.GetBlock(0).GetLoadedBlock()
+		for position := util.INT32_ZERO; position < page.GetPositionCount(); position++ {
+			got = append(got, block.TIMESTAMP_MICROS.GetLong(tsBlock, position))
+		}
+	}
+	return got
+}
+
+func TestMothWriter_Timestamp(t *testing.T) {
+	// the seconds are stored relative to 2015-01-01 UTC, the result must not depend on the local time zone
+	local := time.Local
+	defer func() { time.Local = local }()
+	time.Local = time.FixedZone("UTC+8", 8*60*60)
+
+	micros := []int64{0, 1_420_070_400_000_000, 1_600_000_000_123_456, -5_000_000}
+	if got := readTimestamps(writeTimestamps(micros)); fmt.Sprint(got) != fmt.Sprint(micros) {
+		t.Fatalf("read %v, want %v", got, micros)
+	}
+}
+
+func TestMothReader_LegacyTimestamps(t *testing.T) {
+	// the merger reads timestamps with millisecond precision
+	micros := []int64{0, 1_420_070_400_000_000, 1_600_000_000_123_000, -5_000_000}
+	current := writeTimestamps(micros)
+
+	// writer version 1.0.0 stored the seconds relative to 1970-01-01 UTC
+	writerVersion, epoch := MOTHDB_MOTH_WRITER_VERSION, TIMESTAMP_MOTH_EPOCH_IN_SECONDS
+	MOTHDB_MOTH_WRITER_VERSION, TIMESTAMP_MOTH_EPOCH_IN_SECONDS = MOTHDB_LEGACY_TIMESTAMP_WRITER_VERSION, 0
+	legacy := writeTimestamps(micros)
+	MOTHDB_MOTH_WRITER_VERSION, TIMESTAMP_MOTH_EPOCH_IN_SECONDS = writerVersion, epoch
+
+	if got := readTimestamps(legacy); fmt.Sprint(got) != fmt.Sprint(micros) {
+		t.Fatalf("read %v from a legacy file, want %v", got, micros)
+	}
+	// the stripes of legacy files are rewritten when they are merged with current files
+	out := new(memoryWriteCloser)
+	sources := util.NewArrayList[MothDataSource](NewMemoryMothDataSource(common.NewMothDataSourceId("legacy"), slice.NewWithBuf(legacy)), NewMemoryMothDataSource(common.NewMothDataSourceId("current"), slice.NewWithBuf(current)))
+	copied, err := NewMothFileMerger(NewMothReaderOptions(), NewMothWriterOptions()).TryMerge(sources, NewOutputStreamMothDataSink(mothio.NewOutputStream(out)))
+	if err != nil || copied {
+		t.Fatalf("merged a legacy and a current file, copied %v: %v", copied, err)
+	}
+	if got, want := readTimestamps(out.Bytes()), append(append([]int64{}, micros...), micros...); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("read %v from the merged file, want %v", got, want)
+	}
+}
+
+// writeValidatedFile writes rowCount rows of (id BIGINT, name VARCHAR, price DOUBLE, scores ARRAY(BIGINT)) with write validation enabled.
+func writeValidatedFile(rowCount int, options *MothWriterOptions) (*MothWriter, []byte) {
+	out := new(memoryWriteCloser)
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR, block.DOUBLE, block.NewArrayType(block.BIGINT))
+	columnNames := util.NewArrayList("id", "name", "price", "scores")
+	writer := NewMothWriter(NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), columnNames, types, metadata.CreateRootMothType(columnNames, types), metadata.NONE, options.WithWriteValidation(true), util.EmptyMap[string, string](), NewMothWriterStats())
+	pb := spi.NewPageBuilder(types)
+	for row := 0; row < rowCount; row++ {
+		pb.DeclarePosition()
+		block.WriteNativeValue(block.BIGINT, pb.GetBlockBuilder(0), int64(row))
+		if row%10 == 9 {
+			pb.GetBlockBuilder(1).AppendNull()
+		} else {
+			block.WriteNativeValue(block.VARCHAR, pb.GetBlockBuilder(1), fmt.Sprintf("name-%06d", row))
+		}
+		block.WriteNativeValue(block.DOUBLE, pb.GetBlockBuilder(2), float64(row)/4)
+		scores := block.BIGINT.CreateBlockBuilder2(nil, 3)
+		for score := 0; score < row%3; score++ {
+			scores.WriteLong(int64(row * score))
+		}
+		types.Get(3).WriteObject(pb.GetBlockBuilder(3), scores.Build())
+		if pb.GetPositionCount() == 700 {
+			writer.Write(pb.Build())
+			pb = spi.NewPageBuilder(types)
+		}
+	}
+	if !pb.IsEmpty() {
+		writer.Write(pb.Build())
+	}
+	writer.Close()
+	return writer, out.Bytes()
+}
+
+func TestMothWriter_Validate(t *testing.T) {
+	keyProvider := encryption.NewInMemoryKeyProvider().AddKey("pii", 1, metadata.AES_CTR_256, []byte("0123456789abcdef0123456789abcdef"))
+	options := NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100)
+	writer, data := writeValidatedFile(3500, options)
+	_, otherData := writeValidatedFile(3400, options)
+	encryptedWriter, encryptedData := writeValidatedFile(3500, options.WithColumnEncryption(map[string]string{"name": "pii"}).WithKeyProvider(keyProvider))
+	// a string in the dictionary of the first stripe that is not the minimum or maximum of a row group, the file still decodes
+	changedValue := append([]byte{}, data...)
+	copy(changedValue[bytes.Index(changedValue, []byte("name-000505")):], "name-X00505")
+
+	tests := []struct {
+		name     string
+		writer   *MothWriter
+		data     []byte
+		mismatch string
+	}{
+		{"valid", writer, data, ""},
+		{"encrypted", encryptedWriter, encryptedData, ""},
+		{"other file", writer, otherData, "file has 3400 rows, 3500 were written"},
+		{"changed value", writer, changedValue, "values of column name in stripe 0 do not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.writer.Validate(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), slice.NewWithBuf(tt.data)))
+			if tt.mismatch == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var mothError *common.MothError
+			if !goerrors.As(err, &mothError) || mothError.GetCode() != errors.CORRUPT_FILE || !strings.Contains(err.Error(), tt.mismatch) {
+				t.Fatalf("got %v, want a CORRUPT_FILE error reporting %q", err, tt.mismatch)
+			}
+		})
+	}
+}
+
+func TestMothWriter_MemoryLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		memoryContext memory.AggregatedMemoryContext
+		multiStripe   bool
+	}{
+		{"no memory context", nil, false},
+		{"unbounded", memory.NewSimpleAggregatedMemoryContext(), false},
+		{"bounded", memory.NewBoundedAggregatedMemoryContext(64 * 1024), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := writeTestFile(30000, NewMothWriterOptions().WithMemoryContext(tt.memoryContext), metadata.ZLIB)
+			reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), NewMothReaderOptions()).Get()
+			if stripes := reader.GetFooter().GetStripes().Size(); (stripes > 1) != tt.multiStripe {
+				t.Fatalf("wrote %d stripes", stripes)
+			}
+			if ids := readIds(data, NewMothReaderOptions(), TRUE); !reflect.DeepEqual(ids, idRange(0, 30000)) {
+				t.Fatalf("read %d rows, want 30000", len(ids))
+			}
+			if tt.memoryContext != nil && tt.memoryContext.GetBytes() != 0 {
+				t.Fatalf("closed writer retains %d bytes", tt.memoryContext.GetBytes())
+			}
+		})
+	}
+}
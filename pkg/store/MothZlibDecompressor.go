@@ -34,29 +34,34 @@ func (mr *MothZlibDecompressor) Decompress(input []byte, offset int32, length in
 	inflater, err := zlib.NewReader(b)
 
 	if err != nil && err != io.EOF {
-		panic(fmt.Sprintf("zlib reader error: %s", err.Error()))
+		panic(common.NewMothCorruptionError(mr.mothDataSourceId, "zlib reader error: %s", err.Error()))
 	}
 
 	buffer := output.Initialize(maths.MinInt32(length*EXPECTED_COMPRESSION_RATIO, mr.maxBufferSize))
 
-	var finishError error
-	var size int
 	uncompressedLength := 0
 	for {
-
-		size, finishError = inflater.Read(buffer)
-		if finishError != nil && finishError != io.EOF {
-			panic(fmt.Sprintf("uncompressed error: %s", finishError.Error()))
-		}
-
+		size, finishError := inflater.Read(buffer[uncompressedLength:])
 		uncompressedLength += size
-		bLen := util.Lens(buffer)
-
-		if finishError == io.EOF || bLen >= mr.maxBufferSize || int32(size) <= bLen {
+		if finishError == io.EOF {
 			break
 		}
+		if finishError != nil {
+			panic(common.NewMothCorruptionError(mr.mothDataSourceId, "uncompressed error: %s", finishError.Error()))
+		}
+		bLen := util.Lens(buffer)
+		if uncompressedLength < int(bLen) {
+			continue
+		}
+		if bLen >= mr.maxBufferSize {
+			// the output may end exactly at the buffer size
+			if n, err := inflater.Read(make([]byte, 1)); n == 0 && err == io.EOF {
+				break
+			}
+			panic(common.NewMothCorruptionError(mr.mothDataSourceId, "Could not decompress all input (output buffer too small?)"))
+		}
 
-		buffer = output.Grow(maths.MinInt32(bLen, mr.maxBufferSize))
+		buffer = output.Grow(maths.MinInt32(bLen*2, mr.maxBufferSize))
 		nLen := util.Lens(buffer)
 		if nLen <= bLen {
 			panic(fmt.Sprintf("Buffer failed to grow. Old size %d, current size %d", bLen, nLen))
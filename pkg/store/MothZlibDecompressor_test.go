@@ -1,6 +1,8 @@
 package store
 
 import (
+	"bytes"
+	"compress/zlib"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -62,3 +64,48 @@ func (buf *MemoryOutputBuffer) Initialize(size int32) []byte {
 func (buf *MemoryOutputBuffer) Grow(size int32) []byte {
 	return make([]byte, size)
 }
+
+// keeps the decompressed bytes when the buffer grows
+type growingOutputBuffer struct {
+	buffer []byte
+}
+
+func (gr *growingOutputBuffer) Initialize(size int32) []byte {
+	gr.buffer = make([]byte, size)
+	return gr.buffer
+}
+
+func (gr *growingOutputBuffer) Grow(size int32) []byte {
+	buffer := make([]byte, size)
+	copy(buffer, gr.buffer)
+	gr.buffer = buffer
+	return gr.buffer
+}
+
+func TestMothZlibDecompressor_GrowBuffer(t *testing.T) {
+	// compresses far better than the expected ratio, the buffer grows several times
+	data := bytes.Repeat([]byte("moth-"), 20000)
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	writer.Write(data)
+	writer.Close()
+	input := compressed.Bytes()
+
+	for _, maxBufferSize := range []int32{1 << 20, int32(len(data))} {
+		output := new(growingOutputBuffer)
+		size := NewMothZlibDecompressor(common.NewMothDataSourceId("test"), maxBufferSize).Decompress(input, 0, int32(len(input)), output)
+		if size != int32(len(data)) || !bytes.Equal(output.buffer[:size], data) {
+			t.Fatalf("decompressed %d of %d bytes with a max buffer of %d", size, len(data), maxBufferSize)
+		}
+	}
+
+	// the output does not fit into the max buffer
+	err := func() (err error) {
+		defer recoverMothError(&err, common.NewMothDataSourceId("test"))
+		NewMothZlibDecompressor(common.NewMothDataSourceId("test"), int32(len(data)-1)).Decompress(input, 0, int32(len(input)), new(growingOutputBuffer))
+		return nil
+	}()
+	if err == nil {
+		t.Fatal("decompressed into a too small buffer")
+	}
+}
@@ -1,13 +1,30 @@
 package store
 
 import (
-	"bytes"
 	"fmt"
+	"sync"
 
 	"github.com/klauspost/compress/zstd"
 	"github.com/mothdb-bd/orc-go/pkg/store/common"
 )
 
+var (
+	// a decoder is safe for concurrent DecodeAll calls, so it is shared by all decompressors
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderOnce sync.Once
+)
+
+func getZstdDecoder() *zstd.Decoder {
+	zstdDecoderOnce.Do(func() {
+		decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(MAX_BUFFER_SIZE)))
+		if err != nil {
+			panic(fmt.Sprintf("Failed to create zstd decoder: %s", err.Error()))
+		}
+		zstdDecoder = decoder
+	})
+	return zstdDecoder
+}
+
 type MothZstdDecompressor struct {
 	// 继承
 	MothDecompressor
@@ -25,16 +42,16 @@ func NewMothZstdDecompressor(mothDataSourceId *common.MothDataSourceId, maxBuffe
 
 // @Override
 func (mr *MothZstdDecompressor) Decompress(input []byte, offset int32, length int32, output OutputBuffer) int32 {
-
-	zDecoder, _ := zstd.NewReader(bytes.NewBuffer(input[offset:offset+length]), zstd.WithDecoderMaxMemory(uint64(mr.maxBufferSize)))
-	defer zDecoder.Close()
-
-	re := output.Initialize(mr.maxBufferSize)
-	uncompressedLength, _ := zDecoder.Read(re)
-	if int32(uncompressedLength) > mr.maxBufferSize {
-		panic(fmt.Sprintf("Zstd requires buffer (%d) larger than max size (%d)", uncompressedLength, mr.maxBufferSize))
+	buffer := output.Initialize(mr.maxBufferSize)
+	uncompressed, err := getZstdDecoder().DecodeAll(input[offset:offset+length], buffer[:0])
+	if err != nil {
+		panic(common.NewMothCorruptionError(mr.mothDataSourceId, "Zstd decompression failed: %s", err.Error()))
+	}
+	uncompressedLength := int32(len(uncompressed))
+	if uncompressedLength > mr.maxBufferSize {
+		panic(common.NewMothCorruptionError(mr.mothDataSourceId, "Zstd requires buffer (%d) larger than max size (%d)", uncompressedLength, mr.maxBufferSize))
 	}
-	return int32(uncompressedLength)
+	return uncompressedLength
 }
 
 // @Override
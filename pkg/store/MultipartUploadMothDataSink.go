@@ -0,0 +1,193 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+var (
+	DEFAULT_MULTIPART_PART_SIZE          util.DataSize = util.Ofds(16, util.MB)
+	DEFAULT_MULTIPART_UPLOAD_CONCURRENCY int32         = 4
+	MULTIPART_DATA_SINK_INSTANCE_SIZE    int32         = util.SizeOf(&MultipartUploadMothDataSink{})
+)
+
+/**
+ * Writes a file as a multipart upload of an object store, so files are written to the lake
+ * without local temp files. The written data is cut into parts of partSize bytes, only the last
+ * part is smaller, and at most maxConcurrentUploads parts are uploaded in parallel while the
+ * writer goes on. The upload is completed by Close. When a part can not be uploaded, or the
+ * writer fails, the upload is aborted and no object is created.
+ */
+type MultipartUploadMothDataSink struct {
+	// 继承
+	MothDataSink
+
+	uploader MultipartUploader
+	partSize int32
+	buffer   *slice.DynamicSliceOutput
+	size     int64
+	uploadId string
+	closed   bool
+
+	permits chan struct{}
+	uploads sync.WaitGroup
+
+	lock          sync.Mutex
+	parts         []*CompletedPart
+	inFlightBytes int64
+	failure       error
+}
+
+func NewMultipartUploadMothDataSink(uploader MultipartUploader) *MultipartUploadMothDataSink {
+	return NewMultipartUploadMothDataSink2(uploader, DEFAULT_MULTIPART_PART_SIZE, DEFAULT_MULTIPART_UPLOAD_CONCURRENCY)
+}
+
+func NewMultipartUploadMothDataSink2(uploader MultipartUploader, partSize util.DataSize, maxConcurrentUploads int32) *MultipartUploadMothDataSink {
+	util.CheckArgument2(partSize.Bytes() > 0, "partSize must be positive")
+	util.CheckArgument2(maxConcurrentUploads > 0, "maxConcurrentUploads must be positive")
+	mk := new(MultipartUploadMothDataSink)
+	mk.uploader = uploader
+	mk.partSize = util.Int32Exact(int64(partSize.Bytes()))
+	mk.buffer = slice.NewDynamicSliceOutput(0)
+	mk.permits = make(chan struct{}, maxConcurrentUploads)
+	return mk
+}
+
+// @Override
+func (mk *MultipartUploadMothDataSink) Size() int64 {
+	return mk.size
+}
+
+// @Override
+func (mk *MultipartUploadMothDataSink) GetRetainedSizeInBytes() int64 {
+	mk.lock.Lock()
+	defer mk.lock.Unlock()
+	return int64(MULTIPART_DATA_SINK_INSTANCE_SIZE) + mk.buffer.GetRetainedSize() + mk.inFlightBytes
+}
+
+/**
+ * Buffers the data and uploads the full parts. The parts are cut from the buffer without
+ * copying, only the rest of the data that does not fill a part is copied into a new buffer. A
+ * failed write fails the sink, so Close aborts the upload instead of completing it.
+ */
+// @Override
+func (mk *MultipartUploadMothDataSink) Write(outputData *util.ArrayList[MothDataOutput]) {
+	util.CheckState2(!mk.closed, "Data sink is closed")
+	mk.checkFailure()
+	defer func() {
+		if failure := recover(); failure != nil {
+			mk.lock.Lock()
+			if mk.failure == nil {
+				mk.failure = common.AsMothError(failure, nil)
+			}
+			mk.lock.Unlock()
+			panic(failure)
+		}
+	}()
+	outputData.ForEach(func(data MothDataOutput) {
+		data.WriteData(mk.buffer)
+		mk.size += data.Size()
+		if mk.buffer.Size() < mk.partSize {
+			return
+		}
+		// Reset replaces the buffer, so the parts keep the buffered bytes
+		buffered := mk.buffer.Slice().UnsafeBytes()[:mk.buffer.Size()]
+		mk.buffer.Reset()
+		for int32(len(buffered)) >= mk.partSize {
+			mk.uploadPart(buffered[:mk.partSize:mk.partSize])
+			buffered = buffered[mk.partSize:]
+		}
+		mk.buffer.WriteBytes(buffered)
+	})
+}
+
+/**
+ * Uploads the part on a goroutine, blocks while maxConcurrentUploads parts are in flight.
+ */
+func (mk *MultipartUploadMothDataSink) uploadPart(data []byte) {
+	if mk.uploadId == "" {
+		uploadId, err := mk.uploader.CreateMultipartUpload()
+		if err != nil {
+			mk.closed = true
+			panic(mothio.NewIOError(err, "Failed to create the multipart upload"))
+		}
+		mk.uploadId = uploadId
+	}
+	mk.permits <- struct{}{}
+	mk.lock.Lock()
+	mk.parts = append(mk.parts, nil)
+	partNumber := int32(len(mk.parts))
+	mk.inFlightBytes += int64(len(data))
+	mk.lock.Unlock()
+
+	mk.uploads.Add(1)
+	go func() {
+		defer mk.uploads.Done()
+		eTag, err := mk.uploader.UploadPart(mk.uploadId, partNumber, data)
+
+		mk.lock.Lock()
+		if err != nil && mk.failure == nil {
+			mk.failure = mothio.NewIOError(err, "Failed to upload part %d", partNumber)
+		}
+		mk.parts[partNumber-1] = &CompletedPart{PartNumber: partNumber, ETag: eTag}
+		mk.inFlightBytes -= int64(len(data))
+		mk.lock.Unlock()
+		<-mk.permits
+	}()
+}
+
+/**
+ * Aborts the upload and raises the failure of a part upload.
+ */
+func (mk *MultipartUploadMothDataSink) checkFailure() {
+	mk.lock.Lock()
+	failure := mk.failure
+	mk.lock.Unlock()
+	if failure != nil {
+		mk.Abort()
+		panic(failure)
+	}
+}
+
+/**
+ * Uploads the rest of the data and completes the upload, which creates the object.
+ */
+// @Override
+func (mk *MultipartUploadMothDataSink) Close() {
+	if mk.closed {
+		return
+	}
+	mk.checkFailure()
+	// an empty file is uploaded as one empty part
+	if mk.buffer.Size() > 0 || mk.uploadId == "" {
+		mk.uploadPart(mk.buffer.Slice().UnsafeBytes()[:mk.buffer.Size()])
+		mk.buffer.Reset()
+	}
+	mk.uploads.Wait()
+	mk.checkFailure()
+	mk.closed = true
+	if err := mk.uploader.CompleteMultipartUpload(mk.uploadId, mk.parts); err != nil {
+		mk.uploader.AbortMultipartUpload(mk.uploadId)
+		panic(mothio.NewIOError(err, "Failed to complete the multipart upload"))
+	}
+}
+
+/**
+ * Waits for the part uploads in flight and aborts the upload, the parts are discarded by the
+ * object store.
+ */
+// @Override
+func (mk *MultipartUploadMothDataSink) Abort() {
+	if mk.closed {
+		return
+	}
+	mk.closed = true
+	mk.uploads.Wait()
+	if mk.uploadId != "" {
+		mk.uploader.AbortMultipartUpload(mk.uploadId)
+	}
+}
@@ -0,0 +1,204 @@
+package store
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+// an object store implementing the multipart upload API of S3
+type testObjectStore struct {
+	lock       sync.Mutex
+	uploads    map[string]map[int][]byte
+	objects    map[string][]byte
+	aborted    int
+	failPart   int
+	inFlight   int
+	maxUploads int
+}
+
+func newTestObjectStore() *testObjectStore {
+	return &testObjectStore{uploads: make(map[string]map[int][]byte), objects: make(map[string][]byte)}
+}
+
+func (te *testObjectStore) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	uploadId := query.Get("uploadId")
+	switch {
+	case request.Method == http.MethodPost && query.Has("uploads"):
+		te.lock.Lock()
+		uploadId = fmt.Sprintf("upload-%d", len(te.uploads))
+		te.uploads[uploadId] = make(map[int][]byte)
+		te.lock.Unlock()
+		fmt.Fprintf(writer, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", uploadId)
+	case request.Method == http.MethodPut:
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		data, _ := io.ReadAll(request.Body)
+		te.lock.Lock()
+		te.inFlight++
+		if te.inFlight > te.maxUploads {
+			te.maxUploads = te.inFlight
+		}
+		te.lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		te.lock.Lock()
+		defer te.lock.Unlock()
+		te.inFlight--
+		if partNumber == te.failPart {
+			http.Error(writer, "<Error><Code>InternalError</Code></Error>", http.StatusInternalServerError)
+			return
+		}
+		te.uploads[uploadId][partNumber] = data
+		writer.Header().Set("ETag", fmt.Sprintf("\"etag-%d\"", partNumber))
+	case request.Method == http.MethodPost:
+		var complete struct {
+			Parts []*CompletedPart `xml:"Part"`
+		}
+		body, _ := io.ReadAll(request.Body)
+		if err := xml.Unmarshal(body, &complete); err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		te.lock.Lock()
+		defer te.lock.Unlock()
+		object := make([]byte, 0)
+		for i, part := range complete.Parts {
+			if int(part.PartNumber) != i+1 || part.ETag != fmt.Sprintf("\"etag-%d\"", part.PartNumber) {
+				http.Error(writer, "<Error><Code>InvalidPart</Code></Error>", http.StatusBadRequest)
+				return
+			}
+			object = append(object, te.uploads[uploadId][i+1]...)
+		}
+		te.objects[request.URL.Path] = object
+		delete(te.uploads, uploadId)
+	case request.Method == http.MethodDelete:
+		te.lock.Lock()
+		defer te.lock.Unlock()
+		delete(te.uploads, uploadId)
+		te.aborted++
+		writer.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(writer, "unsupported request", http.StatusBadRequest)
+	}
+}
+
+// data that fails to be written
+type failingMothDataOutput struct{}
+
+func (failingMothDataOutput) Size() int64 {
+	return 1
+}
+
+func (failingMothDataOutput) WriteData(sliceOutput slice.SliceOutput) {
+	panic(mothio.NewIOError(nil, "Failed to write"))
+}
+
+type failingMothDataSink struct {
+	*MultipartUploadMothDataSink
+	failWrites bool
+}
+
+func (fk *failingMothDataSink) Write(outputData *util.ArrayList[MothDataOutput]) {
+	if fk.failWrites {
+		panic(mothio.NewIOError(nil, "Failed to write"))
+	}
+	fk.MultipartUploadMothDataSink.Write(outputData)
+}
+
+// writes the rows, a failed write is returned by Close
+func writeMultipartRows(sink MothDataSink, rowCount int) *MothWriter {
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+	columnNames := util.NewArrayList("id", "name")
+	writer := NewMothWriter(sink, columnNames, types, metadata.CreateRootMothType(columnNames, types), metadata.NONE, NewMothWriterOptions().WithStripeMaxRowCount(1000), util.EmptyMap[string, string](), NewMothWriterStats())
+	pb := spi.NewPageBuilder(types)
+	for i := 0; i < rowCount; i++ {
+		pb.DeclarePosition()
+		block.WriteNativeValue(block.BIGINT, pb.GetBlockBuilder(0), int64(i))
+		block.WriteNativeValue(block.VARCHAR, pb.GetBlockBuilder(1), fmt.Sprintf("name-%06d", i))
+	}
+	writer.TryWrite(pb.Build())
+	return writer
+}
+
+func TestMultipartUploadMothDataSink(t *testing.T) {
+	store := newTestObjectStore()
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	sink := NewMultipartUploadMothDataSink2(NewHttpMultipartUploader(server.Client(), server.URL+"/bucket/test.moth"), util.Ofds(16, util.KB), 3)
+	if err := writeMultipartRows(sink, 20000).TryClose(); err != nil {
+		t.Fatal(err)
+	}
+	object := store.objects["/bucket/test.moth"]
+	if int64(len(object)) != sink.Size() || len(object) < 10*16*1024 || store.maxUploads < 2 || store.maxUploads > 3 {
+		t.Fatalf("uploaded %d of %d bytes with %d parallel uploads", len(object), sink.Size(), store.maxUploads)
+	}
+	recordReader := createRecordReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), slice.NewWithBuf(object)), NewMothReaderOptions(), TRUE)
+	defer recordReader.Close()
+	if ids := pageIds(t, recordReader.ReadRows(0, 20000).ToArray()...); !reflect.DeepEqual(ids, idRange(0, 20000)) {
+		t.Fatalf("read %d rows of the uploaded file", len(ids))
+	}
+
+	// an empty sink uploads an empty object
+	sink = NewMultipartUploadMothDataSink(NewHttpMultipartUploader(server.Client(), server.URL+"/bucket/empty"))
+	sink.Close()
+	if object, ok := store.objects["/bucket/empty"]; !ok || len(object) != 0 {
+		t.Fatalf("uploaded %d bytes of an empty sink", len(object))
+	}
+}
+
+func TestMultipartUploadMothDataSink_Abort(t *testing.T) {
+	store := newTestObjectStore()
+	store.failPart = 3
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	sink := NewMultipartUploadMothDataSink2(NewHttpMultipartUploader(server.Client(), server.URL+"/bucket/test.moth"), util.Ofds(16, util.KB), 2)
+	if err := writeMultipartRows(sink, 20000).TryClose(); err == nil {
+		t.Fatal("wrote a file with a failed part upload")
+	}
+	if len(store.objects) != 0 || len(store.uploads) != 0 || store.aborted != 1 {
+		t.Fatalf("left %d objects and %d uploads after %d aborts", len(store.objects), len(store.uploads), store.aborted)
+	}
+
+	// a writer that fails aborts the upload of its sink
+	store.failPart = 0
+	sink = NewMultipartUploadMothDataSink2(NewHttpMultipartUploader(server.Client(), server.URL+"/bucket/test.moth"), util.Ofds(16, util.KB), 2)
+	failingSink := &failingMothDataSink{MultipartUploadMothDataSink: sink}
+	writer := writeMultipartRows(failingSink, 20000)
+	failingSink.failWrites = true
+	if err := writer.TryClose(); err == nil {
+		t.Fatal("closed a failed writer")
+	}
+	if len(store.objects) != 0 || len(store.uploads) != 0 || store.aborted != 2 {
+		t.Fatalf("left %d objects and %d uploads after %d aborts", len(store.objects), len(store.uploads), store.aborted)
+	}
+
+	// a sink that failed to write aborts the upload on Close
+	sink = NewMultipartUploadMothDataSink2(NewHttpMultipartUploader(server.Client(), server.URL+"/bucket/test.moth"), util.Ofds(16, util.KB), 2)
+	sink.Write(util.NewArrayList(CreateDataOutput(slice.NewWithBuf(make([]byte, 40*1024)))))
+	if runCapturingPanic(func() { sink.Write(util.NewArrayList[MothDataOutput](failingMothDataOutput{})) }) == nil {
+		t.Fatal("wrote failing data")
+	}
+	if runCapturingPanic(sink.Close) == nil {
+		t.Fatal("closed a failed sink")
+	}
+	if len(store.objects) != 0 || len(store.uploads) != 0 || store.aborted != 3 {
+		t.Fatalf("left %d objects and %d uploads after %d aborts", len(store.objects), len(store.uploads), store.aborted)
+	}
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+/**
+ * The multipart upload API of an object store. Parts are numbered from 1 and may be uploaded
+ * concurrently, the object is created from the parts in the order of their numbers when the
+ * upload is completed.
+ */
+type MultipartUploader interface {
+	CreateMultipartUpload() (string, error)
+	UploadPart(uploadId string, partNumber int32, data []byte) (string, error)
+	CompleteMultipartUpload(uploadId string, parts []*CompletedPart) error
+	AbortMultipartUpload(uploadId string) error
+}
+
+type CompletedPart struct {
+	PartNumber int32  `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+/**
+ * Uploads an object with the S3 REST API. The requests are signed by sign, e.g. with AWS
+ * signature version 4, or sent unsigned when sign is nil.
+ */
+type HttpMultipartUploader struct {
+	client *http.Client
+	url    string
+	sign   func(request *http.Request) error
+}
+
+func NewHttpMultipartUploader(client *http.Client, url string) *HttpMultipartUploader {
+	return NewHttpMultipartUploader2(client, url, nil)
+}
+
+func NewHttpMultipartUploader2(client *http.Client, url string, sign func(request *http.Request) error) *HttpMultipartUploader {
+	hr := new(HttpMultipartUploader)
+	hr.client = client
+	hr.url = url
+	hr.sign = sign
+	return hr
+}
+
+// @Override
+func (hr *HttpMultipartUploader) CreateMultipartUpload() (string, error) {
+	response, err := hr.send(http.MethodPost, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		UploadId string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(response, &result); err != nil || result.UploadId == "" {
+		return "", fmt.Errorf("invalid create multipart upload response %q", response)
+	}
+	return result.UploadId, nil
+}
+
+// @Override
+func (hr *HttpMultipartUploader) UploadPart(uploadId string, partNumber int32, data []byte) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(int(partNumber))}, "uploadId": {uploadId}}
+	request, err := hr.newRequest(http.MethodPut, query, data)
+	if err != nil {
+		return "", err
+	}
+	response, err := hr.do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	eTag := response.Header.Get("ETag")
+	if eTag == "" {
+		return "", fmt.Errorf("upload of part %d returned no ETag", partNumber)
+	}
+	return eTag, nil
+}
+
+// @Override
+func (hr *HttpMultipartUploader) CompleteMultipartUpload(uploadId string, parts []*CompletedPart) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name         `xml:"CompleteMultipartUpload"`
+		Parts   []*CompletedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	response, err := hr.send(http.MethodPost, url.Values{"uploadId": {uploadId}}, body)
+	if err != nil {
+		return err
+	}
+	// S3 reports some failures of a started completion in a 200 response
+	if bytes.Contains(response, []byte("<Error>")) {
+		return fmt.Errorf("failed to complete the multipart upload: %s", response)
+	}
+	return nil
+}
+
+// @Override
+func (hr *HttpMultipartUploader) AbortMultipartUpload(uploadId string) error {
+	_, err := hr.send(http.MethodDelete, url.Values{"uploadId": {uploadId}}, nil)
+	return err
+}
+
+func (hr *HttpMultipartUploader) send(method string, query url.Values, body []byte) ([]byte, error) {
+	request, err := hr.newRequest(method, query, body)
+	if err != nil {
+		return nil, err
+	}
+	response, err := hr.do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	return io.ReadAll(response.Body)
+}
+
+func (hr *HttpMultipartUploader) newRequest(method string, query url.Values, body []byte) (*http.Request, error) {
+	requestUrl, err := url.Parse(hr.url)
+	if err != nil {
+		return nil, err
+	}
+	requestUrl.RawQuery = query.Encode()
+	request, err := http.NewRequest(method, requestUrl.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if hr.sign != nil {
+		if err := hr.sign(request); err != nil {
+			return nil, err
+		}
+	}
+	return request, nil
+}
+
+/**
+ * Sends the request, a response that is not a success is returned as an error.
+ */
+func (hr *HttpMultipartUploader) do(request *http.Request) (*http.Response, error) {
+	response, err := hr.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		response.Body.Close()
+		return nil, fmt.Errorf("%s %s returned %s: %s", request.Method, redactUrl(hr.url), response.Status, message)
+	}
+	return response, nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+var NULL_COLUMN_READER_INSTANCE_SIZE int32 = util.SizeOf(&NullColumnReader{})
+
+/**
+ * Reads a column that is missing from the file, all of its values are null.
+ */
+type NullColumnReader struct {
+	// 继承
+	ColumnReader
+
+	kind          block.Type
+	nextBatchSize int32
+}
+
+func NewNullColumnReader(kind block.Type) *NullColumnReader {
+	nr := new(NullColumnReader)
+	nr.kind = kind
+	return nr
+}
+
+// @Override
+func (nr *NullColumnReader) PrepareNextRead(batchSize int32) {
+	nr.nextBatchSize = batchSize
+}
+
+// @Override
+func (nr *NullColumnReader) ReadBlock() block.Block {
+	b := block.CreateRunLengthEncodedBlock(nr.kind, nil, nr.nextBatchSize)
+	nr.nextBatchSize = 0
+	return b
+}
+
+// @Override
+func (nr *NullColumnReader) StartStripe(fileTimeZone *time.Location, dictionaryStreamSources *InputStreamSources, encoding *metadata.ColumnMetadata[*metadata.ColumnEncoding]) {
+	nr.nextBatchSize = 0
+}
+
+// @Override
+func (nr *NullColumnReader) StartRowGroup(dataStreamSources *InputStreamSources) {
+	nr.nextBatchSize = 0
+}
+
+// @Override
+func (nr *NullColumnReader) ToString() string {
+	return util.NewSB().AppendString("null ").AppendString(nr.kind.GetDisplayName()).String()
+}
+
+// @Override
+func (nr *NullColumnReader) Close() {
+}
+
+// @Override
+func (nr *NullColumnReader) GetRetainedSizeInBytes() int64 {
+	return int64(NULL_COLUMN_READER_INSTANCE_SIZE)
+}
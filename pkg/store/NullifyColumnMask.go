@@ -0,0 +1,23 @@
+package store
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+)
+
+type NullifyColumnMask struct {
+	// 继承
+	ColumnMask
+
+	nullValue block.Block
+}
+
+func NewNullifyColumnMask(kind block.Type) *NullifyColumnMask {
+	nk := new(NullifyColumnMask)
+	nk.nullValue = kind.CreateBlockBuilder2(nil, 1).AppendNull().Build()
+	return nk
+}
+
+// @Override
+func (nk *NullifyColumnMask) Mask(b block.Block) block.Block {
+	return block.NewRunLengthEncodedBlock(nk.nullValue, b.GetPositionCount())
+}
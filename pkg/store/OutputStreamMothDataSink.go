@@ -45,5 +45,7 @@ func (ok *OutputStreamMothDataSink) Write(outputData *util.ArrayList[MothDataOut
 
 // @Override
 func (ok *OutputStreamMothDataSink) Close() {
-	ok.output.Close()
+	if err := ok.output.Close(); err != nil {
+		panic(mothio.NewIOError(err, "Failed to close the data sink"))
+	}
 }
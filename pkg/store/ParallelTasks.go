@@ -0,0 +1,46 @@
+package store
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+/**
+ * Runs task for every index from 0 to taskCount on at most parallelism goroutines and waits
+ * for all of them. With a parallelism of one, or a single task, everything runs on the
+ * caller's goroutine. When tasks fail, the failure of the lowest index is raised on the
+ * caller's goroutine, so the reported failure does not depend on scheduling.
+ */
+func runParallel(taskCount int, parallelism int32, task func(index int)) {
+	if parallelism <= 1 || taskCount <= 1 {
+		for i := 0; i < taskCount; i++ {
+			task(i)
+		}
+		return
+	}
+	workers := int(parallelism)
+	if workers > taskCount {
+		workers = taskCount
+	}
+	failures := make([]interface{}, taskCount)
+	nextTask := int64(-1)
+	var done sync.WaitGroup
+	done.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer done.Done()
+			for i := int(atomic.AddInt64(&nextTask, 1)); i < taskCount; i = int(atomic.AddInt64(&nextTask, 1)) {
+				index := i
+				failures[index] = runCapturingPanic(func() {
+					task(index)
+				})
+			}
+		}()
+	}
+	done.Wait()
+	for _, failure := range failures {
+		if failure != nil {
+			panic(failure)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+/**
+ * Maps the fields of a requested struct type to the fields of a struct column by their position,
+ * the names of the fields in the file are ignored.
+ */
+type PositionBasedFieldMapper struct {
+	// 继承
+	FieldMapper
+
+	nestedColumns []*MothColumn
+	fieldIndexes  map[string]int
+}
+
+/**
+ * Creates a mapper of the lower case field names of the requested struct type, in the order of
+ * the requested fields, to the nested columns.
+ */
+func NewPositionBasedFieldMapper(nestedColumns []*MothColumn, fieldNames []string) *PositionBasedFieldMapper {
+	pr := new(PositionBasedFieldMapper)
+	pr.nestedColumns = nestedColumns
+	pr.fieldIndexes = make(map[string]int, len(fieldNames))
+	for i, fieldName := range fieldNames {
+		pr.fieldIndexes[fieldName] = i
+	}
+	return pr
+}
+
+// @Override
+func (pr *PositionBasedFieldMapper) Get(fieldName string) *MothColumn {
+	index, ok := pr.fieldIndexes[fieldName]
+	if !ok || index >= len(pr.nestedColumns) {
+		return nil
+	}
+	return pr.nestedColumns[index]
+}
+
+type PositionBasedFieldMapperFactory struct {
+	// 继承
+	FieldMapperFactory
+
+	fieldNames map[metadata.MothColumnId][]string
+}
+
+/**
+ * Creates a factory of position based mappers, fieldNames holds the lower case field names of the
+ * requested struct type of each struct column that is read.
+ */
+func NewPositionBasedFieldMapperFactory(fieldNames map[metadata.MothColumnId][]string) *PositionBasedFieldMapperFactory {
+	py := new(PositionBasedFieldMapperFactory)
+	py.fieldNames = fieldNames
+	return py
+}
+
+// @Override
+func (py *PositionBasedFieldMapperFactory) Create(column *MothColumn) FieldMapper {
+	return NewPositionBasedFieldMapper(column.GetNestedColumns().ToArray(), py.fieldNames[column.GetColumnId()])
+}
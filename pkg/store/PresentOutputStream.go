@@ -17,9 +17,9 @@ type PresentOutputStream struct {
 	closed              bool
 }
 
-func NewPresentOutputStream(compression metadata.CompressionKind, bufferSize int32) *PresentOutputStream {
+func NewPresentOutputStream(compression metadata.CompressionKind, compressionLevel int32, bufferSize int32) *PresentOutputStream {
 	pm := new(PresentOutputStream)
-	pm.buffer = NewMothOutputBuffer(compression, bufferSize)
+	pm.buffer = NewMothOutputBuffer(compression, compressionLevel, bufferSize)
 	pm.groupsCounts = util.NewArrayList[int32]()
 	return pm
 }
@@ -0,0 +1,267 @@
+package store
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/errors"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+var MAX_CONCURRENT_RANGE_READS int32 = 8
+
+/**
+ * A data source reading with positional reads of an io.ReaderAt, such as an *os.File. Reads
+ * do not share a file position, so the data source is safe for concurrent use and several
+ * record readers over different splits of a file can share one open handle. The merged disk
+ * ranges of a stripe are fetched in parallel, at most MAX_CONCURRENT_RANGE_READS at a time. Small
+ * ranges read lazily, the default, are only read by their first reader unless
+ * MothReaderOptions.WithPrefetchLazyRanges is set. Then they are also fetched in the background and
+ * a reader of a range that is not loaded yet waits for its prefetch or loads it itself. The
+ * prefetched buffers are retained from the moment the stripe is read, so their readers report
+ * them to the memory context of the stripe, and Close waits for the running prefetches.
+ */
+type ReaderAtMothDataSource struct {
+	// 继承
+	AbstractMothDataSource
+
+	reader            io.ReaderAt
+	closer            io.Closer
+	modificationStamp string
+
+	prefetchLock sync.Mutex
+	prefetches   sync.WaitGroup
+	closed       bool
+}
+
+/**
+ * Creates a data source over the first size bytes of the reader. The reader is owned by the
+ * caller, Close does not close it, so the reader can be shared by data sources that are closed
+ * independently.
+ */
+func NewReaderAtMothDataSource(id *common.MothDataSourceId, reader io.ReaderAt, size int64, options *MothReaderOptions) *ReaderAtMothDataSource {
+	re := new(ReaderAtMothDataSource)
+	re.id = id
+	re.reader = reader
+	re.estimatedSize = size
+	re.options = options
+	return re
+}
+
+/**
+ * Opens the file as a data source, Close closes the file. A file that can not be opened is
+ * returned as a *common.MothError.
+ */
+func OpenReaderAtMothDataSource(path string, options *MothReaderOptions) (*ReaderAtMothDataSource, error) {
+	id := common.NewMothDataSourceId(path)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, common.NewMothErrorWithCause(errors.IO_ERROR, id, err, "Failed to open file: %s", err.Error())
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, common.NewMothErrorWithCause(errors.IO_ERROR, id, err, "Failed to open file: %s", err.Error())
+	}
+	re := NewReaderAtMothDataSource(id, file, fi.Size(), options)
+	re.closer = file
+	re.modificationStamp = fi.ModTime().UTC().Format(time.RFC3339Nano)
+	return re, nil
+}
+
+/**
+ * Returns the modification time of an opened file or the ETag of an opened object, empty when the
+ * version of the reader is not known.
+ */
+// @Override
+func (re *ReaderAtMothDataSource) GetModificationStamp() string {
+	return re.modificationStamp
+}
+
+// @Override
+func (re *ReaderAtMothDataSource) GetId() *common.MothDataSourceId {
+	return re.id
+}
+
+// @Override
+func (re *ReaderAtMothDataSource) GetReadBytes() int64 {
+	return atomic.LoadInt64(&re.readBytes)
+}
+
+// @Override
+func (re *ReaderAtMothDataSource) GetReadTimeNanos() int64 {
+	return atomic.LoadInt64(&re.readTimeNanos)
+}
+
+// @Override
+func (re *ReaderAtMothDataSource) GetEstimatedSize() int64 {
+	return re.estimatedSize
+}
+
+// @Override
+func (re *ReaderAtMothDataSource) GetRetainedSize() int64 {
+	return 0
+}
+
+// @Override
+func (re *ReaderAtMothDataSource) ReadTail(length int32) *slice.Slice {
+	return re.ReadFully(re.estimatedSize-int64(length), length)
+}
+
+// @Override
+func (re *ReaderAtMothDataSource) ReadFully(position int64, length int32) *slice.Slice {
+	buffer := make([]byte, length)
+	re.readFully(position, buffer, 0, length)
+	return slice.NewWithBuf(buffer)
+}
+
+func (re *ReaderAtMothDataSource) readFully(position int64, buffer []byte, bufferOffset int32, bufferLength int32) {
+	start := time.Now()
+	n, err := re.reader.ReadAt(buffer[bufferOffset:bufferOffset+bufferLength], position)
+	// a read of the last bytes may return io.EOF with the bytes
+	if n < int(bufferLength) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		panic(common.NewMothErrorWithCause(errors.IO_ERROR, re.id, err, "Failed to read %d bytes at %d: %s", bufferLength, position, err.Error()))
+	}
+	atomic.AddInt64(&re.readTimeNanos, time.Since(start).Nanoseconds())
+	atomic.AddInt64(&re.readBytes, int64(bufferLength))
+}
+
+// @Override
+func (re *ReaderAtMothDataSource) ReadFully2(diskRanges map[StreamId]*DiskRange) map[StreamId]MothDataReader {
+	if len(diskRanges) == 0 {
+		return util.EmptyMap[StreamId, MothDataReader]()
+	}
+	maxReadSizeBytes := re.options.GetMaxBufferSize().Bytes()
+	smallRanges := make(map[StreamId]*DiskRange)
+	slices := make(map[StreamId]MothDataReader)
+	for k, v := range diskRanges {
+		if uint64(v.GetLength()) <= maxReadSizeBytes {
+			smallRanges[k] = v
+		} else {
+			slices[k] = NewDiskMothDataReader(re, v)
+		}
+	}
+	if len(smallRanges) == 0 {
+		return slices
+	}
+
+	mergedRanges := MergeAdjacentDiskRanges(util.MapValues(smallRanges), re.options.GetMaxMergeDistance(), re.options.GetMaxBufferSize()).ToArray()
+	if re.options.IsLazyReadSmallRanges() {
+		loaders := make([]*LazyBufferLoader, len(mergedRanges))
+		for i, mergedRange := range mergedRanges {
+			loaders[i] = NewLazyBufferLoader(mergedRange, re)
+			for key, diskRange := range smallRanges {
+				if mergedRange.Contains(diskRange) {
+					slices[key] = NewMergedMothDataReader(re.id, diskRange, loaders[i])
+				}
+			}
+		}
+		if re.options.IsPrefetchLazyRanges() {
+			re.prefetch(loaders)
+		}
+		return slices
+	}
+	buffers := re.readDiskRanges(mergedRanges)
+	for k, v := range smallRanges {
+		slices[k] = NewMemoryMothDataReader(re.id, GetDiskRangeSlice(v, buffers), int64(v.GetLength()))
+	}
+	return slices
+}
+
+/**
+ * Reads the disk ranges in parallel, the first failed read is raised once all reads are done.
+ */
+func (re *ReaderAtMothDataSource) readDiskRanges(diskRanges []*DiskRange) map[*DiskRange]*slice.Slice {
+	buffers := make([]*slice.Slice, len(diskRanges))
+	failures := make([]interface{}, len(diskRanges))
+	permits := make(chan struct{}, MAX_CONCURRENT_RANGE_READS)
+	var reads sync.WaitGroup
+	for i, diskRange := range diskRanges {
+		reads.Add(1)
+		permits <- struct{}{}
+		go func(i int, diskRange *DiskRange) {
+			defer reads.Done()
+			defer func() { <-permits }()
+			failures[i] = runCapturingPanic(func() {
+				buffers[i] = re.ReadFully(diskRange.GetOffset(), diskRange.GetLength())
+			})
+		}(i, diskRange)
+	}
+	reads.Wait()
+
+	result := make(map[*DiskRange]*slice.Slice, len(diskRanges))
+	for i, diskRange := range diskRanges {
+		if failures[i] != nil {
+			panic(failures[i])
+		}
+		result[diskRange] = buffers[i]
+	}
+	return result
+}
+
+/**
+ * Loads the loaders in the background, at most MAX_CONCURRENT_RANGE_READS at a time. A failed
+ * load is dropped, the reader of the range loads it again and raises the failure. Nothing is
+ * prefetched once the data source is closed.
+ */
+func (re *ReaderAtMothDataSource) prefetch(loaders []*LazyBufferLoader) {
+	re.prefetchLock.Lock()
+	defer re.prefetchLock.Unlock()
+	if re.closed {
+		return
+	}
+	for _, loader := range loaders {
+		loader.prefetched = true
+	}
+	re.prefetches.Add(1)
+	go func() {
+		defer re.prefetches.Done()
+		permits := make(chan struct{}, MAX_CONCURRENT_RANGE_READS)
+		var loads sync.WaitGroup
+		for _, loader := range loaders {
+			permits <- struct{}{}
+			if re.isClosed() {
+				<-permits
+				break
+			}
+			loads.Add(1)
+			go func(loader *LazyBufferLoader) {
+				defer loads.Done()
+				defer func() { <-permits }()
+				runCapturingPanic(loader.load)
+			}(loader)
+		}
+		loads.Wait()
+	}()
+}
+
+func (re *ReaderAtMothDataSource) isClosed() bool {
+	re.prefetchLock.Lock()
+	defer re.prefetchLock.Unlock()
+	return re.closed
+}
+
+// @Override
+func (re *ReaderAtMothDataSource) Close() {
+	re.prefetchLock.Lock()
+	re.closed = true
+	re.prefetchLock.Unlock()
+	// the ranges that are not fetched yet are skipped, the running reads are waited for
+	re.prefetches.Wait()
+	if re.closer != nil {
+		re.closer.Close()
+	}
+}
+
+// @Override
+func (re *ReaderAtMothDataSource) String() string {
+	return re.id.String()
+}
@@ -0,0 +1,247 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+// counts the reads in flight, every read is slow so that parallel reads overlap
+type slowReaderAt struct {
+	reader   io.ReaderAt
+	failAt   int64
+	lock     sync.Mutex
+	inFlight int32
+	maxReads int32
+}
+
+func (sa *slowReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	sa.lock.Lock()
+	sa.inFlight++
+	if sa.inFlight > sa.maxReads {
+		sa.maxReads = sa.inFlight
+	}
+	sa.lock.Unlock()
+	defer func() {
+		sa.lock.Lock()
+		sa.inFlight--
+		sa.lock.Unlock()
+	}()
+	time.Sleep(20 * time.Millisecond)
+	if off == sa.failAt {
+		return 0, errors.New("disk failure")
+	}
+	return sa.reader.ReadAt(p, off)
+}
+
+func TestReaderAtMothDataSource(t *testing.T) {
+	data := writeTestFile(3000, NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100), metadata.ZLIB)
+	path := filepath.Join(t.TempDir(), "test.moth")
+	if err := os.WriteFile(path, data.AvailableBytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, options := range []*MothReaderOptions{NewMothReaderOptions(), NewMothReaderOptions().WithLazyReadSmallRanges(false), NewMothReaderOptions().WithMaxBufferSize(util.Ofds(1, util.KB))} {
+		source, err := OpenReaderAtMothDataSource(path, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recordReader := createRecordReader(source, options, TRUE)
+		ids := pageIds(t, recordReader.ReadRows(0, 3000).ToArray()...)
+		recordReader.Close()
+		if !reflect.DeepEqual(ids, idRange(0, 3000)) || source.GetReadBytes() == 0 {
+			t.Fatalf("read %d rows and %d bytes", len(ids), source.GetReadBytes())
+		}
+	}
+
+	if _, err := OpenReaderAtMothDataSource(filepath.Join(t.TempDir(), "missing.moth"), NewMothReaderOptions()); err == nil {
+		t.Fatal("opened a missing file")
+	}
+}
+
+func TestReaderAtMothDataSource_SharedFile(t *testing.T) {
+	data := writeTestFile(10000, NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100), metadata.ZLIB)
+	path := filepath.Join(t.TempDir(), "test.moth")
+	if err := os.WriteFile(path, data.AvailableBytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// every goroutine reads a split of the file through the one handle
+	source := NewReaderAtMothDataSource(common.NewMothDataSourceId(path), file, int64(data.Size()), NewMothReaderOptions())
+	var readers sync.WaitGroup
+	for split := int64(0); split < 10; split++ {
+		readers.Add(1)
+		go func(start int64) {
+			defer readers.Done()
+			recordReader := createRecordReader(source, NewMothReaderOptions(), TRUE)
+			defer recordReader.Close()
+			for row := start; row < start+1000; row += 250 {
+				if ids := pageIds(t, recordReader.ReadRows(row, 250).ToArray()...); !reflect.DeepEqual(ids, idRange(row, row+250)) {
+					t.Errorf("read rows %d to %d of split %d", ids[0], ids[len(ids)-1], start)
+				}
+			}
+		}(split * 1000)
+	}
+	readers.Wait()
+	if source.GetReadBytes() == 0 {
+		t.Fatal("counted no read bytes")
+	}
+}
+
+func TestReaderAtMothDataSource_ParallelRanges(t *testing.T) {
+	data := make([]byte, 1<<20)
+	for i := range data {
+		data[i] = byte(i * 31)
+	}
+	reader := &slowReaderAt{reader: bytes.NewReader(data), failAt: -1}
+	options := NewMothReaderOptions().WithLazyReadSmallRanges(false).WithMaxMergeDistance(util.Ofds(0, util.B))
+	source := NewReaderAtMothDataSource(common.NewMothDataSourceId("test"), reader, int64(len(data)), options)
+	diskRanges := make(map[StreamId]*DiskRange)
+	for column := uint32(0); column < 16; column++ {
+		diskRanges[NewStreamId(metadata.NewMothColumnId(column), metadata.DATA)] = NewDiskRange(int64(column)*50000, 1000+int32(column))
+	}
+
+	readers := source.ReadFully2(diskRanges)
+	if reader.maxReads < 2 || reader.maxReads > MAX_CONCURRENT_RANGE_READS {
+		t.Fatalf("read 16 ranges with %d parallel reads", reader.maxReads)
+	}
+	for streamId, diskRange := range diskRanges {
+		buffer := readers[streamId].SeekBuffer(0)
+		if !bytes.Equal(buffer.AvailableBytes()[:diskRange.GetLength()], data[diskRange.GetOffset():diskRange.GetEnd()]) {
+			t.Fatalf("read wrong bytes of %s", diskRange)
+		}
+	}
+	if source.GetReadBytes() != 16*1000+120 {
+		t.Fatalf("counted %d read bytes", source.GetReadBytes())
+	}
+
+	// a failed read of one range fails the whole read
+	reader.failAt = 150000
+	err := func() (err error) {
+		defer recoverMothError(&err, source.GetId())
+		source.ReadFully2(diskRanges)
+		return nil
+	}()
+	if err == nil {
+		t.Fatal("read ranges of a failing disk")
+	}
+}
+
+func TestReaderAtMothDataSource_ParallelLazyRanges(t *testing.T) {
+	data := make([]byte, 1<<20)
+	for i := range data {
+		data[i] = byte(i * 31)
+	}
+	reader := &slowReaderAt{reader: bytes.NewReader(data), failAt: -1}
+	// the small ranges are read lazily by default, the prefetch is opted in
+	options := NewMothReaderOptions().WithMaxMergeDistance(util.Ofds(0, util.B)).WithPrefetchLazyRanges(true)
+	source := NewReaderAtMothDataSource(common.NewMothDataSourceId("test"), reader, int64(len(data)), options)
+	diskRanges := make(map[StreamId]*DiskRange)
+	for column := uint32(0); column < 16; column++ {
+		diskRanges[NewStreamId(metadata.NewMothColumnId(column), metadata.DATA)] = NewDiskRange(int64(column)*50000, 1000+int32(column))
+	}
+
+	// the ranges are prefetched in parallel while they are read one by one
+	readers := source.ReadFully2(diskRanges)
+	start := time.Now()
+	// the buffers of the prefetched ranges are retained before they are read
+	for streamId, diskRange := range diskRanges {
+		if readers[streamId].GetRetainedSize() != int64(diskRange.GetLength()) {
+			t.Fatalf("retained %d bytes of prefetched %s", readers[streamId].GetRetainedSize(), diskRange)
+		}
+	}
+	for streamId, diskRange := range diskRanges {
+		buffer := readers[streamId].SeekBuffer(0)
+		if !bytes.Equal(buffer.AvailableBytes()[:diskRange.GetLength()], data[diskRange.GetOffset():diskRange.GetEnd()]) {
+			t.Fatalf("read wrong bytes of %s", diskRange)
+		}
+	}
+	// the reader may load a range that is not prefetched yet next to the prefetches
+	if reader.maxReads < 2 || reader.maxReads > MAX_CONCURRENT_RANGE_READS+1 || time.Since(start) >= 16*20*time.Millisecond {
+		t.Fatalf("read 16 lazy ranges with %d parallel reads in %s", reader.maxReads, time.Since(start))
+	}
+	// every range is read once, by its prefetch or by its reader
+	if source.GetReadBytes() != 16*1000+120 {
+		t.Fatalf("counted %d read bytes", source.GetReadBytes())
+	}
+
+	// a failed prefetch is raised by the reader of the range
+	source.prefetches.Wait()
+	reader.failAt = 150000
+	readers = source.ReadFully2(diskRanges)
+	err := func() (err error) {
+		defer recoverMothError(&err, source.GetId())
+		readers[NewStreamId(metadata.NewMothColumnId(3), metadata.DATA)].SeekBuffer(0)
+		return nil
+	}()
+	if err == nil {
+		t.Fatal("read a range of a failing disk")
+	}
+}
+
+func TestReaderAtMothDataSource_LazyRangesWithoutPrefetch(t *testing.T) {
+	data := make([]byte, 1<<20)
+	reader := &slowReaderAt{reader: bytes.NewReader(data), failAt: -1}
+	options := NewMothReaderOptions().WithMaxMergeDistance(util.Ofds(0, util.B))
+	source := NewReaderAtMothDataSource(common.NewMothDataSourceId("test"), reader, int64(len(data)), options)
+	diskRanges := make(map[StreamId]*DiskRange)
+	for column := uint32(0); column < 16; column++ {
+		diskRanges[NewStreamId(metadata.NewMothColumnId(column), metadata.DATA)] = NewDiskRange(int64(column)*50000, 1000)
+	}
+
+	// nothing is read or retained until a range is read
+	readers := source.ReadFully2(diskRanges)
+	time.Sleep(50 * time.Millisecond)
+	streamId := NewStreamId(metadata.NewMothColumnId(3), metadata.DATA)
+	if source.GetReadBytes() != 0 || readers[streamId].GetRetainedSize() != 0 {
+		t.Fatalf("read %d and retained %d bytes of unread lazy ranges", source.GetReadBytes(), readers[streamId].GetRetainedSize())
+	}
+	readers[streamId].SeekBuffer(0)
+	if source.GetReadBytes() != 1000 || readers[streamId].GetRetainedSize() != 1000 {
+		t.Fatalf("read %d and retained %d bytes of one lazy range", source.GetReadBytes(), readers[streamId].GetRetainedSize())
+	}
+}
+
+func TestReaderAtMothDataSource_CloseWaitsForPrefetches(t *testing.T) {
+	data := make([]byte, 1<<20)
+	reader := &slowReaderAt{reader: bytes.NewReader(data), failAt: -1}
+	options := NewMothReaderOptions().WithMaxMergeDistance(util.Ofds(0, util.B)).WithPrefetchLazyRanges(true)
+	source := NewReaderAtMothDataSource(common.NewMothDataSourceId("test"), reader, int64(len(data)), options)
+	diskRanges := make(map[StreamId]*DiskRange)
+	for column := uint32(0); column < 32; column++ {
+		diskRanges[NewStreamId(metadata.NewMothColumnId(column), metadata.DATA)] = NewDiskRange(int64(column)*30000, 1000)
+	}
+
+	source.ReadFully2(diskRanges)
+	source.Close()
+	// no prefetch runs after Close and the ranges that were not fetched yet are skipped
+	readBytes := source.GetReadBytes()
+	if reader.inFlight != 0 || readBytes == 32*1000 {
+		t.Fatalf("closed the data source with %d reads in flight and %d bytes read", reader.inFlight, readBytes)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if source.GetReadBytes() != readBytes {
+		t.Fatalf("read %d bytes after Close", source.GetReadBytes()-readBytes)
+	}
+
+	// a closed data source does not prefetch
+	readers := source.ReadFully2(diskRanges)
+	time.Sleep(50 * time.Millisecond)
+	if source.GetReadBytes() != readBytes || readers[NewStreamId(metadata.NewMothColumnId(0), metadata.DATA)].GetRetainedSize() != 0 {
+		t.Fatalf("prefetched %d bytes after Close", source.GetReadBytes()-readBytes)
+	}
+}
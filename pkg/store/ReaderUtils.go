@@ -1,10 +1,9 @@
 package store
 
 import (
-	"fmt"
-
 	"github.com/mothdb-bd/orc-go/pkg/maths"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
@@ -15,7 +14,7 @@ func VerifyStreamType(column *MothColumn, actual block.Type, validTypes util.Pre
 }
 
 func InvalidStreamType(column *MothColumn, kind block.Type) {
-	panic(fmt.Sprintf("Cannot read SQL type '%s' from MOTH stream '%s' of type %d with attributes %s", kind, column.GetPath(), column.GetColumnType(), column.GetAttributes()))
+	panic(common.NewMothUnsupportedError(column.GetMothDataSourceId(), "Cannot read SQL type '%s' from MOTH stream '%s' of type %d with attributes %s", kind, column.GetPath(), column.GetColumnType(), column.GetAttributes()))
 }
 
 func MinNonNullValueSize(nonNullCount int32) int32 {
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Replaces every character of a string with the mask character, so the masked value keeps the
+ * length of the value.
+ */
+type RedactColumnMask struct {
+	// 继承
+	ColumnMask
+
+	kind        sliceType
+	replacement string
+}
+
+func NewRedactColumnMask(kind sliceType, replacement string) *RedactColumnMask {
+	if utf8.RuneCountInString(replacement) != 1 || replacement == " " {
+		panic(fmt.Sprintf("Invalid redact mask character '%s'", replacement))
+	}
+	rk := new(RedactColumnMask)
+	rk.kind = kind
+	rk.replacement = replacement
+	return rk
+}
+
+// @Override
+func (rk *RedactColumnMask) Mask(b block.Block) block.Block {
+	builder := rk.kind.CreateBlockBuilder2(nil, b.GetPositionCount())
+	for position := util.INT32_ZERO; position < b.GetPositionCount(); position++ {
+		if b.IsNull(position) {
+			builder.AppendNull()
+			continue
+		}
+		value := rk.kind.GetSlice(b, position).String()
+		rk.kind.WriteSlice(builder, slice.NewWithString(strings.Repeat(rk.replacement, utf8.RuneCountInString(value))))
+	}
+	return builder.Build()
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"encoding/binary"
+
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Returns the key of a bigint, integer, smallint or tinyint key value. The value is encoded big
+ * endian with the sign bit flipped, so the keys compare like the values.
+ */
+func LongKey(value int64) *slice.Slice {
+	b := make([]byte, util.INT64_BYTES)
+	binary.BigEndian.PutUint64(b, uint64(value)^(1<<63))
+	return slice.NewWithBuf(b)
+}
+
+/**
+ * Returns if the values of the type can be used as row keys.
+ */
+func isRowKeyType(kind block.Type) bool {
+	switch kind.(type) {
+	case *block.VarcharType, *block.CharType, *block.VarbinaryType, *block.BigintType, *block.IntegerType, *block.SmallintType, *block.TinyintType:
+		return true
+	}
+	return false
+}
+
+/**
+ * Returns the key of a row, the bytes of a varchar, char or varbinary value and the LongKey of
+ * an integer value, nil when the key is null.
+ */
+func readRowKey(kind block.Type, keys block.Block, position int32) *slice.Slice {
+	if keys.IsNull(position) {
+		return nil
+	}
+	switch kind.(type) {
+	case *block.BigintType, *block.IntegerType, *block.SmallintType, *block.TinyintType:
+		return LongKey(kind.GetLong(keys, position))
+	}
+	return kind.GetSlice(keys, position)
+}
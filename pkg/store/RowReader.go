@@ -0,0 +1,100 @@
+package store
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/maths"
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Reads the rows of a moth file into Go structs of type T. The fields of T are matched by the
+ * column names of its RowSchema with the top level columns of the file, the other columns of
+ * the file are not read. The columns missing from the file, and the fields of nested structs
+ * missing from the file, are read as nulls, see SchemaEvolution.
+ */
+type RowReader[T any] struct {
+	schema        *RowSchema
+	recordReader  *MothRecordReader
+	blocks        []block.Block
+	positionCount int32
+	position      int32
+}
+
+func NewRowReader[T any](reader *MothReader) *RowReader[T] {
+	return NewRowReader2[T](reader, TRUE, memory.NewSimpleAggregatedMemoryContext())
+}
+
+func NewRowReader2[T any](reader *MothReader, predicate MothPredicate, memoryContext memory.AggregatedMemoryContext) *RowReader[T] {
+	rr := new(RowReader[T])
+	rr.schema = GetRowSchema[T]()
+	evolution := NewSchemaEvolution(reader, rr.schema.GetColumnNames(), rr.schema.GetTypes(), MAP_BY_NAME)
+	rr.recordReader = reader.CreateRecordReader3(evolution, predicate, time.UTC, memoryContext, INITIAL_BATCH_SIZE)
+	return rr
+}
+
+func (rr *RowReader[T]) GetSchema() *RowSchema {
+	return rr.schema
+}
+
+func (rr *RowReader[T]) GetRecordReader() *MothRecordReader {
+	return rr.recordReader
+}
+
+/**
+ * Decodes the next rows into rows and returns the number of rows decoded, 0 at the end of the
+ * file. Fewer rows than len(rows) are decoded when the current page of the record reader ends.
+ */
+func (rr *RowReader[T]) Read(rows []T) int {
+	if len(rows) == 0 {
+		return 0
+	}
+	for rr.position >= rr.positionCount {
+		page := rr.recordReader.NextPage()
+		if page == nil {
+			return 0
+		}
+		page = page.GetLoadedPage()
+		rr.blocks = make([]block.Block, page.GetChannelCount())
+		for channel := range rr.blocks {
+			rr.blocks[channel] = page.GetBlock(int32(channel))
+		}
+		rr.positionCount = page.GetPositionCount()
+		rr.position = 0
+	}
+	count := util.Int32Exact(maths.MinInt64s(int64(len(rows)), int64(rr.positionCount-rr.position)))
+	values := reflect.ValueOf(rows)
+	for i := util.INT32_ZERO; i < count; i++ {
+		rr.schema.readRow(rr.blocks, rr.position, values.Index(int(i)))
+		rr.position++
+	}
+	return int(count)
+}
+
+/**
+ * Like Read, but a failure to read the rows is returned as a *common.MothError instead of
+ * panicking. The reader can not be used after a failure.
+ */
+func (rr *RowReader[T]) TryRead(rows []T) (count int, err error) {
+	defer recoverMothError(&err, rr.recordReader.mothDataSource.GetId())
+	return rr.Read(rows), nil
+}
+
+/**
+ * Reads the remaining rows of the file.
+ */
+func (rr *RowReader[T]) ReadAll() []T {
+	rows := make([]T, 0)
+	buffer := make([]T, MAX_BATCH_SIZE)
+	for count := rr.Read(buffer); count > 0; count = rr.Read(buffer) {
+		rows = append(rows, buffer[:count]...)
+	}
+	return rows
+}
+
+func (rr *RowReader[T]) Close() {
+	rr.recordReader.Close()
+}
@@ -0,0 +1,398 @@
+package store
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+var (
+	ROW_SCHEMA_TAG string = "moth"
+
+	// precision and scale of *big.Int fields without a decimal option
+	DEFAULT_ROW_DECIMAL_PRECISION int32 = 38
+	DEFAULT_ROW_DECIMAL_SCALE     int32 = 0
+
+	timeType   = reflect.TypeOf(time.Time{})
+	bigIntType = reflect.TypeOf(big.Int{})
+	bytesType  = reflect.TypeOf([]byte{})
+
+	decimalOptionPattern = regexp.MustCompile(`^decimal\((\d+),\s*(\d+)\)$`)
+
+	rowSchemas sync.Map
+)
+
+/**
+ * Moth schema of a Go struct, the exported fields are the columns of the file. The column name
+ * is taken from the moth tag of the field, e.g. `moth:"name"`, and defaults to the field name,
+ * fields tagged with `moth:"-"` are not stored. The types are mapped as follows:
+ *
+ *   bool                       BOOLEAN
+ *   int8, int16, int32         TINYINT, SMALLINT, INTEGER
+ *   int, int64                 BIGINT
+ *   float32, float64           REAL, DOUBLE
+ *   string, []byte             VARCHAR, VARBINARY
+ *   time.Time                  TIMESTAMP with microseconds, DATE with the date option
+ *   big.Int                    DECIMAL(38, 0), other precisions with the decimal(p,s) option
+ *   struct                     ROW of its fields
+ *   slice                      ARRAY of its elements
+ *   map                        MAP of its keys and values
+ *
+ * A pointer is stored as the value it points to, nil pointers, slices and maps are stored as
+ * null. The options follow the column name, e.g. `moth:"price,decimal(20,2)"` stores the
+ * unscaled value of the big.Int as a DECIMAL(20, 2) and `moth:"day,date"` stores the date of a
+ * time.Time.
+ */
+type RowSchema struct {
+	goType      reflect.Type
+	fields      []*rowField
+	columnNames *util.ArrayList[string]
+	types       *util.ArrayList[block.Type]
+}
+
+type rowField struct {
+	name    string
+	index   int
+	mapping *rowMapping
+}
+
+/**
+ * Mapping of a Go type to a block type, the pointers in front of the Go type are dereferenced.
+ */
+type rowMapping struct {
+	goType   reflect.Type
+	pointers int
+	kind     block.Type
+	date     bool
+	element  *rowMapping
+	key      *rowMapping
+	fields   []*rowField
+}
+
+/**
+ * Returns the schema of the struct type T, the schemas are cached per type.
+ */
+func GetRowSchema[T any]() *RowSchema {
+	return GetRowSchemaOf(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func GetRowSchemaOf(goType reflect.Type) *RowSchema {
+	if schema, ok := rowSchemas.Load(goType); ok {
+		return schema.(*RowSchema)
+	}
+	schema, _ := rowSchemas.LoadOrStore(goType, NewRowSchema(goType))
+	return schema.(*RowSchema)
+}
+
+func NewRowSchema(goType reflect.Type) *RowSchema {
+	util.CheckArgument2(goType.Kind() == reflect.Struct, fmt.Sprintf("%s is not a struct", goType))
+	ra := new(RowSchema)
+	ra.goType = goType
+	ra.fields = createRowFields(goType, nil)
+	util.CheckArgument2(len(ra.fields) > 0, fmt.Sprintf("%s has no stored fields", goType))
+	ra.columnNames = util.NewArrayList[string]()
+	ra.types = util.NewArrayList[block.Type]()
+	for _, field := range ra.fields {
+		ra.columnNames.Add(field.name)
+		ra.types.Add(field.mapping.kind)
+	}
+	return ra
+}
+
+func createRowFields(goType reflect.Type, seen []reflect.Type) []*rowField {
+	for _, seenType := range seen {
+		if seenType == goType {
+			panic(fmt.Sprintf("%s is recursive", goType))
+		}
+	}
+	seen = append(seen, goType)
+	fields := make([]*rowField, 0, goType.NumField())
+	names := make(map[string]bool)
+	for i := 0; i < goType.NumField(); i++ {
+		structField := goType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		tag := structField.Tag.Get(ROW_SCHEMA_TAG)
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = structField.Name
+		}
+		if names[name] {
+			panic(fmt.Sprintf("Duplicate column %s in %s", name, goType))
+		}
+		names[name] = true
+		fields = append(fields, &rowField{name: name, index: i, mapping: createRowMapping(structField.Type, options, seen)})
+	}
+	return fields
+}
+
+func createRowMapping(goType reflect.Type, options string, seen []reflect.Type) *rowMapping {
+	rg := new(rowMapping)
+	for goType.Kind() == reflect.Pointer {
+		goType = goType.Elem()
+		rg.pointers++
+	}
+	rg.goType = goType
+	switch {
+	case goType == timeType:
+		rg.date = options == "date"
+		rg.kind = util.Ternary[block.Type](rg.date, block.DATE, block.TIMESTAMP_MICROS)
+		return rg
+	case goType == bigIntType:
+		precision, scale := DEFAULT_ROW_DECIMAL_PRECISION, DEFAULT_ROW_DECIMAL_SCALE
+		if match := decimalOptionPattern.FindStringSubmatch(options); match != nil {
+			p, _ := strconv.Atoi(match[1])
+			s, _ := strconv.Atoi(match[2])
+			precision, scale = int32(p), int32(s)
+		}
+		rg.kind = block.CreateDecimalType(precision, scale)
+		return rg
+	case goType == bytesType:
+		rg.kind = block.VARBINARY
+		return rg
+	}
+	switch goType.Kind() {
+	case reflect.Bool:
+		rg.kind = block.BOOLEAN
+	case reflect.Int8:
+		rg.kind = block.TINYINT
+	case reflect.Int16:
+		rg.kind = block.SMALLINT
+	case reflect.Int32:
+		rg.kind = block.INTEGER
+	case reflect.Int, reflect.Int64:
+		rg.kind = block.BIGINT
+	case reflect.Float32:
+		rg.kind = block.REAL
+	case reflect.Float64:
+		rg.kind = block.DOUBLE
+	case reflect.String:
+		rg.kind = block.VARCHAR
+	case reflect.Slice:
+		rg.element = createRowMapping(goType.Elem(), "", seen)
+		rg.kind = block.NewArrayType(rg.element.kind)
+	case reflect.Map:
+		rg.key = createRowMapping(goType.Key(), "", seen)
+		rg.element = createRowMapping(goType.Elem(), "", seen)
+		rg.kind = block.NewMapType(rg.key.kind, rg.element.kind)
+	case reflect.Struct:
+		rg.fields = createRowFields(goType, seen)
+		util.CheckArgument2(len(rg.fields) > 0, fmt.Sprintf("%s has no stored fields", goType))
+		fields := util.NewArrayList[*block.Field]()
+		for _, field := range rg.fields {
+			fields.Add(block.CreateField(field.name, field.mapping.kind))
+		}
+		rg.kind = block.From(fields)
+	default:
+		panic(fmt.Sprintf("Unsupported field type %s", goType))
+	}
+	return rg
+}
+
+func (ra *RowSchema) GetGoType() reflect.Type {
+	return ra.goType
+}
+
+func (ra *RowSchema) GetColumnNames() *util.ArrayList[string] {
+	return ra.columnNames
+}
+
+func (ra *RowSchema) GetTypes() *util.ArrayList[block.Type] {
+	return ra.types
+}
+
+func (ra *RowSchema) GetMothTypes() *metadata.ColumnMetadata[*metadata.MothType] {
+	return metadata.CreateRootMothType(ra.columnNames, ra.types)
+}
+
+/**
+ * Appends the fields of the struct row to the block builders of its columns.
+ */
+func (ra *RowSchema) writeRow(row reflect.Value, blockBuilders func(channel int32) block.BlockBuilder) {
+	for channel, field := range ra.fields {
+		field.mapping.writeValue(blockBuilders(int32(channel)), row.Field(field.index))
+	}
+}
+
+/**
+ * Sets the fields of the struct row to the values of the position of the blocks of its columns.
+ */
+func (ra *RowSchema) readRow(blocks []block.Block, position int32, row reflect.Value) {
+	for channel, field := range ra.fields {
+		field.mapping.readValue(blocks[channel], position, row.Field(field.index))
+	}
+}
+
+func (rg *rowMapping) writeValue(blockBuilder block.BlockBuilder, value reflect.Value) {
+	for i := 0; i < rg.pointers; i++ {
+		if value.IsNil() {
+			blockBuilder.AppendNull()
+			return
+		}
+		value = value.Elem()
+	}
+	switch {
+	case rg.goType == timeType:
+		t := value.Interface().(time.Time)
+		if rg.date {
+			days := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+			rg.kind.WriteLong(blockBuilder, days)
+		} else {
+			rg.kind.WriteLong(blockBuilder, t.UnixMicro())
+		}
+		return
+	case rg.goType == bigIntType:
+		var unscaled *big.Int
+		if value.CanAddr() {
+			unscaled = value.Addr().Interface().(*big.Int)
+		} else {
+			// map values can not be addressed
+			copied := value.Interface().(big.Int)
+			unscaled = &copied
+		}
+		if decimalType := rg.kind.(block.IDecimalType); decimalType.IsShort() {
+			util.CheckArgument2(unscaled.IsInt64(), fmt.Sprintf("%s does not fit %s", unscaled, rg.kind.GetDisplayName()))
+			rg.kind.WriteLong(blockBuilder, unscaled.Int64())
+		} else {
+			decimal, accurate := block.I128FromBigInt(unscaled)
+			util.CheckArgument2(accurate, fmt.Sprintf("%s does not fit %s", unscaled, rg.kind.GetDisplayName()))
+			rg.kind.WriteObject(blockBuilder, decimal)
+		}
+		return
+	case rg.goType == bytesType:
+		if value.IsNil() {
+			blockBuilder.AppendNull()
+		} else {
+			block.WriteNativeValue(rg.kind, blockBuilder, value.Bytes())
+		}
+		return
+	}
+	switch rg.goType.Kind() {
+	case reflect.Bool:
+		rg.kind.WriteBoolean(blockBuilder, value.Bool())
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64:
+		rg.kind.WriteLong(blockBuilder, value.Int())
+	case reflect.Float32:
+		rg.kind.WriteLong(blockBuilder, int64(math.Float32bits(float32(value.Float()))))
+	case reflect.Float64:
+		rg.kind.WriteDouble(blockBuilder, value.Float())
+	case reflect.String:
+		s, _ := slice.NewByString(value.String())
+		rg.kind.WriteSlice2(blockBuilder, s, 0, int32(s.Size()))
+	case reflect.Slice:
+		if value.IsNil() {
+			blockBuilder.AppendNull()
+			return
+		}
+		entryBuilder := blockBuilder.BeginBlockEntry()
+		for i := 0; i < value.Len(); i++ {
+			rg.element.writeValue(entryBuilder, value.Index(i))
+		}
+		blockBuilder.CloseEntry()
+	case reflect.Map:
+		if value.IsNil() {
+			blockBuilder.AppendNull()
+			return
+		}
+		entryBuilder := blockBuilder.BeginBlockEntry()
+		entries := value.MapRange()
+		for entries.Next() {
+			rg.key.writeValue(entryBuilder, entries.Key())
+			rg.element.writeValue(entryBuilder, entries.Value())
+		}
+		blockBuilder.CloseEntry()
+	case reflect.Struct:
+		entryBuilder := blockBuilder.BeginBlockEntry()
+		for _, field := range rg.fields {
+			field.mapping.writeValue(entryBuilder, value.Field(field.index))
+		}
+		blockBuilder.CloseEntry()
+	}
+}
+
+/**
+ * Sets value to the value at the position of the block. A null sets value to its zero value,
+ * which is nil for pointers, slices and maps.
+ */
+func (rg *rowMapping) readValue(b block.Block, position int32, value reflect.Value) {
+	if b.IsNull(position) {
+		value.Set(reflect.Zero(value.Type()))
+		return
+	}
+	// the values are not shared with the rows read before, which may be reused by the caller
+	for i := 0; i < rg.pointers; i++ {
+		value.Set(reflect.New(value.Type().Elem()))
+		value = value.Elem()
+	}
+	switch {
+	case rg.goType == timeType:
+		if rg.date {
+			value.Set(reflect.ValueOf(time.Unix(rg.kind.GetLong(b, position)*86400, 0).UTC()))
+		} else {
+			value.Set(reflect.ValueOf(time.UnixMicro(rg.kind.GetLong(b, position)).UTC()))
+		}
+		return
+	case rg.goType == bigIntType:
+		unscaled := new(big.Int)
+		if decimalType := rg.kind.(block.IDecimalType); decimalType.IsShort() {
+			unscaled.SetInt64(rg.kind.GetLong(b, position))
+		} else {
+			rg.kind.GetObject(b, position).(*block.Int128).IntoBigInt(unscaled)
+		}
+		value.Set(reflect.ValueOf(unscaled).Elem())
+		return
+	case rg.goType == bytesType:
+		value.SetBytes(rg.kind.GetSlice(b, position).AvailableBytes())
+		return
+	}
+	switch rg.goType.Kind() {
+	case reflect.Bool:
+		value.SetBool(rg.kind.GetBoolean(b, position))
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64:
+		value.SetInt(rg.kind.GetLong(b, position))
+	case reflect.Float32:
+		value.SetFloat(float64(math.Float32frombits(uint32(rg.kind.GetLong(b, position)))))
+	case reflect.Float64:
+		value.SetFloat(rg.kind.GetDouble(b, position))
+	case reflect.String:
+		value.SetString(rg.kind.GetSlice(b, position).String())
+	case reflect.Slice:
+		elements := rg.kind.GetObject(b, position).(block.Block)
+		values := reflect.MakeSlice(value.Type(), int(elements.GetPositionCount()), int(elements.GetPositionCount()))
+		for i := util.INT32_ZERO; i < elements.GetPositionCount(); i++ {
+			rg.element.readValue(elements, i, values.Index(int(i)))
+		}
+		value.Set(values)
+	case reflect.Map:
+		entries := rg.kind.GetObject(b, position).(block.Block)
+		values := reflect.MakeMapWithSize(value.Type(), int(entries.GetPositionCount()/2))
+		for i := util.INT32_ZERO; i < entries.GetPositionCount(); i += 2 {
+			key := reflect.New(value.Type().Key()).Elem()
+			rg.key.readValue(entries, i, key)
+			element := reflect.New(value.Type().Elem()).Elem()
+			rg.element.readValue(entries, i+1, element)
+			values.SetMapIndex(key, element)
+		}
+		value.Set(values)
+	case reflect.Struct:
+		fields := rg.kind.GetObject(b, position).(block.Block)
+		for i, field := range rg.fields {
+			field.mapping.readValue(fields, int32(i), value.Field(field.index))
+		}
+	}
+}
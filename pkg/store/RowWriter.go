@@ -0,0 +1,90 @@
+package store
+
+import (
+	"reflect"
+
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+var ROW_WRITER_MAX_PAGE_ROWS int32 = 1024
+
+/**
+ * Writes Go structs of type T as the rows of a moth file, the columns are described by the
+ * RowSchema of T. The rows are buffered into pages of up to ROW_WRITER_MAX_PAGE_ROWS rows for the
+ * MothWriter.
+ */
+type RowWriter[T any] struct {
+	schema      *RowSchema
+	writer      *MothWriter
+	pageBuilder *spi.PageBuilder
+}
+
+func NewRowWriter[T any](mothDataSink MothDataSink, compression metadata.CompressionKind, options *MothWriterOptions) *RowWriter[T] {
+	return NewRowWriter2[T](mothDataSink, compression, options, util.EmptyMap[string, string](), NewMothWriterStats())
+}
+
+func NewRowWriter2[T any](mothDataSink MothDataSink, compression metadata.CompressionKind, options *MothWriterOptions, userMetadata map[string]string, stats *MothWriterStats) *RowWriter[T] {
+	rr := new(RowWriter[T])
+	rr.schema = GetRowSchema[T]()
+	rr.writer = NewMothWriter(mothDataSink, rr.schema.GetColumnNames(), rr.schema.GetTypes(), rr.schema.GetMothTypes(), compression, options, userMetadata, stats)
+	rr.pageBuilder = spi.NewPageBuilder(rr.schema.GetTypes())
+	return rr
+}
+
+func (rr *RowWriter[T]) GetSchema() *RowSchema {
+	return rr.schema
+}
+
+func (rr *RowWriter[T]) GetWriter() *MothWriter {
+	return rr.writer
+}
+
+func (rr *RowWriter[T]) Write(rows []T) {
+	values := reflect.ValueOf(rows)
+	for i := 0; i < values.Len(); i++ {
+		rr.pageBuilder.DeclarePosition()
+		rr.schema.writeRow(values.Index(i), rr.pageBuilder.GetBlockBuilder)
+		if rr.pageBuilder.IsFull() || rr.pageBuilder.GetPositionCount() >= ROW_WRITER_MAX_PAGE_ROWS {
+			rr.Flush()
+		}
+	}
+}
+
+/**
+ * Like Write, but a failure to write the rows is returned as a *common.MothError instead of
+ * panicking. The writer can not be used after a failure, other than to be closed.
+ */
+func (rr *RowWriter[T]) TryWrite(rows []T) (err error) {
+	defer recoverMothError(&err, nil)
+	rr.Write(rows)
+	return nil
+}
+
+/**
+ * Passes the buffered rows to the MothWriter.
+ */
+func (rr *RowWriter[T]) Flush() {
+	if rr.pageBuilder.IsEmpty() {
+		return
+	}
+	page := rr.pageBuilder.Build()
+	rr.pageBuilder = spi.NewPageBuilder(rr.schema.GetTypes())
+	rr.writer.Write(page)
+}
+
+func (rr *RowWriter[T]) Close() {
+	defer rr.writer.Close()
+	rr.Flush()
+}
+
+/**
+ * Like Close, but a failure to write the file is returned as a *common.MothError instead of
+ * panicking.
+ */
+func (rr *RowWriter[T]) TryClose() (err error) {
+	defer recoverMothError(&err, nil)
+	rr.Close()
+	return nil
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+type testAddress struct {
+	Street string `moth:"street"`
+	Number int32  `moth:"number"`
+}
+
+type testOrder struct {
+	Id       int64             `moth:"id"`
+	Customer string            `moth:"customer"`
+	Note     *string           `moth:"note"`
+	Quantity int16             `moth:"quantity"`
+	Flags    int8              `moth:"flags"`
+	Paid     bool              `moth:"paid"`
+	Weight   float32           `moth:"weight"`
+	Price    float64           `moth:"price"`
+	Total    *big.Int          `moth:"total,decimal(12,2)"`
+	Balance  big.Int           `moth:"balance,decimal(30,4)"`
+	Created  time.Time         `moth:"created"`
+	Day      time.Time         `moth:"day,date"`
+	Payload  []byte            `moth:"payload"`
+	Address  testAddress       `moth:"address"`
+	Billing  *testAddress      `moth:"billing"`
+	Items    []int64           `moth:"items"`
+	Counts   map[int32]float64 `moth:"counts"`
+	Tags     []string          `moth:"tags"`
+	Labels   map[string]int32  `moth:"labels"`
+	Ignored  string            `moth:"-"`
+	internal string
+}
+
+func newTestOrder(id int) testOrder {
+	order := testOrder{
+		Id:       int64(id),
+		Customer: fmt.Sprintf("customer-%05d", id),
+		Quantity: int16(id % 1000),
+		Flags:    int8(id % 100),
+		Paid:     id%2 == 0,
+		Weight:   float32(id) / 8,
+		Price:    float64(id) / 4,
+		Total:    big.NewInt(int64(id) * 1001),
+		Created:  time.UnixMicro(1_600_000_000_123_456 + int64(id)).UTC(),
+		Day:      time.Date(2022, 3, 1+id%28, 0, 0, 0, 0, time.UTC),
+		Payload:  []byte{byte(id), byte(id >> 8)},
+		Address:  testAddress{Street: fmt.Sprintf("street-%d", id), Number: int32(id)},
+		Items:    []int64{},
+		Counts:   map[int32]float64{},
+		Tags:     []string{},
+		Labels:   map[string]int32{},
+	}
+	order.Balance.Mul(big.NewInt(int64(id)), new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil))
+	if id%3 == 0 {
+		note := fmt.Sprintf("note-%d", id)
+		order.Note = &note
+		order.Billing = &testAddress{Street: "billing", Number: int32(-id)}
+	}
+	for i := 0; i < id%4; i++ {
+		order.Items = append(order.Items, int64(id*10+i))
+		order.Counts[int32(i)] = float64(id) + float64(i)/2
+		order.Tags = append(order.Tags, fmt.Sprintf("tag-%d", i))
+		order.Labels[fmt.Sprintf("label-%d", i)] = int32(id + i)
+	}
+	if id%7 == 0 {
+		order.Total = nil
+		order.Payload = nil
+		order.Items = nil
+		order.Counts = nil
+	}
+	return order
+}
+
+func TestRowSchema(t *testing.T) {
+	schema := GetRowSchema[testOrder]()
+	names := []string{"id", "customer", "note", "quantity", "flags", "paid", "weight", "price", "total", "balance", "created", "day", "payload", "address", "billing", "items", "counts", "tags", "labels"}
+	if got := schema.GetColumnNames().ToArray(); !reflect.DeepEqual(got, names) {
+		t.Fatalf("got columns %v, want %v", got, names)
+	}
+	address := block.From(util.NewArrayList(block.CreateField("street", block.VARCHAR), block.CreateField("number", block.INTEGER)))
+	types := []block.Type{block.BIGINT, block.VARCHAR, block.VARCHAR, block.SMALLINT, block.TINYINT, block.BOOLEAN, block.REAL, block.DOUBLE, block.CreateDecimalType(12, 2), block.CreateDecimalType(30, 4), block.TIMESTAMP_MICROS, block.DATE, block.VARBINARY, address, address, block.NewArrayType(block.BIGINT), block.NewMapType(block.INTEGER, block.DOUBLE), block.NewArrayType(block.VARCHAR), block.NewMapType(block.VARCHAR, block.INTEGER)}
+	if got := schema.GetTypes().ToArray(); !reflect.DeepEqual(got, types) {
+		t.Fatalf("got types %v, want %v", got, types)
+	}
+	if GetRowSchema[testOrder]() != schema {
+		t.Fatal("the schema is not cached")
+	}
+
+	type unsupported struct {
+		Value uint64
+	}
+	defer func() {
+		if failure := recover(); failure == nil {
+			t.Fatal("created a schema for an unsupported field")
+		}
+	}()
+	GetRowSchema[unsupported]()
+}
+
+func TestRowWriter_RoundTrip(t *testing.T) {
+	orders := make([]testOrder, 0)
+	for id := 0; id < 2500; id++ {
+		orders = append(orders, newTestOrder(id))
+	}
+	out := new(memoryWriteCloser)
+	writer := NewRowWriter[testOrder](NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), metadata.ZLIB, NewMothWriterOptions().WithRowGroupMaxRowCount(1000))
+	if err := writer.TryWrite(orders[:1700]); err != nil {
+		t.Fatal(err)
+	}
+	writer.Write(orders[1700:])
+	if err := writer.TryClose(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), slice.NewWithBuf(out.Bytes())), NewMothReaderOptions()).Get()
+	rowReader := NewRowReader[testOrder](reader)
+	defer rowReader.Close()
+	got := rowReader.ReadAll()
+	if len(got) != len(orders) {
+		t.Fatalf("read %d rows, want %d", len(got), len(orders))
+	}
+	for i := range orders {
+		if !reflect.DeepEqual(got[i], orders[i]) {
+			t.Fatalf("read %+v, want %+v", got[i], orders[i])
+		}
+	}
+
+	// a struct with a subset of the columns in another order
+	type orderTotal struct {
+		Total *big.Int `moth:"total,decimal(12,2)"`
+		Id    int64    `moth:"id"`
+	}
+	totalReader := NewRowReader[orderTotal](CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), slice.NewWithBuf(out.Bytes())), NewMothReaderOptions()).Get())
+	defer totalReader.Close()
+	rows := make([]orderTotal, 100)
+	count, err := totalReader.TryRead(rows)
+	if err != nil || count == 0 || rows[count-1].Id != int64(count-1) {
+		t.Fatalf("read %d rows ending with %+v, %v", count, rows[count-1], err)
+	}
+}
@@ -0,0 +1,234 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * How the columns and struct fields of a requested schema are matched with the columns of a file.
+ */
+type ColumnMappingMode int8
+
+const (
+	/**
+	 * Columns and fields are matched by their names, ignoring the case.
+	 */
+	MAP_BY_NAME ColumnMappingMode = iota
+
+	/**
+	 * Columns and fields are matched by their position, renamed columns are still found but
+	 * columns can only be dropped and added at the end.
+	 */
+	MAP_BY_POSITION
+)
+
+func (me ColumnMappingMode) String() string {
+	switch me {
+	case MAP_BY_NAME:
+		return "MAP_BY_NAME"
+	case MAP_BY_POSITION:
+		return "MAP_BY_POSITION"
+	}
+	panic(fmt.Sprintf("Unexpected value: %d", me))
+}
+
+/**
+ * Reconciles a requested schema with the schema of a file written with an older or newer version
+ * of the schema. The columns and struct fields are matched by name, by position or by field id,
+ * the requested columns and fields missing from the file are read as nulls, and the file types are
+ * widened to the requested types:
+ *
+ * tinyint to smallint, integer or bigint, smallint to integer or bigint, integer to bigint, real to
+ * double, a decimal to a decimal with at least as many integer and fraction digits, and any
+ * varchar or char length.
+ *
+ * Other type changes fail when the schema evolution is created, instead of when the columns are
+ * read.
+ */
+type SchemaEvolution struct {
+	types              *metadata.ColumnMetadata[*metadata.MothType]
+	readColumns        *util.ArrayList[*MothColumn]
+	readTypes          *util.ArrayList[block.Type]
+	fieldNames         map[metadata.MothColumnId][]string
+	fieldMapperFactory FieldMapperFactory
+}
+
+func NewSchemaEvolution(reader *MothReader, columnNames *util.ArrayList[string], readTypes *util.ArrayList[block.Type], mappingMode ColumnMappingMode) *SchemaEvolution {
+	fieldNames := make(map[metadata.MothColumnId][]string)
+	if mappingMode == MAP_BY_POSITION {
+		return newSchemaEvolution(reader, columnNames, readTypes, fieldNames, NewPositionBasedFieldMapperFactory(fieldNames))
+	}
+	return newSchemaEvolution(reader, columnNames, readTypes, fieldNames, NewFieldMapperFactory())
+}
+
+/**
+ * Creates a schema evolution matching the columns and struct fields with the mappers of
+ * fieldMapperFactory, such as the IdBasedFieldMapperFactory of a table with field ids.
+ */
+func NewSchemaEvolution2(reader *MothReader, columnNames *util.ArrayList[string], readTypes *util.ArrayList[block.Type], fieldMapperFactory FieldMapperFactory) *SchemaEvolution {
+	return newSchemaEvolution(reader, columnNames, readTypes, make(map[metadata.MothColumnId][]string), fieldMapperFactory)
+}
+
+func newSchemaEvolution(reader *MothReader, columnNames *util.ArrayList[string], readTypes *util.ArrayList[block.Type], fieldNames map[metadata.MothColumnId][]string, fieldMapperFactory FieldMapperFactory) *SchemaEvolution {
+	util.CheckArgument2(columnNames.Size() == readTypes.Size(), "columnNames and readTypes must have the same size")
+	sn := new(SchemaEvolution)
+	sn.types = reader.GetFooter().GetTypes()
+	sn.readTypes = readTypes
+	sn.fieldNames = fieldNames
+	sn.fieldMapperFactory = fieldMapperFactory
+
+	root := reader.GetRootColumn()
+	names := make([]string, columnNames.Size())
+	for i, columnName := range columnNames.ToArray() {
+		names[i] = strings.ToLower(columnName)
+	}
+	sn.fieldNames[root.GetColumnId()] = names
+	rootMapper := sn.fieldMapperFactory.Create(root)
+	sn.readColumns = util.NewArrayList[*MothColumn]()
+	for i, name := range names {
+		column := rootMapper.Get(name)
+		if column != nil {
+			sn.resolve(column, readTypes.Get(i))
+		}
+		sn.readColumns.Add(column)
+	}
+	return sn
+}
+
+/**
+ * Checks that the column can be read as kind, and records the requested field names of the
+ * struct columns.
+ */
+func (sn *SchemaEvolution) resolve(column *MothColumn, kind block.Type) {
+	switch column.GetColumnType() {
+	case metadata.STRUCT:
+		rowType, flag := kind.(*block.RowType)
+		if !flag {
+			sn.invalidEvolution(column, kind)
+		}
+		fields := rowType.GetFields().ToArray()
+		names := make([]string, len(fields))
+		for i, field := range fields {
+			names[i] = strings.ToLower(field.GetName().OrElseThrow(fmt.Sprintf("ROW type does not have field names declared: %s", kind)))
+		}
+		sn.fieldNames[column.GetColumnId()] = names
+		fieldMapper := sn.fieldMapperFactory.Create(column)
+		for i, field := range fields {
+			fieldColumn := fieldMapper.Get(names[i])
+			if fieldColumn != nil {
+				sn.resolve(fieldColumn, field.GetType())
+			}
+		}
+	case metadata.LIST:
+		arrayType, flag := kind.(*block.ArrayType)
+		if !flag {
+			sn.invalidEvolution(column, kind)
+		}
+		sn.resolve(column.GetNestedColumns().Get(0), arrayType.GetElementType())
+	case metadata.MAP:
+		mapType, flag := kind.(*block.MapType)
+		if !flag {
+			sn.invalidEvolution(column, kind)
+		}
+		sn.resolve(column.GetNestedColumns().Get(0), mapType.GetKeyType())
+		sn.resolve(column.GetNestedColumns().Get(1), mapType.GetValueType())
+	default:
+		if !sn.canReadAs(column, kind) {
+			sn.invalidEvolution(column, kind)
+		}
+	}
+}
+
+func (sn *SchemaEvolution) canReadAs(column *MothColumn, kind block.Type) bool {
+	switch column.GetColumnType() {
+	case metadata.BOOLEAN:
+		_, flag := kind.(*block.BooleanType)
+		return flag
+	case metadata.BYTE:
+		switch kind.(type) {
+		case *block.TinyintType, *block.SmallintType, *block.IntegerType, *block.BigintType:
+			return true
+		}
+	case metadata.SHORT:
+		switch kind.(type) {
+		case *block.SmallintType, *block.IntegerType, *block.BigintType:
+			return true
+		}
+	case metadata.INT:
+		switch kind.(type) {
+		case *block.IntegerType, *block.BigintType:
+			return true
+		}
+	case metadata.LONG:
+		switch kind.(type) {
+		case *block.BigintType, *block.TimeType:
+			return true
+		}
+	case metadata.DATE:
+		_, flag := kind.(*block.DateType)
+		return flag
+	case metadata.FLOAT:
+		switch kind.(type) {
+		case *block.RealType, *block.DoubleType:
+			return true
+		}
+	case metadata.DOUBLE:
+		_, flag := kind.(*block.DoubleType)
+		return flag
+	case metadata.STRING, metadata.VARCHAR, metadata.CHAR:
+		switch kind.(type) {
+		case *block.VarcharType, *block.CharType:
+			return true
+		}
+	case metadata.BINARY:
+		_, flag := kind.(*block.VarbinaryType)
+		return flag
+	case metadata.DECIMAL:
+		decimalType, flag := kind.(block.IDecimalType)
+		if !flag {
+			return false
+		}
+		mothType := sn.types.Get(column.GetColumnId())
+		if mothType.GetPrecision().IsEmpty() || mothType.GetScale().IsEmpty() {
+			return true
+		}
+		precision, scale := mothType.GetPrecision().Get(), mothType.GetScale().Get()
+		return decimalType.GetScale() >= scale && decimalType.GetPrecision()-decimalType.GetScale() >= precision-scale
+	default:
+		// timestamps and unions are checked by their column readers
+		return true
+	}
+	return false
+}
+
+func (sn *SchemaEvolution) invalidEvolution(column *MothColumn, kind block.Type) {
+	panic(common.NewMothUnsupportedError(column.GetMothDataSourceId(), "Cannot read column '%s' of type %s as %s", column.GetPath(), column.GetColumnType(), kind.GetDisplayName()))
+}
+
+/**
+ * Returns the file columns of the requested columns, nil for the columns missing from the file.
+ */
+func (sn *SchemaEvolution) GetReadColumns() *util.ArrayList[*MothColumn] {
+	return sn.readColumns
+}
+
+func (sn *SchemaEvolution) GetReadTypes() *util.ArrayList[block.Type] {
+	return sn.readTypes
+}
+
+func (sn *SchemaEvolution) GetFieldMapperFactory() FieldMapperFactory {
+	return sn.fieldMapperFactory
+}
+
+/**
+ * Returns if the requested column is missing from the file and is read as nulls.
+ */
+func (sn *SchemaEvolution) IsMissing(column int) bool {
+	return sn.readColumns.Get(column) == nil
+}
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+type evolutionPointV1 struct {
+	X int32 `moth:"x"`
+}
+
+type evolutionRowV1 struct {
+	Id      int8               `moth:"id"`
+	Count   int32              `moth:"count"`
+	Ratio   float32            `moth:"ratio"`
+	Amount  *big.Int           `moth:"amount,decimal(10,2)"`
+	Name    string             `moth:"name"`
+	Dropped string             `moth:"dropped"`
+	Point   evolutionPointV1   `moth:"point"`
+	Points  []evolutionPointV1 `moth:"points"`
+}
+
+type evolutionPointV2 struct {
+	X int64  `moth:"x"`
+	Y *int64 `moth:"y"`
+}
+
+type evolutionRowV2 struct {
+	Name   string             `moth:"name"`
+	Id     int64              `moth:"id"`
+	Count  int64              `moth:"count"`
+	Ratio  float64            `moth:"ratio"`
+	Amount *big.Int           `moth:"amount,decimal(20,4)"`
+	Added  *string            `moth:"added"`
+	Point  evolutionPointV2   `moth:"point"`
+	Points []evolutionPointV2 `moth:"points"`
+}
+
+func writeEvolutionFile(t *testing.T, rowCount int) []byte {
+	rows := make([]evolutionRowV1, rowCount)
+	for i := range rows {
+		rows[i] = evolutionRowV1{
+			Id:      int8(i - 64),
+			Count:   int32(i * 1000),
+			Ratio:   float32(i) / 4,
+			Amount:  big.NewInt(int64(i) * 101),
+			Name:    "name-" + string(rune('a'+i%26)),
+			Dropped: "dropped",
+			Point:   evolutionPointV1{X: int32(-i)},
+			Points:  []evolutionPointV1{{X: int32(i)}, {X: int32(i + 1)}},
+		}
+		if i%5 == 0 {
+			rows[i].Amount = nil
+		}
+	}
+	out := new(memoryWriteCloser)
+	writer := NewRowWriter[evolutionRowV1](NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), metadata.ZLIB, NewMothWriterOptions())
+	writer.Write(rows)
+	if err := writer.TryClose(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func openEvolutionFile(data []byte) *MothReader {
+	return CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("evolution"), slice.NewWithBuf(data)), NewMothReaderOptions()).Get()
+}
+
+func TestSchemaEvolution_ByName(t *testing.T) {
+	data := writeEvolutionFile(t, 100)
+	rowReader := NewRowReader[evolutionRowV2](openEvolutionFile(data))
+	defer rowReader.Close()
+	rows := rowReader.ReadAll()
+	if len(rows) != 100 {
+		t.Fatalf("read %d rows, want 100", len(rows))
+	}
+	for i, row := range rows {
+		want := evolutionRowV2{
+			Name:   "name-" + string(rune('a'+i%26)),
+			Id:     int64(i - 64),
+			Count:  int64(i * 1000),
+			Ratio:  float64(i) / 4,
+			Amount: big.NewInt(int64(i) * 10100),
+			Point:  evolutionPointV2{X: int64(-i)},
+			Points: []evolutionPointV2{{X: int64(i)}, {X: int64(i + 1)}},
+		}
+		if i%5 == 0 {
+			want.Amount = nil
+		}
+		if !reflect.DeepEqual(row, want) {
+			t.Fatalf("read %+v, want %+v", row, want)
+		}
+	}
+}
+
+func TestSchemaEvolution_ByPosition(t *testing.T) {
+	reader := openEvolutionFile(writeEvolutionFile(t, 10))
+	renamedPoint := block.From(util.NewArrayList(block.CreateField("renamed_x", block.BIGINT), block.CreateField("renamed_y", block.BIGINT)))
+	columnNames := util.NewArrayList("renamed_id", "renamed_count", "renamed_ratio", "renamed_amount", "renamed_name", "renamed_dropped", "renamed_point", "renamed_points", "added")
+	types := util.NewArrayList[block.Type](block.SMALLINT, block.BIGINT, block.DOUBLE, block.CreateDecimalType(12, 2), block.CreateVarcharType(3), block.VARCHAR, renamedPoint, block.NewArrayType(renamedPoint), block.INTEGER)
+	evolution := NewSchemaEvolution(reader, columnNames, types, MAP_BY_POSITION)
+	if !evolution.IsMissing(8) || evolution.IsMissing(0) {
+		t.Fatal("wrong missing columns")
+	}
+	recordReader := reader.CreateRecordReader3(evolution, TRUE, nil, memory.NewSimpleAggregatedMemoryContext(), MAX_BATCH_SIZE)
+	defer recordReader.Close()
+	page := recordReader.NextPage().GetLoadedPage()
+	if page.GetPositionCount() != 10 {
+		t.Fatalf("read %d positions, want 10", page.GetPositionCount())
+	}
+	if value := block.SMALLINT.GetLong(page.GetBlock(0), 3); value != -61 {
+		t.Fatalf("read id %d, want -61", value)
+	}
+	if value := block.DOUBLE.GetDouble(page.GetBlock(2), 3); value != 0.75 {
+		t.Fatalf("read ratio %f, want 0.75", value)
+	}
+	if value := block.VARCHAR.GetSlice(page.GetBlock(4), 3).String(); value != "nam" {
+		t.Fatalf("read name %s, want nam", value)
+	}
+	point := renamedPoint.GetObject(page.GetBlock(6), 3).(block.Block)
+	if value := block.BIGINT.GetLong(point, 0); value != -3 || !point.IsNull(1) {
+		t.Fatalf("read point x %d, y null %t", value, point.IsNull(1))
+	}
+	missing := page.GetBlock(8)
+	for position := util.INT32_ZERO; position < page.GetPositionCount(); position++ {
+		if !missing.IsNull(position) {
+			t.Fatalf("the missing column is not null at %d", position)
+		}
+	}
+}
+
+func TestSchemaEvolution_Invalid(t *testing.T) {
+	reader := openEvolutionFile(writeEvolutionFile(t, 10))
+	invalid := []block.Type{block.TINYINT, block.REAL, block.CreateDecimalType(10, 1), block.CreateDecimalType(10, 3), block.BIGINT}
+	columnNames := []string{"count", "name", "amount", "amount", "point"}
+	for i, kind := range invalid {
+		func() {
+			defer func() {
+				if _, flag := recover().(*common.MothError); !flag {
+					t.Errorf("read %s as %s", columnNames[i], kind.GetDisplayName())
+				}
+			}()
+			NewSchemaEvolution(reader, util.NewArrayList(columnNames[i]), util.NewArrayList(kind), MAP_BY_NAME)
+		}()
+	}
+}
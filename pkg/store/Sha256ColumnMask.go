@@ -0,0 +1,48 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Replaces values with the lower case hex encoded SHA-256 of the value. Equal values have equal
+ * masks, so masked columns can still be joined and grouped. The hash is truncated to the length
+ * of bounded types.
+ */
+type Sha256ColumnMask struct {
+	// 继承
+	ColumnMask
+
+	kind      sliceType
+	maxLength int32
+}
+
+func NewSha256ColumnMask(kind sliceType, maxLength int32) *Sha256ColumnMask {
+	sk := new(Sha256ColumnMask)
+	sk.kind = kind
+	sk.maxLength = maxLength
+	return sk
+}
+
+// @Override
+func (sk *Sha256ColumnMask) Mask(b block.Block) block.Block {
+	builder := sk.kind.CreateBlockBuilder2(nil, b.GetPositionCount())
+	for position := util.INT32_ZERO; position < b.GetPositionCount(); position++ {
+		if b.IsNull(position) {
+			builder.AppendNull()
+			continue
+		}
+		hash := sha256.Sum256(sk.kind.GetSlice(b, position).AvailableBytes())
+		masked := hex.EncodeToString(hash[:])
+		if sk.maxLength >= 0 && int32(len(masked)) > sk.maxLength {
+			masked = masked[:sk.maxLength]
+		}
+		sk.kind.WriteSlice(builder, slice.NewWithString(masked))
+	}
+	return builder.Build()
+}
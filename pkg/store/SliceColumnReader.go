@@ -1,12 +1,12 @@
 package store
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/slice"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -52,7 +52,7 @@ func (sr *SliceColumnReader) StartStripe(fileTimeZone *time.Location, dictionary
 	} else if columnEncodingKind == metadata.DICTIONARY || columnEncodingKind == metadata.DICTIONARY_V2 {
 		sr.currentReader = sr.dictionaryReader
 	} else {
-		panic(fmt.Sprintf("Unsupported encoding %d", columnEncodingKind))
+		panic(common.NewMothUnsupportedError(sr.column.GetMothDataSourceId(), "Unsupported encoding %d", columnEncodingKind))
 	}
 	sr.currentReader.StartStripe(fileTimeZone, dictionaryStreamSources, encoding)
 }
@@ -70,7 +70,10 @@ func (sr *SliceColumnReader) ToString() string {
 func getMaxCodePointCount(kind block.Type) int32 {
 	varcharType, flag := kind.(*block.VarcharType)
 	if flag {
-		return util.Ternary(varcharType.IsUnbounded(), -1, varcharType.GetBoundedLength())
+		if varcharType.IsUnbounded() {
+			return -1
+		}
+		return varcharType.GetBoundedLength()
 	}
 	charType, cflag := kind.(*block.CharType)
 	if cflag {
@@ -80,7 +83,7 @@ func getMaxCodePointCount(kind block.Type) int32 {
 	if flag {
 		return -1
 	}
-	panic(fmt.Sprintf("Unsupported encoding %s", kind.GetDisplayName()))
+	panic(common.NewMothUnsupportedError(nil, "Unsupported encoding %s", kind.GetDisplayName()))
 }
 
 func ComputeTruncatedLength(slice *slice.Slice, offset int32, length int32, maxCodePointCount int32, isCharType bool) int32 {
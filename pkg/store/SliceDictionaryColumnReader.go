@@ -9,6 +9,7 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/slice"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -88,7 +89,7 @@ func (sr *SliceDictionaryColumnReader) ReadBlock() block.Block {
 		}
 		if sr.readOffset > 0 {
 			if sr.dataStream == nil {
-				panic("Value is not null but data stream is missing")
+				panic(common.NewMothCorruptionError(sr.column.GetMothDataSourceId(), "Value is not null but data stream is missing"))
 			}
 			sr.dataStream.Skip(int64(sr.readOffset))
 		}
@@ -96,7 +97,7 @@ func (sr *SliceDictionaryColumnReader) ReadBlock() block.Block {
 	var block block.Block
 	if sr.dataStream == nil {
 		if sr.presentStream == nil {
-			panic("Value is null but present stream is missing")
+			panic(common.NewMothCorruptionError(sr.column.GetMothDataSourceId(), "Value is null but present stream is missing"))
 		}
 		sr.presentStream.Skip(int64(sr.nextBatchSize))
 		block = sr.readAllNullsBlock()
@@ -178,7 +179,7 @@ func (sr *SliceDictionaryColumnReader) openRowGroup() {
 			}
 
 			if lengthStream == nil {
-				panic("Dictionary is not empty but dictionary length stream is missing")
+				panic(common.NewMothCorruptionError(sr.column.GetMothDataSourceId(), "Dictionary is not empty but dictionary length stream is missing"))
 			}
 			lengthStream.Next3(sr.dictionaryLength, sr.dictionarySize)
 			dataLength := util.INT64_ZERO
@@ -27,6 +27,7 @@ type SliceDictionaryColumnWriter struct {
 	columnId                  metadata.MothColumnId
 	kind                      block.Type
 	compression               metadata.CompressionKind
+	compressionLevel          int32
 	bufferSize                int32
 	dataStream                LongOutputStream
 	presentStream             *PresentOutputStream
@@ -48,7 +49,7 @@ type SliceDictionaryColumnWriter struct {
 	directColumnWriter        *SliceDirectColumnWriter
 }
 
-func NewSliceDictionaryColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, bufferSize int32, statisticsBuilderSupplier function.Supplier[metadata.SliceColumnStatisticsBuilder]) *SliceDictionaryColumnWriter {
+func NewSliceDictionaryColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, statisticsBuilderSupplier function.Supplier[metadata.SliceColumnStatisticsBuilder]) *SliceDictionaryColumnWriter {
 	sr := new(SliceDictionaryColumnWriter)
 
 	sr.dictionary = NewDictionaryBuilder(100)
@@ -57,11 +58,12 @@ func NewSliceDictionaryColumnWriter(columnId metadata.MothColumnId, kind block.T
 	sr.columnId = columnId
 	sr.kind = kind
 	sr.compression = compression
+	sr.compressionLevel = compressionLevel
 	sr.bufferSize = bufferSize
-	sr.dataStream = NewLongOutputStreamV2(compression, bufferSize, false, metadata.DATA)
-	sr.presentStream = NewPresentOutputStream(compression, bufferSize)
-	sr.dictionaryDataStream = NewByteArrayOutputStream2(compression, bufferSize, metadata.DICTIONARY_DATA)
-	sr.dictionaryLengthStream = CreateLengthOutputStream(compression, bufferSize)
+	sr.dataStream = NewLongOutputStreamV2(compression, compressionLevel, bufferSize, false, metadata.DATA)
+	sr.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
+	sr.dictionaryDataStream = NewByteArrayOutputStream2(compression, compressionLevel, bufferSize, metadata.DICTIONARY_DATA)
+	sr.dictionaryLengthStream = CreateLengthOutputStream(compression, compressionLevel, bufferSize)
 	sr.values = array.NewIntBigArray()
 	sr.statisticsBuilderSupplier = statisticsBuilderSupplier
 	sr.statisticsBuilder = statisticsBuilderSupplier.Get()
@@ -115,7 +117,7 @@ func (sr *SliceDictionaryColumnWriter) TryConvertToDirect(maxDirectBytes int32)
 	util.CheckState(!sr.closed)
 	util.CheckState(!sr.directEncoded)
 	if sr.directColumnWriter == nil {
-		sr.directColumnWriter = NewSliceDirectColumnWriter(sr.columnId, sr.kind, sr.compression, sr.bufferSize, sr.statisticsBuilderSupplier)
+		sr.directColumnWriter = NewSliceDirectColumnWriter(sr.columnId, sr.kind, sr.compression, sr.compressionLevel, sr.bufferSize, sr.statisticsBuilderSupplier)
 	}
 	util.CheckState(sr.directColumnWriter.GetBufferedBytes() == 0)
 	dictionaryValues := sr.dictionary.GetElementBlock()
@@ -370,6 +372,9 @@ func createSliceColumnPositionList(compressed bool, dataCheckpoint LongStreamChe
 
 // @Override
 func (sr *SliceDictionaryColumnWriter) GetBloomFilters(metadataWriter *CompressedMetadataWriter) *util.ArrayList[*StreamDataOutput] {
+	if sr.directEncoded {
+		return sr.directColumnWriter.GetBloomFilters(metadataWriter)
+	}
 	// bloomFilters := sr.rowGroups.stream().Map(func(rowGroup interface{}) {
 	// 	rowGroup.getColumnStatistics().getBloomFilter()
 	// }).filter(Objects.nonNull).collect(toImmutableList())
@@ -493,19 +498,16 @@ func NewInts(data []int32, b block.Block) *Ints {
 func (is Ints) Len() int { return len(is.data) }
 
 func (is Ints) Less(i, j int) bool {
-	left := int32(i)
-	right := int32(j)
+	left := is.data[i]
+	right := is.data[j]
 	nullLeft := is.b.IsNull(left)
 	nullRight := is.b.IsNull(right)
-	if nullRight {
-		return true
-	}
-	if nullLeft && nullRight {
-		return false
-	}
 	if nullLeft {
 		return false
 	}
+	if nullRight {
+		return true
+	}
 	return is.b.CompareTo(left, 0, is.b.GetSliceLength(left), is.b, right, 0, is.b.GetSliceLength(right)) < 0
 }
 
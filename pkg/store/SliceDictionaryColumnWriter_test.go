@@ -0,0 +1,27 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/spi/predicate"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func TestSliceDictionaryColumnWriter_DirectEncodedBloomFilters(t *testing.T) {
+	// the dictionary outgrows the max memory and the column is written direct encoded
+	options := NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100).WithDictionaryMaxMemory(util.Ofds(1, util.KB)).WithBloomFilterColumns(util.NewSetWithItems(util.SET_NonThreadSafe, "name"))
+	data := writeTestFile(2000, options, metadata.ZLIB)
+
+	// inside the min/max range of row group 1200, but never written
+	missing := NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(2), predicate.SingleValueDomain(block.VARCHAR, slice.NewWithString("name-001234x"))).Build()
+	if ids := readIds(data, NewMothReaderOptions().WithBloomFiltersEnabled(true), missing); len(ids) != 0 {
+		t.Errorf("read %d rows with the bloom filters of a direct column, want 0", len(ids))
+	}
+	present := NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(2), predicate.SingleValueDomain(block.VARCHAR, slice.NewWithString("name-001234"))).Build()
+	if ids := readIds(data, NewMothReaderOptions().WithBloomFiltersEnabled(true), present); len(ids) != 100 || ids[0] != 1200 {
+		t.Errorf("read %d rows with the bloom filters of a direct column, want 100", len(ids))
+	}
+}
@@ -1,12 +1,13 @@
 package store
 
 import (
-	"fmt"
 	"time"
 
+	"github.com/mothdb-bd/orc-go/pkg/errors"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/slice"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -64,12 +65,12 @@ func (sr *SliceDirectColumnReader) ReadBlock() block.Block {
 		}
 		if sr.readOffset > 0 {
 			if sr.lengthStream == nil {
-				panic("Value is not null but length stream is missing")
+				panic(common.NewMothCorruptionError(sr.column.GetMothDataSourceId(), "Value is not null but length stream is missing"))
 			}
 			dataSkipSize := sr.lengthStream.Sum(sr.readOffset)
 			if dataSkipSize > 0 {
 				if sr.dataStream == nil {
-					panic("Value is not null but data stream is missing")
+					panic(common.NewMothCorruptionError(sr.column.GetMothDataSourceId(), "Value is not null but data stream is missing"))
 				}
 				sr.dataStream.Skip(dataSkipSize)
 			}
@@ -77,7 +78,7 @@ func (sr *SliceDirectColumnReader) ReadBlock() block.Block {
 	}
 	if sr.lengthStream == nil {
 		if sr.presentStream == nil {
-			panic("Value is null but present stream is missing")
+			panic(common.NewMothCorruptionError(sr.column.GetMothDataSourceId(), "Value is null but present stream is missing"))
 		}
 		sr.presentStream.Skip(int64(sr.nextBatchSize))
 		nullValueBlock := sr.readAllNullsBlock()
@@ -99,7 +100,7 @@ func (sr *SliceDirectColumnReader) ReadBlock() block.Block {
 			return nullValueBlock
 		}
 		if sr.lengthStream == nil {
-			panic("Value is not null but length stream is missing")
+			panic(common.NewMothCorruptionError(sr.column.GetMothDataSourceId(), "Value is not null but length stream is missing"))
 		}
 		if nullCount == 0 {
 			isNullVector = nil
@@ -120,10 +121,10 @@ func (sr *SliceDirectColumnReader) ReadBlock() block.Block {
 		return block.NewVariableWidthBlock(currentBatchSize, slice.EMPTY_SLICE, offsetVector, optional.OfNullable(isNullVector))
 	}
 	if totalLength > int64(ONE_GIGABYTE) {
-		panic(fmt.Sprintf("Values in column \"%s\" are too large to process for Moth. %d column values are larger than 1GB [%s]", sr.column.GetPath(), sr.nextBatchSize, sr.column.GetMothDataSourceId()))
+		panic(common.NewMothError(errors.GENERIC_INSUFFICIENT_RESOURCES, sr.column.GetMothDataSourceId(), "Values in column \"%s\" are too large to process for Moth. %d column values are larger than 1GB", sr.column.GetPath(), sr.nextBatchSize))
 	}
 	if sr.dataStream == nil {
-		panic("Value is not null but data stream is missing")
+		panic(common.NewMothCorruptionError(sr.column.GetMothDataSourceId(), "Value is not null but data stream is missing"))
 	}
 	data := make([]byte, util.Int32Exact(totalLength))
 	var s *slice.Slice = nil
@@ -27,15 +27,15 @@ type SliceDirectColumnWriter struct {
 	closed                    bool
 }
 
-func NewSliceDirectColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, bufferSize int32, statisticsBuilderSupplier function.Supplier[metadata.SliceColumnStatisticsBuilder]) *SliceDirectColumnWriter {
+func NewSliceDirectColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, statisticsBuilderSupplier function.Supplier[metadata.SliceColumnStatisticsBuilder]) *SliceDirectColumnWriter {
 	sr := new(SliceDirectColumnWriter)
 	sr.columnId = columnId
 	sr.kind = kind
 	sr.compressed = compression != metadata.NONE
 	sr.columnEncoding = metadata.NewColumnEncoding(metadata.DIRECT_V2, 0)
-	sr.lengthStream = CreateLengthOutputStream(compression, bufferSize)
-	sr.dataStream = NewByteArrayOutputStream(compression, bufferSize)
-	sr.presentStream = NewPresentOutputStream(compression, bufferSize)
+	sr.lengthStream = CreateLengthOutputStream(compression, compressionLevel, bufferSize)
+	sr.dataStream = NewByteArrayOutputStream(compression, compressionLevel, bufferSize)
+	sr.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 	sr.statisticsBuilderSupplier = statisticsBuilderSupplier
 	sr.statisticsBuilder = statisticsBuilderSupplier.Get()
 	sr.rowGroupColumnStatistics = util.NewArrayList[*metadata.ColumnStatistics]()
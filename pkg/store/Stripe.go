@@ -14,6 +14,7 @@ type Stripe struct {
 	columnEncodings         *metadata.ColumnMetadata[*metadata.ColumnEncoding]
 	rowGroups               *util.ArrayList[*RowGroup]
 	dictionaryStreamSources *InputStreamSources
+	decodedStreams          *util.ArrayList[*DecodedMothChunkLoader]
 }
 
 func NewStripe(rowCount int64, fileTimeZone *time.Location, columnEncodings *metadata.ColumnMetadata[*metadata.ColumnEncoding], rowGroups *util.ArrayList[*RowGroup], dictionaryStreamSources *InputStreamSources) *Stripe {
@@ -23,6 +24,13 @@ func NewStripe(rowCount int64, fileTimeZone *time.Location, columnEncodings *met
 	se.columnEncodings = columnEncodings
 	se.rowGroups = rowGroups
 	se.dictionaryStreamSources = dictionaryStreamSources
+	se.decodedStreams = util.NewArrayList[*DecodedMothChunkLoader]()
+	return se
+}
+
+func NewStripe2(rowCount int64, fileTimeZone *time.Location, columnEncodings *metadata.ColumnMetadata[*metadata.ColumnEncoding], rowGroups *util.ArrayList[*RowGroup], dictionaryStreamSources *InputStreamSources, decodedStreams *util.ArrayList[*DecodedMothChunkLoader]) *Stripe {
+	se := NewStripe(rowCount, fileTimeZone, columnEncodings, rowGroups, dictionaryStreamSources)
+	se.decodedStreams = decodedStreams
 	return se
 }
 
@@ -46,6 +54,16 @@ func (se *Stripe) GetDictionaryStreamSources() *InputStreamSources {
 	return se.dictionaryStreamSources
 }
 
+/**
+ * Reads and decompresses the data streams of the stripe ahead of time, does nothing when the
+ * stripe was read without decoded streams.
+ */
+func (se *Stripe) DecodeStreams() {
+	for _, stream := range se.decodedStreams.ToArray() {
+		stream.Decode()
+	}
+}
+
 // @Override
 func (se *Stripe) ToString() string {
 	return util.NewSB().AddInt64("rowCount", se.rowCount).AddString("fileTimeZone", se.fileTimeZone.String()).AddString("columnEncodings", se.columnEncodings.String()).AddString("rowGroups", "rowGroups").AddString("dictionaryStreams", "dictionaryStreamSources").String()
@@ -0,0 +1,193 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Reads the stripes of a record reader ahead of the consumer. The stripes are read one after
+ * the other on a single goroutine, so the data source always sees the reads in file order, and
+ * their data streams are decompressed on a pool of goroutines. At most prefetchCount stripes
+ * are in flight. A stripe read ahead of the consumer first reserves its size in the memory
+ * context of the record reader, which holds the buffers of the prefetched stripes, and is only
+ * read once the context grants it, so a bounded context limits the prefetched bytes. The next
+ * stripe of the consumer is always read. Stripes are returned by Next in file order.
+ */
+type StripePrefetcher struct {
+	stripeReader  *StripeReader
+	stripes       *util.ArrayList[*metadata.StripeInformation]
+	memoryUsage   memory.AggregatedMemoryContext
+	prefetchCount int32
+
+	lock      sync.Mutex
+	condition *sync.Cond
+	inFlight  int32
+	closed    bool
+
+	started    bool
+	nextStripe int32
+	results    []chan *prefetchedStripe
+	tasks      chan *prefetchedStripe
+	goroutines sync.WaitGroup
+}
+
+type prefetchedStripe struct {
+	index         int32
+	stripe        *Stripe
+	memoryContext memory.AggregatedMemoryContext
+	// the size of the stripe reserved until its buffers are read and decoded
+	reservation memory.LocalMemoryContext
+	failure     interface{}
+}
+
+func NewStripePrefetcher(stripeReader *StripeReader, stripes *util.ArrayList[*metadata.StripeInformation], memoryUsage memory.AggregatedMemoryContext, prefetchCount int32) *StripePrefetcher {
+	util.CheckArgument2(prefetchCount > 0, "prefetchCount must be positive")
+	sr := new(StripePrefetcher)
+	sr.stripeReader = stripeReader
+	sr.stripes = stripes
+	sr.memoryUsage = memoryUsage
+	sr.prefetchCount = prefetchCount
+	sr.condition = sync.NewCond(&sr.lock)
+	sr.results = make([]chan *prefetchedStripe, stripes.Size())
+	for i := range sr.results {
+		sr.results[i] = make(chan *prefetchedStripe, 1)
+	}
+	sr.tasks = make(chan *prefetchedStripe, prefetchCount)
+	return sr
+}
+
+/**
+ * Returns the next stripe and the memory context holding its buffers, which is owned by the
+ * caller from now on. The stripe is nil when all of its row groups were pruned. A failure
+ * while reading or decoding the stripe is raised here, on the consumer goroutine.
+ */
+func (sr *StripePrefetcher) Next() (*Stripe, memory.AggregatedMemoryContext) {
+	util.CheckState2(sr.nextStripe < sr.stripes.SizeInt32(), "No more stripes")
+	if !sr.started {
+		sr.start()
+	}
+	result := <-sr.results[sr.nextStripe]
+	sr.nextStripe++
+
+	sr.lock.Lock()
+	sr.inFlight--
+	sr.condition.Broadcast()
+	sr.lock.Unlock()
+
+	if result.failure != nil {
+		panic(result.failure)
+	}
+	return result.stripe, result.memoryContext
+}
+
+/**
+ * Returns the index of the stripe the next call to Next returns.
+ */
+func (sr *StripePrefetcher) GetNextStripe() int32 {
+	return sr.nextStripe
+}
+
+/**
+ * Stops prefetching, waits for the running reads to finish and releases the stripes that
+ * were not consumed.
+ */
+func (sr *StripePrefetcher) Close() {
+	if !sr.started {
+		return
+	}
+	sr.lock.Lock()
+	sr.closed = true
+	sr.condition.Broadcast()
+	sr.lock.Unlock()
+	sr.goroutines.Wait()
+
+	for _, results := range sr.results[sr.nextStripe:] {
+		select {
+		case result := <-results:
+			result.memoryContext.Close()
+		default:
+		}
+	}
+}
+
+func (sr *StripePrefetcher) start() {
+	sr.started = true
+	sr.goroutines.Add(int(sr.prefetchCount) + 1)
+	for i := util.INT32_ZERO; i < sr.prefetchCount; i++ {
+		go sr.decodeStripes()
+	}
+	go sr.readStripes()
+}
+
+func (sr *StripePrefetcher) readStripes() {
+	defer sr.goroutines.Done()
+	defer close(sr.tasks)
+	for i := util.INT32_ZERO; i < sr.stripes.SizeInt32(); i++ {
+		stripe := sr.stripes.GetByInt32(i)
+		result := &prefetchedStripe{index: i, memoryContext: sr.memoryUsage.NewAggregatedMemoryContext()}
+		result.reservation = result.memoryContext.NewLocalMemoryContext("StripePrefetcher")
+		if !sr.awaitCapacity(result.reservation, int64(stripe.GetTotalLength())) {
+			result.memoryContext.Close()
+			return
+		}
+		result.failure = runCapturingPanic(func() {
+			result.stripe = sr.stripeReader.ReadStripe(stripe, result.memoryContext)
+		})
+		if result.failure != nil || result.stripe == nil {
+			result.reservation.Close()
+			sr.results[i] <- result
+			if result.failure != nil {
+				return
+			}
+			continue
+		}
+		sr.tasks <- result
+	}
+}
+
+/**
+ * Waits until the stripe can be read and reserves its size, the stripe the consumer waits for
+ * is reserved even when the memory context does not grant it. Returns false when the prefetcher
+ * is closed.
+ */
+func (sr *StripePrefetcher) awaitCapacity(reservation memory.LocalMemoryContext, stripeSize int64) bool {
+	sr.lock.Lock()
+	defer sr.lock.Unlock()
+	for !sr.closed {
+		if sr.inFlight == 0 {
+			reservation.SetBytes(stripeSize)
+			break
+		}
+		if sr.inFlight < sr.prefetchCount && reservation.TrySetBytes(stripeSize) {
+			break
+		}
+		sr.condition.Wait()
+	}
+	if sr.closed {
+		return false
+	}
+	sr.inFlight++
+	return true
+}
+
+func (sr *StripePrefetcher) decodeStripes() {
+	defer sr.goroutines.Done()
+	for result := range sr.tasks {
+		result.failure = runCapturingPanic(result.stripe.DecodeStreams)
+		// the decoded buffers are held in the memory context of the stripe from now on
+		result.reservation.Close()
+		sr.results[result.index] <- result
+	}
+}
+
+func runCapturingPanic(task func()) (failure interface{}) {
+	defer func() {
+		failure = recover()
+	}()
+	task()
+	return nil
+}
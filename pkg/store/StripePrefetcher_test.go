@@ -0,0 +1,152 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/spi/predicate"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func readRows(dataSource MothDataSource, options *MothReaderOptions, mothPredicate MothPredicate) ([]int64, []string) {
+	reader := CreateMothReader(dataSource, options).Get()
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+	recordReader := reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns(), types, mothPredicate, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+	defer recordReader.Close()
+	ids := make([]int64, 0)
+	names := make([]string, 0)
+	for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+		idBlock := page.GetBlock(0).GetLoadedBlock()
+		nameBlock := page.GetBlock(1).GetLoadedBlock()
+		for position := util.INT32_ZERO; position < idBlock.GetPositionCount(); position++ {
+			ids = append(ids, block.BIGINT.GetLong(idBlock, position))
+			names = append(names, block.VARCHAR.GetSlice(nameBlock, position).String())
+		}
+	}
+	return ids, names
+}
+
+func TestStripePrefetcher_NextPage(t *testing.T) {
+	options := NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100)
+	zlibData := writeTestFile(10500, options, metadata.ZLIB)
+	noneData := writeTestFile(10500, options, metadata.NONE)
+
+	between := func(low int64, high int64) MothPredicate {
+		domain := predicate.NewDomain(predicate.ValueSetOfRanges(block.BIGINT, predicate.NewRange(block.BIGINT, low, true, high, true)), false)
+		return NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(1), domain).Build()
+	}
+	memorySource := func(data *slice.Slice) func(options *MothReaderOptions) MothDataSource {
+		return func(options *MothReaderOptions) MothDataSource {
+			return NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "test.moth")
+	if err := os.WriteFile(path, zlibData.AvailableBytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fileSource := func(options *MothReaderOptions) MothDataSource {
+		return NewFileMothDataSource(path, options)
+	}
+	uncachedOptions := NewMothReaderOptions().WithTinyStripeThreshold(util.Ofds(1, util.B)).WithMaxMergeDistance(util.Ofds(1, util.B))
+	tests := []struct {
+		name       string
+		dataSource func(options *MothReaderOptions) MothDataSource
+		options    *MothReaderOptions
+		predicate  MothPredicate
+	}{
+		{"zlib", memorySource(zlibData), NewMothReaderOptions().WithStripePrefetchCount(4), TRUE},
+		{"uncompressed", memorySource(noneData), NewMothReaderOptions().WithStripePrefetchCount(2), TRUE},
+		{"single stripe in flight", memorySource(zlibData), NewMothReaderOptions().WithStripePrefetchCount(1), TRUE},
+		{"row groups", memorySource(zlibData), NewMothReaderOptions().WithStripePrefetchCount(4), between(2590, 2610)},
+		{"file", fileSource, uncachedOptions.WithStripePrefetchCount(4), TRUE},
+		{"file row groups", fileSource, uncachedOptions.WithStripePrefetchCount(4), between(2590, 2610)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sequentialOptions := tt.options.WithStripePrefetchCount(0)
+			wantIds, wantNames := readRows(tt.dataSource(sequentialOptions), sequentialOptions, tt.predicate)
+			if len(wantIds) == 0 {
+				t.Fatal("sequential read returned no rows")
+			}
+			ids, names := readRows(tt.dataSource(tt.options), tt.options, tt.predicate)
+			if !reflect.DeepEqual(ids, wantIds) {
+				t.Fatalf("read %d ids, want %d ids in the same order", len(ids), len(wantIds))
+			}
+			if !reflect.DeepEqual(names, wantNames) {
+				t.Fatalf("read %d names, want %d names in the same order", len(names), len(wantNames))
+			}
+		})
+	}
+}
+
+func TestStripePrefetcher_MemoryContext(t *testing.T) {
+	data := writeTestFile(10500, NewMothWriterOptions().WithStripeMaxRowCount(1000), metadata.ZLIB)
+	options := NewMothReaderOptions().WithStripePrefetchCount(4)
+	inFlight := func(memoryUsage memory.AggregatedMemoryContext, want int32) int32 {
+		reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), options).Get()
+		types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+		recordReader := reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns(), types, TRUE, time.UTC, memoryUsage, INITIAL_BATCH_SIZE)
+		defer recordReader.Close()
+		if page := recordReader.NextPage(); page == nil {
+			t.Fatal("NextPage returned no page")
+		}
+		prefetcher := recordReader.stripePrefetcher
+		for deadline := time.Now().Add(200 * time.Millisecond); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			prefetcher.lock.Lock()
+			count := prefetcher.inFlight
+			prefetcher.lock.Unlock()
+			if count > want {
+				return count
+			}
+		}
+		return want
+	}
+
+	// the stripes are read ahead while the memory context grants their size
+	if count := inFlight(memory.NewSimpleAggregatedMemoryContext(), 3); count != 4 {
+		t.Fatalf("prefetched %d stripes with an unbounded memory context", count)
+	}
+	// the stripe the consumer waits for is read even when it is not granted
+	pool := memory.NewBoundedAggregatedMemoryContext(1)
+	if count := inFlight(pool, 1); count != 1 {
+		t.Fatalf("prefetched %d stripes with a memory context of 1 byte", count)
+	}
+	if pool.GetBytes() != 0 {
+		t.Fatalf("reserved %d bytes after closing the record reader", pool.GetBytes())
+	}
+
+	// every row is read with a memory context that grants no stripe
+	pool = memory.NewBoundedAggregatedMemoryContext(1)
+	reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), options).Get()
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+	recordReader := reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns(), types, TRUE, time.UTC, pool, INITIAL_BATCH_SIZE)
+	defer recordReader.Close()
+	if ids := pageIds(t, recordReader.ReadRows(0, 10500).ToArray()...); !reflect.DeepEqual(ids, idRange(0, 10500)) {
+		t.Fatalf("read %d rows with a memory context of 1 byte", len(ids))
+	}
+}
+
+func TestStripePrefetcher_Close(t *testing.T) {
+	data := writeTestFile(10500, NewMothWriterOptions().WithStripeMaxRowCount(1000), metadata.ZLIB)
+	goroutines := runtime.NumGoroutine()
+	options := NewMothReaderOptions().WithStripePrefetchCount(4)
+	reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), options).Get()
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR)
+	recordReader := reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns(), types, TRUE, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+	if page := recordReader.NextPage(); page == nil {
+		t.Fatal("NextPage returned no page")
+	}
+	recordReader.Close()
+	if got := runtime.NumGoroutine(); got > goroutines {
+		t.Fatalf("%d goroutines after close, want at most %d", got, goroutines)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/maths"
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/encryption"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -20,10 +22,13 @@ type StripeReader struct {
 	includedMothColumnIds util.SetInterface[metadata.MothColumnId]
 	rowsInRowGroup        *optional.OptionalInt
 	predicate             MothPredicate
+	bloomFiltersEnabled   bool
 	metadataReader        metadata.MetadataReader
+	decryptedVariants     *util.ArrayList[*DecryptedVariant]
+	decodeStreams         bool
 }
 
-func NewStripeReader(mothDataSource MothDataSource, legacyFileTimeZone *time.Location, decompressor *optional.Optional[MothDecompressor], types *metadata.ColumnMetadata[*metadata.MothType], readColumns util.SetInterface[*MothColumn], rowsInRowGroup *optional.OptionalInt, predicate MothPredicate, hiveWriterVersion metadata.HiveWriterVersion, metadataReader metadata.MetadataReader) *StripeReader {
+func NewStripeReader(mothDataSource MothDataSource, legacyFileTimeZone *time.Location, decompressor *optional.Optional[MothDecompressor], types *metadata.ColumnMetadata[*metadata.MothType], readColumns util.SetInterface[*MothColumn], rowsInRowGroup *optional.OptionalInt, predicate MothPredicate, bloomFiltersEnabled bool, hiveWriterVersion metadata.HiveWriterVersion, metadataReader metadata.MetadataReader, decryptedVariants *util.ArrayList[*DecryptedVariant], decodeStreams bool) *StripeReader {
 	sr := new(StripeReader)
 	sr.mothDataSource = mothDataSource
 	sr.legacyFileTimeZone = legacyFileTimeZone
@@ -32,8 +37,11 @@ func NewStripeReader(mothDataSource MothDataSource, legacyFileTimeZone *time.Loc
 	sr.includedMothColumnIds = getIncludeColumns(readColumns)
 	sr.rowsInRowGroup = rowsInRowGroup
 	sr.predicate = predicate
+	sr.bloomFiltersEnabled = bloomFiltersEnabled
 	sr.hiveWriterVersion = hiveWriterVersion
 	sr.metadataReader = metadataReader
+	sr.decryptedVariants = decryptedVariants
+	sr.decodeStreams = decodeStreams
 	return sr
 }
 
@@ -41,15 +49,21 @@ func (sr *StripeReader) ReadStripe(stripe *metadata.StripeInformation, memoryUsa
 	stripeFooter := sr.readStripeFooter(stripe, memoryUsage)
 	columnEncodings := stripeFooter.GetColumnEncodings()
 	fileTimeZone := stripeFooter.GetTimeZone()
+	stripeStreams := stripeFooter.GetStreams()
+	stripeDiskRanges := getDiskRanges(stripeStreams)
+	streamKeys := util.EmptyMap[StreamId, []byte]()
+	if !sr.decryptedVariants.IsEmpty() {
+		stripeStreams, columnEncodings = sr.mergeDecryptedVariants(stripeFooter, stripeDiskRanges, streamKeys)
+	}
 	streams := util.EmptyMap[StreamId, *metadata.Stream]()
-	for _, stream := range stripeFooter.GetStreams().ToArray() {
-		if sr.includedMothColumnIds.Has(stream.GetColumnId()) && isSupportedStreamType(stream, sr.types.Get(stream.GetColumnId()).GetMothTypeKind()) {
+	for _, stream := range stripeStreams.ToArray() {
+		if sr.includedMothColumnIds.Has(stream.GetColumnId()) && isSupportedStreamType(stream, sr.types.Get(stream.GetColumnId()).GetMothTypeKind()) && (sr.bloomFiltersEnabled || !isBloomFilterStream(stream)) {
 			streams[NewSId(stream)] = stream
 		}
 	}
 	invalidCheckPoint := false
 	if sr.rowsInRowGroup.IsPresent() && stripe.GetNumberOfRows() > sr.rowsInRowGroup.Get() {
-		diskRanges := getDiskRanges(stripeFooter.GetStreams())
+		diskRanges := stripeDiskRanges
 
 		// diskRanges = Maps.filterKeys(, Predicates.in(streams.keySet()))
 		diskRanges = util.FilterKeys(diskRanges, func(k StreamId) bool {
@@ -57,7 +71,7 @@ func (sr *StripeReader) ReadStripe(stripe *metadata.StripeInformation, memoryUsa
 			return ok
 		})
 
-		streamsData := sr.readDiskRanges(int64(stripe.GetOffset()), diskRanges, memoryUsage)
+		streamsData := sr.readDiskRanges(int64(stripe.GetOffset()), diskRanges, stripe.GetEncryptStripeId(), streamKeys, memoryUsage)
 		bloomFilterIndexes := sr.readBloomFilterIndexes(streams, streamsData)
 		columnIndexes := sr.readColumnIndexes(streams, streamsData, bloomFilterIndexes)
 		selectedRowGroups := sr.selectRowGroups(stripe, columnIndexes)
@@ -65,20 +79,21 @@ func (sr *StripeReader) ReadStripe(stripe *metadata.StripeInformation, memoryUsa
 			memoryUsage.Close()
 			return nil
 		}
+		decodedStreams := sr.createDecodedStreams(streams, streamsData, memoryUsage)
 		valueStreams := sr.createValueStreams(streams, streamsData, columnEncodings)
 		dictionaryStreamSources := sr.createDictionaryStreamSources(streams, valueStreams, columnEncodings)
 		rowGroups := sr.createRowGroups(stripe.GetNumberOfRows(), streams, valueStreams, columnIndexes, selectedRowGroups, columnEncodings)
-		return NewStripe(int64(stripe.GetNumberOfRows()), fileTimeZone, columnEncodings, rowGroups, dictionaryStreamSources)
+		return NewStripe2(int64(stripe.GetNumberOfRows()), fileTimeZone, columnEncodings, rowGroups, dictionaryStreamSources, decodedStreams)
 	}
 	diskRangesBuilder := util.EmptyMap[StreamId, *DiskRange]()
-	for k, v := range getDiskRanges(stripeFooter.GetStreams()) {
+	for k, v := range stripeDiskRanges {
 		_, ok := streams[k]
 		if ok {
 			diskRangesBuilder[k] = v
 		}
 	}
 	diskRanges := diskRangesBuilder
-	streamsData := sr.readDiskRanges(int64(stripe.GetOffset()), diskRanges, memoryUsage)
+	streamsData := sr.readDiskRanges(int64(stripe.GetOffset()), diskRanges, stripe.GetEncryptStripeId(), streamKeys, memoryUsage)
 	minAverageRowBytes := util.INT64_ZERO
 	for k := range streams {
 		if k.GetStreamKind() == metadata.ROW_INDEX {
@@ -98,6 +113,7 @@ func (sr *StripeReader) ReadStripe(stripe *metadata.StripeInformation, memoryUsa
 			}
 		}
 	}
+	decodedStreams := sr.createDecodedStreams(streams, streamsData, memoryUsage)
 	valueStreams := sr.createValueStreams(streams, streamsData, columnEncodings)
 	dictionaryStreamSources := sr.createDictionaryStreamSources(streams, valueStreams, columnEncodings)
 	builder := util.EmptyMap[StreamId, InputStreamSource]() // InputStreamSource
@@ -105,10 +121,80 @@ func (sr *StripeReader) ReadStripe(stripe *metadata.StripeInformation, memoryUsa
 		builder[k] = NewValueInputStreamSource(v)
 	}
 	rowGroup := NewRowGroup(0, 0, int64(stripe.GetNumberOfRows()), minAverageRowBytes, NewInputStreamSources(builder))
-	return NewStripe(int64(stripe.GetNumberOfRows()), fileTimeZone, columnEncodings, util.NewArrayList(rowGroup), dictionaryStreamSources)
+	return NewStripe2(int64(stripe.GetNumberOfRows()), fileTimeZone, columnEncodings, util.NewArrayList(rowGroup), dictionaryStreamSources, decodedStreams)
+}
+
+/**
+ * Replaces the masked streams and encodings of the decrypted columns with the streams and
+ * encodings of their encryption variant. The encrypted streams of a variant are stored in the
+ * ENCRYPTED_INDEX and ENCRYPTED_DATA areas of the variant root, in the order of the variant
+ * stream list. The disk ranges and keys of the variant streams are added to the given maps.
+ */
+func (sr *StripeReader) mergeDecryptedVariants(stripeFooter *metadata.StripeFooter, diskRanges map[StreamId]*DiskRange, streamKeys map[StreamId][]byte) (*util.ArrayList[*metadata.Stream], *metadata.ColumnMetadata[*metadata.ColumnEncoding]) {
+	stripeStreams := util.NewArrayList[*metadata.Stream]()
+	for _, stream := range stripeFooter.GetStreams().ToArray() {
+		if getDecryptedVariant(sr.decryptedVariants, stream.GetColumnId()) == nil {
+			stripeStreams.Add(stream)
+		} else {
+			delete(diskRanges, NewSId(stream))
+		}
+	}
+	encodings := make(map[metadata.MothColumnId]*metadata.ColumnEncoding)
+	for _, variant := range sr.decryptedVariants.ToArray() {
+		if variant.GetVariantIndex() >= stripeFooter.GetEncryption().SizeInt32() {
+			panic(common.NewMothCorruptionError(sr.mothDataSource.GetId(), "Stripe footer is missing encryption variant %d", variant.GetVariantIndex()))
+		}
+		stripeVariant := stripeFooter.GetEncryption().GetByInt32(variant.GetVariantIndex())
+		indexOffset := getEncryptedAreaOffset(stripeFooter.GetStreams(), variant.GetRoot(), metadata.ENCRYPTED_INDEX)
+		dataOffset := getEncryptedAreaOffset(stripeFooter.GetStreams(), variant.GetRoot(), metadata.ENCRYPTED_DATA)
+		for _, stream := range stripeVariant.GetStreams().ToArray() {
+			streamId := NewSId(stream)
+			offset := &dataOffset
+			if isIndexStream(stream) {
+				offset = &indexOffset
+			}
+			if stream.GetLength() > 0 {
+				diskRanges[streamId] = NewDiskRange(*offset, stream.GetLength())
+			}
+			*offset += int64(stream.GetLength())
+			streamKeys[streamId] = variant.GetLocalKey()
+			stripeStreams.Add(stream)
+		}
+		for i := util.INT32_ZERO; i < stripeVariant.GetEncodings().SizeInt32(); i++ {
+			encodings[variant.GetRoot()+metadata.MothColumnId(i)] = stripeVariant.GetEncodings().GetByInt32(i)
+		}
+	}
+	columnEncodings := util.NewArrayList[*metadata.ColumnEncoding]()
+	for i := util.INT32_ZERO; i < stripeFooter.GetColumnEncodings().Size(); i++ {
+		columnId := metadata.MothColumnId(i)
+		if encoding, ok := encodings[columnId]; ok {
+			columnEncodings.Add(encoding)
+		} else {
+			columnEncodings.Add(stripeFooter.GetColumnEncodings().Get(columnId))
+		}
+	}
+	return stripeStreams, metadata.NewColumnMetadata(columnEncodings)
+}
+
+/**
+ * Offset in the stripe of the encrypted area of a variant, the area is recorded as a stream of the
+ * variant root in the stripe footer
+ */
+func getEncryptedAreaOffset(streams *util.ArrayList[*metadata.Stream], root metadata.MothColumnId, kind metadata.StreamKind) int64 {
+	offset := util.INT64_ZERO
+	for _, stream := range streams.ToArray() {
+		if stream.GetColumnId() == root && stream.GetStreamKind() == kind {
+			return offset
+		}
+		offset += int64(stream.GetLength())
+	}
+	panic(common.NewMothCorruptionError(nil, "Stripe footer is missing the %d stream of column %d", kind, root))
 }
 
 func isSupportedStreamType(stream *metadata.Stream, mothTypeKind metadata.MothTypeKind) bool {
+	if stream.GetStreamKind() == metadata.ENCRYPTED_INDEX || stream.GetStreamKind() == metadata.ENCRYPTED_DATA {
+		return false
+	}
 	if stream.GetStreamKind() == metadata.BLOOM_FILTER {
 		switch mothTypeKind {
 		case metadata.STRING, metadata.VARCHAR, metadata.CHAR:
@@ -125,7 +211,7 @@ func isSupportedStreamType(stream *metadata.Stream, mothTypeKind metadata.MothTy
 	return true
 }
 
-func (sr *StripeReader) readDiskRanges(stripeOffset int64, diskRanges map[StreamId]*DiskRange, memoryUsage memory.AggregatedMemoryContext) map[StreamId]MothChunkLoader {
+func (sr *StripeReader) readDiskRanges(stripeOffset int64, diskRanges map[StreamId]*DiskRange, stripeId uint64, streamKeys map[StreamId][]byte, memoryUsage memory.AggregatedMemoryContext) map[StreamId]MothChunkLoader {
 	diskRangesBuilder := util.EmptyMap[StreamId, *DiskRange]()
 	for k, v := range diskRanges {
 		diskRangesBuilder[k] = NewDiskRange(stripeOffset+v.GetOffset(), v.GetLength())
@@ -134,11 +220,34 @@ func (sr *StripeReader) readDiskRanges(stripeOffset int64, diskRanges map[Stream
 	streamsData := sr.mothDataSource.ReadFully2(diskRanges)
 	dataBuilder := util.EmptyMap[StreamId, MothChunkLoader]()
 	for k, v := range streamsData {
+		if key, ok := streamKeys[k]; ok {
+			v = NewDecryptingMothDataReader(v, key, encryption.CreateIv(k.GetColumnId(), k.GetStreamKind(), stripeId))
+		}
 		dataBuilder[k] = CreateChunkLoader2(v, sr.decompressor, memoryUsage)
 	}
 	return dataBuilder
 }
 
+/**
+ * Wraps the chunk loaders of the data streams so they can be decompressed ahead of time by
+ * Stripe.DecodeStreams. Index streams are read while the stripe is read and are left as is.
+ */
+func (sr *StripeReader) createDecodedStreams(streams map[StreamId]*metadata.Stream, streamsData map[StreamId]MothChunkLoader, memoryUsage memory.AggregatedMemoryContext) *util.ArrayList[*DecodedMothChunkLoader] {
+	decodedStreams := util.NewArrayList[*DecodedMothChunkLoader]()
+	if !sr.decodeStreams {
+		return decodedStreams
+	}
+	for streamId, stream := range streams {
+		if isIndexStream(stream) || stream.GetLength() == 0 {
+			continue
+		}
+		decodedStream := NewDecodedMothChunkLoader(streamsData[streamId], memoryUsage)
+		streamsData[streamId] = decodedStream
+		decodedStreams.Add(decodedStream)
+	}
+	return decodedStreams
+}
+
 func (sr *StripeReader) createValueStreams(streams map[StreamId]*metadata.Stream, streamsData map[StreamId]MothChunkLoader, columnEncodings *metadata.ColumnMetadata[*metadata.ColumnEncoding]) map[StreamId]IValueInputStream {
 	valueStreams := util.EmptyMap[StreamId, IValueInputStream]()
 	for k, v := range streams {
@@ -226,6 +335,10 @@ func isIndexStream(stream *metadata.Stream) bool {
 	return stream.GetStreamKind() == metadata.ROW_INDEX || stream.GetStreamKind() == metadata.DICTIONARY_COUNT || stream.GetStreamKind() == metadata.BLOOM_FILTER || stream.GetStreamKind() == metadata.BLOOM_FILTER_UTF8
 }
 
+func isBloomFilterStream(stream *metadata.Stream) bool {
+	return stream.GetStreamKind() == metadata.BLOOM_FILTER || stream.GetStreamKind() == metadata.BLOOM_FILTER_UTF8
+}
+
 func (sr *StripeReader) readBloomFilterIndexes(streams map[StreamId]*metadata.Stream, streamsData map[StreamId]MothChunkLoader) map[metadata.MothColumnId]*util.ArrayList[*metadata.BloomFilter] {
 	bloomFilters := make(map[metadata.MothColumnId]*util.ArrayList[*metadata.BloomFilter])
 	for k, v := range streams {
@@ -274,7 +387,7 @@ func (sr *StripeReader) selectRowGroups(stripe *metadata.StripeInformation, colu
 	if sr.rowsInRowGroup.IsPresent() {
 		rowsInRowGroup = sr.rowsInRowGroup.Get()
 	} else {
-		panic("Cannot create row groups if row group info is missing")
+		panic(common.NewMothCorruptionError(sr.mothDataSource.GetId(), "Cannot create row groups if row group info is missing"))
 	}
 
 	rowsInStripe := stripe.GetNumberOfRows()
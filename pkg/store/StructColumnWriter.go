@@ -25,12 +25,12 @@ type StructColumnWriter struct {
 	closed                   bool
 }
 
-func NewStructColumnWriter(columnId metadata.MothColumnId, compression metadata.CompressionKind, bufferSize int32, structFields *util.ArrayList[ColumnWriter]) *StructColumnWriter {
+func NewStructColumnWriter(columnId metadata.MothColumnId, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, structFields *util.ArrayList[ColumnWriter]) *StructColumnWriter {
 	sr := new(StructColumnWriter)
 	sr.columnId = columnId
 	sr.compressed = compression != metadata.NONE
 	sr.structFields = structFields
-	sr.presentStream = NewPresentOutputStream(compression, bufferSize)
+	sr.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 	sr.rowGroupColumnStatistics = util.NewArrayList[*metadata.ColumnStatistics]()
 	return sr
 }
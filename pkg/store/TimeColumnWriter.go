@@ -13,14 +13,14 @@ type TimeColumnWriter struct {
 	LongColumnWriter
 }
 
-func NewTimeColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, bufferSize int32, statisticsBuilderSupplier function.Supplier[metadata.LongValueStatisticsBuilder]) *TimeColumnWriter {
+func NewTimeColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, statisticsBuilderSupplier function.Supplier[metadata.LongValueStatisticsBuilder]) *TimeColumnWriter {
 	tr := new(TimeColumnWriter)
 	tr.columnId = columnId
 	tr.kind = kind
 	tr.compressed = compression != metadata.NONE
 	tr.columnEncoding = metadata.NewColumnEncoding(metadata.DIRECT_V2, 0)
-	tr.dataStream = NewLongOutputStreamV2(compression, bufferSize, true, metadata.DATA)
-	tr.presentStream = NewPresentOutputStream(compression, bufferSize)
+	tr.dataStream = NewLongOutputStreamV2(compression, compressionLevel, bufferSize, true, metadata.DATA)
+	tr.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 	tr.statisticsBuilderSupplier = statisticsBuilderSupplier
 	tr.statisticsBuilder = statisticsBuilderSupplier.Get()
 	return tr
@@ -8,6 +8,7 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -26,7 +27,7 @@ const (
 
 var (
 	// *LocalDateTime = LocalDateTime.of(2015, 1, 1, 0, 0, 0, 0)
-	MOTH_EPOCH = time.Date(2015, 1, 1, 0, 0, 0, 0, time.Local)
+	MOTH_EPOCH = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
 	// .toEpochSecond(ZoneOffset.UTC)
 	BASE_INSTANT_IN_SECONDS               int64 = MOTH_EPOCH.UTC().Unix()
 	TIMESTAMP_COLUMN_READER_INSTANCE_SIZE int32 = util.SizeOf(&TimestampColumnReader{})
@@ -52,6 +53,7 @@ type TimestampColumnReader struct {
 	column                 *MothColumn
 	timestampKind          TimestampKind
 	baseTimestampInSeconds int64
+	baseInstantInSeconds   int64
 
 	// fileDateTimeZone       *DateTimeZone
 	fileDateTimeZone    *time.Location
@@ -128,7 +130,7 @@ func (tr *TimestampColumnReader) ReadBlock() block.Block {
 	var b block.Block
 	if tr.secondsStream == nil && tr.nanosStream == nil {
 		if tr.presentStream == nil {
-			panic("Value is null but present stream is missing")
+			panic(common.NewMothCorruptionError(tr.column.GetMothDataSourceId(), "Value is null but present stream is missing"))
 		}
 		tr.presentStream.Skip(int64(tr.nextBatchSize))
 		b = block.CreateRunLengthEncodedBlock(tr.kind, nil, tr.nextBatchSize)
@@ -152,10 +154,10 @@ func (tr *TimestampColumnReader) ReadBlock() block.Block {
 
 func (tr *TimestampColumnReader) verifyStreamsPresent() {
 	if tr.secondsStream == nil {
-		panic("Value is not null but seconds stream is missing")
+		panic(common.NewMothCorruptionError(tr.column.GetMothDataSourceId(), "Value is not null but seconds stream is missing"))
 	}
 	if tr.nanosStream == nil {
-		panic("Value is not null but nanos stream is missing")
+		panic(common.NewMothCorruptionError(tr.column.GetMothDataSourceId(), "Value is not null but nanos stream is missing"))
 	}
 }
 
@@ -224,9 +226,17 @@ func (tr *TimestampColumnReader) openRowGroup() {
 
 // @Override
 func (tr *TimestampColumnReader) StartStripe(fileTimeZone *time.Location, dictionaryStreamSources *InputStreamSources, encoding *metadata.ColumnMetadata[*metadata.ColumnEncoding]) {
-	// ZonedDateTime.ofLocal(MOTH_EPOCH, fileTimeZone, nil).toEpochSecond()
-	tr.baseTimestampInSeconds = MOTH_EPOCH.In(fileTimeZone).Unix()
-	tr.fileDateTimeZone = fileTimeZone
+	if tr.column.HasLegacyTimestamps() {
+		// writer version 1.0.0 stored the seconds relative to 1970-01-01 UTC
+		tr.baseTimestampInSeconds = 0
+		tr.baseInstantInSeconds = 0
+		tr.fileDateTimeZone = time.UTC
+	} else {
+		// ZonedDateTime.ofLocal(MOTH_EPOCH, fileTimeZone, nil).toEpochSecond()
+		tr.baseTimestampInSeconds = time.Date(MOTH_EPOCH.Year(), MOTH_EPOCH.Month(), MOTH_EPOCH.Day(), 0, 0, 0, 0, fileTimeZone).Unix()
+		tr.baseInstantInSeconds = BASE_INSTANT_IN_SECONDS
+		tr.fileDateTimeZone = fileTimeZone
+	}
 	tr.presentStreamSource = MissingStreamSource() // [*BooleanInputStream]()
 	tr.secondsStreamSource = MissingStreamSource() // [LongInputStream]()
 	tr.nanosStreamSource = MissingStreamSource()   // [LongInputStream]()
@@ -270,6 +280,15 @@ func (tr *TimestampColumnReader) isFileUtc() bool {
 	return tr.fileDateTimeZone == time.UTC
 }
 
+/**
+ * Returns the offset of the file time zone at the instant, the seconds of a timestamp are
+ * relative to the ORC epoch in the time zone of the writer.
+ */
+func (tr *TimestampColumnReader) fileZoneOffsetSeconds(epochMillis int64) int64 {
+	_, offset := time.UnixMilli(epochMillis).In(tr.fileDateTimeZone).Zone()
+	return int64(offset)
+}
+
 func (tr *TimestampColumnReader) decodeNanos(serialized int64) int32 {
 	// the last three bits encode the leading zeros removed minus one
 	zeros := int32(serialized & 0b111)
@@ -278,7 +297,7 @@ func (tr *TimestampColumnReader) decodeNanos(serialized int64) int32 {
 		nanos *= POWERS_OF_TEN[zeros+1]
 	}
 	if (nanos < 0) || (nanos > 999_999_999) {
-		panic(fmt.Sprintf("Nanos field of timestamp is out of range: %d", nanos))
+		panic(common.NewMothCorruptionError(tr.column.GetMothDataSourceId(), "Nanos field of timestamp is out of range: %d", nanos))
 	}
 	return nanos
 }
@@ -315,9 +334,8 @@ func (tr *TimestampColumnReader) readTimestampMillis() int64 {
 		millis += block.RoundDiv(nanos, time.Millisecond.Nanoseconds()) // NANOSECONDS_PER_MILLISECOND
 	}
 	if !tr.isFileUtc() {
-		// TODO:
-		t := time.Unix(seconds, nanos)
-		millis = t.In(tr.fileDateTimeZone).UnixMilli()
+		// millis = fileDateTimeZone.convertUTCToLocal(millis)
+		millis += tr.fileZoneOffsetSeconds(millis) * time.Second.Milliseconds()
 	}
 	// MICROSECONDS_PER_MILLISECOND
 	return millis * time.Millisecond.Microseconds()
@@ -353,13 +371,9 @@ func (tr *TimestampColumnReader) readTimestampMicros() int64 {
 		micros += block.RoundDiv(nanos, time.Microsecond.Nanoseconds()) //NANOSECONDS_PER_MICROSECOND
 	}
 	if !tr.isFileUtc() {
-		millis := maths.FloorDiv(micros, time.Millisecond.Microseconds())         //floorDiv(micros, MICROSECONDS_PER_MILLISECOND)
-		microsFraction := maths.FloorMod(micros, time.Millisecond.Microseconds()) //Maths.floorMod(micros, MICROSECONDS_PER_MILLISECOND)
-
+		millis := maths.FloorDiv(micros, time.Millisecond.Microseconds()) //floorDiv(micros, MICROSECONDS_PER_MILLISECOND)
 		// millis = fileDateTimeZone.convertUTCToLocal(millis)
-		t := time.Unix(seconds, nanos)
-		millis = t.In(tr.fileDateTimeZone).UnixMilli()
-		micros = (millis * time.Millisecond.Microseconds()) + microsFraction
+		micros += tr.fileZoneOffsetSeconds(millis) * time.Second.Microseconds()
 	}
 	return micros
 }
@@ -399,12 +413,9 @@ func (tr *TimestampColumnReader) readTimestampNanos(i int32, microsValues []int6
 		picosFraction = util.Int32Exact(nanos * 1000)    //PICOSECONDS_PER_NANOSECOND
 	}
 	if !tr.isFileUtc() {
-		millis := maths.FloorDiv(micros, time.Millisecond.Microseconds()) //MICROSECONDS_PER_MILLISECOND
-		microsFraction := maths.FloorMod(micros, time.Millisecond.Microseconds())
+		millis := maths.FloorDiv(micros, time.Millisecond.Microseconds()) //floorDiv(micros, MICROSECONDS_PER_MILLISECOND)
 		// millis = fileDateTimeZone.convertUTCToLocal(millis)
-		t := time.Unix(seconds, nanos)
-		millis = t.In(tr.fileDateTimeZone).UnixMilli()
-		micros = (millis * time.Millisecond.Microseconds()) + microsFraction
+		micros += tr.fileZoneOffsetSeconds(millis) * time.Second.Microseconds()
 	}
 	microsValues[i] = micros
 	picosFractionValues[i] = picosFraction
@@ -431,7 +442,7 @@ func (tr *TimestampColumnReader) readNullInstantMillis(isNull []bool) block.Bloc
 func (tr *TimestampColumnReader) readInstantMillis() int64 {
 	seconds := tr.secondsStream.Next()
 	serializedNanos := tr.nanosStream.Next()
-	millis := (seconds + BASE_INSTANT_IN_SECONDS) * time.Second.Milliseconds() // MILLIS_PER_SECOND
+	millis := (seconds + tr.baseInstantInSeconds) * time.Second.Milliseconds() // MILLIS_PER_SECOND
 	nanos := int64(tr.decodeNanos(serializedNanos))
 	if nanos != 0 {
 		if millis < 0 {
@@ -465,7 +476,7 @@ func (tr *TimestampColumnReader) readNullInstantMicros(isNull []bool) block.Bloc
 func (tr *TimestampColumnReader) readInstantMicros(i int32, millisValues []int64, picosFractionValues []int32) {
 	seconds := tr.secondsStream.Next()
 	serializedNanos := tr.nanosStream.Next()
-	millis := (seconds + BASE_INSTANT_IN_SECONDS) * time.Second.Milliseconds() //MILLIS_PER_SECOND
+	millis := (seconds + tr.baseInstantInSeconds) * time.Second.Milliseconds() //MILLIS_PER_SECOND
 	nanos := int64(tr.decodeNanos(serializedNanos))
 	picosFraction := util.INT32_ZERO
 	if nanos != 0 {
@@ -503,7 +514,7 @@ func (tr *TimestampColumnReader) readNullInstantNanos(isNull []bool) block.Block
 func (tr *TimestampColumnReader) readInstantNanos(i int32, millisValues []int64, picosFractionValues []int32) {
 	seconds := tr.secondsStream.Next()
 	serializedNanos := tr.nanosStream.Next()
-	millis := (seconds + BASE_INSTANT_IN_SECONDS) * time.Second.Milliseconds() //MILLIS_PER_SECOND
+	millis := (seconds + tr.baseInstantInSeconds) * time.Second.Milliseconds() //MILLIS_PER_SECOND
 	nanos := int64(tr.decodeNanos(serializedNanos))
 	picosFraction := util.INT32_ZERO
 	if nanos != 0 {
@@ -14,7 +14,7 @@ import (
 
 var (
 	TIMESTAMP_INSTANCE_SIZE         int32 = util.SizeOf(&TimestampColumnWriter{})
-	TIMESTAMP_MOTH_EPOCH_IN_SECONDS int64 = int64(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).Second())
+	TIMESTAMP_MOTH_EPOCH_IN_SECONDS int64 = int64(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
 )
 
 // type TimestampKind int8
@@ -67,16 +67,16 @@ type TimestampColumnWriter struct {
 	closed                    bool
 }
 
-func NewTimestampColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, bufferSize int32, statisticsBuilderSupplier function.Supplier[*metadata.TimestampStatisticsBuilder]) *TimestampColumnWriter {
+func NewTimestampColumnWriter(columnId metadata.MothColumnId, kind block.Type, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, statisticsBuilderSupplier function.Supplier[*metadata.TimestampStatisticsBuilder]) *TimestampColumnWriter {
 	tr := new(TimestampColumnWriter)
 	tr.columnId = columnId
 	tr.kind = kind
 	tr.timestampKind = timestampKindForType(kind)
 	tr.compressed = compression != metadata.NONE
 	tr.columnEncoding = metadata.NewColumnEncoding(metadata.DIRECT_V2, 0)
-	tr.secondsStream = NewLongOutputStreamV2(compression, bufferSize, true, metadata.DATA)
-	tr.nanosStream = NewLongOutputStreamV2(compression, bufferSize, false, metadata.SECONDARY)
-	tr.presentStream = NewPresentOutputStream(compression, bufferSize)
+	tr.secondsStream = NewLongOutputStreamV2(compression, compressionLevel, bufferSize, true, metadata.DATA)
+	tr.nanosStream = NewLongOutputStreamV2(compression, compressionLevel, bufferSize, false, metadata.SECONDARY)
+	tr.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
 	tr.statisticsBuilderSupplier = statisticsBuilderSupplier
 	tr.statisticsBuilder = statisticsBuilderSupplier.Get()
 
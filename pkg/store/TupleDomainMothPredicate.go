@@ -0,0 +1,272 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/mothdb-bd/orc-go/pkg/basic"
+	"github.com/mothdb-bd/orc-go/pkg/maths"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/spi/predicate"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+	"github.com/shopspring/decimal"
+)
+
+var MICROSECONDS_PER_MILLISECOND int64 = 1000
+
+/**
+ * MothPredicate which evaluates a domain per column against the file, stripe and
+ * row group statistics. The column domains are combined with AND. Point lookups and
+ * IN lists are also tested against the row group bloom filters when they are present.
+ */
+type TupleDomainMothPredicate struct {
+	// 继承
+	MothPredicate
+
+	columnDomains *util.ArrayList[*ColumnDomain]
+}
+
+func NewTupleDomainMothPredicate(columnDomains *util.ArrayList[*ColumnDomain]) *TupleDomainMothPredicate {
+	te := new(TupleDomainMothPredicate)
+	te.columnDomains = columnDomains
+	return te
+}
+
+func (te *TupleDomainMothPredicate) GetColumnDomains() *util.ArrayList[*ColumnDomain] {
+	return te.columnDomains
+}
+
+// @Override
+func (te *TupleDomainMothPredicate) Matches(numberOfRows int64, allColumnStatistics *metadata.ColumnMetadata[*metadata.ColumnStatistics]) bool {
+	for _, column := range te.columnDomains.ToArray() {
+		if int32(column.GetColumnId()) >= allColumnStatistics.Size() {
+			continue
+		}
+		columnStatistics := allColumnStatistics.Get(column.GetColumnId())
+		if columnStatistics == nil {
+			// no statistics for this column, so we can't exclude this section
+			continue
+		}
+		if !te.columnOverlaps(column.GetDomain(), numberOfRows, columnStatistics) {
+			return false
+		}
+	}
+	// this section was not excluded
+	return true
+}
+
+func (te *TupleDomainMothPredicate) columnOverlaps(predicateDomain *predicate.Domain, numberOfRows int64, columnStatistics *metadata.ColumnStatistics) bool {
+	stripeDomain := GetDomain(predicateDomain.GetType(), numberOfRows, columnStatistics)
+	if !stripeDomain.Overlaps(predicateDomain) {
+		// there is no overlap between the predicate and this column, so skip this section
+		return false
+	}
+
+	// bloom filters are only attached to row group statistics when they are enabled in the reader options
+	bloomFilter := columnStatistics.GetBloomFilter()
+	if bloomFilter == nil {
+		return true
+	}
+	// a null in the section matches a predicate which allows null, the bloom filter can't tell us anything
+	if predicateDomain.IsNullAllowed() && stripeDomain.IsNullAllowed() {
+		return true
+	}
+	// only point lookups and IN lists can be checked against a bloom filter
+	if !predicateDomain.GetValues().IsDiscreteSet() {
+		return true
+	}
+	// if none of the discrete predicate values are found in the bloom filter, there is no overlap and the section should be skipped
+	for _, value := range predicateDomain.GetDiscreteValues().ToArray() {
+		if checkInBloomFilter(bloomFilter, value, stripeDomain.GetType()) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkInBloomFilter(bloomFilter *metadata.BloomFilter, predicateValue basic.Object, kind block.Type) bool {
+	switch kind.(type) {
+	case *block.TinyintType, *block.SmallintType, *block.IntegerType, *block.BigintType, *block.DateType:
+		return bloomFilter.TestLong(predicateValue.(int64))
+	case *block.RealType, *block.DoubleType:
+		// REAL values are widened to double when they are added to the bloom filter
+		return bloomFilter.TestDouble(predicateValue.(float64))
+	case *block.VarcharType:
+		return bloomFilter.TestSlice(predicateValue.(*slice.Slice))
+	case *block.ShortTimestampType, *block.LongTimestampType, *block.ShortTimestampWithTimeZoneType, *block.LongTimestampWithTimeZoneType:
+		// the bloom filter contains the millis written to the statistics
+		return bloomFilter.TestLong(maths.FloorDiv(predicateValue.(int64), MICROSECONDS_PER_MILLISECOND))
+	}
+	// DECIMAL columns are written without bloom filters, and the bloom filters of CHAR columns are
+	// not read because writers differ in whether they pad the values, see isSupportedStreamType
+	return true
+}
+
+// @Override
+func (te *TupleDomainMothPredicate) String() string {
+	parts := make([]string, 0, te.columnDomains.Size())
+	for _, column := range te.columnDomains.ToArray() {
+		parts = append(parts, column.String())
+	}
+	return "TupleDomainMothPredicate{" + strings.Join(parts, ", ") + "}"
+}
+
+/**
+ * Converts the statistics of a column into the domain of values the column may contain.
+ */
+func GetDomain(kind block.Type, rowCount int64, columnStatistics *metadata.ColumnStatistics) *predicate.Domain {
+	if rowCount == 0 {
+		return predicate.NoneDomain(kind)
+	}
+	if columnStatistics == nil {
+		return predicate.AllDomain(kind)
+	}
+	if columnStatistics.HasNumberOfValues() && columnStatistics.GetNumberOfValues() == 0 {
+		return predicate.OnlyNullDomain(kind)
+	}
+	hasNullValue := columnStatistics.GetNumberOfValues() != rowCount
+
+	switch t := kind.(type) {
+	case *block.BooleanType:
+		booleanStatistics := columnStatistics.GetBooleanStatistics()
+		if booleanStatistics != nil {
+			hasTrueValues := booleanStatistics.GetTrueValueCount() != 0
+			hasFalseValues := columnStatistics.GetNumberOfValues() != booleanStatistics.GetTrueValueCount()
+			if hasTrueValues && hasFalseValues {
+				return predicate.NewDomain(predicate.AllValueSet(kind), hasNullValue)
+			}
+			if hasTrueValues {
+				return predicate.NewDomain(predicate.ValueSetOf(kind, true), hasNullValue)
+			}
+			if hasFalseValues {
+				return predicate.NewDomain(predicate.ValueSetOf(kind, false), hasNullValue)
+			}
+		}
+	case *block.TinyintType, *block.SmallintType, *block.IntegerType, *block.BigintType:
+		integerStatistics := columnStatistics.GetIntegerStatistics()
+		if integerStatistics != nil {
+			return createDomain(kind, hasNullValue, integerStatistics.GetMin(), integerStatistics.GetMax())
+		}
+	case *block.DateType:
+		dateStatistics := columnStatistics.GetDateStatistics()
+		if dateStatistics != nil {
+			return createDomain(kind, hasNullValue, int64(dateStatistics.GetMin()), int64(dateStatistics.GetMax()))
+		}
+	case *block.RealType, *block.DoubleType:
+		doubleStatistics := columnStatistics.GetDoubleStatistics()
+		if doubleStatistics != nil {
+			return createDomain(kind, hasNullValue, doubleStatistics.GetMin(), doubleStatistics.GetMax())
+		}
+	case *block.VarcharType:
+		stringStatistics := columnStatistics.GetStringStatistics()
+		if stringStatistics != nil {
+			return createDomain(kind, hasNullValue, optionalSlice(stringStatistics.GetMin()), optionalSlice(stringStatistics.GetMax()))
+		}
+	case *block.CharType:
+		stringStatistics := columnStatistics.GetStringStatistics()
+		if stringStatistics != nil {
+			var min, max basic.Object
+			if stringStatistics.GetMin() != nil {
+				min = truncateToLengthAndTrimSpaces(stringStatistics.GetMin(), t)
+			}
+			if stringStatistics.GetMax() != nil {
+				max = truncateToLengthAndTrimSpaces(stringStatistics.GetMax(), t)
+			}
+			return createDomain(kind, hasNullValue, min, max)
+		}
+	case *block.ShortDecimalType, *block.LongDecimalType:
+		decimalStatistics := columnStatistics.GetDecimalStatistics()
+		if decimalStatistics != nil {
+			return createDomain(kind, hasNullValue, optionalDecimal(decimalStatistics.GetMin()), optionalDecimal(decimalStatistics.GetMax()))
+		}
+	case *block.ShortTimestampType, *block.LongTimestampType, *block.ShortTimestampWithTimeZoneType, *block.LongTimestampWithTimeZoneType:
+		timestampStatistics := columnStatistics.GetTimestampStatistics()
+		if timestampStatistics != nil {
+			// statistics are recorded in millis, values are truncated to millis when written
+			min := timestampStatistics.GetMin() * MICROSECONDS_PER_MILLISECOND
+			max := timestampStatistics.GetMax()*MICROSECONDS_PER_MILLISECOND + MICROSECONDS_PER_MILLISECOND - 1
+			return createDomain(kind, hasNullValue, min, max)
+		}
+	}
+	return predicate.NewDomain(predicate.AllValueSet(kind), hasNullValue)
+}
+
+func createDomain(kind block.Type, hasNullValue bool, min basic.Object, max basic.Object) *predicate.Domain {
+	if min != nil && max != nil {
+		return predicate.NewDomain(predicate.ValueSetOfRanges(kind, predicate.NewRange(kind, min, true, max, true)), hasNullValue)
+	}
+	if max != nil {
+		return predicate.NewDomain(predicate.ValueSetOfRanges(kind, predicate.LessThanOrEqualRange(kind, max)), hasNullValue)
+	}
+	if min != nil {
+		return predicate.NewDomain(predicate.ValueSetOfRanges(kind, predicate.GreaterThanOrEqualRange(kind, min)), hasNullValue)
+	}
+	return predicate.NewDomain(predicate.AllValueSet(kind), hasNullValue)
+}
+
+func optionalSlice(value *slice.Slice) basic.Object {
+	if value == nil {
+		return nil
+	}
+	return value
+}
+
+func optionalDecimal(value *decimal.Decimal) basic.Object {
+	if value == nil {
+		return nil
+	}
+	return value
+}
+
+func truncateToLengthAndTrimSpaces(value *slice.Slice, kind *block.CharType) *slice.Slice {
+	length := block.ByteCountWithoutTrailingSpace(value, 0, value.SizeInt32(), kind.GetLength())
+	s, _ := value.MakeSlice(0, int(length))
+	return s
+}
+
+type ColumnDomain struct {
+	columnId metadata.MothColumnId
+	domain   *predicate.Domain
+}
+
+func NewColumnDomain(columnId metadata.MothColumnId, domain *predicate.Domain) *ColumnDomain {
+	cn := new(ColumnDomain)
+	cn.columnId = columnId
+	cn.domain = domain
+	return cn
+}
+
+func (cn *ColumnDomain) GetColumnId() metadata.MothColumnId {
+	return cn.columnId
+}
+
+func (cn *ColumnDomain) GetDomain() *predicate.Domain {
+	return cn.domain
+}
+
+// @Override
+func (cn *ColumnDomain) String() string {
+	return util.NewSB().AddInt32("columnId", int32(cn.columnId.GetId())).AddString("domain", cn.domain.String()).String()
+}
+
+type TupleDomainMothPredicateBuilder struct {
+	columns *util.ArrayList[*ColumnDomain]
+}
+
+func NewTupleDomainMothPredicateBuilder() *TupleDomainMothPredicateBuilder {
+	tr := new(TupleDomainMothPredicateBuilder)
+	tr.columns = util.NewArrayList[*ColumnDomain]()
+	return tr
+}
+
+func (tr *TupleDomainMothPredicateBuilder) AddColumn(columnId metadata.MothColumnId, domain *predicate.Domain) *TupleDomainMothPredicateBuilder {
+	if !domain.IsAll() {
+		tr.columns.Add(NewColumnDomain(columnId, domain))
+	}
+	return tr
+}
+
+func (tr *TupleDomainMothPredicateBuilder) Build() *TupleDomainMothPredicate {
+	return NewTupleDomainMothPredicate(tr.columns)
+}
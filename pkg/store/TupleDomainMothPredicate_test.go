@@ -0,0 +1,229 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/basic"
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/spi/predicate"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func TestTupleDomainMothPredicate_Matches(t *testing.T) {
+	stats := func(min int64, max int64, numberOfValues int64) *metadata.ColumnMetadata[*metadata.ColumnStatistics] {
+		integerStatistics := metadata.NewIntegerStatistics(min, max, 0)
+		return metadata.NewColumnMetadata(util.NewArrayList(nil, metadata.NewColumnStatistics(numberOfValues, 0, nil, integerStatistics, nil, nil, nil, nil, nil, nil, nil)))
+	}
+	build := func(domain *predicate.Domain) MothPredicate {
+		return NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(1), domain).Build()
+	}
+	tests := []struct {
+		name      string
+		predicate MothPredicate
+		stats     *metadata.ColumnMetadata[*metadata.ColumnStatistics]
+		rows      int64
+		want      bool
+	}{
+		{"single value inside", build(predicate.SingleValueDomain(block.BIGINT, int64(15))), stats(10, 20, 100), 100, true},
+		{"single value outside", build(predicate.SingleValueDomain(block.BIGINT, int64(25))), stats(10, 20, 100), 100, false},
+		{"range overlap", build(predicate.NewDomain(predicate.ValueSetOfRanges(block.BIGINT, predicate.GreaterThanRange(block.BIGINT, int64(19))), false)), stats(10, 20, 100), 100, true},
+		{"range boundary", build(predicate.NewDomain(predicate.ValueSetOfRanges(block.BIGINT, predicate.GreaterThanRange(block.BIGINT, int64(20))), false)), stats(10, 20, 100), 100, false},
+		{"in list", build(predicate.MultipleValuesDomain(block.BIGINT, int64(1), int64(5), int64(21))), stats(10, 20, 100), 100, false},
+		{"is null without nulls", build(predicate.OnlyNullDomain(block.BIGINT)), stats(10, 20, 100), 100, false},
+		{"is null with nulls", build(predicate.OnlyNullDomain(block.BIGINT)), stats(10, 20, 90), 100, true},
+		{"not null all nulls", build(predicate.NotNullDomain(block.BIGINT)), stats(10, 20, 0), 100, false},
+		{"or", NewOrMothPredicate(build(predicate.SingleValueDomain(block.BIGINT, int64(25))), build(predicate.SingleValueDomain(block.BIGINT, int64(12)))), stats(10, 20, 100), 100, true},
+		{"and", NewAndMothPredicate(build(predicate.SingleValueDomain(block.BIGINT, int64(25))), build(predicate.SingleValueDomain(block.BIGINT, int64(12)))), stats(10, 20, 100), 100, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.predicate.Matches(tt.rows, tt.stats); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTupleDomainMothPredicate_Prune(t *testing.T) {
+	options := NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100)
+	data := writeTestFile(5000, options, metadata.NONE)
+
+	between := func(low int64, high int64) MothPredicate {
+		domain := predicate.NewDomain(predicate.ValueSetOfRanges(block.BIGINT, predicate.NewRange(block.BIGINT, low, true, high, true)), false)
+		return NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(1), domain).Build()
+	}
+	nameEquals := func(name string) MothPredicate {
+		domain := predicate.SingleValueDomain(block.VARCHAR, slice.NewWithString(name))
+		return NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(2), domain).Build()
+	}
+	tests := []struct {
+		name      string
+		predicate MothPredicate
+		wantFirst int64
+		wantCount int
+	}{
+		{"all", TRUE, 0, 5000},
+		{"row group", between(2510, 2550), 2500, 100},
+		{"two row groups", between(2590, 2610), 2500, 200},
+		{"string", nameEquals("name-004321"), 4300, 100},
+		{"none", between(6000, 7000), -1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := readIds(data, NewMothReaderOptions(), tt.predicate)
+			if len(ids) != tt.wantCount {
+				t.Fatalf("read %d rows, want %d", len(ids), tt.wantCount)
+			}
+			if len(ids) > 0 && ids[0] != tt.wantFirst {
+				t.Errorf("first id = %d, want %d", ids[0], tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestTupleDomainMothPredicate_BloomFilter(t *testing.T) {
+	bloomFilter := metadata.NewBloomFilter(100, 0.01)
+	for value := int64(10); value <= 20; value++ {
+		if value != 15 {
+			bloomFilter.AddLong(value)
+		}
+	}
+	stats := metadata.NewColumnMetadata(util.NewArrayList(nil, metadata.NewColumnStatistics(100, 0, nil, metadata.NewIntegerStatistics(10, 20, 0), nil, nil, nil, nil, nil, nil, bloomFilter)))
+	build := func(domain *predicate.Domain) MothPredicate {
+		return NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(1), domain).Build()
+	}
+	tests := []struct {
+		name      string
+		predicate MothPredicate
+		want      bool
+	}{
+		{"value in filter", build(predicate.SingleValueDomain(block.BIGINT, int64(12))), true},
+		{"value not in filter", build(predicate.SingleValueDomain(block.BIGINT, int64(15))), false},
+		{"in list", build(predicate.MultipleValuesDomain(block.BIGINT, int64(15), int64(16))), true},
+		{"range", build(predicate.NewDomain(predicate.ValueSetOfRanges(block.BIGINT, predicate.NewRange(block.BIGINT, int64(14), true, int64(15), true)), false)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.predicate.Matches(100, stats); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	options := NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100).WithBloomFilterColumns(util.NewSetWithItems(util.SET_NonThreadSafe, "name"))
+	data := writeTestFile(2000, options, metadata.ZLIB)
+	// inside the min/max range of row group 1200, but never written
+	missing := NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(2), predicate.SingleValueDomain(block.VARCHAR, slice.NewWithString("name-001234x"))).Build()
+	if ids := readIds(data, NewMothReaderOptions(), missing); len(ids) != 100 {
+		t.Errorf("read %d rows without bloom filters, want 100", len(ids))
+	}
+	if ids := readIds(data, NewMothReaderOptions().WithBloomFiltersEnabled(true), missing); len(ids) != 0 {
+		t.Errorf("read %d rows with bloom filters, want 0", len(ids))
+	}
+	present := NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(2), predicate.SingleValueDomain(block.VARCHAR, slice.NewWithString("name-001234"))).Build()
+	if ids := readIds(data, NewMothReaderOptions().WithBloomFiltersEnabled(true), present); len(ids) != 100 || ids[0] != 1200 {
+		t.Errorf("read %d rows with bloom filters, want 100", len(ids))
+	}
+}
+
+// writeBloomFilterFile writes rowCount rows of (id BIGINT, name VARCHAR, value BIGINT, score DOUBLE)
+// with bloom filters on value, the even numbers 2 * id, and score, id + 0.25.
+func writeBloomFilterFile(rowCount int) *slice.Slice {
+	out := new(memoryWriteCloser)
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR, block.BIGINT, block.DOUBLE)
+	columnNames := util.NewArrayList("id", "name", "value", "score")
+	options := NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100).WithBloomFilterColumns(util.NewSetWithItems(util.SET_NonThreadSafe, "value", "score"))
+	writer := NewMothWriter(NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), columnNames, types, metadata.CreateRootMothType(columnNames, types), metadata.ZLIB, options, util.EmptyMap[string, string](), NewMothWriterStats())
+	pb := spi.NewPageBuilder(types)
+	for i := 0; i < rowCount; i++ {
+		pb.DeclarePosition()
+		block.WriteNativeValue(block.BIGINT, pb.GetBlockBuilder(0), int64(i))
+		block.WriteNativeValue(block.VARCHAR, pb.GetBlockBuilder(1), fmt.Sprintf("name-%06d", i))
+		block.WriteNativeValue(block.BIGINT, pb.GetBlockBuilder(2), int64(2*i))
+		block.WriteNativeValue(block.DOUBLE, pb.GetBlockBuilder(3), float64(i)+0.25)
+	}
+	writer.Write(pb.Build())
+	writer.Close()
+	return slice.NewWithBuf(out.Bytes())
+}
+
+func readBloomFilterIds(data *slice.Slice, options *MothReaderOptions, mothPredicate MothPredicate) []int64 {
+	reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), options).Get()
+	types := util.NewArrayList[block.Type](block.BIGINT, block.VARCHAR, block.BIGINT, block.DOUBLE)
+	recordReader := reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns(), types, mothPredicate, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+	defer recordReader.Close()
+	ids := make([]int64, 0)
+	for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+		b := page.GetBlock(0).GetLoadedBlock()
+		for position := util.INT32_ZERO; position < b.GetPositionCount(); position++ {
+			ids = append(ids, block.BIGINT.GetLong(b, position))
+		}
+	}
+	return ids
+}
+
+func TestTupleDomainMothPredicate_BloomFilterInList(t *testing.T) {
+	data := writeBloomFilterFile(2000)
+	build := func(column uint32, domain *predicate.Domain) MothPredicate {
+		return NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(column), domain).Build()
+	}
+	tests := []struct {
+		name      string
+		predicate MothPredicate
+		// the rows read with the bloom filters, the row groups of the min/max range are read without
+		wantRows  int
+		wantFirst int64
+	}{
+		// odd values inside the min/max range of row group 1200, but never written, and none of them a
+		// false positive of the bloom filters written with the default fpp
+		{"bigint missing", build(3, predicate.MultipleValuesDomain(block.BIGINT, int64(2401), int64(2403), int64(2405))), 0, 0},
+		{"bigint present", build(3, predicate.MultipleValuesDomain(block.BIGINT, int64(2401), int64(2500))), 100, 1200},
+		{"double missing", build(4, predicate.MultipleValuesDomain(block.DOUBLE, float64(1234.5), float64(1250), float64(1298.75))), 0, 0},
+		{"double present", build(4, predicate.MultipleValuesDomain(block.DOUBLE, float64(1234.5), float64(1250.25))), 100, 1200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ids := readBloomFilterIds(data, NewMothReaderOptions(), tt.predicate); len(ids) != 100 || ids[0] != 1200 {
+				t.Fatalf("read %d rows without bloom filters, want the 100 rows of row group 1200", len(ids))
+			}
+			ids := readBloomFilterIds(data, NewMothReaderOptions().WithBloomFiltersEnabled(true), tt.predicate)
+			if len(ids) != tt.wantRows || (len(ids) > 0 && ids[0] != tt.wantFirst) {
+				t.Errorf("read %d rows with bloom filters, want %d", len(ids), tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestValueSet_Normalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []basic.Object
+		ranges []*predicate.Range
+		want   string
+	}{
+		{"discrete", []basic.Object{int64(3), int64(1), int64(3)}, nil, "{[1], [3]}"},
+		{"merge", nil, []*predicate.Range{predicate.NewRange(block.BIGINT, int64(1), true, int64(5), false), predicate.NewRange(block.BIGINT, int64(5), true, int64(7), true)}, "{[1, 7]}"},
+		{"disjoint", nil, []*predicate.Range{predicate.GreaterThanRange(block.BIGINT, int64(5)), predicate.LessThanRange(block.BIGINT, int64(5))}, "{(<min>, 5), (5, <max>)}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var valueSet *predicate.ValueSet
+			if tt.values != nil {
+				valueSet = predicate.ValueSetOf(block.BIGINT, tt.values...)
+			} else {
+				valueSet = predicate.ValueSetOfRanges(block.BIGINT, tt.ranges...)
+			}
+			if got := valueSet.String(); got != tt.want {
+				t.Errorf("ValueSet = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
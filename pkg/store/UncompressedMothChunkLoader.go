@@ -49,7 +49,7 @@ func (ur *UncompressedMothChunkLoader) GetLastCheckpoint() int64 {
 func (ur *UncompressedMothChunkLoader) SeekToCheckpoint(checkpoint int64) {
 	compressedOffset := DecodeCompressedBlockOffset(checkpoint)
 	if compressedOffset != 0 {
-		panic("Uncompressed stream does not support seeking to a compressed offset")
+		panic(common.NewMothCorruptionError(ur.dataReader.GetMothDataSourceId(), "Uncompressed stream does not support seeking to a compressed offset"))
 	}
 	decompressedOffset := DecodeDecompressedOffset(checkpoint)
 	ur.nextPosition = decompressedOffset
@@ -59,7 +59,7 @@ func (ur *UncompressedMothChunkLoader) SeekToCheckpoint(checkpoint int64) {
 // @Override
 func (ur *UncompressedMothChunkLoader) NextChunk() *slice.Slice {
 	if ur.nextPosition >= ur.dataReader.GetSize() {
-		panic("Read past end of stream")
+		panic(common.NewMothCorruptionError(ur.dataReader.GetMothDataSourceId(), "Read past end of stream"))
 	}
 	chunk := ur.dataReader.SeekBuffer(ur.nextPosition)
 	ur.dataReaderMemoryUsage.SetBytes(ur.dataReader.GetRetainedSize())
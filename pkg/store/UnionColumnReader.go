@@ -6,6 +6,7 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/memory"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -61,7 +62,7 @@ func (ur *UnionColumnReader) ReadBlock() block.Block {
 		}
 		if ur.readOffset > 0 {
 			if ur.dataStream == nil {
-				panic("Value is not null but data stream is missing")
+				panic(common.NewMothCorruptionError(ur.column.GetMothDataSourceId(), "Value is not null but data stream is missing"))
 			}
 			readOffsets := make([]int32, ur.fieldReaders.Size())
 			for _, tag := range ur.dataStream.Next2(ur.readOffset) {
@@ -152,12 +153,15 @@ func (ur *UnionColumnReader) ToString() string {
 
 func (ur *UnionColumnReader) getBlocks(positionCount int32) []block.Block {
 	if ur.dataStream == nil {
-		panic("Value is not null but data stream is missing")
+		panic(common.NewMothCorruptionError(ur.column.GetMothDataSourceId(), "Value is not null but data stream is missing"))
 	}
 	blocks := make([]block.Block, ur.fieldReaders.Size()+1)
 	tags := ur.dataStream.Next2(positionCount)
 	blocks[0] = block.NewByteArrayBlock(positionCount, optional.Empty[[]bool](), tags)
-	valueIsNonNull := make([][]bool, ur.fieldReaders.Size(), positionCount)
+	valueIsNonNull := make([][]bool, ur.fieldReaders.Size())
+	for i := range valueIsNonNull {
+		valueIsNonNull[i] = make([]bool, positionCount)
+	}
 	nonNullValueCount := make([]int32, ur.fieldReaders.Size())
 	for i := util.INT32_ZERO; i < positionCount; i++ {
 		valueIsNonNull[tags[i]][i] = true
@@ -0,0 +1,215 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/mothdb-bd/orc-go/pkg/optional"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+var (
+	UNION_INSTANCE_SIZE   int32                    = util.SizeOf(&UnionColumnWriter{})
+	UNION_COLUMN_ENCODING *metadata.ColumnEncoding = metadata.NewColumnEncoding(metadata.DIRECT, 0)
+)
+
+/**
+ * Writes a union column, see metadata.CreateUnionType for the block layout. The tag of
+ * every non-null row is written to the DATA stream, and each variant writer only receives
+ * the rows selected by its tag.
+ */
+type UnionColumnWriter struct {
+	//继承
+	ColumnWriter
+
+	columnId                 metadata.MothColumnId
+	compressed               bool
+	dataStream               *ByteOutputStream
+	presentStream            *PresentOutputStream
+	variantWriters           *util.ArrayList[ColumnWriter]
+	rowGroupColumnStatistics *util.ArrayList[*metadata.ColumnStatistics]
+	nonNullValueCount        int32
+	closed                   bool
+}
+
+func NewUnionColumnWriter(columnId metadata.MothColumnId, compression metadata.CompressionKind, compressionLevel int32, bufferSize int32, variantWriters *util.ArrayList[ColumnWriter]) *UnionColumnWriter {
+	ur := new(UnionColumnWriter)
+	ur.columnId = columnId
+	ur.compressed = compression != metadata.NONE
+	ur.variantWriters = variantWriters
+	ur.dataStream = NewByteOutputStream(compression, compressionLevel, bufferSize)
+	ur.presentStream = NewPresentOutputStream(compression, compressionLevel, bufferSize)
+	ur.rowGroupColumnStatistics = util.NewArrayList[*metadata.ColumnStatistics]()
+	return ur
+}
+
+// @Override
+func (ur *UnionColumnWriter) GetNestedColumnWriters() *util.ArrayList[ColumnWriter] {
+	nestedColumnWriters := util.NewArrayList[ColumnWriter]()
+	for _, variantWriter := range ur.variantWriters.ToArray() {
+		nestedColumnWriters.Add(variantWriter).AddAll(variantWriter.GetNestedColumnWriters())
+	}
+	return nestedColumnWriters
+}
+
+// @Override
+func (ur *UnionColumnWriter) GetColumnEncodings() map[metadata.MothColumnId]*metadata.ColumnEncoding {
+	encodings := util.NewMap(ur.columnId, UNION_COLUMN_ENCODING)
+	for _, variantWriter := range ur.variantWriters.ToArray() {
+		util.PutAll(encodings, variantWriter.GetColumnEncodings())
+	}
+	return encodings
+}
+
+// @Override
+func (ur *UnionColumnWriter) BeginRowGroup() {
+	ur.presentStream.RecordCheckpoint()
+	ur.dataStream.RecordCheckpoint()
+	ur.variantWriters.ForEach(ColumnWriter.BeginRowGroup)
+}
+
+// @Override
+func (ur *UnionColumnWriter) WriteBlock(b block.Block) {
+	util.CheckState(!ur.closed)
+	util.CheckArgument2(b.GetPositionCount() > 0, "Block is empty")
+	columnarRow := block.ToColumnarRow(b)
+	ur.writeColumnarRow(columnarRow)
+}
+
+func (ur *UnionColumnWriter) writeColumnarRow(columnarRow *block.ColumnarRow) {
+	for position := util.INT32_ZERO; position < columnarRow.GetPositionCount(); position++ {
+		ur.presentStream.WriteBoolean(!columnarRow.IsNull(position))
+	}
+
+	// the field blocks only contain the non-null rows
+	tagBlock := columnarRow.GetField(0)
+	variantPositions := make([][]int32, ur.variantWriters.Size())
+	for position := util.INT32_ZERO; position < tagBlock.GetPositionCount(); position++ {
+		if tagBlock.IsNull(position) {
+			panic("Union tag is null")
+		}
+		tag := block.TINYINT.GetLong(tagBlock, position)
+		if tag < 0 || tag >= int64(ur.variantWriters.Size()) {
+			panic(fmt.Sprintf("Invalid union tag %d, union has %d variants", tag, ur.variantWriters.Size()))
+		}
+		ur.dataStream.WriteByte(byte(tag))
+		variantPositions[tag] = append(variantPositions[tag], position)
+		ur.nonNullValueCount++
+	}
+	for i := util.INT32_ZERO; i < ur.variantWriters.SizeInt32(); i++ {
+		positions := variantPositions[i]
+		if len(positions) > 0 {
+			ur.variantWriters.GetByInt32(i).WriteBlock(columnarRow.GetField(i+1).CopyPositions(positions, 0, util.Lens(positions)))
+		}
+	}
+}
+
+// @Override
+func (ur *UnionColumnWriter) FinishRowGroup() map[metadata.MothColumnId]*metadata.ColumnStatistics {
+	util.CheckState(!ur.closed)
+	statistics := metadata.NewColumnStatistics(int64(ur.nonNullValueCount), 0, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	ur.rowGroupColumnStatistics.Add(statistics)
+	ur.nonNullValueCount = 0
+	columnStatistics := util.NewMap(ur.columnId, statistics)
+	for _, variantWriter := range ur.variantWriters.ToArray() {
+		util.PutAll(columnStatistics, variantWriter.FinishRowGroup())
+	}
+	return columnStatistics
+}
+
+// @Override
+func (ur *UnionColumnWriter) Close() {
+	ur.closed = true
+	ur.variantWriters.ForEach(ColumnWriter.Close)
+	ur.dataStream.Close()
+	ur.presentStream.Close()
+}
+
+// @Override
+func (ur *UnionColumnWriter) GetColumnStripeStatistics() map[metadata.MothColumnId]*metadata.ColumnStatistics {
+	util.CheckState(ur.closed)
+	columnStatistics := util.NewMap(ur.columnId, metadata.MergeColumnStatistics(ur.rowGroupColumnStatistics))
+	for _, variantWriter := range ur.variantWriters.ToArray() {
+		util.PutAll(columnStatistics, variantWriter.GetColumnStripeStatistics())
+	}
+	return columnStatistics
+}
+
+// @Override
+func (ur *UnionColumnWriter) GetIndexStreams(metadataWriter *CompressedMetadataWriter) *util.ArrayList[*StreamDataOutput] {
+	util.CheckState(ur.closed)
+	rowGroupIndexes := util.NewArrayList[*metadata.RowGroupIndex]()
+	dataCheckpoints := ur.dataStream.GetCheckpoints()
+	presentCheckpoints := ur.presentStream.GetCheckpoints()
+	for i := 0; i < ur.rowGroupColumnStatistics.Size(); i++ {
+		groupId := i
+		columnStatistics := ur.rowGroupColumnStatistics.Get(groupId)
+		dataCheckpoint := dataCheckpoints.Get(groupId)
+		presentCheckpoint := optional.Map(presentCheckpoints, func(checkpoints *util.ArrayList[*BooleanStreamCheckpoint]) *BooleanStreamCheckpoint {
+			return checkpoints.Get(groupId)
+		})
+		// the tag stream is a byte stream, so the positions are the same as for a byte column
+		positions := createByteColumnPositionList(ur.compressed, dataCheckpoint, presentCheckpoint)
+		rowGroupIndexes.Add(metadata.NewRowGroupIndex(positions, columnStatistics))
+	}
+	slice := metadataWriter.WriteRowIndexes(rowGroupIndexes)
+	stream := metadata.NewStream(ur.columnId, metadata.ROW_INDEX, slice.SizeInt32(), false)
+
+	indexStreams := util.NewArrayList(NewStreamDataOutput(slice, stream))
+	for _, variantWriter := range ur.variantWriters.ToArray() {
+		indexStreams.AddAll(variantWriter.GetIndexStreams(metadataWriter))
+		indexStreams.AddAll(variantWriter.GetBloomFilters(metadataWriter))
+	}
+	return indexStreams
+}
+
+// @Override
+func (ur *UnionColumnWriter) GetBloomFilters(metadataWriter *CompressedMetadataWriter) *util.ArrayList[*StreamDataOutput] {
+	return util.EMPTY_LIST[*StreamDataOutput]()
+}
+
+// @Override
+func (ur *UnionColumnWriter) GetDataStreams() *util.ArrayList[*StreamDataOutput] {
+	util.CheckState(ur.closed)
+	outputDataStreams := util.NewArrayList[*StreamDataOutput]()
+	ur.presentStream.GetStreamDataOutput(ur.columnId).IfPresent(func(s *StreamDataOutput) {
+		outputDataStreams.Add(s)
+	})
+	outputDataStreams.Add(ur.dataStream.GetStreamDataOutput(ur.columnId))
+	for _, variantWriter := range ur.variantWriters.ToArray() {
+		outputDataStreams.AddAll(variantWriter.GetDataStreams())
+	}
+	return outputDataStreams
+}
+
+// @Override
+func (ur *UnionColumnWriter) GetBufferedBytes() int64 {
+	bufferedBytes := ur.dataStream.GetBufferedBytes() + ur.presentStream.GetBufferedBytes()
+	for _, variantWriter := range ur.variantWriters.ToArray() {
+		bufferedBytes += variantWriter.GetBufferedBytes()
+	}
+	return bufferedBytes
+}
+
+// @Override
+func (ur *UnionColumnWriter) GetRetainedBytes() int64 {
+	retainedBytes := int64(UNION_INSTANCE_SIZE) + ur.dataStream.GetRetainedBytes() + ur.presentStream.GetRetainedBytes()
+	for _, variantWriter := range ur.variantWriters.ToArray() {
+		retainedBytes += variantWriter.GetRetainedBytes()
+	}
+	for _, statistics := range ur.rowGroupColumnStatistics.ToArray() {
+		retainedBytes += statistics.GetRetainedSizeInBytes()
+	}
+	return retainedBytes
+}
+
+// @Override
+func (ur *UnionColumnWriter) Reset() {
+	ur.closed = false
+	ur.dataStream.Reset()
+	ur.presentStream.Reset()
+	ur.variantWriters.ForEach(ColumnWriter.Reset)
+	ur.rowGroupColumnStatistics.Clear()
+	ur.nonNullValueCount = 0
+}
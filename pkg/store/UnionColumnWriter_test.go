@@ -0,0 +1,131 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mothdb-bd/orc-go/pkg/memory"
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/optional"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/spi"
+	"github.com/mothdb-bd/orc-go/pkg/spi/block"
+	"github.com/mothdb-bd/orc-go/pkg/spi/predicate"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+// unionTestValue returns the tag of row i, or -1 when the union is null
+func unionTestValue(i int) int8 {
+	if i%7 == 3 {
+		return -1
+	}
+	return int8(i % 2)
+}
+
+func TestUnionColumnWriter_WriteBlock(t *testing.T) {
+	unionType := metadata.CreateUnionType(block.BIGINT, block.VARCHAR)
+	// a struct of the fields of a union is not a union
+	structType := block.CreateRowType(block.CreateField("tag", block.TINYINT), block.CreateField("field0", block.BIGINT), block.CreateField("field1", block.VARCHAR))
+	if !metadata.IsUnionType(unionType) || metadata.IsUnionType(structType) || metadata.IsUnionType(block.AnonymousRow(block.TINYINT, block.BIGINT)) {
+		t.Fatalf("IsUnionType() does not match CreateUnionType()")
+	}
+	if kind := metadata.CreateRootMothType(util.NewArrayList("value"), util.NewArrayList[block.Type](structType)).Get(1).GetMothTypeKind(); kind != metadata.STRUCT {
+		t.Fatalf("wrote a struct of the fields of a union as %s", kind)
+	}
+
+	rowCount := 2500
+	types := util.NewArrayList[block.Type](block.BIGINT, unionType)
+	columnNames := util.NewArrayList("id", "value")
+	out := new(memoryWriteCloser)
+	options := NewMothWriterOptions().WithStripeMaxRowCount(1000).WithRowGroupMaxRowCount(100)
+	writer := NewMothWriter(NewOutputStreamMothDataSink(mothio.NewOutputStream(out)), columnNames, types, metadata.CreateRootMothType(columnNames, types), metadata.ZLIB, options, util.EmptyMap[string, string](), NewMothWriterStats())
+	for start := 0; start < rowCount; start += 500 {
+		ids := block.BIGINT.CreateBlockBuilder2(nil, 500)
+		tags := block.TINYINT.CreateBlockBuilder2(nil, 500)
+		longs := block.BIGINT.CreateBlockBuilder2(nil, 500)
+		strings := block.VARCHAR.CreateBlockBuilder2(nil, 500)
+		rowIsNull := make([]bool, 500)
+		for i := start; i < start+500; i++ {
+			block.WriteNativeValue(block.BIGINT, ids, int64(i))
+			switch unionTestValue(i) {
+			case -1:
+				rowIsNull[i-start] = true
+				continue
+			case 0:
+				block.WriteNativeValue(block.BIGINT, longs, int64(i))
+				strings.AppendNull()
+			case 1:
+				longs.AppendNull()
+				block.WriteNativeValue(block.VARCHAR, strings, fmt.Sprintf("value-%d", i))
+			}
+			block.WriteNativeValue(block.TINYINT, tags, int64(unionTestValue(i)))
+		}
+		values := block.FromFieldBlocks(500, optional.Of(rowIsNull), []block.Block{tags.Build(), longs.Build(), strings.Build()})
+		writer.Write(spi.NewPage3(500, ids.Build(), values))
+	}
+	writer.Close()
+
+	reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), slice.NewWithBuf(out.Bytes())), NewMothReaderOptions()).Get()
+	valueColumn := reader.GetRootColumn().GetNestedColumns().Get(1)
+	if valueColumn.GetColumnType() != metadata.UNION || valueColumn.GetNestedColumns().Size() != 2 {
+		t.Fatalf("value column is %d with %d variants, want UNION with 2", valueColumn.GetColumnType(), valueColumn.GetNestedColumns().Size())
+	}
+	fileStatistics := reader.GetFooter().GetFileStats().OrElse(nil)
+	longStatistics := fileStatistics.Get(valueColumn.GetNestedColumns().Get(0).GetColumnId())
+	if longStatistics.GetIntegerStatistics().GetMin() != 0 || longStatistics.GetIntegerStatistics().GetMax() != 2498 {
+		t.Errorf("variant statistics = %v, want min 0 and max 2498", longStatistics.GetIntegerStatistics())
+	}
+
+	// reads the rows from firstRow on, checking the tag and the variants of every row
+	readUnions := func(mothPredicate MothPredicate, firstRow int) int {
+		recordReader := reader.CreateRecordReader(reader.GetRootColumn().GetNestedColumns(), types, mothPredicate, time.UTC, memory.NewSimpleAggregatedMemoryContext(), INITIAL_BATCH_SIZE)
+		defer recordReader.Close()
+		row := firstRow
+		for page := recordReader.NextPage(); page != nil; page = recordReader.NextPage() {
+			ids := page.GetBlock(0).GetLoadedBlock()
+			values := page.GetBlock(1).GetLoadedBlock()
+			columnarRow := block.ToColumnarRow(values)
+			field := util.INT32_ZERO
+			for position := util.INT32_ZERO; position < ids.GetPositionCount(); position++ {
+				id := int(block.BIGINT.GetLong(ids, position))
+				if id != row {
+					t.Fatalf("id = %d, want %d", id, row)
+				}
+				row++
+				tag := unionTestValue(id)
+				if columnarRow.IsNull(position) != (tag == -1) {
+					t.Fatalf("row %d: null = %v, want %v", id, columnarRow.IsNull(position), tag == -1)
+				}
+				if tag == -1 {
+					continue
+				}
+				if got := int8(block.TINYINT.GetLong(columnarRow.GetField(0), field)); got != tag {
+					t.Fatalf("row %d: tag = %d, want %d", id, got, tag)
+				}
+				longs := columnarRow.GetField(1).GetLoadedBlock()
+				strings := columnarRow.GetField(2).GetLoadedBlock()
+				if tag == 0 && (!strings.IsNull(field) || block.BIGINT.GetLong(longs, field) != int64(id)) {
+					t.Fatalf("row %d: long variant mismatch", id)
+				}
+				if tag == 1 && (!longs.IsNull(field) || block.VARCHAR.GetSlice(strings, field).String() != fmt.Sprintf("value-%d", id)) {
+					t.Fatalf("row %d: string variant mismatch", id)
+				}
+				field++
+			}
+		}
+		return row - firstRow
+	}
+	if rows := readUnions(TRUE, 0); rows != rowCount {
+		t.Errorf("read %d rows, want %d", rows, rowCount)
+	}
+
+	// a row group in the middle of a stripe is read from the checkpoints of the tag stream
+	domain := predicate.NewDomain(predicate.ValueSetOfRanges(block.BIGINT, predicate.NewRange(block.BIGINT, int64(1250), true, int64(1260), true)), false)
+	mothPredicate := NewTupleDomainMothPredicateBuilder().AddColumn(metadata.NewMothColumnId(1), domain).Build()
+	if rows := readUnions(mothPredicate, 1200); rows != 100 {
+		t.Errorf("read %d rows of a row group, want 100", rows)
+	}
+}
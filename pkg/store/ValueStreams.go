@@ -1,8 +1,7 @@
 package store
 
 import (
-	"fmt"
-
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
 )
 
@@ -56,7 +55,7 @@ func CreateValueStreams(streamId StreamId, chunkLoader MothChunkLoader, kind met
 		default:
 		}
 	}
-	panic(fmt.Sprintf("Unsupported column type %d for stream %s with encoding %d", kind, streamId, encoding))
+	panic(common.NewMothUnsupportedError(nil, "Unsupported column type %d for stream %s with encoding %d", kind, streamId, encoding))
 }
 
 func createLongStream(inputStream *MothInputStream, encoding metadata.ColumnEncodingKind, signed bool) IValueInputStream {
@@ -65,6 +64,6 @@ func createLongStream(inputStream *MothInputStream, encoding metadata.ColumnEnco
 	} else if encoding == metadata.DIRECT || encoding == metadata.DICTIONARY {
 		return NewLongInputStreamV1(inputStream, signed)
 	} else {
-		panic(fmt.Sprintf("Unsupported encoding for long stream: %d", encoding))
+		panic(common.NewMothUnsupportedError(nil, "Unsupported encoding for long stream: %d", encoding))
 	}
 }
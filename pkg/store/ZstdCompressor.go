@@ -1,43 +1,87 @@
 package store
 
-// import (
-// 	"bytes"
-// 	"fmt"
-
-// 	"github.com/mothdb-bd/orc-go/pkg/util"
-// 	"github.com/DataDog/zstd"
-// )
-
-// type ZstdCompressor struct {
-// 	// 继承
-// 	Compressor
-// }
-
-// func NewZstdCompressor() Compressor {
-// 	return new(ZstdCompressor)
-// }
-
-// //@Override
-// func (sr *ZstdCompressor) MaxCompressedLength(uncompressedSize int32) int32 {
-// 	return int32(zstd.CompressBound(int(uncompressedSize)))
-// }
-
-// //@Override
-// func (sr *ZstdCompressor) Compress(input []byte, inputOffset int32, inputLength int32, output []byte, outputOffset int32, maxOutputLength int32) int32 {
-// 	maxCompressedLength := sr.MaxCompressedLength(inputLength)
-// 	if maxOutputLength < maxCompressedLength {
-// 		panic(fmt.Sprintf("Output buffer must be at least %d bytes", maxCompressedLength))
-// 	}
-
-// 	dest := make([]byte, maxCompressedLength)
-// 	zstd.Compress(input[inputOffset:inputOffset+inputLength], dest)
-
-// 	util.CopyArrays(dest, 0, output, outputOffset, maxCompressedLength)
-
-// 	return maxCompressedLength
-// }
-
-// //@Override
-// func (dr *ZstdCompressor) Decompress2(input *bytes.Buffer, output *bytes.Buffer) {
-// 	panic("Compression of byte buffer not supported for snappy")
-// }
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	MIN_ZSTD_COMPRESSION_LEVEL int32 = 1
+	MAX_ZSTD_COMPRESSION_LEVEL int32 = 22
+
+	// encoders are safe for concurrent EncodeAll calls, so one encoder is shared per encoder
+	// level, which bounds the shared encoders to the four levels of the encoder
+	zstdEncoders     = make(map[zstd.EncoderLevel]*zstd.Encoder)
+	zstdEncodersLock sync.Mutex
+)
+
+type ZstdCompressor struct {
+	// 继承
+	Compressor
+
+	encoder *zstd.Encoder
+}
+
+/**
+ * The encoder implements four of the zstd levels, so the compression level is mapped onto the
+ * closest of them: levels 1 and 2 use the fastest encoder, 3 to 5 the default encoder, 6 to 9
+ * the better encoder and 10 to 22 the best encoder. Levels that map onto the same encoder write
+ * the same bytes.
+ */
+func NewZstdCompressor(compressionLevel int32) Compressor {
+	if compressionLevel < MIN_ZSTD_COMPRESSION_LEVEL || compressionLevel > MAX_ZSTD_COMPRESSION_LEVEL {
+		panic(fmt.Sprintf("Invalid zstd compression level %d, must be between %d and %d", compressionLevel, MIN_ZSTD_COMPRESSION_LEVEL, MAX_ZSTD_COMPRESSION_LEVEL))
+	}
+	sr := new(ZstdCompressor)
+	sr.encoder = getZstdEncoder(compressionLevel)
+	return sr
+}
+
+func getZstdEncoder(compressionLevel int32) *zstd.Encoder {
+	encoderLevel := zstd.EncoderLevelFromZstd(int(compressionLevel))
+	zstdEncodersLock.Lock()
+	defer zstdEncodersLock.Unlock()
+	encoder, ok := zstdEncoders[encoderLevel]
+	if !ok {
+		var err error
+		encoder, err = zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel), zstd.WithEncoderCRC(false))
+		if err != nil {
+			panic(fmt.Sprintf("Failed to create zstd encoder: %s", err.Error()))
+		}
+		zstdEncoders[encoderLevel] = encoder
+	}
+	return encoder
+}
+
+// @Override
+func (sr *ZstdCompressor) MaxCompressedLength(uncompressedSize int32) int32 {
+	// ZSTD_COMPRESSBOUND
+	result := uncompressedSize + (uncompressedSize >> 8)
+	if uncompressedSize < 128*1024 {
+		result += (128*1024 - uncompressedSize) >> 11
+	}
+	return result
+}
+
+// @Override
+func (sr *ZstdCompressor) Compress(input []byte, inputOffset int32, inputLength int32, output []byte, outputOffset int32, maxOutputLength int32) int32 {
+	maxCompressedLength := sr.MaxCompressedLength(inputLength)
+	if maxOutputLength < maxCompressedLength {
+		panic(fmt.Sprintf("Output buffer must be at least %d bytes", maxCompressedLength))
+	}
+
+	dest := output[outputOffset:outputOffset:(outputOffset + maxOutputLength)]
+	compressed := sr.encoder.EncodeAll(input[inputOffset:inputOffset+inputLength], dest)
+	if int32(len(compressed)) > maxOutputLength {
+		panic(fmt.Sprintf("Zstd output (%d bytes) exceeds the output buffer (%d bytes)", len(compressed), maxOutputLength))
+	}
+	return int32(len(compressed))
+}
+
+// @Override
+func (sr *ZstdCompressor) Compress2(input *bytes.Buffer, output *bytes.Buffer) {
+	panic("Compression of byte buffer not supported for zstd")
+}
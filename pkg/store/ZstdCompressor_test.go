@@ -0,0 +1,78 @@
+package store
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/mothdb-bd/orc-go/pkg/store/common"
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+func TestZstdCompressor_Compress(t *testing.T) {
+	input := bytes.Repeat([]byte("1122cccdddeeaass908873331122"), 100)
+	tests := []struct {
+		name  string
+		level int32
+	}{
+		{"fastest", 1},
+		{"default", DEFAULT_ZSTD_COMPRESSION_LEVEL},
+		{"best", 19},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := NewZstdCompressor(tt.level)
+			output := make([]byte, sr.MaxCompressedLength(int32(len(input))))
+			compressedSize := sr.Compress(input, 0, int32(len(input)), output, 0, int32(len(output)))
+			if compressedSize <= 0 || compressedSize >= int32(len(input)) {
+				t.Fatalf("ZstdCompressor.Compress() = %v, want a size between 0 and %v", compressedSize, len(input))
+			}
+
+			decompressed := new(retainedOutputBuffer)
+			size := NewMothZstdDecompressor(common.NewMothDataSourceId("id1"), MAX_BUFFER_SIZE).Decompress(output, 0, compressedSize, decompressed)
+			if size != int32(len(input)) || !bytes.Equal(decompressed.buffer[:size], input) {
+				t.Errorf("MothZstdDecompressor.Decompress() = %v bytes, want %v", size, len(input))
+			}
+		})
+	}
+}
+
+func TestZstdCompressor_EncoderLevels(t *testing.T) {
+	encoders := make(map[*zstd.Encoder]bool)
+	for level := MIN_ZSTD_COMPRESSION_LEVEL; level <= MAX_ZSTD_COMPRESSION_LEVEL; level++ {
+		encoders[NewZstdCompressor(level).(*ZstdCompressor).encoder] = true
+	}
+	if len(encoders) != 4 {
+		t.Fatalf("levels %d to %d use %d encoders, want 4", MIN_ZSTD_COMPRESSION_LEVEL, MAX_ZSTD_COMPRESSION_LEVEL, len(encoders))
+	}
+	if NewZstdCompressor(1).(*ZstdCompressor).encoder != NewZstdCompressor(2).(*ZstdCompressor).encoder || NewZstdCompressor(10).(*ZstdCompressor).encoder != NewZstdCompressor(22).(*ZstdCompressor).encoder {
+		t.Fatal("levels of the same encoder level do not share the encoder")
+	}
+}
+
+// retainedOutputBuffer keeps the last buffer handed out so the decompressed bytes can be checked
+type retainedOutputBuffer struct {
+	buffer []byte
+}
+
+func (buf *retainedOutputBuffer) Initialize(size int32) []byte {
+	buf.buffer = make([]byte, size)
+	return buf.buffer
+}
+
+func (buf *retainedOutputBuffer) Grow(size int32) []byte {
+	buf.buffer = append(buf.buffer, make([]byte, int(size)-len(buf.buffer))...)
+	return buf.buffer
+}
+
+func TestZstdCompressor_WriteFile(t *testing.T) {
+	data := writeTestFile(5000, NewMothWriterOptions().WithZstdCompressionLevel(7), metadata.ZSTD)
+	reader := CreateMothReader(NewMemoryMothDataSource(common.NewMothDataSourceId("test"), data), NewMothReaderOptions()).Get()
+	if reader.GetCompressionKind() != metadata.ZSTD {
+		t.Errorf("compression = %v, want ZSTD", reader.GetCompressionKind())
+	}
+	ids := readIds(data, NewMothReaderOptions(), TRUE)
+	if len(ids) != 5000 || ids[0] != 0 || ids[4999] != 4999 {
+		t.Errorf("read %d rows, want 5000", len(ids))
+	}
+}
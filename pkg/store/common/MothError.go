@@ -0,0 +1,153 @@
+package common
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"runtime"
+	"strings"
+
+	"github.com/mothdb-bd/orc-go/pkg/errors"
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+)
+
+/**
+ * Failure of reading or writing a moth file. The readers raise it by panicking with it, the error
+ * returning APIs recover it and return it together with the stripe and column being read.
+ */
+type MothError struct {
+	code             *errors.StandardErrorCode
+	mothDataSourceId *MothDataSourceId
+	stripeOffset     int64
+	columnPath       string
+	message          string
+	cause            error
+}
+
+func NewMothError(code *errors.StandardErrorCode, mothDataSourceId *MothDataSourceId, format string, args ...interface{}) *MothError {
+	return NewMothErrorWithCause(code, mothDataSourceId, nil, format, args...)
+}
+
+func NewMothErrorWithCause(code *errors.StandardErrorCode, mothDataSourceId *MothDataSourceId, cause error, format string, args ...interface{}) *MothError {
+	mr := new(MothError)
+	mr.code = code
+	mr.mothDataSourceId = mothDataSourceId
+	mr.stripeOffset = -1
+	mr.message = fmt.Sprintf(format, args...)
+	mr.cause = cause
+	return mr
+}
+
+func NewMothCorruptionError(mothDataSourceId *MothDataSourceId, format string, args ...interface{}) *MothError {
+	return NewMothError(errors.CORRUPT_FILE, mothDataSourceId, format, args...)
+}
+
+func NewMothUnsupportedError(mothDataSourceId *MothDataSourceId, format string, args ...interface{}) *MothError {
+	return NewMothError(errors.NOT_SUPPORTED, mothDataSourceId, format, args...)
+}
+
+/**
+ * Converts the value of a recovered panic to a MothError. Panics raised without a MothError are
+ * classified by their value: I/O failures, unsupported operations and standard errors keep their
+ * meaning, anything else is an internal error. The data source is recorded when the failure does
+ * not name one yet.
+ */
+func AsMothError(failure interface{}, mothDataSourceId *MothDataSourceId) *MothError {
+	var mothError *MothError
+	switch f := failure.(type) {
+	case *MothError:
+		mothError = f
+	case *errors.StandardError:
+		mothError = NewMothErrorWithCause(f.GetCode(), nil, f, "%s", f.GetMessage())
+	case *errors.UnsupportedError:
+		mothError = NewMothErrorWithCause(errors.NOT_SUPPORTED, nil, f, "%s", f.Error())
+	case runtime.Error:
+		mothError = NewMothErrorWithCause(errors.GENERIC_INTERNAL_ERROR, nil, f, "%s", f.Error())
+	case error:
+		if isIOError(f) {
+			mothError = NewMothErrorWithCause(errors.IO_ERROR, nil, f, "%s", f.Error())
+		} else {
+			mothError = NewMothErrorWithCause(errors.GENERIC_INTERNAL_ERROR, nil, f, "%s", f.Error())
+		}
+	default:
+		mothError = NewMothError(errors.GENERIC_INTERNAL_ERROR, nil, "%v", failure)
+	}
+	if mothError.mothDataSourceId == nil {
+		mothError.mothDataSourceId = mothDataSourceId
+	}
+	return mothError
+}
+
+func isIOError(err error) bool {
+	var ioError *mothio.IOError
+	var pathError *fs.PathError
+	return goerrors.As(err, &ioError) || goerrors.As(err, &pathError) || goerrors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func (mr *MothError) GetCode() *errors.StandardErrorCode {
+	return mr.code
+}
+
+func (mr *MothError) GetMothDataSourceId() *MothDataSourceId {
+	return mr.mothDataSourceId
+}
+
+/**
+ * Returns the offset of the stripe that was read when the failure happened, -1 when the failure
+ * is not related to a stripe.
+ */
+func (mr *MothError) GetStripeOffset() int64 {
+	return mr.stripeOffset
+}
+
+/**
+ * Records the stripe being read, unless the failure already names one.
+ */
+func (mr *MothError) SetStripeOffset(stripeOffset int64) {
+	if mr.stripeOffset < 0 {
+		mr.stripeOffset = stripeOffset
+	}
+}
+
+/**
+ * Returns the path of the column that was read when the failure happened, empty when the failure
+ * is not related to a column.
+ */
+func (mr *MothError) GetColumnPath() string {
+	return mr.columnPath
+}
+
+/**
+ * Records the column being read, unless the failure already names one.
+ */
+func (mr *MothError) SetColumnPath(columnPath string) {
+	if mr.columnPath == "" {
+		mr.columnPath = columnPath
+	}
+}
+
+func (mr *MothError) GetMessage() string {
+	return mr.message
+}
+
+func (mr *MothError) Error() string {
+	context := make([]string, 0, 3)
+	if mr.mothDataSourceId != nil {
+		context = append(context, mr.mothDataSourceId.String())
+	}
+	if mr.stripeOffset >= 0 {
+		context = append(context, fmt.Sprintf("stripe offset %d", mr.stripeOffset))
+	}
+	if mr.columnPath != "" {
+		context = append(context, "column "+mr.columnPath)
+	}
+	if len(context) == 0 {
+		return mr.code.GetName() + ": " + mr.message
+	}
+	return fmt.Sprintf("%s: %s [%s]", mr.code.GetName(), mr.message, strings.Join(context, ", "))
+}
+
+func (mr *MothError) Unwrap() error {
+	return mr.cause
+}
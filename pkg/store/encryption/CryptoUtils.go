@@ -0,0 +1,104 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+var (
+	IV_LENGTH            int32 = aes.BlockSize
+	COLUMN_ID_LENGTH     int32 = 3
+	KIND_LENGTH          int32 = 2
+	STRIPE_ID_LENGTH     int32 = 3
+	MAX_COLUMN_ID        int64 = (1 << (COLUMN_ID_LENGTH * 8)) - 1
+	MAX_STRIPE_ID        int64 = (1 << (STRIPE_ID_LENGTH * 8)) - 1
+	STRIPE_STATISTICS_ID int32 = 100
+	FILE_STATISTICS_ID   int32 = 101
+	KEY_INDEX_ID         int32 = 102
+)
+
+/**
+ * Creates the IV of an encrypted stream: 3 bytes column id, 2 bytes stream kind and 3 bytes
+ * stripe id, followed by the 8 byte block counter. The layout and the stream kind numbers are
+ * the ones used by ORC.
+ */
+func CreateIv(columnId metadata.MothColumnId, streamKind metadata.StreamKind, stripeId uint64) []byte {
+	if int64(columnId) > MAX_COLUMN_ID {
+		panic(fmt.Sprintf("Column id %d is too large for an IV", columnId))
+	}
+	if int64(stripeId) > MAX_STRIPE_ID {
+		panic(fmt.Sprintf("Stripe id %d is too large for an IV", stripeId))
+	}
+	iv := make([]byte, IV_LENGTH)
+	putUint24(iv[0:], uint32(columnId))
+	binary.BigEndian.PutUint16(iv[COLUMN_ID_LENGTH:], uint16(toKindId(streamKind)))
+	putUint24(iv[COLUMN_ID_LENGTH+KIND_LENGTH:], uint32(stripeId))
+	return iv
+}
+
+func toKindId(streamKind metadata.StreamKind) int32 {
+	switch streamKind {
+	case metadata.STRIPE_STATISTICS:
+		return STRIPE_STATISTICS_ID
+	case metadata.FILE_STATISTICS:
+		return FILE_STATISTICS_ID
+	case metadata.KEY_INDEX:
+		return KEY_INDEX_ID
+	}
+	// the other kinds are numbered like the protobuf stream kinds
+	return int32(streamKind)
+}
+
+func putUint24(b []byte, value uint32) {
+	b[0] = byte(value >> 16)
+	b[1] = byte(value >> 8)
+	b[2] = byte(value)
+}
+
+/**
+ * Returns an AES-CTR key stream positioned offset bytes after the start of the stream with the
+ * given IV, so a stream can be decrypted from any position.
+ */
+func NewCipherStream(key []byte, iv []byte, offset int64) cipher.Stream {
+	blockCipher, err := aes.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+	counter := make([]byte, IV_LENGTH)
+	copy(counter, iv)
+	addToCounter(counter, uint64(offset/int64(IV_LENGTH)))
+	stream := cipher.NewCTR(blockCipher, counter)
+	if skip := offset % int64(IV_LENGTH); skip > 0 {
+		discard := make([]byte, skip)
+		stream.XORKeyStream(discard, discard)
+	}
+	return stream
+}
+
+func addToCounter(counter []byte, blocks uint64) {
+	for i := len(counter) - 1; i >= 0 && blocks > 0; i-- {
+		sum := uint64(counter[i]) + (blocks & 0xff)
+		counter[i] = byte(sum)
+		blocks = (blocks >> 8) + (sum >> 8)
+	}
+}
+
+/**
+ * Encrypts or decrypts data in place, they are the same operation in CTR mode
+ */
+func Crypt(key []byte, iv []byte, offset int64, data []byte) {
+	NewCipherStream(key, iv, offset).XORKeyStream(data, data)
+}
+
+func randomBytes(length int32) []byte {
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}
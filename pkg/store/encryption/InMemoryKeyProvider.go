@@ -0,0 +1,123 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"sort"
+
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Key provider holding the master keys in memory, intended for tests and for local key files.
+ * Local keys are wrapped with AES-GCM as the random nonce followed by the sealed local key, so a
+ * wrong master key is detected instead of producing a wrong local key.
+ */
+type InMemoryKeyProvider struct {
+	// 继承
+	KeyProvider
+
+	currentKeys map[string]*metadata.EncryptionKey
+	masterKeys  map[string]map[uint32][]byte
+}
+
+func NewInMemoryKeyProvider() *InMemoryKeyProvider {
+	ir := new(InMemoryKeyProvider)
+	ir.currentKeys = make(map[string]*metadata.EncryptionKey)
+	ir.masterKeys = make(map[string]map[uint32][]byte)
+	return ir
+}
+
+/**
+ * Adds a version of a master key, the highest version of a key is used for new files.
+ */
+func (ir *InMemoryKeyProvider) AddKey(keyName string, keyVersion uint32, algorithm metadata.EncryptionAlgorithm, masterKey []byte) *InMemoryKeyProvider {
+	if util.Lens(masterKey) != algorithm.GetKeyLength() {
+		panic(fmt.Sprintf("Key %s has %d bytes, %s needs %d", keyName, len(masterKey), algorithm, algorithm.GetKeyLength()))
+	}
+	versions, ok := ir.masterKeys[keyName]
+	if !ok {
+		versions = make(map[uint32][]byte)
+		ir.masterKeys[keyName] = versions
+	}
+	versions[keyVersion] = bytes.Clone(masterKey)
+	current, ok := ir.currentKeys[keyName]
+	if !ok || current.GetKeyVersion() < keyVersion {
+		ir.currentKeys[keyName] = metadata.NewEncryptionKey(keyName, keyVersion, algorithm)
+	}
+	return ir
+}
+
+// @Override
+func (ir *InMemoryKeyProvider) GetKeyNames() []string {
+	keyNames := make([]string, 0, len(ir.currentKeys))
+	for keyName := range ir.currentKeys {
+		keyNames = append(keyNames, keyName)
+	}
+	sort.Strings(keyNames)
+	return keyNames
+}
+
+// @Override
+func (ir *InMemoryKeyProvider) GetCurrentKeyVersion(keyName string) *metadata.EncryptionKey {
+	return ir.currentKeys[keyName]
+}
+
+// @Override
+func (ir *InMemoryKeyProvider) CreateLocalKey(key *metadata.EncryptionKey) *LocalKey {
+	masterKey := ir.getMasterKey(key)
+	if masterKey == nil {
+		panic(fmt.Sprintf("Unknown key %s", key))
+	}
+	decryptedKey := randomBytes(key.GetAlgorithm().GetKeyLength())
+	aead := newKeyWrapCipher(masterKey)
+	nonce := randomBytes(int32(aead.NonceSize()))
+	encryptedKey := aead.Seal(nonce, nonce, decryptedKey, nil)
+	return NewLocalKey(decryptedKey, encryptedKey)
+}
+
+// @Override
+func (ir *InMemoryKeyProvider) DecryptLocalKey(key *metadata.EncryptionKey, encryptedKey []byte) []byte {
+	masterKey := ir.getMasterKey(key)
+	if masterKey == nil {
+		return nil
+	}
+	aead := newKeyWrapCipher(masterKey)
+	if len(encryptedKey) < aead.NonceSize() {
+		panic(fmt.Sprintf("Encrypted local key for %s has an invalid length %d", key.GetKeyName(), len(encryptedKey)))
+	}
+	decryptedKey, err := aead.Open(nil, encryptedKey[:aead.NonceSize()], encryptedKey[aead.NonceSize():], nil)
+	if err != nil {
+		// the local key was wrapped with a different master key
+		return nil
+	}
+	return decryptedKey
+}
+
+// @Override
+func (ir *InMemoryKeyProvider) GetKind() metadata.KeyProviderKind {
+	return metadata.UNKNOWN_KEY_PROVIDER
+}
+
+func newKeyWrapCipher(masterKey []byte) cipher.AEAD {
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		panic(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	return aead
+}
+
+func (ir *InMemoryKeyProvider) getMasterKey(key *metadata.EncryptionKey) []byte {
+	versions, ok := ir.masterKeys[key.GetKeyName()]
+	if !ok {
+		return nil
+	}
+	return versions[key.GetKeyVersion()]
+}
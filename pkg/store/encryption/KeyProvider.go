@@ -0,0 +1,35 @@
+package encryption
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+/**
+ * Gives access to the master keys of a key management system. Column data is encrypted with a
+ * local key per file and column, and only the local key wrapped by the master key is stored in
+ * the file.
+ */
+type KeyProvider interface {
+
+	/**
+	 * Names of the master keys known to the provider
+	 */
+	GetKeyNames() []string
+
+	/**
+	 * The current version of a master key, or nil when the key is unknown
+	 */
+	GetCurrentKeyVersion(keyName string) *metadata.EncryptionKey
+
+	/**
+	 * Creates a random local key for the master key
+	 */
+	CreateLocalKey(key *metadata.EncryptionKey) *LocalKey
+
+	/**
+	 * Unwraps a local key, returns nil when the provider does not hold the master key
+	 */
+	DecryptLocalKey(key *metadata.EncryptionKey, encryptedKey []byte) []byte
+
+	GetKind() metadata.KeyProviderKind
+}
@@ -0,0 +1,66 @@
+package encryption
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mothdb-bd/orc-go/pkg/store/metadata"
+)
+
+/**
+ * Creates a key provider from a local key file. Every line holds one key version as
+ *
+ *   keyName keyVersion algorithm base64Key
+ *
+ * where the algorithm is AES_CTR_128 or AES_CTR_256. Empty lines and lines starting with # are
+ * ignored.
+ */
+func NewLocalFileKeyProvider(path string) *InMemoryKeyProvider {
+	file, err := os.Open(path)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	provider := NewInMemoryKeyProvider()
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			panic(fmt.Sprintf("Invalid key file %s line %d: expected 4 fields but found %d", path, lineNumber, len(fields)))
+		}
+		keyVersion, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			panic(fmt.Sprintf("Invalid key file %s line %d: invalid key version %s", path, lineNumber, fields[1]))
+		}
+		masterKey, err := base64.StdEncoding.DecodeString(fields[3])
+		if err != nil {
+			panic(fmt.Sprintf("Invalid key file %s line %d: key is not base64", path, lineNumber))
+		}
+		provider.AddKey(fields[0], uint32(keyVersion), parseAlgorithm(path, lineNumber, fields[2]), masterKey)
+	}
+	if err := scanner.Err(); err != nil {
+		panic(err)
+	}
+	return provider
+}
+
+func parseAlgorithm(path string, lineNumber int, name string) metadata.EncryptionAlgorithm {
+	switch name {
+	case metadata.AES_CTR_128.String():
+		return metadata.AES_CTR_128
+	case metadata.AES_CTR_256.String():
+		return metadata.AES_CTR_256
+	}
+	panic(fmt.Sprintf("Invalid key file %s line %d: unknown algorithm %s", path, lineNumber, name))
+}
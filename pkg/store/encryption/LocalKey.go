@@ -0,0 +1,27 @@
+package encryption
+
+type LocalKey struct {
+	decryptedKey []byte
+	encryptedKey []byte
+}
+
+func NewLocalKey(decryptedKey []byte, encryptedKey []byte) *LocalKey {
+	ly := new(LocalKey)
+	ly.decryptedKey = decryptedKey
+	ly.encryptedKey = encryptedKey
+	return ly
+}
+
+/**
+ * The key the column data is encrypted with
+ */
+func (ly *LocalKey) GetDecryptedKey() []byte {
+	return ly.decryptedKey
+}
+
+/**
+ * The key wrapped by the master key, as stored in the file
+ */
+func (ly *LocalKey) GetEncryptedKey() []byte {
+	return ly.encryptedKey
+}
@@ -13,6 +13,20 @@ const (
 	DICTIONARY_V2
 )
 
+func (cd ColumnEncodingKind) String() string {
+	switch cd {
+	case DIRECT:
+		return "DIRECT"
+	case DICTIONARY:
+		return "DICTIONARY"
+	case DIRECT_V2:
+		return "DIRECT_V2"
+	case DICTIONARY_V2:
+		return "DICTIONARY_V2"
+	}
+	return "UNKNOWN_ENCODING"
+}
+
 type ColumnEncoding struct {
 	columnEncodingKind ColumnEncodingKind
 	dictionarySize     uint32
@@ -0,0 +1,79 @@
+package metadata
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * The type attribute holding the field id of a column, the key used by Iceberg.
+ */
+var ICEBERG_ID_KEY string = "iceberg.id"
+
+/**
+ * The stable field id of a column of a table and the ids of its nested fields. The children of a
+ * struct are its fields, a list has its element as the only child and a map has its key and its
+ * value.
+ */
+type ColumnIdentity struct {
+	id       int32
+	name     string
+	children *util.ArrayList[*ColumnIdentity]
+}
+
+func NewColumnIdentity(id int32, name string, children ...*ColumnIdentity) *ColumnIdentity {
+	cy := new(ColumnIdentity)
+	cy.id = id
+	cy.name = name
+	cy.children = util.NewArrayList(children...)
+	return cy
+}
+
+func (cy *ColumnIdentity) GetId() int32 {
+	return cy.id
+}
+
+func (cy *ColumnIdentity) GetName() string {
+	return cy.name
+}
+
+func (cy *ColumnIdentity) GetChildren() *util.ArrayList[*ColumnIdentity] {
+	return cy.children
+}
+
+func (cy *ColumnIdentity) String() string {
+	return fmt.Sprintf("%s:%d", cy.name, cy.id)
+}
+
+/**
+ * Returns a copy of the types with the field id of every column stored in the ICEBERG_ID_KEY
+ * attribute of its type. The columns are the identities of the fields of the root struct, in
+ * the order of the fields.
+ */
+func AddFieldIds(types *ColumnMetadata[*MothType], columns *util.ArrayList[*ColumnIdentity]) *ColumnMetadata[*MothType] {
+	result := make([]*MothType, types.Size())
+	copy(result, types.List().ToArray())
+	addFieldIds(result, ROOT_COLUMN, columns)
+	return NewColumnMetadata(util.NewArrayList(result...))
+}
+
+func addFieldIds(types []*MothType, columnId MothColumnId, fields *util.ArrayList[*ColumnIdentity]) {
+	mothType := types[columnId]
+	util.CheckArgument2(fields.SizeInt32() == mothType.GetFieldCount(), fmt.Sprintf("Column %d of type %s has %d fields, not %d", columnId, mothType.GetMothTypeKind(), mothType.GetFieldCount(), fields.Size()))
+	for i, field := range fields.ToArray() {
+		fieldColumnId := mothType.GetFieldTypeIndex(int32(i))
+		types[fieldColumnId] = withAttribute(types[fieldColumnId], ICEBERG_ID_KEY, strconv.Itoa(int(field.GetId())))
+		addFieldIds(types, fieldColumnId, field.GetChildren())
+	}
+}
+
+func withAttribute(mothType *MothType, key string, value string) *MothType {
+	attributes := make(map[string]string, len(mothType.GetAttributes())+1)
+	for k, v := range mothType.GetAttributes() {
+		attributes[k] = v
+	}
+	attributes[key] = value
+	return NewMothType5(mothType.GetMothTypeKind(), mothType.GetFieldTypeIndexes(), mothType.GetFieldNames(), mothType.GetLength(), mothType.GetPrecision(), mothType.GetScale(), attributes)
+}
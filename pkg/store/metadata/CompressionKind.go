@@ -9,3 +9,19 @@ const (
 	LZ4
 	ZSTD
 )
+
+func (cd CompressionKind) String() string {
+	switch cd {
+	case NONE:
+		return "NONE"
+	case ZLIB:
+		return "ZLIB"
+	case SNAPPY:
+		return "SNAPPY"
+	case LZ4:
+		return "LZ4"
+	case ZSTD:
+		return "ZSTD"
+	}
+	return "UNKNOWN_COMPRESSION"
+}
@@ -0,0 +1,34 @@
+package metadata
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * Describes how the unencrypted copy of an encrypted column was masked.
+ */
+type DataMask struct {
+	name       string
+	parameters *util.ArrayList[string]
+	columns    *util.ArrayList[MothColumnId]
+}
+
+func NewDataMask(name string, parameters *util.ArrayList[string], columns *util.ArrayList[MothColumnId]) *DataMask {
+	dk := new(DataMask)
+	dk.name = name
+	dk.parameters = parameters
+	dk.columns = columns
+	return dk
+}
+
+func (dk *DataMask) GetName() string {
+	return dk.name
+}
+
+func (dk *DataMask) GetParameters() *util.ArrayList[string] {
+	return dk.parameters
+}
+
+func (dk *DataMask) GetColumns() *util.ArrayList[MothColumnId] {
+	return dk.columns
+}
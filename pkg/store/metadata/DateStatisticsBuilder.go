@@ -1,6 +1,8 @@
 package metadata
 
 import (
+	"math"
+
 	"github.com/mothdb-bd/orc-go/pkg/maths"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/util"
@@ -18,6 +20,8 @@ type DateStatisticsBuilder struct {
 
 func NewDateStatisticsBuilder(bloomFilterBuilder BloomFilterBuilder) *DateStatisticsBuilder {
 	dr := new(DateStatisticsBuilder)
+	dr.minimum = math.MaxInt32
+	dr.maximum = math.MinInt32
 	dr.bloomFilterBuilder = bloomFilterBuilder
 	return dr
 }
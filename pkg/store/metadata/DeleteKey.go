@@ -0,0 +1,28 @@
+package metadata
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+)
+
+/**
+ * A deleted key, the rows with the key written with a version older than ts are deleted.
+ */
+type DeleteKey struct {
+	key *slice.Slice
+	ts  uint64
+}
+
+func NewDeleteKey(key *slice.Slice, ts uint64) *DeleteKey {
+	dy := new(DeleteKey)
+	dy.key = key
+	dy.ts = ts
+	return dy
+}
+
+func (dy *DeleteKey) GetKey() *slice.Slice {
+	return dy.key
+}
+
+func (dy *DeleteKey) GetTs() uint64 {
+	return dy.ts
+}
@@ -21,6 +21,8 @@ type DoubleStatisticsBuilder struct {
 
 func NewDoubleStatisticsBuilder(bloomFilterBuilder BloomFilterBuilder) *DoubleStatisticsBuilder {
 	dr := new(DoubleStatisticsBuilder)
+	dr.minimum = math.Inf(1)
+	dr.maximum = math.Inf(-1)
 	dr.bloomFilterBuilder = bloomFilterBuilder
 	return dr
 }
@@ -0,0 +1,37 @@
+package metadata
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+type Encryption struct {
+	masks       *util.ArrayList[*DataMask]
+	keys        *util.ArrayList[*EncryptionKey]
+	variants    *util.ArrayList[*EncryptionVariant]
+	keyProvider KeyProviderKind
+}
+
+func NewEncryption(masks *util.ArrayList[*DataMask], keys *util.ArrayList[*EncryptionKey], variants *util.ArrayList[*EncryptionVariant], keyProvider KeyProviderKind) *Encryption {
+	en := new(Encryption)
+	en.masks = masks
+	en.keys = keys
+	en.variants = variants
+	en.keyProvider = keyProvider
+	return en
+}
+
+func (en *Encryption) GetMasks() *util.ArrayList[*DataMask] {
+	return en.masks
+}
+
+func (en *Encryption) GetKeys() *util.ArrayList[*EncryptionKey] {
+	return en.keys
+}
+
+func (en *Encryption) GetVariants() *util.ArrayList[*EncryptionVariant] {
+	return en.variants
+}
+
+func (en *Encryption) GetKeyProvider() KeyProviderKind {
+	return en.keyProvider
+}
@@ -0,0 +1,32 @@
+package metadata
+
+type EncryptionAlgorithm int8
+
+const (
+	UNKNOWN_ENCRYPTION EncryptionAlgorithm = iota
+	AES_CTR_128
+	AES_CTR_256
+)
+
+/**
+ * Length in bytes of the keys used by the algorithm
+ */
+func (em EncryptionAlgorithm) GetKeyLength() int32 {
+	switch em {
+	case AES_CTR_128:
+		return 16
+	case AES_CTR_256:
+		return 32
+	}
+	panic("Unknown encryption algorithm")
+}
+
+func (em EncryptionAlgorithm) String() string {
+	switch em {
+	case AES_CTR_128:
+		return "AES_CTR_128"
+	case AES_CTR_256:
+		return "AES_CTR_256"
+	}
+	return "UNKNOWN_ENCRYPTION"
+}
@@ -0,0 +1,39 @@
+package metadata
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * A version of a master key, the key material itself is only known to the key provider.
+ */
+type EncryptionKey struct {
+	keyName    string
+	keyVersion uint32
+	algorithm  EncryptionAlgorithm
+}
+
+func NewEncryptionKey(keyName string, keyVersion uint32, algorithm EncryptionAlgorithm) *EncryptionKey {
+	ey := new(EncryptionKey)
+	ey.keyName = keyName
+	ey.keyVersion = keyVersion
+	ey.algorithm = algorithm
+	return ey
+}
+
+func (ey *EncryptionKey) GetKeyName() string {
+	return ey.keyName
+}
+
+func (ey *EncryptionKey) GetKeyVersion() uint32 {
+	return ey.keyVersion
+}
+
+func (ey *EncryptionKey) GetAlgorithm() EncryptionAlgorithm {
+	return ey.algorithm
+}
+
+// @Override
+func (ey *EncryptionKey) String() string {
+	return util.NewSB().AddString("keyName", ey.keyName).AddUInt64("keyVersion", uint64(ey.keyVersion)).AddString("algorithm", ey.algorithm.String()).ToStringHelper()
+}
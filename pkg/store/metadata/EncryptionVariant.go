@@ -0,0 +1,41 @@
+package metadata
+
+/**
+ * An encrypted copy of the column subtree starting at root. The local key the data is encrypted
+ * with is stored wrapped by the master key, and the file statistics of the subtree are stored
+ * compressed and encrypted with the local key.
+ */
+type EncryptionVariant struct {
+	root           MothColumnId
+	keyIndex       int32
+	encryptedKey   []byte
+	fileStatistics []byte
+}
+
+func NewEncryptionVariant(root MothColumnId, keyIndex int32, encryptedKey []byte, fileStatistics []byte) *EncryptionVariant {
+	et := new(EncryptionVariant)
+	et.root = root
+	et.keyIndex = keyIndex
+	et.encryptedKey = encryptedKey
+	et.fileStatistics = fileStatistics
+	return et
+}
+
+func (et *EncryptionVariant) GetRoot() MothColumnId {
+	return et.root
+}
+
+/**
+ * Index of the master key in Encryption.GetKeys
+ */
+func (et *EncryptionVariant) GetKeyIndex() int32 {
+	return et.keyIndex
+}
+
+func (et *EncryptionVariant) GetEncryptedKey() []byte {
+	return et.encryptedKey
+}
+
+func (et *EncryptionVariant) GetFileStatistics() []byte {
+	return et.fileStatistics
+}
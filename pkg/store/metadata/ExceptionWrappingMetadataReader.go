@@ -3,7 +3,9 @@ package metadata
 import (
 	"time"
 
+	"github.com/mothdb-bd/orc-go/pkg/errors"
 	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/store/common"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
@@ -25,35 +27,74 @@ func NewExceptionWrappingMetadataReader(mothDataSourceId *common.MothDataSourceI
 
 // @Override
 func (er *ExceptionWrappingMetadataReader) ReadPostScript(inputStream mothio.InputStream) *PostScript {
+	defer er.propagate("Invalid postscript")
 	return er.delegate.ReadPostScript(inputStream)
 }
 
 // @Override
 func (er *ExceptionWrappingMetadataReader) ReadMetadata(hiveWriterVersion HiveWriterVersion, inputStream mothio.InputStream) *Metadata {
+	defer er.propagate("Invalid file metadata")
 	return er.delegate.ReadMetadata(hiveWriterVersion, inputStream)
 }
 
 // @Override
 func (er *ExceptionWrappingMetadataReader) ReadFooter(hiveWriterVersion HiveWriterVersion, inputStream mothio.InputStream) *Footer {
+	defer er.propagate("Invalid file footer")
 	return er.delegate.ReadFooter(hiveWriterVersion, inputStream)
 }
 
+// @Override
+func (er *ExceptionWrappingMetadataReader) ReadFileStatistics(hiveWriterVersion HiveWriterVersion, inputStream mothio.InputStream) *optional.Optional[*ColumnMetadata[*ColumnStatistics]] {
+	defer er.propagate("Invalid file statistics")
+	return er.delegate.ReadFileStatistics(hiveWriterVersion, inputStream)
+}
+
 // @Override
 func (er *ExceptionWrappingMetadataReader) ReadStripeFooter(types *ColumnMetadata[*MothType], inputStream mothio.InputStream, legacyFileTimeZone *time.Location) *StripeFooter {
+	defer er.propagate("Invalid stripe footer")
 	return er.delegate.ReadStripeFooter(types, inputStream, legacyFileTimeZone)
 }
 
 // @Override
 func (er *ExceptionWrappingMetadataReader) ReadRowIndexes(hiveWriterVersion HiveWriterVersion, inputStream mothio.InputStream) *util.ArrayList[*RowGroupIndex] {
+	defer er.propagate("Invalid stripe row index")
 	return er.delegate.ReadRowIndexes(hiveWriterVersion, inputStream)
 }
 
 // @Override
 func (er *ExceptionWrappingMetadataReader) ReadBloomFilterIndexes(inputStream mothio.InputStream) *util.ArrayList[*BloomFilter] {
+	defer er.propagate("Invalid bloom filter")
 	return er.delegate.ReadBloomFilterIndexes(inputStream)
 }
 
-// func (er *ExceptionWrappingMetadataReader) propagate(throwable *Throwable, message string) *MothCorruptionException {
-// 	propagateIfPossible(throwable, MothException.class)
-// 	return NewMothCorruptionException(throwable, mothDataSourceId, message)
-// }
+// @Override
+func (er *ExceptionWrappingMetadataReader) ReadDeleteKeys(inputStream mothio.InputStream) *util.ArrayList[*DeleteKey] {
+	defer er.propagate("Invalid delete keys")
+	return er.delegate.ReadDeleteKeys(inputStream)
+}
+
+// @Override
+func (er *ExceptionWrappingMetadataReader) ReadKeys(inputStream mothio.InputStream) *util.ArrayList[*Key] {
+	defer er.propagate("Invalid key index")
+	return er.delegate.ReadKeys(inputStream)
+}
+
+/**
+ * Converts a failure while decoding metadata to a corruption error of the data source, failures
+ * that already are moth errors and I/O failures are raised as they are.
+ */
+func (er *ExceptionWrappingMetadataReader) propagate(message string) {
+	failure := recover()
+	if failure == nil {
+		return
+	}
+	mothError := common.AsMothError(failure, er.mothDataSourceId)
+	if _, ok := failure.(*common.MothError); ok || mothError.GetCode() == errors.IO_ERROR {
+		panic(mothError)
+	}
+	cause := mothError.Unwrap()
+	if cause == nil {
+		cause = mothError
+	}
+	panic(common.NewMothErrorWithCause(errors.CORRUPT_FILE, er.mothDataSourceId, cause, message))
+}
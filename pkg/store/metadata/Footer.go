@@ -6,6 +6,8 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
+var FOOTER_INSTANCE_SIZE int32 = util.SizeOf(&Footer{})
+
 type Footer struct {
 	numberOfRows   uint64
 	rowsInRowGroup *optional.OptionalInt
@@ -15,9 +17,14 @@ type Footer struct {
 	// Map<String, Slice> userMetadata;
 	userMetadata map[string]*slice.Slice
 	writerId     *optional.Optional[uint32]
+	encryption   *optional.Optional[*Encryption]
 }
 
 func NewFooter(numberOfRows uint64, rowsInRowGroup *optional.OptionalInt, stripes *util.ArrayList[*StripeInformation], types *ColumnMetadata[*MothType], fileStats *optional.Optional[*ColumnMetadata[*ColumnStatistics]], userMetadata map[string]*slice.Slice, writerId *optional.Optional[uint32]) *Footer {
+	return NewFooter2(numberOfRows, rowsInRowGroup, stripes, types, fileStats, userMetadata, writerId, optional.Empty[*Encryption]())
+}
+
+func NewFooter2(numberOfRows uint64, rowsInRowGroup *optional.OptionalInt, stripes *util.ArrayList[*StripeInformation], types *ColumnMetadata[*MothType], fileStats *optional.Optional[*ColumnMetadata[*ColumnStatistics]], userMetadata map[string]*slice.Slice, writerId *optional.Optional[uint32], encryption *optional.Optional[*Encryption]) *Footer {
 	fr := new(Footer)
 	fr.numberOfRows = numberOfRows
 
@@ -30,6 +37,7 @@ func NewFooter(numberOfRows uint64, rowsInRowGroup *optional.OptionalInt, stripe
 	fr.fileStats = fileStats
 	fr.userMetadata = userMetadata
 	fr.writerId = writerId
+	fr.encryption = encryption
 	return fr
 }
 
@@ -61,3 +69,35 @@ func (fr *Footer) GetUserMetadata() map[string]*slice.Slice {
 func (fr *Footer) GetWriterId() *optional.Optional[uint32] {
 	return fr.writerId
 }
+
+func (fr *Footer) GetEncryption() *optional.Optional[*Encryption] {
+	return fr.encryption
+}
+
+/**
+ * Returns the size of the footer held in memory, the stripes, types, file statistics and user
+ * metadata included.
+ */
+func (fr *Footer) GetRetainedSizeInBytes() int64 {
+	retainedSizeInBytes := int64(FOOTER_INSTANCE_SIZE) + int64(fr.stripes.Size())*int64(STRIPE_INFORMATION_INSTANCE_SIZE)
+	for _, mothType := range fr.types.List().ToArray() {
+		retainedSizeInBytes += mothType.GetRetainedSizeInBytes()
+	}
+	if fr.fileStats.IsPresent() {
+		for _, statistics := range fr.fileStats.Get().List().ToArray() {
+			// statistics of encrypted columns are missing
+			if statistics != nil {
+				retainedSizeInBytes += statistics.GetRetainedSizeInBytes()
+			}
+		}
+	}
+	for key, value := range fr.userMetadata {
+		retainedSizeInBytes += int64(len(key)) + int64(value.GetRetainedSize())
+	}
+	if fr.encryption.IsPresent() {
+		for _, variant := range fr.encryption.Get().GetVariants().ToArray() {
+			retainedSizeInBytes += int64(len(variant.GetFileStatistics()))
+		}
+	}
+	return retainedSizeInBytes
+}
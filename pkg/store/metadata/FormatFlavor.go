@@ -0,0 +1,108 @@
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+)
+
+var (
+	ORC_MAGIC       string = "ORC"
+	ORC_MAGIC_SLICE        = slice.NewWithString("ORC")
+
+	/**
+	 * Writer id registered for Trino in the ORC specification, the writer of this package is a
+	 * port of the Trino writer and shares its writer versions.
+	 */
+	ORC_TRINO_WRITER_ID  uint32   = 4
+	ORC_METADATA_VERSION []uint32 = []uint32{0, 12}
+)
+
+/**
+ * The streams, encodings and protobuf messages of Moth and ORC files are the same, the flavour
+ * decides the magic at the start and in the postscript of the file, the file format version, the
+ * writer id in the footer and if the protobuf messages are prefixed with their length.
+ */
+type FormatFlavor int8
+
+const (
+	/**
+	 * Moth files, starting with the MOTH magic.
+	 */
+	MOTH_FLAVOR FormatFlavor = iota
+
+	/**
+	 * Apache ORC files, readable by Hive, Spark, Trino and the ORC libraries.
+	 */
+	ORC_FLAVOR
+)
+
+func (fr FormatFlavor) GetMagic() string {
+	switch fr {
+	case MOTH_FLAVOR:
+		return MAGIC
+	case ORC_FLAVOR:
+		return ORC_MAGIC
+	}
+	panic(fmt.Sprintf("Unexpected value: %d", fr))
+}
+
+func (fr FormatFlavor) GetMagicSlice() *slice.Slice {
+	switch fr {
+	case MOTH_FLAVOR:
+		return MAGIC_SLICE
+	case ORC_FLAVOR:
+		return ORC_MAGIC_SLICE
+	}
+	panic(fmt.Sprintf("Unexpected value: %d", fr))
+}
+
+/**
+ * Version written to the postscript, both formats use the layout of Hive 0.12.
+ */
+func (fr FormatFlavor) GetMetadataVersion() []uint32 {
+	switch fr {
+	case MOTH_FLAVOR:
+		return MOTH_METADATA_VERSION
+	case ORC_FLAVOR:
+		return ORC_METADATA_VERSION
+	}
+	panic(fmt.Sprintf("Unexpected value: %d", fr))
+}
+
+/**
+ * Writer id written to the footer of files not written in the legacy Hive compatible mode.
+ */
+func (fr FormatFlavor) GetWriterId() uint32 {
+	switch fr {
+	case MOTH_FLAVOR:
+		return MOTH_WRITER_ID
+	case ORC_FLAVOR:
+		return ORC_TRINO_WRITER_ID
+	}
+	panic(fmt.Sprintf("Unexpected value: %d", fr))
+}
+
+/**
+ * Returns if the messages of the file tail, the stripe footers and the index streams are prefixed
+ * with their length. Moth files prefix them, ORC files store the bare protobuf messages.
+ */
+func (fr FormatFlavor) HasLengthPrefixedMessages() bool {
+	switch fr {
+	case MOTH_FLAVOR:
+		return true
+	case ORC_FLAVOR:
+		return false
+	}
+	panic(fmt.Sprintf("Unexpected value: %d", fr))
+}
+
+func (fr FormatFlavor) String() string {
+	switch fr {
+	case MOTH_FLAVOR:
+		return "MOTH"
+	case ORC_FLAVOR:
+		return "ORC"
+	}
+	return "UNKNOWN_FLAVOR"
+}
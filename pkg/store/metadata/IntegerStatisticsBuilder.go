@@ -22,6 +22,8 @@ type IntegerStatisticsBuilder struct {
 
 func NewIntegerStatisticsBuilder(bloomFilterBuilder BloomFilterBuilder) *IntegerStatisticsBuilder {
 	ir := new(IntegerStatisticsBuilder)
+	ir.minimum = math.MaxInt64
+	ir.maximum = math.MinInt64
 	ir.bloomFilterBuilder = bloomFilterBuilder
 	return ir
 }
@@ -0,0 +1,35 @@
+package metadata
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+)
+
+/**
+ * An entry of the key index of a stripe, the key of a row with the position of the row in the
+ * stripe and the time it was written.
+ */
+type Key struct {
+	key      *slice.Slice
+	position uint32
+	ts       uint64
+}
+
+func NewKey(key *slice.Slice, position uint32, ts uint64) *Key {
+	ky := new(Key)
+	ky.key = key
+	ky.position = position
+	ky.ts = ts
+	return ky
+}
+
+func (ky *Key) GetKey() *slice.Slice {
+	return ky.key
+}
+
+func (ky *Key) GetPosition() uint32 {
+	return ky.position
+}
+
+func (ky *Key) GetTs() uint64 {
+	return ky.ts
+}
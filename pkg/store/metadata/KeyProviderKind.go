@@ -0,0 +1,27 @@
+package metadata
+
+type KeyProviderKind int8
+
+const (
+	UNKNOWN_KEY_PROVIDER KeyProviderKind = iota
+	HADOOP
+	AWS
+	GCP
+	AZURE
+)
+
+func (kd KeyProviderKind) String() string {
+	switch kd {
+	case UNKNOWN_KEY_PROVIDER:
+		return "UNKNOWN"
+	case HADOOP:
+		return "HADOOP"
+	case AWS:
+		return "AWS"
+	case GCP:
+		return "GCP"
+	case AZURE:
+		return "AZURE"
+	}
+	return "UNKNOWN_KEY_PROVIDER"
+}
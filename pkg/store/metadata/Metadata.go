@@ -5,6 +5,8 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
+var METADATA_INSTANCE_SIZE int32 = util.SizeOf(&Metadata{})
+
 type Metadata struct {
 	stripeStatistics *util.ArrayList[*optional.Optional[*StripeStatistics]]
 }
@@ -18,3 +20,16 @@ func NewMetadata(stripeStatistics *util.ArrayList[*optional.Optional[*StripeStat
 func (ma *Metadata) GetStripeStatsList() *util.ArrayList[*optional.Optional[*StripeStatistics]] {
 	return ma.stripeStatistics
 }
+
+/**
+ * Returns the size of the stripe statistics held in memory.
+ */
+func (ma *Metadata) GetRetainedSizeInBytes() int64 {
+	retainedSizeInBytes := int64(METADATA_INSTANCE_SIZE)
+	for _, stripeStatistics := range ma.stripeStatistics.ToArray() {
+		if stripeStatistics.IsPresent() {
+			retainedSizeInBytes += stripeStatistics.Get().GetRetainedSizeInBytes()
+		}
+	}
+	return retainedSizeInBytes
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
@@ -24,6 +25,11 @@ type MetadataReader interface {
 	 */
 	ReadFooter(hiveWriterVersion HiveWriterVersion, inputStream mothio.InputStream) *Footer
 
+	/**
+	 * Reads the file statistics of an encryption variant
+	 */
+	ReadFileStatistics(hiveWriterVersion HiveWriterVersion, inputStream mothio.InputStream) *optional.Optional[*ColumnMetadata[*ColumnStatistics]]
+
 	/**
 	 * types
 	 */
@@ -38,4 +44,14 @@ type MetadataReader interface {
 	 * Reader
 	 */
 	ReadBloomFilterIndexes(inputStream mothio.InputStream) *util.ArrayList[*BloomFilter]
+
+	/**
+	 * Reads the keys of a delete file
+	 */
+	ReadDeleteKeys(inputStream mothio.InputStream) *util.ArrayList[*DeleteKey]
+
+	/**
+	 * Reads the key index of a stripe
+	 */
+	ReadKeys(inputStream mothio.InputStream) *util.ArrayList[*Key]
 }
@@ -18,9 +18,21 @@ type MetadataWriter interface {
 
 	WriteFooter(output slice.SliceOutput, footer *Footer) int32
 
+	WriteFileStatistics(output slice.SliceOutput, fileStatistics *ColumnMetadata[*ColumnStatistics]) int32
+
 	WriteStripeFooter(output slice.SliceOutput, footer *StripeFooter) int32
 
 	WriteRowIndexes(output slice.SliceOutput, rowGroupIndexes *util.ArrayList[*RowGroupIndex]) int32
 
 	WriteBloomFilters(output slice.SliceOutput, bloomFilters *util.ArrayList[*BloomFilter]) int32
+
+	/**
+	 * Writes the keys of a delete file
+	 */
+	WriteDeleteKeys(output slice.SliceOutput, deleteKeys *util.ArrayList[*DeleteKey]) int32
+
+	/**
+	 * Writes the key index of a stripe
+	 */
+	WriteKeys(output slice.SliceOutput, keys *util.ArrayList[*Key]) int32
 }
@@ -23,16 +23,24 @@ var (
 
 type MothMetadataReader struct {
 	MetadataReader
+
+	formatFlavor FormatFlavor
 }
 
 func NewMothMetadataReader() *MothMetadataReader {
-	return new(MothMetadataReader)
+	return NewMothMetadataReader2(MOTH_FLAVOR)
+}
+
+func NewMothMetadataReader2(formatFlavor FormatFlavor) *MothMetadataReader {
+	mr := new(MothMetadataReader)
+	mr.formatFlavor = formatFlavor
+	return mr
 }
 
 // @Override
 func (mr *MothMetadataReader) ReadPostScript(inputStream mothio.InputStream) *PostScript {
 	postScript := &proto.PostScript{}
-	readProtobufObject(inputStream, postScript)
+	mr.readMessage(inputStream, postScript)
 	return NewPostScript(postScript.GetVersion(), int64(postScript.GetFooterLength()), int64(postScript.GetMetadataLength()), toCompression(postScript.GetCompression()), postScript.GetCompressionBlockSize(), toHiveWriterVersion(postScript.GetWriterVersion()))
 }
 
@@ -47,7 +55,7 @@ func toHiveWriterVersion(writerVersion uint32) HiveWriterVersion {
 func (mr *MothMetadataReader) ReadMetadata(hiveWriterVersion HiveWriterVersion, inputStream mothio.InputStream) *Metadata {
 
 	metadata := &proto.Metadata{}
-	readProtobufObject(inputStream, metadata)
+	mr.readMessage(inputStream, metadata)
 	return NewMetadata(reader_toStripeStatistics(hiveWriterVersion, metadata.GetStripeStats()))
 }
 
@@ -67,36 +75,103 @@ func toStripeStatistics2(hiveWriterVersion HiveWriterVersion, stripeStatistics *
 // @Override
 func (mr *MothMetadataReader) ReadFooter(hiveWriterVersion HiveWriterVersion, inputStream mothio.InputStream) *Footer {
 	footer := &proto.Footer{}
-	readProtobufObject(inputStream, footer)
-	return NewFooter(footer.GetNumberOfRows(), util.Ternary(footer.GetRowIndexStride() == 0, optional.OptionalIntEmpty(), optional.OptionalIntof(int32(footer.GetRowIndexStride()))), r_toStripeInformation(footer.GetStripes()), r_toType2(footer.GetTypes()), toColumnStatistics2(hiveWriterVersion, footer.GetStatistics(), false), toUserMetadata(footer.GetMetadata()), optional.Of(footer.GetWriter()))
+	mr.readMessage(inputStream, footer)
+	encryption := optional.Empty[*Encryption]()
+	if footer.GetEncryption() != nil {
+		encryption = optional.Of(toEncryption(footer.GetEncryption()))
+	}
+	return NewFooter2(footer.GetNumberOfRows(), util.Ternary(footer.GetRowIndexStride() == 0, optional.OptionalIntEmpty(), optional.OptionalIntof(int32(footer.GetRowIndexStride()))), r_toStripeInformation(footer.GetStripes()), r_toType2(footer.GetTypes()), toColumnStatistics2(hiveWriterVersion, footer.GetStatistics(), false), toUserMetadata(footer.GetMetadata()), optional.Of(footer.GetWriter()), encryption)
+}
+
+// @Override
+func (mr *MothMetadataReader) ReadFileStatistics(hiveWriterVersion HiveWriterVersion, inputStream mothio.InputStream) *optional.Optional[*ColumnMetadata[*ColumnStatistics]] {
+	fileStatistics := &proto.FileStatistics{}
+	mr.readMessage(inputStream, fileStatistics)
+	return toColumnStatistics2(hiveWriterVersion, fileStatistics.GetColumn(), false)
+}
+
+func toEncryption(encryption *proto.Encryption) *Encryption {
+	masks := util.NewArrayList[*DataMask]()
+	for _, mask := range encryption.GetMask() {
+		columns := util.NewArrayList[MothColumnId]()
+		for _, column := range mask.GetColumns() {
+			columns.Add(NewMothColumnId(column))
+		}
+		masks.Add(NewDataMask(mask.GetName(), util.NewArrayList(mask.GetMaskParameters()...), columns))
+	}
+	keys := util.NewArrayList[*EncryptionKey]()
+	for _, key := range encryption.GetKey() {
+		keys.Add(NewEncryptionKey(key.GetKeyName(), key.GetKeyVersion(), toEncryptionAlgorithm(key.GetAlgorithm())))
+	}
+	variants := util.NewArrayList[*EncryptionVariant]()
+	for _, variant := range encryption.GetVariants() {
+		variants.Add(NewEncryptionVariant(NewMothColumnId(variant.GetRoot()), int32(variant.GetKey()), variant.GetEncryptedKey(), variant.GetFileStatistics()))
+	}
+	return NewEncryption(masks, keys, variants, toKeyProviderKind(encryption.GetKeyProvider()))
+}
+
+func toEncryptionAlgorithm(algorithm proto.EncryptionAlgorithm) EncryptionAlgorithm {
+	switch algorithm {
+	case proto.EncryptionAlgorithm_AES_CTR_128:
+		return AES_CTR_128
+	case proto.EncryptionAlgorithm_AES_CTR_256:
+		return AES_CTR_256
+	}
+	return UNKNOWN_ENCRYPTION
+}
+
+func toKeyProviderKind(kind proto.KeyProviderKind) KeyProviderKind {
+	switch kind {
+	case proto.KeyProviderKind_HADOOP:
+		return HADOOP
+	case proto.KeyProviderKind_AWS:
+		return AWS
+	case proto.KeyProviderKind_GCP:
+		return GCP
+	case proto.KeyProviderKind_AZURE:
+		return AZURE
+	}
+	return UNKNOWN_KEY_PROVIDER
 }
 
 func r_toStripeInformation(types []*proto.StripeInformation) *util.ArrayList[*StripeInformation] {
 	re := util.NewArrayList[*StripeInformation]()
+	// the stripe id is only stored when it does not follow the previous stripe
+	encryptStripeId := uint64(0)
 	for i := 0; i < len(types); i++ {
-		re.Add(r_toStripeInformation2(types[i]))
+		if types[i].EncryptStripeId != nil {
+			encryptStripeId = types[i].GetEncryptStripeId()
+		} else {
+			encryptStripeId++
+		}
+		re.Add(r_toStripeInformation2(types[i], encryptStripeId))
 	}
 	return re
 }
 
-func r_toStripeInformation2(stripeInformation *proto.StripeInformation) *StripeInformation {
-	return NewStripeInformation(util.Int32Exact(int64(stripeInformation.GetNumberOfRows())), stripeInformation.GetOffset(), stripeInformation.GetIndexLength(), stripeInformation.GetDataLength(), stripeInformation.GetFooterLength())
+func r_toStripeInformation2(stripeInformation *proto.StripeInformation, encryptStripeId uint64) *StripeInformation {
+	return NewStripeInformation2(util.Int32Exact(int64(stripeInformation.GetNumberOfRows())), stripeInformation.GetOffset(), stripeInformation.GetIndexLength(), stripeInformation.GetDataLength(), stripeInformation.GetFooterLength(), encryptStripeId)
 }
 
 // @Override
 func (mr *MothMetadataReader) ReadStripeFooter(types *ColumnMetadata[*MothType], inputStream mothio.InputStream, legacyFileTimeZone *time.Location) *StripeFooter {
 	stripeFooter := &proto.StripeFooter{}
-	readProtobufObject(inputStream, stripeFooter)
+	mr.readMessage(inputStream, stripeFooter)
 
 	tzStr := stripeFooter.GetWriterTimezone()
 
-	var tz *time.Location
-	if tzStr == "" {
-		tz, _ = time.LoadLocation(tzStr)
-	} else {
-		tz = legacyFileTimeZone
+	// files written before Hive 0.13 have no writer time zone
+	tz := legacyFileTimeZone
+	if tzStr != "" {
+		if location, err := time.LoadLocation(tzStr); err == nil {
+			tz = location
+		}
 	}
-	return NewStripeFooter(toStream2(stripeFooter.GetStreams()), toColumnEncoding2(stripeFooter.GetColumns()), tz)
+	encryption := util.NewArrayList[*StripeEncryptionVariant]()
+	for _, variant := range stripeFooter.GetEncryption() {
+		encryption.Add(NewStripeEncryptionVariant(toStream2(variant.GetStreams()), toColumnEncoding2(variant.GetEncoding()).List()))
+	}
+	return NewStripeFooter2(toStream2(stripeFooter.GetStreams()), toColumnEncoding2(stripeFooter.GetColumns()), tz, encryption)
 }
 
 func toStream(stream *proto.Stream) *Stream {
@@ -126,7 +201,7 @@ func toColumnEncoding2(columnEncodings []*proto.ColumnEncoding) *ColumnMetadata[
 // @Override
 func (mr *MothMetadataReader) ReadRowIndexes(hiveWriterVersion HiveWriterVersion, inputStream mothio.InputStream) *util.ArrayList[*RowGroupIndex] {
 	rowIndex := &proto.RowIndex{}
-	readProtobufObject(inputStream, rowIndex)
+	mr.readMessage(inputStream, rowIndex)
 
 	list := util.NewArrayList[*RowGroupIndex]()
 	for _, e := range rowIndex.GetEntry() {
@@ -139,7 +214,7 @@ func (mr *MothMetadataReader) ReadRowIndexes(hiveWriterVersion HiveWriterVersion
 // @Override
 func (mr *MothMetadataReader) ReadBloomFilterIndexes(inputStream mothio.InputStream) *util.ArrayList[*BloomFilter] {
 	bloomFilter := &proto.BloomFilterIndex{}
-	readProtobufObject(inputStream, bloomFilter)
+	mr.readMessage(inputStream, bloomFilter)
 	bloomFilterList := bloomFilter.GetBloomFilter()
 
 	builder := util.NewArrayList[*BloomFilter]()
@@ -165,6 +240,28 @@ func (mr *MothMetadataReader) ReadBloomFilterIndexes(inputStream mothio.InputStr
 	return builder
 }
 
+// @Override
+func (mr *MothMetadataReader) ReadDeleteKeys(inputStream mothio.InputStream) *util.ArrayList[*DeleteKey] {
+	deleteKeys := util.NewArrayList[*DeleteKey]()
+	deleteKey := &proto.DeleteKey{}
+	for readProtobufObject(inputStream, deleteKey) {
+		deleteKeys.Add(NewDeleteKey(slice.NewWithBuf(deleteKey.GetKey()), deleteKey.GetTs()))
+		deleteKey = &proto.DeleteKey{}
+	}
+	return deleteKeys
+}
+
+// @Override
+func (mr *MothMetadataReader) ReadKeys(inputStream mothio.InputStream) *util.ArrayList[*Key] {
+	keys := util.NewArrayList[*Key]()
+	key := &proto.Key{}
+	for readProtobufObject(inputStream, key) {
+		keys.Add(NewKey(slice.NewWithBuf(key.GetKey()), key.GetPosition(), key.GetTs()))
+		key = &proto.Key{}
+	}
+	return keys
+}
+
 func toRowGroupIndex(hiveWriterVersion HiveWriterVersion, rowIndexEntry *proto.RowIndexEntry) *RowGroupIndex {
 	positionsList := rowIndexEntry.GetPositions()
 	positions := util.NewArrayList[int32]()
@@ -251,7 +348,7 @@ func toColumnStatistics(hiveWriterVersion HiveWriterVersion, statistics *proto.C
 }
 
 func toColumnStatistics2(hiveWriterVersion HiveWriterVersion, columnStatistics []*proto.ColumnStatistics, isRowGroup bool) *optional.Optional[*ColumnMetadata[*ColumnStatistics]] {
-	if columnStatistics == nil || len(columnStatistics) != 0 {
+	if len(columnStatistics) == 0 {
 		return optional.Empty[*ColumnMetadata[*ColumnStatistics]]()
 	}
 
@@ -507,7 +604,9 @@ func toStreamKind(streamKind proto.Stream_Kind) StreamKind {
 	case proto.Stream_BLOOM_FILTER_UTF8:
 		return BLOOM_FILTER_UTF8
 	case proto.Stream_ENCRYPTED_INDEX:
+		return ENCRYPTED_INDEX
 	case proto.Stream_ENCRYPTED_DATA:
+		return ENCRYPTED_DATA
 	case proto.Stream_STRIPE_STATISTICS:
 	case proto.Stream_FILE_STATISTICS:
 		// unsupported
@@ -548,17 +647,57 @@ func toCompression(compression proto.CompressionKind) CompressionKind {
 	panic(" compression not implemented yet")
 }
 
-func readProtobufObject(input mothio.InputStream, object protobuf.Message) {
+/**
+ * Reads a message of the file tail, a stripe footer or an index stream, which is the whole input
+ * of an ORC file and prefixed with its length in a Moth file, see MothMetadataWriter.writeMessage.
+ */
+func (mr *MothMetadataReader) readMessage(input mothio.InputStream, object protobuf.Message) {
+	if mr.formatFlavor.HasLengthPrefixedMessages() {
+		readProtobufObject(input, object)
+		return
+	}
+	b := make([]byte, 0)
+	buf := make([]byte, 4096)
+	for {
+		n, err := input.ReadBS3(buf, 0, len(buf))
+		if err != nil || n <= 0 {
+			break
+		}
+		b = append(b, buf[:n]...)
+	}
+	if err := protobuf.Unmarshal(b, object); err != nil {
+		panic(err)
+	}
+}
+
+/**
+ * Reads the next message of the input, returns false at the end of the input.
+ */
+func readProtobufObject(input mothio.InputStream, object protobuf.Message) bool {
 
 	buf := make([]byte, util.INT32_BYTES)
-	_, err := input.ReadBS2(buf)
-	if err == nil {
-		size := int32(binary.LittleEndian.Uint32(buf))
-		b := make([]byte, size)
-		input.ReadBS2(b)
-		err := protobuf.Unmarshal(b, object)
-		if err != nil {
-			panic(err)
+	if !readFully(input, buf) {
+		return false
+	}
+	size := int32(binary.LittleEndian.Uint32(buf))
+	b := make([]byte, size)
+	readFully(input, b)
+	err := protobuf.Unmarshal(b, object)
+	if err != nil {
+		panic(err)
+	}
+	return true
+}
+
+// readFully reads until b is full, a single read may stop at the end of a compressed chunk
+func readFully(input mothio.InputStream, b []byte) bool {
+	offset := 0
+	for offset < len(b) {
+		n, err := input.ReadBS3(b, offset, len(b)-offset)
+		if err != nil || n <= 0 {
+			return false
 		}
+		offset += n
 	}
+	return true
 }
@@ -0,0 +1,34 @@
+package metadata
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/mothdb-bd/orc-go/pkg/mothio"
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+func TestMothMetadataReader_ReadKeys(t *testing.T) {
+	keys := util.NewArrayList[*Key]()
+	for i := uint32(0); i < 3; i++ {
+		keys.Add(NewKey(slice.NewWithString(string(rune('a'+i))+"-key"), i*10, uint64(i)))
+	}
+	output := slice.NewDynamicSliceOutput(64)
+	NewMothMetadataWriter(MOTH).WriteKeys(output, keys)
+
+	// every read returns one byte, as a read that stops at the end of a compressed chunk
+	input := mothio.NewInputStream(io.NopCloser(iotest.OneByteReader(bytes.NewReader(output.Slice().AvailableBytes()))))
+	read := NewMothMetadataReader().ReadKeys(input)
+	if read.Size() != keys.Size() {
+		t.Fatalf("read %d of %d keys", read.Size(), keys.Size())
+	}
+	for i, key := range read.ToArray() {
+		want := keys.Get(i)
+		if key.GetKey().String() != want.GetKey().String() || key.GetPosition() != want.GetPosition() || key.GetTs() != want.GetTs() {
+			t.Errorf("read key %d as %s at %d", i, key.GetKey().String(), key.GetPosition())
+		}
+	}
+}
@@ -36,25 +36,31 @@ type MothMetadataWriter struct {
 	MetadataWriter
 
 	writerIdentification WriterIdentification
+	formatFlavor         FormatFlavor
 }
 
 func NewMothMetadataWriter(writerIdentification WriterIdentification) *MothMetadataWriter {
+	return NewMothMetadataWriter2(writerIdentification, MOTH_FLAVOR)
+}
+
+func NewMothMetadataWriter2(writerIdentification WriterIdentification, formatFlavor FormatFlavor) *MothMetadataWriter {
 	mr := new(MothMetadataWriter)
 	mr.writerIdentification = writerIdentification
+	mr.formatFlavor = formatFlavor
 	return mr
 }
 
 // @Override
 func (mr *MothMetadataWriter) GetMothMetadataVersion() []uint32 {
-	return MOTH_METADATA_VERSION
+	return mr.formatFlavor.GetMetadataVersion()
 }
 
 // @Override
 func (mr *MothMetadataWriter) WritePostscript(output slice.SliceOutput, footerLength uint64, metadataLength uint64, compression CompressionKind, compressionBlockSize uint64) int32 {
 	postScriptProtobuf := &proto.PostScript{}
-	postScriptProtobuf.Version = MOTH_METADATA_VERSION  //addAllVersion(MOTH_METADATA_VERSION)
-	postScriptProtobuf.FooterLength = &footerLength     //setFooterLength(footerLength)
-	postScriptProtobuf.MetadataLength = &metadataLength // setMetadataLength()
+	postScriptProtobuf.Version = mr.formatFlavor.GetMetadataVersion() //addAllVersion(MOTH_METADATA_VERSION)
+	postScriptProtobuf.FooterLength = &footerLength                   //setFooterLength(footerLength)
+	postScriptProtobuf.MetadataLength = &metadataLength               // setMetadataLength()
 
 	com := w_toCompression(compression)
 	postScriptProtobuf.Compression = &com
@@ -62,9 +68,10 @@ func (mr *MothMetadataWriter) WritePostscript(output slice.SliceOutput, footerLe
 
 	tmp := mr.getMothWriterVersion()
 	postScriptProtobuf.WriterVersion = &tmp
-	postScriptProtobuf.Magic = &MAGIC //.setMagic(MAGIC.toStringUtf8())
+	magic := mr.formatFlavor.GetMagic()
+	postScriptProtobuf.Magic = &magic //.setMagic(MAGIC.toStringUtf8())
 
-	return writeProtobufObject(output, postScriptProtobuf)
+	return mr.writeMessage(output, postScriptProtobuf)
 }
 
 func (mr *MothMetadataWriter) getMothWriterVersion() uint32 {
@@ -87,7 +94,7 @@ func (mr *MothMetadataWriter) WriteMetadata(output slice.SliceOutput, metadata *
 		ssArray[i] = toStripeStatistics(ss.Get())
 	}
 	metadataProtobuf.StripeStats = ssArray
-	return writeProtobufObject(output, metadataProtobuf)
+	return mr.writeMessage(output, metadataProtobuf)
 }
 
 func toStripeStatistics(stripeStatistics *StripeStatistics) *proto.StripeStatistics {
@@ -102,6 +109,17 @@ func toStripeStatistics(stripeStatistics *StripeStatistics) *proto.StripeStatist
 	return stripeStatisticsProto
 }
 
+// @Override
+func (mr *MothMetadataWriter) WriteFileStatistics(output slice.SliceOutput, fileStatistics *ColumnMetadata[*ColumnStatistics]) int32 {
+	fileStatisticsProto := &proto.FileStatistics{}
+	cs := make([]*proto.ColumnStatistics, fileStatistics.Size())
+	for i, statistics := range fileStatistics.List().ToArray() {
+		cs[i] = w_toColumnStatistics(statistics)
+	}
+	fileStatisticsProto.Column = cs
+	return mr.writeMessage(output, fileStatisticsProto)
+}
+
 // @Override
 func (mr *MothMetadataWriter) WriteFooter(output slice.SliceOutput, footer *Footer) int32 {
 	fotterProto := &proto.Footer{}
@@ -139,6 +157,9 @@ func (mr *MothMetadataWriter) WriteFooter(output slice.SliceOutput, footer *Foot
 		i++
 	}
 	fotterProto.Metadata = ud
+	if footer.GetEncryption().IsPresent() {
+		fotterProto.Encryption = w_toEncryption(footer.GetEncryption().Get())
+	}
 
 	// builder := MothProto.Footer.newBuilder()
 	// .setNumberOfRows(footer.getNumberOfRows())
@@ -148,7 +169,7 @@ func (mr *MothMetadataWriter) WriteFooter(output slice.SliceOutput, footer *Foot
 	// .addAllStatistics(footer.getFileStats().Map(ColumnMetadata.stream).orElseGet(java.util.stream.Stream.empty).Map(MothMetadataWriter.w_toColumnStatistics).collect(toList()))
 	// .addAllMetadata(footer.getUserMetadata().entrySet().stream().Map(MothMetadataWriter.w_toUserMetadata).collect(toList()))
 	mr.setWriter(fotterProto)
-	return writeProtobufObject(output, fotterProto)
+	return mr.writeMessage(output, fotterProto)
 }
 
 func (mr *MothMetadataWriter) setWriter(builder *proto.Footer) {
@@ -156,12 +177,65 @@ func (mr *MothMetadataWriter) setWriter(builder *proto.Footer) {
 	case LEGACY_HIVE_COMPATIBLE:
 		return
 	case MOTH:
-		builder.Writer = &MOTH_WRITER_ID
+		writerId := mr.formatFlavor.GetWriterId()
+		builder.Writer = &writerId
 		return
 	}
 	panic(fmt.Sprintf("Unexpected value: %d", mr.writerIdentification))
 }
 
+func w_toEncryption(encryption *Encryption) *proto.Encryption {
+	en := &proto.Encryption{}
+	en.Mask = make([]*proto.DataMask, encryption.GetMasks().Size())
+	for i, mask := range encryption.GetMasks().ToArray() {
+		name := mask.GetName()
+		columns := make([]uint32, mask.GetColumns().Size())
+		for j, column := range mask.GetColumns().ToArray() {
+			columns[j] = column.GetId()
+		}
+		en.Mask[i] = &proto.DataMask{Name: &name, MaskParameters: mask.GetParameters().ToArray(), Columns: columns}
+	}
+	en.Key = make([]*proto.EncryptionKey, encryption.GetKeys().Size())
+	for i, key := range encryption.GetKeys().ToArray() {
+		keyName := key.GetKeyName()
+		keyVersion := key.GetKeyVersion()
+		algorithm := w_toEncryptionAlgorithm(key.GetAlgorithm())
+		en.Key[i] = &proto.EncryptionKey{KeyName: &keyName, KeyVersion: &keyVersion, Algorithm: &algorithm}
+	}
+	en.Variants = make([]*proto.EncryptionVariant, encryption.GetVariants().Size())
+	for i, variant := range encryption.GetVariants().ToArray() {
+		keyIndex := uint32(variant.GetKeyIndex())
+		en.Variants[i] = &proto.EncryptionVariant{Root: variant.GetRoot().GetIdPtr(), Key: &keyIndex, EncryptedKey: variant.GetEncryptedKey(), FileStatistics: variant.GetFileStatistics()}
+	}
+	keyProvider := w_toKeyProviderKind(encryption.GetKeyProvider())
+	en.KeyProvider = &keyProvider
+	return en
+}
+
+func w_toEncryptionAlgorithm(algorithm EncryptionAlgorithm) proto.EncryptionAlgorithm {
+	switch algorithm {
+	case AES_CTR_128:
+		return proto.EncryptionAlgorithm_AES_CTR_128
+	case AES_CTR_256:
+		return proto.EncryptionAlgorithm_AES_CTR_256
+	}
+	panic(fmt.Sprintf("Unsupported encryption algorithm: %d", algorithm))
+}
+
+func w_toKeyProviderKind(kind KeyProviderKind) proto.KeyProviderKind {
+	switch kind {
+	case HADOOP:
+		return proto.KeyProviderKind_HADOOP
+	case AWS:
+		return proto.KeyProviderKind_AWS
+	case GCP:
+		return proto.KeyProviderKind_GCP
+	case AZURE:
+		return proto.KeyProviderKind_AZURE
+	}
+	return proto.KeyProviderKind_UNKNOWN
+}
+
 func toStripeInformation(stripe *StripeInformation) *proto.StripeInformation {
 	sn := &proto.StripeInformation{}
 	ns := uint64(stripe.GetNumberOfRows())
@@ -174,6 +248,10 @@ func toStripeInformation(stripe *StripeInformation) *proto.StripeInformation {
 	sn.DataLength = &dh
 	fh := stripe.GetFooterLength()
 	sn.FooterLength = &fh
+	if stripe.GetEncryptStripeId() > 0 {
+		ed := stripe.GetEncryptStripeId()
+		sn.EncryptStripeId = &ed
+	}
 	return sn
 }
 
@@ -365,11 +443,25 @@ func (mr *MothMetadataWriter) WriteStripeFooter(output slice.SliceOutput, footer
 	footerProtobuf.Columns = columns
 	timeZone := footer.GetTimeZone().String()
 	footerProtobuf.WriterTimezone = &timeZone
+
+	encryption := make([]*proto.StripeEncryptionVariant, footer.GetEncryption().Size())
+	for i, variant := range footer.GetEncryption().ToArray() {
+		variantStreams := make([]*proto.Stream, variant.GetStreams().Size())
+		for j, stream := range variant.GetStreams().ToArray() {
+			variantStreams[j] = w_toStream(stream)
+		}
+		variantEncodings := make([]*proto.ColumnEncoding, variant.GetEncodings().Size())
+		for j, encoding := range variant.GetEncodings().ToArray() {
+			variantEncodings[j] = w_toColumnEncoding(encoding)
+		}
+		encryption[i] = &proto.StripeEncryptionVariant{Streams: variantStreams, Encoding: variantEncodings}
+	}
+	footerProtobuf.Encryption = encryption
 	// footerProtobuf := MothProto.StripeFooter.newBuilder()
 	// .addAllStreams(footer.getStreams().stream().Map(MothMetadataWriter.w_toStream).collect(toList()))
 	// .addAllColumns(footer.getColumnEncodings().stream().Map(MothMetadataWriter.w_toColumnEncoding).collect(toList()))
 	// .setWriterTimezone(footer.getTimeZone().getId()).build()
-	return writeProtobufObject(output, footerProtobuf)
+	return mr.writeMessage(output, footerProtobuf)
 }
 
 func w_toStream(stream *Stream) *proto.Stream {
@@ -403,6 +495,10 @@ func w_toStreamKind(streamKind StreamKind) proto.Stream_Kind {
 		// unsupported
 	case BLOOM_FILTER_UTF8:
 		return proto.Stream_BLOOM_FILTER_UTF8
+	case ENCRYPTED_INDEX:
+		return proto.Stream_ENCRYPTED_INDEX
+	case ENCRYPTED_DATA:
+		return proto.Stream_ENCRYPTED_DATA
 	}
 	panic(fmt.Sprintf("Unsupported stream kind: %d", streamKind))
 }
@@ -443,7 +539,7 @@ func (mr *MothMetadataWriter) WriteRowIndexes(output slice.SliceOutput, rowGroup
 	}
 
 	rowIndexProtobuf.Entry = entrys
-	return writeProtobufObject(output, rowIndexProtobuf)
+	return mr.writeMessage(output, rowIndexProtobuf)
 }
 
 func w_toRowGroupIndex(rowGroupIndex *RowGroupIndex) *proto.RowIndexEntry {
@@ -469,7 +565,30 @@ func (mr *MothMetadataWriter) WriteBloomFilters(output slice.SliceOutput, bloomF
 	}
 	bloomFilterIndex.BloomFilter = bs
 
-	return writeProtobufObject(output, bloomFilterIndex)
+	return mr.writeMessage(output, bloomFilterIndex)
+}
+
+/**
+ * Writes each delete key as a message of its own, so the keys are read back one by one.
+ */
+// @Override
+func (mr *MothMetadataWriter) WriteDeleteKeys(output slice.SliceOutput, deleteKeys *util.ArrayList[*DeleteKey]) int32 {
+	size := util.INT32_ZERO
+	for _, deleteKey := range deleteKeys.ToArray() {
+		ts := deleteKey.GetTs()
+		size += writeProtobufObject(output, &proto.DeleteKey{Key: deleteKey.GetKey().AvailableBytes(), Ts: &ts})
+	}
+	return size
+}
+
+// @Override
+func (mr *MothMetadataWriter) WriteKeys(output slice.SliceOutput, keys *util.ArrayList[*Key]) int32 {
+	size := util.INT32_ZERO
+	for _, key := range keys.ToArray() {
+		position, ts := key.GetPosition(), key.GetTs()
+		size += writeProtobufObject(output, &proto.Key{Key: key.GetKey().AvailableBytes(), Position: &position, Ts: &ts})
+	}
+	return size
 }
 
 func toBloomFilter(bloomFilter *BloomFilter) *proto.BloomFilter {
@@ -502,6 +621,23 @@ func w_toCompression(compressionKind CompressionKind) proto.CompressionKind {
 	panic(fmt.Sprintf("Unsupported compression kind: %d", compressionKind))
 }
 
+/**
+ * Writes a message of the file tail, a stripe footer or an index stream. ORC files store the bare
+ * message, its length is known from the postscript, the stripe or the stream, Moth files prefix
+ * it with its length like the keys, see writeProtobufObject.
+ */
+func (mr *MothMetadataWriter) writeMessage(output slice.SliceOutput, object protobuf.Message) int32 {
+	if mr.formatFlavor.HasLengthPrefixedMessages() {
+		return writeProtobufObject(output, object)
+	}
+	b, err := protobuf.Marshal(object)
+	if err != nil {
+		return 0
+	}
+	output.WriteBS2(b, 0, util.Lens(b))
+	return util.Lens(b)
+}
+
 func writeProtobufObject(output slice.SliceOutput, object protobuf.Message) int32 {
 
 	b, err := protobuf.Marshal(object)
@@ -34,6 +34,54 @@ const (
 	UNION
 )
 
+func (md MothTypeKind) String() string {
+	switch md {
+	case BOOLEAN:
+		return "BOOLEAN"
+	case BYTE:
+		return "BYTE"
+	case SHORT:
+		return "SHORT"
+	case INT:
+		return "INT"
+	case LONG:
+		return "LONG"
+	case DECIMAL:
+		return "DECIMAL"
+	case FLOAT:
+		return "FLOAT"
+	case DOUBLE:
+		return "DOUBLE"
+	case STRING:
+		return "STRING"
+	case VARCHAR:
+		return "VARCHAR"
+	case CHAR:
+		return "CHAR"
+	case BINARY:
+		return "BINARY"
+	case DATE:
+		return "DATE"
+	case TIMESTAMP:
+		return "TIMESTAMP"
+	case TIMESTAMP_INSTANT:
+		return "TIMESTAMP_INSTANT"
+	case LIST:
+		return "LIST"
+	case MAP:
+		return "MAP"
+	case STRUCT:
+		return "STRUCT"
+	case UNION:
+		return "UNION"
+	}
+	return "UNKNOWN_TYPE"
+}
+
+var UNION_TAG_FIELD_NAME = "tag"
+
+var MOTH_TYPE_INSTANCE_SIZE int32 = util.SizeOf(&MothType{})
+
 type MothType struct {
 	mothTypeKind MothTypeKind
 	// List<MothColumnId> fieldTypeIndexes;
@@ -76,6 +124,20 @@ func NewMothType5(mothTypeKind MothTypeKind, fieldTypeIndexes *util.ArrayList[Mo
 	return me
 }
 
+/**
+ * Returns the size of the type held in memory, its field names and attributes included.
+ */
+func (me *MothType) GetRetainedSizeInBytes() int64 {
+	retainedSizeInBytes := int64(MOTH_TYPE_INSTANCE_SIZE) + int64(me.fieldTypeIndexes.Size())*util.INT32_BYTES
+	for _, fieldName := range me.fieldNames.ToArray() {
+		retainedSizeInBytes += int64(len(fieldName))
+	}
+	for key, value := range me.attributes {
+		retainedSizeInBytes += int64(len(key) + len(value))
+	}
+	return retainedSizeInBytes
+}
+
 func (me *MothType) GetMothTypeKind() MothTypeKind {
 	return me.mothTypeKind
 }
@@ -232,6 +294,9 @@ func toMothType(nextFieldTypeIndex int32, kind block.Type) *util.ArrayList[*Moth
 	// 	fieldTypes := kind.getTypeParameters()
 	// 	return createMothRowType(nextFieldTypeIndex, fieldNames, fieldTypes)
 	// }
+	if IsUnionType(kind) {
+		return createMothUnionType(nextFieldTypeIndex, kind.GetTypeParameters().SubList(1, kind.GetTypeParameters().Size()))
+	}
 	rt, flag := kind.(*block.RowType)
 	if flag {
 		fieldNames := util.NewArrayList[string]()
@@ -270,6 +335,112 @@ func createMothMapType(nextFieldTypeIndex int32, keyType block.Type, valueType b
 	return mothTypes
 }
 
+// List<MothType> createMothUnionType(int nextFieldTypeIndex, List<Type> variantTypes)
+func createMothUnionType(nextFieldTypeIndex int32, variantTypes *util.ArrayList[block.Type]) *util.ArrayList[*MothType] {
+	nextFieldTypeIndex++
+	fieldTypeIndexes := util.NewArrayList[MothColumnId]()
+	fieldNames := util.NewArrayList[string]()
+	variantMothTypes := util.NewArrayList[*MothType]()
+	for i := 0; i < variantTypes.Size(); i++ {
+		fieldTypeIndexes.Add(NewMothColumnId(uint32(nextFieldTypeIndex)))
+		fieldNames.Add(unionFieldName(i))
+		variantTypeList := toMothType(nextFieldTypeIndex, variantTypes.Get(i))
+		variantMothTypes.AddAll(variantTypeList)
+		nextFieldTypeIndex += variantTypeList.SizeInt32()
+	}
+	mothTypes := util.NewArrayList[*MothType]()
+	mothTypes.Add(NewMothType4(UNION, fieldTypeIndexes, fieldNames))
+	mothTypes.AddAll(variantMothTypes)
+	return mothTypes
+}
+
+/**
+ * Creates the block type of a UNION column. Like the UnionColumnReader produces it, a union
+ * is a row with a TINYINT tag followed by one field per variant, named field0, field1, ...
+ * In every non-null row only the field selected by the tag holds a value.
+ */
+func CreateUnionType(variantTypes ...block.Type) *block.RowType {
+	if len(variantTypes) == 0 {
+		panic("Union type must have at least 1 variant")
+	}
+	fields := util.NewArrayList(block.CreateField(UNION_TAG_FIELD_NAME, block.TINYINT))
+	for i, variantType := range variantTypes {
+		fields.Add(block.CreateField(unionFieldName(i), variantType))
+	}
+	return block.NewUnionRowType(fields)
+}
+
+/**
+ * Returns true if the type was created by CreateUnionType, a row type of the same fields is a
+ * struct.
+ */
+func IsUnionType(kind block.Type) bool {
+	rt, flag := kind.(*block.RowType)
+	return flag && rt.IsUnion()
+}
+
+func unionFieldName(variant int) string {
+	return "field" + strconv.Itoa(variant)
+}
+
+/**
+ * Creates the block type that the column readers produce for a column, the inverse of the type
+ * mapping used by the writer. Struct fields keep their names and unions use the row layout of
+ * CreateUnionType.
+ */
+func ToBlockType(types *ColumnMetadata[*MothType], columnId MothColumnId) block.Type {
+	mothType := types.Get(columnId)
+	switch mothType.GetMothTypeKind() {
+	case BOOLEAN:
+		return block.BOOLEAN
+	case BYTE:
+		return block.TINYINT
+	case SHORT:
+		return block.SMALLINT
+	case INT:
+		return block.INTEGER
+	case LONG:
+		return block.BIGINT
+	case FLOAT:
+		return block.REAL
+	case DOUBLE:
+		return block.DOUBLE
+	case STRING:
+		return block.VARCHAR
+	case VARCHAR:
+		return block.CreateVarcharType(mothType.GetLength().Get())
+	case CHAR:
+		return block.CreateCharType(int64(mothType.GetLength().Get()))
+	case BINARY:
+		return block.VARBINARY
+	case DATE:
+		return block.DATE
+	case TIMESTAMP:
+		return block.TIMESTAMP_MILLIS
+	case TIMESTAMP_INSTANT:
+		return block.TIMESTAMP_TZ_MILLIS
+	case DECIMAL:
+		return block.CreateDecimalType(mothType.GetPrecision().Get(), mothType.GetScale().Get())
+	case LIST:
+		return block.NewArrayType(ToBlockType(types, mothType.GetFieldTypeIndex(0)))
+	case MAP:
+		return block.NewMapType(ToBlockType(types, mothType.GetFieldTypeIndex(0)), ToBlockType(types, mothType.GetFieldTypeIndex(1)))
+	case STRUCT:
+		fields := util.NewArrayList[*block.Field]()
+		for i := util.INT32_ZERO; i < mothType.GetFieldCount(); i++ {
+			fields.Add(block.CreateField(mothType.GetFieldName(i), ToBlockType(types, mothType.GetFieldTypeIndex(i))))
+		}
+		return block.From(fields)
+	case UNION:
+		variantTypes := make([]block.Type, mothType.GetFieldCount())
+		for i := range variantTypes {
+			variantTypes[i] = ToBlockType(types, mothType.GetFieldTypeIndex(int32(i)))
+		}
+		return CreateUnionType(variantTypes...)
+	}
+	panic(fmt.Sprintf("Unsupported moth type: %s", mothType.GetMothTypeKind()))
+}
+
 // ColumnMetadata<MothType> createRootMothType(List<String> fieldNames, List<Type> fieldTypes)
 func CreateRootMothType(fieldNames *util.ArrayList[string], fieldTypes *util.ArrayList[block.Type]) *ColumnMetadata[*MothType] {
 	return NewColumnMetadata(createMothRowType(0, fieldNames, fieldTypes))
@@ -305,3 +476,15 @@ func createMothRowType(nextFieldTypeIndex int32, fieldNames *util.ArrayList[stri
 	}
 	return mothTypes
 }
+
+/**
+ * Number of columns in the subtree of the column, including the column itself. The columns of
+ * a subtree have consecutive ids starting at the root.
+ */
+func GetSubtreeColumnCount(types *ColumnMetadata[*MothType], root MothColumnId) int32 {
+	count := int32(1)
+	for _, fieldTypeIndex := range types.Get(root).GetFieldTypeIndexes().ToArray() {
+		count += GetSubtreeColumnCount(types, fieldTypeIndex)
+	}
+	return count
+}
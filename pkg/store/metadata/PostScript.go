@@ -1,6 +1,9 @@
 package metadata
 
-import "github.com/mothdb-bd/orc-go/pkg/slice"
+import (
+	"github.com/mothdb-bd/orc-go/pkg/slice"
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
 
 var MAGIC string = "MOTH"
 var MAGIC_SLICE = slice.NewWithString("MOTH")
@@ -12,6 +15,18 @@ const (
 	MOTH_HIVE_8732
 )
 
+func (hn HiveWriterVersion) String() string {
+	switch hn {
+	case ORIGINAL:
+		return "ORIGINAL"
+	case MOTH_HIVE_8732:
+		return "MOTH_HIVE_8732"
+	}
+	return "UNKNOWN_VERSION"
+}
+
+var POST_SCRIPT_INSTANCE_SIZE int32 = util.SizeOf(&PostScript{})
+
 type PostScript struct {
 	version              []uint32
 	footerLength         int64
@@ -32,6 +47,10 @@ func NewPostScript(version []uint32, footerLength int64, metadataLength int64, c
 	return pt
 }
 
+func (pt *PostScript) GetRetainedSizeInBytes() int64 {
+	return int64(POST_SCRIPT_INSTANCE_SIZE) + int64(len(pt.version))*util.INT32_BYTES
+}
+
 func (pt *PostScript) GetVersion() []uint32 {
 	return pt.version
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"hash"
 	"math"
+	"reflect"
 
 	"github.com/mothdb-bd/orc-go/pkg/maths"
 	"github.com/mothdb-bd/orc-go/pkg/slice"
@@ -84,12 +85,13 @@ func (sr *StatisticsHasher) PutOptionalDouble(present bool, value float64) *Stat
 }
 
 func (sr *StatisticsHasher) PutOptionalHashable(value Hashable) *StatisticsHasher {
-	if value != nil {
+	// the statistics are passed as typed nil pointers when they are absent
+	if value != nil && !reflect.ValueOf(value).IsNil() {
 		sr.hasher.Write([]byte{TRUE})
+		value.AddHash(sr)
 	} else {
 		sr.hasher.Write([]byte{FALSE})
 	}
-	value.AddHash(sr)
 	return sr
 }
 
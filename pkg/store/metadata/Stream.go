@@ -16,8 +16,48 @@ const (
 	ROW_INDEX
 	BLOOM_FILTER
 	BLOOM_FILTER_UTF8
+	ENCRYPTED_INDEX
+	ENCRYPTED_DATA
+	STRIPE_STATISTICS
+	FILE_STATISTICS
+	// the stripe key index, like the statistics kinds it is only used for the IV of its encryption
+	KEY_INDEX
 )
 
+func (sd StreamKind) String() string {
+	switch sd {
+	case PRESENT:
+		return "PRESENT"
+	case DATA:
+		return "DATA"
+	case LENGTH:
+		return "LENGTH"
+	case DICTIONARY_DATA:
+		return "DICTIONARY_DATA"
+	case DICTIONARY_COUNT:
+		return "DICTIONARY_COUNT"
+	case SECONDARY:
+		return "SECONDARY"
+	case ROW_INDEX:
+		return "ROW_INDEX"
+	case BLOOM_FILTER:
+		return "BLOOM_FILTER"
+	case BLOOM_FILTER_UTF8:
+		return "BLOOM_FILTER_UTF8"
+	case ENCRYPTED_INDEX:
+		return "ENCRYPTED_INDEX"
+	case ENCRYPTED_DATA:
+		return "ENCRYPTED_DATA"
+	case STRIPE_STATISTICS:
+		return "STRIPE_STATISTICS"
+	case FILE_STATISTICS:
+		return "FILE_STATISTICS"
+	case KEY_INDEX:
+		return "KEY_INDEX"
+	}
+	return "UNKNOWN_STREAM"
+}
+
 type Stream struct {
 	columnId   MothColumnId
 	streamKind StreamKind
@@ -0,0 +1,30 @@
+package metadata
+
+import (
+	"github.com/mothdb-bd/orc-go/pkg/util"
+)
+
+/**
+ * The streams of an encryption variant in one stripe, in file order. Index streams are stored in
+ * the ENCRYPTED_INDEX area and data streams in the ENCRYPTED_DATA area of the variant root. The
+ * encodings are for the columns of the variant subtree, starting at the root.
+ */
+type StripeEncryptionVariant struct {
+	streams   *util.ArrayList[*Stream]
+	encodings *util.ArrayList[*ColumnEncoding]
+}
+
+func NewStripeEncryptionVariant(streams *util.ArrayList[*Stream], encodings *util.ArrayList[*ColumnEncoding]) *StripeEncryptionVariant {
+	st := new(StripeEncryptionVariant)
+	st.streams = streams
+	st.encodings = encodings
+	return st
+}
+
+func (st *StripeEncryptionVariant) GetStreams() *util.ArrayList[*Stream] {
+	return st.streams
+}
+
+func (st *StripeEncryptionVariant) GetEncodings() *util.ArrayList[*ColumnEncoding] {
+	return st.encodings
+}
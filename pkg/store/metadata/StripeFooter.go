@@ -10,14 +10,20 @@ type StripeFooter struct {
 	streams         *util.ArrayList[*Stream]
 	columnEncodings *ColumnMetadata[*ColumnEncoding]
 	// timeZone        *ZoneId
-	timeZone *time.Location
+	timeZone   *time.Location
+	encryption *util.ArrayList[*StripeEncryptionVariant]
 }
 
 func NewStripeFooter(streams *util.ArrayList[*Stream], columnEncodings *ColumnMetadata[*ColumnEncoding], timeZone *time.Location) *StripeFooter {
+	return NewStripeFooter2(streams, columnEncodings, timeZone, util.NewArrayList[*StripeEncryptionVariant]())
+}
+
+func NewStripeFooter2(streams *util.ArrayList[*Stream], columnEncodings *ColumnMetadata[*ColumnEncoding], timeZone *time.Location, encryption *util.ArrayList[*StripeEncryptionVariant]) *StripeFooter {
 	sr := new(StripeFooter)
 	sr.streams = streams
 	sr.columnEncodings = columnEncodings
 	sr.timeZone = timeZone
+	sr.encryption = encryption
 	return sr
 }
 
@@ -32,3 +38,10 @@ func (sr *StripeFooter) GetStreams() *util.ArrayList[*Stream] {
 func (sr *StripeFooter) GetTimeZone() *time.Location {
 	return sr.timeZone
 }
+
+/**
+ * The streams of every encryption variant of the file, in the order of Encryption.GetVariants
+ */
+func (sr *StripeFooter) GetEncryption() *util.ArrayList[*StripeEncryptionVariant] {
+	return sr.encryption
+}
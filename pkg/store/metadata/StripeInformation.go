@@ -4,21 +4,30 @@ import (
 	"github.com/mothdb-bd/orc-go/pkg/util"
 )
 
+var STRIPE_INFORMATION_INSTANCE_SIZE int32 = util.SizeOf(&StripeInformation{})
+
 type StripeInformation struct {
 	numberOfRows int32
 	offset       uint64
 	indexLength  uint64
 	dataLength   uint64
 	footerLength uint64
+	// stripe id used in the IV of encrypted streams
+	encryptStripeId uint64
 }
 
 func NewStripeInformation(numberOfRows int32, offset uint64, indexLength uint64, dataLength uint64, footerLength uint64) *StripeInformation {
+	return NewStripeInformation2(numberOfRows, offset, indexLength, dataLength, footerLength, 0)
+}
+
+func NewStripeInformation2(numberOfRows int32, offset uint64, indexLength uint64, dataLength uint64, footerLength uint64, encryptStripeId uint64) *StripeInformation {
 	sn := new(StripeInformation)
 	sn.numberOfRows = numberOfRows
 	sn.offset = offset
 	sn.indexLength = indexLength
 	sn.dataLength = dataLength
 	sn.footerLength = footerLength
+	sn.encryptStripeId = encryptStripeId
 	return sn
 }
 
@@ -42,6 +51,10 @@ func (sn *StripeInformation) GetFooterLength() uint64 {
 	return sn.footerLength
 }
 
+func (sn *StripeInformation) GetEncryptStripeId() uint64 {
+	return sn.encryptStripeId
+}
+
 func (sn *StripeInformation) GetTotalLength() uint64 {
 	return sn.indexLength + sn.dataLength + sn.footerLength
 }
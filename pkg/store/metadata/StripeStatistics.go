@@ -14,7 +14,13 @@ type StripeStatistics struct {
 func NewStripeStatistics(columnStatistics *ColumnMetadata[*ColumnStatistics]) *StripeStatistics {
 	ss := new(StripeStatistics)
 	ss.columnStatistics = columnStatistics
-	ss.retainedSizeInBytes = int64(STRIPE_STATISTICS_INSTANCE_SIZE) + columnStatistics.Stream().MapToLong((*ColumnStatistics).GetRetainedSizeInBytes).Sum()
+	ss.retainedSizeInBytes = int64(STRIPE_STATISTICS_INSTANCE_SIZE)
+	for _, statistics := range columnStatistics.List().ToArray() {
+		// statistics of encrypted columns are missing for readers holding the key
+		if statistics != nil {
+			ss.retainedSizeInBytes += statistics.GetRetainedSizeInBytes()
+		}
+	}
 	return ss
 }
 
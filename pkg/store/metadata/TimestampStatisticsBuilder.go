@@ -1,6 +1,8 @@
 package metadata
 
 import (
+	"math"
+
 	"github.com/mothdb-bd/orc-go/pkg/maths"
 	"github.com/mothdb-bd/orc-go/pkg/optional"
 	"github.com/mothdb-bd/orc-go/pkg/spi/block"
@@ -33,6 +35,8 @@ func NewTimestampStatisticsBuilder2(millisFunction func(_ block.Type, block bloc
 }
 func NewTimestampStatisticsBuilder3(bloomFilterBuilder BloomFilterBuilder, millisFunction func(_ block.Type, block block.Block, position int32) int64) *TimestampStatisticsBuilder {
 	tr := new(TimestampStatisticsBuilder)
+	tr.minimum = math.MaxInt64
+	tr.maximum = math.MinInt64
 	tr.bloomFilterBuilder = bloomFilterBuilder
 	tr.millisFunction = millisFunction
 	return tr
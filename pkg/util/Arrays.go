@@ -82,7 +82,7 @@ func CopyBoolsOfRange(original []bool, from int32, to int32) []bool {
 
 func CopyBools(from []bool, srcPos int32, dest []bool, destPos int, length int32) {
 	n := 0
-	for i := srcPos; i < length && int(i) < len(from); i++ {
+	for i := srcPos; i < srcPos+length && int(i) < len(from); i++ {
 		dest[n+destPos] = from[i]
 		n++
 	}
@@ -98,7 +98,7 @@ func CopyOfBytes(original []byte, newLength int32) []byte {
 
 func CopyBytes(from []byte, srcPos int32, dest []byte, destPos int32, length int32) {
 	n := INT32_ZERO
-	for i := srcPos; i < length && int(i) < len(from); i++ {
+	for i := srcPos; i < srcPos+length && int(i) < len(from); i++ {
 		dest[n+destPos] = from[i]
 		n++
 	}
@@ -125,7 +125,7 @@ func CopyInt16sOfRange(original []int16, from int32, to int32) []int16 {
 
 func CopyInt16s(from []int16, srcPos int32, dest []int16, destPos int32, length int32) {
 	n := INT32_ZERO
-	for i := srcPos; i < length && int(i) < len(from); i++ {
+	for i := srcPos; i < srcPos+length && int(i) < len(from); i++ {
 		dest[n+destPos] = from[i]
 		n++
 	}
@@ -152,7 +152,7 @@ func CopyOfInt32(original []int32, newLength int32) []int32 {
 
 func CopyInt32s(from []int32, srcPos int32, dest []int32, destPos int32, length int32) {
 	n := INT32_ZERO
-	for i := srcPos; i < length && int(i) < len(from); i++ {
+	for i := srcPos; i < srcPos+length && int(i) < len(from); i++ {
 		dest[n+destPos] = from[i]
 		n++
 	}
@@ -168,7 +168,7 @@ func CopyOfInt64s(original []int64, newLength int32) []int64 {
 
 func CopyInt64s(from []int64, srcPos int32, dest []int64, destPos int32, length int32) {
 	n := INT32_ZERO
-	for i := srcPos; i < length && int(i) < len(from); i++ {
+	for i := srcPos; i < srcPos+length && int(i) < len(from); i++ {
 		dest[n+destPos] = from[i]
 		n++
 	}
@@ -184,7 +184,7 @@ func CopyOfFloat64s(original []float64, newLength int32) []float64 {
 
 func CopyFloat64s(from []float64, srcPos int32, dest []float64, destPos int32, length int32) {
 	n := INT32_ZERO
-	for i := srcPos; i < length && int(i) < len(from); i++ {
+	for i := srcPos; i < srcPos+length && int(i) < len(from); i++ {
 		dest[n+destPos] = from[i]
 		n++
 	}
@@ -192,7 +192,7 @@ func CopyFloat64s(from []float64, srcPos int32, dest []float64, destPos int32, l
 
 func CopyArrays[T basic.Object](from []T, srcPos int32, dest []T, destPos int32, length int32) {
 	n := INT32_ZERO
-	for i := srcPos; i < length && int(i) < len(from); i++ {
+	for i := srcPos; i < srcPos+length && int(i) < len(from); i++ {
 		dest[n+destPos] = from[i]
 		n++
 	}